@@ -1,41 +1,48 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	_ "time/tzdata" // Scheduler time zones also resolve in images without a zoneinfo database
 
 	"github.com/ThraaxSession/gintainer/internal/caddy"
 	"github.com/ThraaxSession/gintainer/internal/config"
 	"github.com/ThraaxSession/gintainer/internal/handlers"
 	"github.com/ThraaxSession/gintainer/internal/logger"
-	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/middleware"
 	"github.com/ThraaxSession/gintainer/internal/runtime"
 	"github.com/ThraaxSession/gintainer/internal/scheduler"
+	"github.com/ThraaxSession/gintainer/internal/version"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	// Initialize configuration manager
-	configPath := os.Getenv("CONFIG_PATH")
-	if configPath == "" {
-		configPath = "gintainer.yaml"
-	}
+	configPath := config.ConfigPathFromEnv()
 
 	configManager, err := config.NewManager(configPath)
 	if err != nil {
 		logger.Fatalf("Failed to initialize config manager: %v", err)
 	}
-	defer configManager.Close()
 
 	cfg := configManager.GetConfig()
 
-	// Set logger level based on config mode
-	if cfg.Server.Mode == "debug" {
-		logger.SetLevel(logger.DebugLevel)
-		logger.Info("Logger level set to DEBUG")
-	} else {
-		logger.SetLevel(logger.InfoLevel)
-		logger.Info("Logger level set to INFO")
-	}
+	// Set logger format, level and log file from config
+	logger.SetFormat(cfg.Server.LogFormat)
+	applyLogLevel(cfg.Server)
+	applyLogFile(cfg.Server)
+	logger.ResizeBuffer(cfg.Server.LogBufferSize)
+	defer logger.Close()
+
+	build := version.Get()
+	logger.Info("Main: Starting Gintainer", "version", build.Version, "commit", build.Commit, "build_date", build.BuildDate)
 
 	// Set Gin mode from config
 	gin.SetMode(cfg.Server.Mode)
@@ -44,33 +51,8 @@ func main() {
 	runtimeManager := runtime.NewManager()
 	logger.Debug("Main: Runtime manager created")
 
-	// Initialize Docker runtime if enabled
-	if cfg.Docker.Enabled {
-		logger.Debug("Main: Docker runtime is enabled in config, attempting to initialize")
-		dockerRuntime, err := runtime.NewDockerRuntime()
-		if err != nil {
-			logger.Printf("Warning: Failed to initialize Docker runtime: %v", err)
-		} else {
-			runtimeManager.RegisterRuntime("docker", dockerRuntime)
-			logger.Println("Docker runtime initialized")
-		}
-	} else {
-		logger.Debug("Main: Docker runtime is disabled in config")
-	}
-
-	// Initialize Podman runtime if enabled
-	if cfg.Podman.Enabled {
-		logger.Debug("Main: Podman runtime is enabled in config, attempting to initialize")
-		podmanRuntime, err := runtime.NewPodmanRuntime()
-		if err != nil {
-			logger.Printf("Warning: Failed to initialize Podman runtime: %v", err)
-		} else {
-			runtimeManager.RegisterRuntime("podman", podmanRuntime)
-			logger.Println("Podman runtime initialized")
-		}
-	} else {
-		logger.Debug("Main: Podman runtime is disabled in config")
-	}
+	// Initialize the enabled runtimes
+	syncRuntimes(runtimeManager, cfg)
 
 	// Check if at least one runtime is available
 	availableRuntimes := runtimeManager.GetAllRuntimes()
@@ -83,19 +65,17 @@ func main() {
 	sched := scheduler.NewScheduler(runtimeManager)
 
 	// Apply scheduler config from file
-	if cfg.Scheduler.Enabled {
-		schedConfig := models.CronJobConfig{
-			Schedule: cfg.Scheduler.Schedule,
-			Enabled:  cfg.Scheduler.Enabled,
-			Filters:  cfg.Scheduler.Filters,
-		}
-		if err := sched.UpdateConfig(schedConfig); err != nil {
-			logger.Printf("Warning: Failed to configure scheduler: %v", err)
-		}
+	if err := sched.UpdateConfig(scheduler.FromConfig(cfg.Scheduler)); err != nil {
+		logger.Printf("Warning: Failed to configure scheduler: %v", err)
 	}
+	sched.SetNotifications(cfg.Notifications)
+	sched.SetRegistries(cfg.Registries)
 
 	sched.Start()
-	defer sched.Stop()
+
+	// Restart containers that exit unexpectedly (opt-in)
+	autoRestarter := scheduler.NewAutoRestarter(runtimeManager)
+	autoRestarter.UpdateConfig(cfg.AutoRestart)
 
 	// Initialize Caddy service
 	caddyService := caddy.NewService(&cfg.Caddy)
@@ -105,18 +85,34 @@ func main() {
 
 	// Initialize handlers
 	handler := handlers.NewHandler(runtimeManager, caddyService, configManager)
+	handler.UpdateListCache(cfg.Server.ListCacheDuration())
+	handler.WatchRemovedContainers()
 	schedulerHandler := handlers.NewSchedulerHandler(sched, configManager)
 	webHandler := handlers.NewWebHandler(runtimeManager, configManager)
 	caddyHandler := handlers.NewCaddyHandler(caddyService)
 
+	// Cancelled on SIGINT/SIGTERM to shut the server down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Ends long-lived streams on shutdown, the server would wait for them otherwise
+	stream := middleware.CancelOnShutdown(ctx)
+
 	// Set up Gin router
 	router := gin.Default()
+	// Match routes on the escaped path so image references can be passed URL-encoded (e.g. ghcr.io%2Forg%2Fapp)
+	router.UseRawPath = true
+
+	if cfg.Server.CORS.Enabled {
+		router.Use(middleware.CORS(cfg.Server.CORS))
+		logger.Info("Main: CORS enabled", "origins", cfg.Server.CORS.AllowedOrigins)
+	}
 
 	// Load HTML templates
 	router.LoadHTMLGlob("web/templates/*")
 
 	// Health check endpoint
 	router.GET("/health", handler.HealthCheck)
+	router.GET("/livez", handler.Livez)
 
 	// Web UI routes
 	router.GET("/", webHandler.Dashboard)
@@ -128,17 +124,51 @@ func main() {
 
 	// API v1 routes
 	api := router.Group("/api")
+	if cfg.Server.RateLimit.Enabled {
+		api.Use(middleware.NewRateLimiter(cfg.Server.RateLimit).Middleware())
+		logger.Info("Main: Rate limiting enabled", "requests_per_minute", cfg.Server.RateLimit.RequestsPerMinute)
+	}
+	api.Use(handler.InvalidateListCache())
 	{
 		// Container routes
 		api.GET("/containers", handler.ListContainers)
+		api.GET("/containers/export", handler.ExportContainers)
 		api.POST("/containers", handler.CreateContainer)
 		api.POST("/containers/run", handler.RunContainer)
+		api.POST("/containers/restore", handler.RestoreContainer)
+		api.POST("/containers/prune", handler.PruneContainers)
 		api.DELETE("/containers/:id", handler.DeleteContainer)
 		api.POST("/containers/:id/start", handler.StartContainer)
 		api.POST("/containers/:id/stop", handler.StopContainer)
 		api.POST("/containers/:id/restart", handler.RestartContainer)
+		api.POST("/containers/:id/kill", handler.KillContainer)
+		api.PATCH("/containers/:id/resources", handler.UpdateContainerResources)
+		api.POST("/containers/:id/checkpoint", handler.CheckpointContainer)
+		api.GET("/containers/:id/systemd", handler.GenerateSystemd)
+		api.GET("/containers/:id/kube", handler.GenerateContainerKube)
 		api.POST("/containers/update", handler.UpdateContainers)
-		api.GET("/containers/:id/logs", handler.StreamLogs)
+		api.POST("/containers/bulk", handler.BulkContainerAction)
+		api.GET("/containers/:id/logs", stream, handler.StreamLogs)
+		api.GET("/containers/:id/top", handler.ContainerTop)
+		api.GET("/containers/:id/ls", handler.ListContainerPath)
+		api.GET("/containers/:id/exec/ws", stream, handler.ExecWebSocket)
+		api.PUT("/containers/:id/caddy", handler.UpdateContainerCaddyLabels)
+
+		// Image routes
+		api.POST("/images/pull", handler.PullImage)
+		api.POST("/images/build", handler.BuildImage)
+		api.GET("/images/:id/history", handler.ImageHistory)
+		api.POST("/images/:id/tag", handler.TagImage)
+		api.POST("/images/:id/push", handler.PushImage)
+
+		// System routes
+		api.POST("/system/prune", handler.SystemPrune)
+		api.GET("/system/df", handler.DiskUsage)
+		api.GET("/runtimes", handler.ListRuntimes)
+		api.GET("/version", handler.GetVersion)
+
+		// Event routes
+		api.GET("/events", stream, handler.StreamEvents)
 
 		// Pod routes
 		api.GET("/pods", handler.ListPods)
@@ -146,13 +176,21 @@ func main() {
 		api.POST("/pods/:id/start", handler.StartPod)
 		api.POST("/pods/:id/stop", handler.StopPod)
 		api.POST("/pods/:id/restart", handler.RestartPod)
+		api.GET("/pods/:id/stats", handler.PodStats)
+		api.GET("/pods/:id/kube", handler.GeneratePodKube)
 
 		// Compose routes
 		api.POST("/compose", handler.DeployCompose)
+		api.GET("/deployments", handler.ListDeployments)
+		api.DELETE("/deployments/:name", handler.DeleteDeployment)
+		api.POST("/deployments/:name/scale", handler.ScaleDeployment)
 
 		// Scheduler routes
 		api.GET("/scheduler/config", schedulerHandler.GetConfig)
 		api.PUT("/scheduler/config", schedulerHandler.UpdateConfig)
+		api.POST("/scheduler/run", schedulerHandler.RunNow)
+		api.POST("/scheduler/preview", schedulerHandler.PreviewSchedule)
+		api.GET("/scheduler/history", schedulerHandler.GetHistory)
 
 		// Caddy routes (only enabled when Caddy integration is enabled)
 		if cfg.Caddy.Enabled {
@@ -162,29 +200,50 @@ func main() {
 			api.PUT("/caddy/files/:id", caddyHandler.UpdateCaddyfile)
 			api.DELETE("/caddy/files/:id", caddyHandler.DeleteCaddyfile)
 			api.POST("/caddy/reload", caddyHandler.ReloadCaddy)
+			api.POST("/caddy/validate", caddyHandler.ValidateCaddy)
 		}
 
 		// Config routes
 		api.GET("/config", webHandler.GetConfig)
 		api.POST("/config", webHandler.UpdateConfigAPI)
+		api.PATCH("/config", webHandler.PatchConfig)
+		api.POST("/config/diff", webHandler.DiffConfig)
+		api.POST("/config/reset", webHandler.ResetConfig)
+		api.GET("/config/schema", webHandler.ConfigSchema)
+		api.GET("/config/backups", webHandler.ListConfigBackups)
+		api.POST("/config/restore", webHandler.RestoreConfig)
 
 		// Logs routes
-		api.GET("/logs", webHandler.StreamLogs)
+		api.GET("/logs", stream, webHandler.StreamLogs)
+		api.GET("/logs/download", webHandler.DownloadLogs)
 	}
 
 	// Set up hot-reload for configuration
 	configManager.SetOnChange(func(newConfig *config.Config) {
 		logger.Println("Configuration changed, applying new settings...")
 
-		// Update scheduler if config changed
-		schedConfig := models.CronJobConfig{
-			Schedule: newConfig.Scheduler.Schedule,
-			Enabled:  newConfig.Scheduler.Enabled,
-			Filters:  newConfig.Scheduler.Filters,
+		logger.SetFormat(newConfig.Server.LogFormat)
+		applyLogLevel(newConfig.Server)
+		applyLogFile(newConfig.Server)
+		logger.ResizeBuffer(newConfig.Server.LogBufferSize)
+
+		// Start newly enabled and drop newly disabled runtimes
+		syncRuntimes(runtimeManager, newConfig)
+		if len(runtimeManager.GetAllRuntimes()) == 0 {
+			logger.Warn("Main: No container runtime available after config reload")
 		}
-		if err := sched.UpdateConfig(schedConfig); err != nil {
+
+		// Update scheduler if config changed
+		if err := sched.UpdateConfig(scheduler.FromConfig(newConfig.Scheduler)); err != nil {
 			logger.Printf("Error updating scheduler config: %v", err)
 		}
+		sched.SetNotifications(newConfig.Notifications)
+		sched.SetRegistries(newConfig.Registries)
+
+		// Also picks up runtimes that were just enabled
+		autoRestarter.UpdateConfig(newConfig.AutoRestart)
+		handler.UpdateListCache(newConfig.Server.ListCacheDuration())
+		handler.WatchRemovedContainers()
 
 		// Update Caddy service if config changed
 		caddyService.UpdateConfig(&newConfig.Caddy)
@@ -196,15 +255,137 @@ func main() {
 	})
 	configManager.StartWatching()
 
-	// Get port from config or environment
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = cfg.Server.Port
+	// Port already includes GINTAINER_SERVER_PORT/PORT overrides
+	port := cfg.Server.Port
+	srv := &http.Server{Addr: ":" + port, Handler: router.Handler()}
+
+	listen := srv.ListenAndServe
+	if cfg.Server.TLS.Enabled {
+		listen = listenTLS(ctx, srv, cfg.Server.TLS)
+	} else {
+		logger.Printf("Starting Gintainer on port %s", port)
+		logger.Printf("Web UI available at http://localhost:%s", port)
 	}
 
-	logger.Printf("Starting Gintainer on port %s", port)
-	logger.Printf("Web UI available at http://localhost:%s", port)
-	if err := router.Run(":" + port); err != nil {
+	// Stop the background jobs and the config watcher once in-flight requests are done
+	err = serve(ctx, srv, listen, shutdownTimeout,
+		autoRestarter.Stop,
+		handler.StopListCache,
+		handler.StopWatchingRemovedContainers,
+		sched.Stop,
+		func() { configManager.Close() },
+	)
+	if err != nil {
 		logger.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// listenTLS returns the function serving srv over HTTPS, optionally redirecting plain HTTP
+// requests to it until ctx is done
+func listenTLS(ctx context.Context, srv *http.Server, tlsConfig config.TLSConfig) func() error {
+	// Fail early with a clear message instead of an opaque listener error
+	if _, err := tls.LoadX509KeyPair(tlsConfig.CertFile, tlsConfig.KeyFile); err != nil {
+		logger.Fatalf("Failed to load TLS certificate %s / key %s: %v", tlsConfig.CertFile, tlsConfig.KeyFile, err)
+	}
+
+	port := strings.TrimPrefix(srv.Addr, ":")
+	if tlsConfig.RedirectHTTP {
+		redirect := &http.Server{Addr: ":" + tlsConfig.HTTPPort, Handler: middleware.HTTPSRedirect(port)}
+		context.AfterFunc(ctx, func() { redirect.Close() })
+		go func() {
+			logger.Printf("Redirecting HTTP on port %s to HTTPS", tlsConfig.HTTPPort)
+			if err := redirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Main: HTTP redirect listener stopped", "error", err)
+			}
+		}()
+	}
+
+	logger.Printf("Starting Gintainer with TLS on port %s", port)
+	logger.Printf("Web UI available at https://localhost:%s", port)
+	return func() error {
+		return srv.ListenAndServeTLS(tlsConfig.CertFile, tlsConfig.KeyFile)
+	}
+}
+
+// runtimeFactories create the runtime with the given name from its config section
+var runtimeFactories = map[string]func(config.RuntimeConfig) (runtime.ContainerRuntime, error){
+	"docker": func(rc config.RuntimeConfig) (runtime.ContainerRuntime, error) {
+		return runtime.NewDockerRuntime(runtime.DockerConnection{
+			Socket:      rc.Socket,
+			Context:     rc.Context,
+			ListWorkers: rc.ListWorkers,
+		})
+	},
+	"podman": func(rc config.RuntimeConfig) (runtime.ContainerRuntime, error) {
+		return runtime.NewPodmanRuntime(runtime.PodmanConnection{
+			Socket:      rc.Socket,
+			Identity:    rc.Identity,
+			Passphrase:  rc.Passphrase,
+			ListWorkers: rc.ListWorkers,
+		})
+	},
+}
+
+// syncRuntimes registers the enabled runtimes that are not running yet and
+// unregisters the disabled ones. A runtime that fails to initialize is skipped
+// and tried again on the next config change.
+func syncRuntimes(manager *runtime.Manager, cfg *config.Config) {
+	sections := map[string]config.RuntimeConfig{
+		"docker": cfg.Docker,
+		"podman": cfg.Podman,
+	}
+	registered := manager.GetAllRuntimes()
+
+	for _, name := range []string{"docker", "podman"} {
+		section := sections[name]
+		_, active := registered[name]
+
+		switch {
+		case section.Enabled && !active:
+			logger.Debug("Main: Runtime is enabled in config, attempting to initialize", "runtime", name)
+			rt, err := runtimeFactories[name](section)
+			if err != nil {
+				logger.Warn("Main: Failed to initialize runtime", "runtime", name, "error", err)
+				continue
+			}
+			manager.RegisterRuntime(name, rt)
+			logger.Info("Main: Runtime initialized", "runtime", name)
+		case !section.Enabled && active:
+			rt, _ := manager.UnregisterRuntime(name)
+			if closer, ok := rt.(io.Closer); ok {
+				closer.Close()
+			}
+			logger.Info("Main: Runtime disabled", "runtime", name)
+		case !section.Enabled:
+			logger.Debug("Main: Runtime is disabled in config", "runtime", name)
+		}
+	}
+}
+
+// applyLogLevel sets the logger level from the server.log_level config value.
+// If it is unset, debug mode logs at DEBUG and release mode at INFO.
+func applyLogLevel(server config.ServerConfig) {
+	name := server.LogLevel
+	if name == "" && server.Mode == "debug" {
+		name = "debug"
+	}
+	level, err := logger.ParseLevel(name)
+	if err != nil {
+		logger.Warn("Invalid log level, using INFO", "log_level", name, "error", err)
+	}
+	logger.SetLevel(level)
+	logger.Info("Logger level set", "level", strings.ToUpper(level.String()))
+}
+
+// applyLogFile starts, switches or stops writing logs to the configured server.log_file
+func applyLogFile(server config.ServerConfig) {
+	err := logger.SetLogFile(logger.FileOptions{
+		Path:       server.LogFile,
+		MaxSizeMB:  server.LogMaxSizeMB,
+		MaxBackups: server.LogMaxBackups,
+		MaxAgeDays: server.LogMaxAgeDays,
+	})
+	if err != nil {
+		logger.Warn("Failed to set up log file", "log_file", server.LogFile, "error", err)
+	}
+}
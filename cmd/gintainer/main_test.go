@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyLogLevel(t *testing.T) {
+	defer logger.SetLevel(logger.InfoLevel)
+
+	// Without a log level the mode decides
+	applyLogLevel(config.ServerConfig{Mode: "debug"})
+	assert.Equal(t, logger.DebugLevel, logger.GetLogger().GetLevel())
+
+	applyLogLevel(config.ServerConfig{Mode: "release"})
+	assert.Equal(t, logger.InfoLevel, logger.GetLogger().GetLevel())
+
+	// An explicit log level wins over the mode
+	applyLogLevel(config.ServerConfig{Mode: "debug", LogLevel: "warn"})
+	assert.Equal(t, logger.WarnLevel, logger.GetLogger().GetLevel())
+}
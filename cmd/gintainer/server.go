@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+)
+
+// shutdownTimeout is how long in-flight requests get to finish after a shutdown signal
+const shutdownTimeout = 10 * time.Second
+
+// serve runs listen (e.g. srv.ListenAndServe) until ctx is done, then shuts the server down
+// gracefully within timeout. The cleanup functions are called in order once the server
+// stopped, whether it was shut down or failed to listen.
+func serve(ctx context.Context, srv *http.Server, listen func() error, timeout time.Duration, cleanup ...func()) error {
+	defer func() {
+		for _, fn := range cleanup {
+			fn()
+		}
+	}()
+
+	listenErr := make(chan error, 1)
+	go func() { listenErr <- listen() }()
+
+	select {
+	case err := <-listenErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("Main: Shutting down server", "timeout", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+	logger.Info("Main: Server stopped")
+	return nil
+}
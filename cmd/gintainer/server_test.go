@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeShutsDownGracefully(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx, signal := context.WithCancel(context.Background())
+	defer signal()
+
+	streaming := make(chan struct{})
+	router := gin.New()
+	router.GET("/stream", middleware.CancelOnShutdown(ctx), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+		close(streaming)
+		<-c.Request.Context().Done()
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &http.Server{Handler: router.Handler()}
+
+	var cleanedUp []string
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(ctx, srv, func() error { return srv.Serve(listener) }, 5*time.Second,
+			func() { cleanedUp = append(cleanedUp, "scheduler") },
+			func() { cleanedUp = append(cleanedUp, "config") },
+		)
+	}()
+
+	// An open stream must not hold up the shutdown
+	resp, err := http.Get(fmt.Sprintf("http://%s/stream", listener.Addr()))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	<-streaming
+
+	start := time.Now()
+	signal()
+	select {
+	case err := <-served:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, []string{"scheduler", "config"}, cleanedUp)
+
+	// No new connections are accepted
+	_, err = http.Get(fmt.Sprintf("http://%s/stream", listener.Addr()))
+	assert.Error(t, err)
+}
+
+func TestServeListenError(t *testing.T) {
+	cleanedUp := false
+	err := serve(context.Background(), &http.Server{}, func() error { return errors.New("address already in use") }, time.Second,
+		func() { cleanedUp = true },
+	)
+	assert.EqualError(t, err, "address already in use")
+	assert.True(t, cleanedUp)
+}
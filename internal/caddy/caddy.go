@@ -1,28 +1,62 @@
 package caddy
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/ThraaxSession/gintainer/internal/config"
 	"github.com/ThraaxSession/gintainer/internal/models"
 )
 
+// ErrInvalidCaddyfile is returned when Caddy rejects a managed Caddyfile
+var ErrInvalidCaddyfile = errors.New("invalid Caddyfile")
+
 // Service manages Caddy integration for container reverse proxying
 type Service struct {
-	config *config.CaddyConfig
-	mu     sync.RWMutex
+	config     *config.CaddyConfig
+	mu         sync.RWMutex
+	runCommand commandRunner // Runs caddy/systemctl; replaced in tests
+	httpClient *http.Client  // Talks to the Caddy admin API
+}
+
+// Combined mode file layout, relative to the configured Caddyfile path
+const (
+	combinedFileName = "gintainer.caddy" // All managed sites in one file
+	fragmentDirName  = ".gintainer"      // Per-container Caddyfiles the combined file is built from
+)
+
+// defaultAdminAddress is the address of Caddy's admin API when none is configured
+const defaultAdminAddress = "http://localhost:2019"
+
+// commandRunner runs a command and returns its combined output
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// execCommand runs a command on the host
+func execCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
 }
 
 // NewService creates a new Caddy service
 func NewService(cfg *config.CaddyConfig) *Service {
 	return &Service{
-		config: cfg,
+		config:     cfg,
+		runCommand: execCommand,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
 	}
 }
 
@@ -47,52 +81,54 @@ func (s *Service) GenerateCaddyfile(ctx context.Context, container models.Contai
 	}
 
 	// Check if container has Caddy labels
-	domain := container.Labels["caddy.domain"]
-	if domain == "" {
+	site, ok, err := siteFromLabels(container)
+	if err != nil {
+		return err
+	}
+	if !ok {
 		// No Caddy configuration for this container
 		return nil
 	}
 
-	// Get port from label or use first exposed port
-	portStr := container.Labels["caddy.port"]
-	if portStr == "" && len(container.Ports) > 0 {
-		portStr = fmt.Sprintf("%d", container.Ports[0].HostPort)
-	}
-	if portStr == "" {
-		return fmt.Errorf("no port configured for Caddy reverse proxy")
-	}
-
-	// Get optional path prefix
-	pathPrefix := container.Labels["caddy.path"]
-	if pathPrefix == "" {
-		pathPrefix = "/"
-	}
-
-	// Get optional TLS configuration
-	tls := container.Labels["caddy.tls"]
-	if tls == "" {
-		tls = "auto" // Default to automatic HTTPS
-	}
-
 	// Generate Caddyfile content
-	caddyfileContent := s.buildCaddyfileContent(domain, portStr, pathPrefix, tls)
+	caddyfileContent := s.buildCaddyfileContent(site)
 
-	// Write Caddyfile
-	filename := s.getCaddyfilePath(container.ID)
+	return s.writeCaddyfile(ctx, s.getCaddyfilePath(container.ID), caddyfileContent)
+}
+
+// writeCaddyfile writes a managed Caddyfile and reloads Caddy if auto-reload is enabled.
+// If the new configuration fails validation, the previous file is restored and Caddy is not reloaded.
+func (s *Service) writeCaddyfile(ctx context.Context, filename, content string) error {
 	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
 		return fmt.Errorf("failed to create Caddyfile directory: %w", err)
 	}
 
-	if err := os.WriteFile(filename, []byte(caddyfileContent), 0644); err != nil {
+	previous, readErr := os.ReadFile(filename)
+
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write Caddyfile: %w", err)
 	}
 
+	if err := s.syncCombined(); err != nil {
+		return err
+	}
+
 	// Reload Caddy if auto-reload is enabled
-	if s.config.AutoReload {
-		return s.Reload(ctx)
+	if !s.config.AutoReload {
+		return nil
 	}
 
-	return nil
+	if err := s.Validate(ctx); err != nil {
+		if readErr == nil {
+			_ = os.WriteFile(filename, previous, 0644)
+		} else {
+			_ = os.Remove(filename)
+		}
+		_ = s.syncCombined()
+		return err
+	}
+
+	return s.Reload(ctx)
 }
 
 // UpdateCaddyfile updates an existing Caddyfile for a container
@@ -117,8 +153,15 @@ func (s *Service) DeleteCaddyfile(ctx context.Context, containerID string) error
 		return fmt.Errorf("failed to delete Caddyfile: %w", err)
 	}
 
+	if err := s.syncCombined(); err != nil {
+		return err
+	}
+
 	// Reload Caddy if auto-reload is enabled
 	if s.config.AutoReload {
+		if err := s.Validate(ctx); err != nil {
+			return err
+		}
 		return s.Reload(ctx)
 	}
 
@@ -131,9 +174,7 @@ func (s *Service) ListCaddyfiles() ([]string, error) {
 		return nil, nil
 	}
 
-	s.mu.RLock()
-	caddyfilePath := s.config.CaddyfilePath
-	s.mu.RUnlock()
+	caddyfilePath := s.managedDir()
 
 	if _, err := os.Stat(caddyfilePath); os.IsNotExist(err) {
 		return []string{}, nil
@@ -175,18 +216,67 @@ func (s *Service) SetCaddyfileContent(ctx context.Context, containerID, content
 		return fmt.Errorf("Caddy integration is not enabled")
 	}
 
-	filename := s.getCaddyfilePath(containerID)
-	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
-		return fmt.Errorf("failed to create Caddyfile directory: %w", err)
+	return s.writeCaddyfile(ctx, s.getCaddyfilePath(containerID), content)
+}
+
+// Validate checks every managed Caddyfile with "caddy validate".
+// Validation failures wrap ErrInvalidCaddyfile and include Caddy's output.
+func (s *Service) Validate(ctx context.Context) error {
+	if !s.IsEnabled() {
+		return nil
 	}
 
-	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write Caddyfile: %w", err)
+	files, err := s.ListCaddyfiles()
+	if err != nil {
+		return err
 	}
 
-	// Reload Caddy if auto-reload is enabled
-	if s.config.AutoReload {
-		return s.Reload(ctx)
+	caddyfilePath := s.managedDir()
+
+	s.mu.RLock()
+	useSudo := s.config.UseSudo
+	caddyBinary := binaryOrDefault(s.config.CaddyBinaryPath)
+	reloadMethod := s.config.ReloadMethod
+	combined := s.config.Combined
+	s.mu.RUnlock()
+
+	// Without access to the binary, let the admin API adapt each file instead
+	if reloadMethod == "admin" {
+		for _, file := range files {
+			content, err := os.ReadFile(filepath.Join(caddyfilePath, file))
+			if err != nil {
+				return fmt.Errorf("failed to read Caddyfile: %w", err)
+			}
+			if _, err := s.adapt(ctx, content); err != nil {
+				if errors.Is(err, ErrInvalidCaddyfile) {
+					return fmt.Errorf("%w (%s)", err, file)
+				}
+				return fmt.Errorf("failed to validate Caddyfile %s: %w", file, err)
+			}
+		}
+		return nil
+	}
+
+	// In combined mode a single validation of the combined file covers all sites
+	if combined && len(files) > 0 {
+		caddyfilePath = filepath.Dir(caddyfilePath)
+		files = []string{combinedFileName}
+	}
+
+	for _, file := range files {
+		args := []string{caddyBinary, "validate", "--config", filepath.Join(caddyfilePath, file), "--adapter", "caddyfile"}
+		if useSudo {
+			args = append([]string{"sudo"}, args...)
+		}
+
+		output, err := s.runCommand(ctx, args[0], args[1:]...)
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return fmt.Errorf("%w: %s: %s", ErrInvalidCaddyfile, file, strings.TrimSpace(string(output)))
+			}
+			return fmt.Errorf("failed to validate Caddyfile %s: %w", file, err)
+		}
 	}
 
 	return nil
@@ -199,75 +289,552 @@ func (s *Service) Reload(ctx context.Context) error {
 	}
 
 	s.mu.RLock()
-	useSudo := s.config.UseSudo
-	caddyBinary := s.config.CaddyBinaryPath
 	reloadMethod := s.config.ReloadMethod
 	s.mu.RUnlock()
 
-	// Default to binary if not specified
-	if reloadMethod == "" {
-		reloadMethod = "binary"
+	if reloadMethod == "admin" {
+		return s.reloadViaAdmin(ctx)
 	}
 
-	var cmd *exec.Cmd
-	if reloadMethod == "systemctl" {
-		// Use systemctl to reload Caddy
-		if useSudo {
-			cmd = exec.CommandContext(ctx, "sudo", "systemctl", "reload", "caddy")
-		} else {
-			cmd = exec.CommandContext(ctx, "systemctl", "reload", "caddy")
+	args := s.reloadCommand()
+	output, err := s.runCommand(ctx, args[0], args[1:]...)
+	if err != nil {
+		return fmt.Errorf("failed to reload Caddy: %w (output: %s)", err, string(output))
+	}
+
+	return nil
+}
+
+// reloadViaAdmin adapts the main Caddyfile, with its imports of the managed Caddyfiles
+// inlined, to JSON through the admin API's /adapt endpoint and loads the result with /load.
+// The loaded config replaces Caddy's whole running config, so the main Caddyfile is required.
+func (s *Service) reloadViaAdmin(ctx context.Context) error {
+	s.mu.RLock()
+	mainCaddyfile := s.config.MainCaddyfile
+	s.mu.RUnlock()
+
+	if mainCaddyfile == "" {
+		return fmt.Errorf("failed to reload Caddy: main_caddyfile must be set for the admin reload method")
+	}
+
+	full, err := inlineImports(mainCaddyfile)
+	if err != nil {
+		return fmt.Errorf("failed to reload Caddy: %w", err)
+	}
+
+	adapted, err := s.adapt(ctx, full)
+	if err != nil {
+		return fmt.Errorf("failed to reload Caddy: %w", err)
+	}
+
+	if _, err := s.adminRequest(ctx, "/load", "application/json", adapted); err != nil {
+		return fmt.Errorf("failed to reload Caddy: %w", err)
+	}
+
+	return nil
+}
+
+// combinedCaddyfile concatenates all managed Caddyfiles
+func (s *Service) combinedCaddyfile() ([]byte, error) {
+	files, err := s.ListCaddyfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	caddyfilePath := s.managedDir()
+
+	var combined bytes.Buffer
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(caddyfilePath, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Caddyfile: %w", err)
 		}
-	} else {
-		// Use binary command (default)
-		if useSudo {
-			cmd = exec.CommandContext(ctx, "sudo", caddyBinary, "reload")
-		} else {
-			cmd = exec.CommandContext(ctx, caddyBinary, "reload")
+		combined.Write(content)
+		combined.WriteString("\n")
+	}
+
+	return combined.Bytes(), nil
+}
+
+// inlineImports reads a Caddyfile and replaces each "import <pattern>" line that matches
+// files with their content, like Caddy does when it loads the file itself.
+// Relative patterns are resolved against the Caddyfile's directory. Imports of snippets,
+// imports with arguments and patterns without matches are kept for Caddy to resolve.
+func inlineImports(filename string) ([]byte, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read main Caddyfile: %w", err)
+	}
+
+	var full bytes.Buffer
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "import" {
+			full.WriteString(line)
+			continue
+		}
+
+		pattern := fields[1]
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(filename), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil || len(matches) == 0 {
+			full.WriteString(line)
+			continue
+		}
+
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || info.IsDir() {
+				continue
+			}
+			imported, err := os.ReadFile(match)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read imported Caddyfile: %w", err)
+			}
+			full.Write(imported)
+			full.WriteString("\n")
 		}
 	}
 
-	output, err := cmd.CombinedOutput()
+	return full.Bytes(), nil
+}
+
+// adapt converts Caddyfile content to Caddy's JSON config using the admin API.
+// An adapter error wraps ErrInvalidCaddyfile.
+func (s *Service) adapt(ctx context.Context, caddyfile []byte) ([]byte, error) {
+	body, err := s.adminRequest(ctx, "/adapt", "text/caddyfile", caddyfile)
 	if err != nil {
-		return fmt.Errorf("failed to reload Caddy: %w (output: %s)", err, string(output))
+		var statusErr *adminStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusBadRequest {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCaddyfile, statusErr.Message)
+		}
+		return nil, err
 	}
 
-	return nil
+	var adaptResponse struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(body, &adaptResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode adapted config: %w", err)
+	}
+	return adaptResponse.Result, nil
+}
+
+// adminStatusError is returned when the admin API answers with a non-2xx status
+type adminStatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *adminStatusError) Error() string {
+	return fmt.Sprintf("Caddy admin API returned %d: %s", e.StatusCode, e.Message)
+}
+
+// adminRequest POSTs a body to the Caddy admin API and returns the response body
+func (s *Service) adminRequest(ctx context.Context, path, contentType string, body []byte) ([]byte, error) {
+	s.mu.RLock()
+	address := s.config.AdminAddress
+	s.mu.RUnlock()
+	if address == "" {
+		address = defaultAdminAddress
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(address, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin API request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Caddy admin API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read admin API response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(respBody))
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+			message = apiErr.Error
+		}
+		return nil, &adminStatusError{StatusCode: resp.StatusCode, Message: message}
+	}
+
+	return respBody, nil
+}
+
+// binaryOrDefault returns the configured Caddy binary, falling back to "caddy" in PATH
+func binaryOrDefault(path string) string {
+	if path == "" {
+		return "caddy"
+	}
+	return path
+}
+
+// reloadCommand returns the command line used to reload Caddy for the configured reload method
+func (s *Service) reloadCommand() []string {
+	s.mu.RLock()
+	useSudo := s.config.UseSudo
+	caddyBinary := binaryOrDefault(s.config.CaddyBinaryPath)
+	reloadMethod := s.config.ReloadMethod
+	s.mu.RUnlock()
+
+	var args []string
+	switch reloadMethod {
+	case "systemctl":
+		// Use systemctl to reload Caddy
+		args = []string{"systemctl", "reload", "caddy"}
+	default:
+		// Use binary command (default)
+		args = []string{caddyBinary, "reload"}
+	}
+
+	if useSudo {
+		args = append([]string{"sudo"}, args...)
+	}
+	return args
 }
 
 // getCaddyfilePath returns the file path for a container's Caddyfile
 func (s *Service) getCaddyfilePath(containerID string) string {
+	return filepath.Join(s.managedDir(), fmt.Sprintf("gintainer-%s.caddy", containerID))
+}
+
+// managedDir returns the directory holding the per-container Caddyfiles.
+// In combined mode they live in a hidden subdirectory, which Caddy's glob imports skip.
+func (s *Service) managedDir() string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return filepath.Join(s.config.CaddyfilePath, fmt.Sprintf("gintainer-%s.caddy", containerID))
+	if s.config.Combined {
+		return filepath.Join(s.config.CaddyfilePath, fragmentDirName)
+	}
+	return s.config.CaddyfilePath
+}
+
+// syncCombined rewrites the combined Caddyfile from the per-container Caddyfiles.
+// It does nothing unless combined mode is enabled.
+func (s *Service) syncCombined() error {
+	s.mu.RLock()
+	combined := s.config.Combined
+	filename := filepath.Join(s.config.CaddyfilePath, combinedFileName)
+	s.mu.RUnlock()
+
+	if !combined {
+		return nil
+	}
+
+	content, err := s.combinedCaddyfile()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filename, content, 0644); err != nil {
+		return fmt.Errorf("failed to write combined Caddyfile: %w", err)
+	}
+	return nil
+}
+
+// siteConfig describes the Caddy site generated for a container
+type siteConfig struct {
+	Domains    []string // Site addresses, from the comma-separated caddy.domain label
+	Upstreams  []string // Proxy targets, from the comma-separated caddy.port label
+	LBPolicy   string   // Load balancing policy for multiple upstreams, from caddy.lb_policy
+	PathPrefix string
+	TLS        string
+	BasicAuth  []basicAuthUser // Users for the basic_auth directive, from caddy.basicauth
+	Headers    []header        // Response headers, from caddy.headers
+	Compress   bool            // Emit an encode directive, from caddy.compression
+}
+
+// header is a response header set by the header directive
+type header struct {
+	Name  string
+	Value string
+}
+
+// parseHeaders parses a caddy.headers label of the form "Name:value;Other:value".
+// Pairs without a separator, names that are no HTTP tokens and values with control
+// characters other than tabs are ignored. Whitespace is trimmed.
+func parseHeaders(value string) []header {
+	var headers []header
+	for _, pair := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(pair, ":")
+		name, val = strings.TrimSpace(name), strings.TrimSpace(val)
+		if !ok || !isHeaderName(name) || strings.IndexFunc(val, isControl) >= 0 {
+			continue
+		}
+		headers = append(headers, header{Name: name, Value: val})
+	}
+	return headers
+}
+
+// isHeaderName reports whether name is an HTTP token (RFC 9110 section 5.6.2)
+func isHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		isAlnum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlnum && !strings.ContainsRune("!#$%&'*+-.^_`|~", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isControl reports control characters that must not end up in a Caddyfile token; tabs are allowed
+func isControl(r rune) bool {
+	return r != '\t' && unicode.IsControl(r)
+}
+
+// formatHeaders encodes headers as a caddy.headers label value, sorted by name
+func formatHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+":"+headers[name])
+	}
+	return strings.Join(pairs, ";")
+}
+
+// caddyValueEscaper escapes backslashes and quotes within a quoted Caddyfile token
+var caddyValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quoteCaddyValue quotes a value for use as a single Caddyfile token.
+// Values must not contain newlines or other control characters (see parseHeaders).
+func quoteCaddyValue(value string) string {
+	return `"` + caddyValueEscaper.Replace(value) + `"`
+}
+
+// basicAuthUser is a username with its bcrypt password hash
+type basicAuthUser struct {
+	Username string
+	Hash     string
+}
+
+// bcryptHashPattern matches bcrypt hashes as printed by caddy hash-password
+var bcryptHashPattern = regexp.MustCompile(`^\$2[aby]\$\d{2}\$[./A-Za-z0-9]{53}$`)
+
+// parseBasicAuth parses a caddy.basicauth label of the form "user:hash[,user:hash...]".
+// Entries without a valid username or bcrypt hash are ignored.
+func parseBasicAuth(value string) []basicAuthUser {
+	var users []basicAuthUser
+	for _, entry := range splitList(value) {
+		username, hash, ok := strings.Cut(entry, ":")
+		username, hash = strings.TrimSpace(username), strings.TrimSpace(hash)
+		if !ok || !isBasicAuthUsername(username) || !bcryptHashPattern.MatchString(hash) {
+			continue
+		}
+		users = append(users, basicAuthUser{Username: username, Hash: hash})
+	}
+	return users
+}
+
+// isBasicAuthUsername reports whether a username can be written as a single Caddyfile token
+func isBasicAuthUsername(username string) bool {
+	return username != "" && !strings.ContainsFunc(username, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || strings.ContainsRune(`{}"`, r)
+	})
+}
+
+// LabelsFromRequest converts a Caddy labels request into container labels
+func LabelsFromRequest(req models.CaddyLabelsRequest) map[string]string {
+	labels := map[string]string{"caddy.domain": req.Domain}
+	if req.Port != "" {
+		labels["caddy.port"] = req.Port
+	}
+	if req.Path != "" {
+		labels["caddy.path"] = req.Path
+	}
+	if req.Upstream != "" {
+		labels["caddy.upstream"] = req.Upstream
+	}
+	if req.LBPolicy != "" {
+		labels["caddy.lb_policy"] = req.LBPolicy
+	}
+	if req.TLS != "" {
+		labels["caddy.tls"] = req.TLS
+	}
+	if req.BasicAuthUser != "" && req.BasicAuthHash != "" {
+		labels["caddy.basicauth"] = req.BasicAuthUser + ":" + req.BasicAuthHash
+	}
+	if len(req.Headers) > 0 {
+		labels["caddy.headers"] = formatHeaders(req.Headers)
+	}
+	if req.Compress {
+		labels["caddy.compression"] = "true"
+	}
+	return labels
+}
+
+// splitList splits a comma-separated label value and drops empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// UpstreamContainerIP is the caddy.upstream value that proxies to the container's own IP address
+const UpstreamContainerIP = "container-ip"
+
+// upstreamAddress turns a caddy.port entry into a reverse_proxy upstream.
+// A bare port is combined with host (empty means the local host), a host:port entry is used as is.
+func upstreamAddress(host, entry string) string {
+	if strings.Contains(entry, ":") {
+		return entry
+	}
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		host = "[" + host + "]" // IPv6 address
+	}
+	return host + ":" + entry
+}
+
+// siteFromLabels reads the Caddy labels of a container.
+// It returns false if the container has no caddy.domain label.
+func siteFromLabels(container models.ContainerInfo) (siteConfig, bool, error) {
+	domains := splitList(container.Labels["caddy.domain"])
+	if len(domains) == 0 {
+		return siteConfig{}, false, nil
+	}
+
+	// Get optional upstream host, resolving the container IP if requested
+	upstreamHost := strings.TrimSpace(container.Labels["caddy.upstream"])
+	useContainerIP := upstreamHost == UpstreamContainerIP
+	if useContainerIP {
+		if container.IPAddress == "" {
+			return siteConfig{}, false, fmt.Errorf("caddy.upstream=%s but the IP address of container %s is unknown", UpstreamContainerIP, container.Name)
+		}
+		upstreamHost = container.IPAddress
+	}
+
+	// Get port from label or use first exposed port.
+	// Behind the container IP the container port is reachable directly, not the published one.
+	portStr := container.Labels["caddy.port"]
+	if portStr == "" && len(container.Ports) > 0 {
+		if useContainerIP {
+			portStr = fmt.Sprintf("%d", container.Ports[0].ContainerPort)
+		} else {
+			portStr = fmt.Sprintf("%d", container.Ports[0].HostPort)
+		}
+	}
+	if portStr == "" {
+		return siteConfig{}, false, fmt.Errorf("no port configured for Caddy reverse proxy")
+	}
+
+	// Get optional path prefix
+	pathPrefix := container.Labels["caddy.path"]
+	if pathPrefix == "" {
+		pathPrefix = "/"
+	}
+
+	// Get optional TLS configuration
+	tls := container.Labels["caddy.tls"]
+	if tls == "" {
+		tls = "auto" // Default to automatic HTTPS
+	}
+
+	// Compression is off unless explicitly enabled
+	compress, _ := strconv.ParseBool(container.Labels["caddy.compression"])
+
+	var upstreams []string
+	for _, entry := range splitList(portStr) {
+		upstreams = append(upstreams, upstreamAddress(upstreamHost, entry))
+	}
+	if len(upstreams) == 0 {
+		return siteConfig{}, false, fmt.Errorf("no port configured for Caddy reverse proxy")
+	}
+
+	return siteConfig{
+		Domains:    domains,
+		Upstreams:  upstreams,
+		LBPolicy:   strings.TrimSpace(container.Labels["caddy.lb_policy"]),
+		PathPrefix: pathPrefix,
+		TLS:        tls,
+		BasicAuth:  parseBasicAuth(container.Labels["caddy.basicauth"]),
+		Headers:    parseHeaders(container.Labels["caddy.headers"]),
+		Compress:   compress,
+	}, true, nil
 }
 
 // buildCaddyfileContent builds the Caddyfile content
-func (s *Service) buildCaddyfileContent(domain, port, pathPrefix, tls string) string {
+func (s *Service) buildCaddyfileContent(site siteConfig) string {
 	var sb strings.Builder
 
 	// Domain block
-	sb.WriteString(domain)
+	sb.WriteString(strings.Join(site.Domains, ", "))
 	sb.WriteString(" {\n")
 
 	// TLS configuration
-	if tls != "off" {
-		if tls == "auto" {
+	if site.TLS != "off" {
+		if site.TLS == "auto" {
 			sb.WriteString("\ttls internal\n")
 		} else {
-			sb.WriteString(fmt.Sprintf("\ttls %s\n", tls))
+			sb.WriteString(fmt.Sprintf("\ttls %s\n", site.TLS))
 		}
 	}
 
+	// Basic authentication
+	if len(site.BasicAuth) > 0 {
+		sb.WriteString("\tbasic_auth {\n")
+		for _, user := range site.BasicAuth {
+			sb.WriteString(fmt.Sprintf("\t\t%s %s\n", user.Username, user.Hash))
+		}
+		sb.WriteString("\t}\n")
+	}
+
+	// Response compression
+	if site.Compress {
+		sb.WriteString("\tencode zstd gzip\n")
+	}
+
+	// Response headers
+	if len(site.Headers) > 0 {
+		sb.WriteString("\theader {\n")
+		for _, h := range site.Headers {
+			sb.WriteString(fmt.Sprintf("\t\t%s %s\n", h.Name, quoteCaddyValue(h.Value)))
+		}
+		sb.WriteString("\t}\n")
+	}
+
 	// Reverse proxy configuration
-	if pathPrefix != "/" {
-		sb.WriteString(fmt.Sprintf("\thandle_path %s* {\n", pathPrefix))
-		sb.WriteString(fmt.Sprintf("\t\treverse_proxy :%s\n", port))
+	if site.PathPrefix != "/" {
+		sb.WriteString(fmt.Sprintf("\thandle_path %s* {\n", site.PathPrefix))
+		writeReverseProxy(&sb, "\t\t", site)
 		sb.WriteString("\t}\n")
 	} else {
-		sb.WriteString(fmt.Sprintf("\treverse_proxy :%s\n", port))
+		writeReverseProxy(&sb, "\t", site)
 	}
 
 	sb.WriteString("}\n")
 
 	return sb.String()
 }
+
+// writeReverseProxy writes the reverse_proxy directive for the site's upstreams.
+// The lb_policy is only emitted when there is more than one upstream.
+func writeReverseProxy(sb *strings.Builder, indent string, site siteConfig) {
+	sb.WriteString(fmt.Sprintf("%sreverse_proxy %s", indent, strings.Join(site.Upstreams, " ")))
+	if len(site.Upstreams) > 1 && site.LBPolicy != "" {
+		sb.WriteString(" {\n")
+		sb.WriteString(fmt.Sprintf("%s\tlb_policy %s\n", indent, site.LBPolicy))
+		sb.WriteString(indent + "}")
+	}
+	sb.WriteString("\n")
+}
@@ -2,8 +2,12 @@ package caddy
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ThraaxSession/gintainer/internal/config"
@@ -216,23 +220,275 @@ func TestBuildCaddyfileContent(t *testing.T) {
 	service := NewService(&config.CaddyConfig{})
 
 	// Test basic configuration
-	content := service.buildCaddyfileContent("example.com", "8080", "/", "auto")
+	content := service.buildCaddyfileContent(siteConfig{Domains: []string{"example.com"}, Upstreams: []string{":8080"}, PathPrefix: "/", TLS: "auto"})
 	assert.Contains(t, content, "example.com")
 	assert.Contains(t, content, "reverse_proxy :8080")
 	assert.Contains(t, content, "tls internal")
 
 	// Test with path prefix
-	content = service.buildCaddyfileContent("api.example.com", "9000", "/api", "auto")
+	content = service.buildCaddyfileContent(siteConfig{Domains: []string{"api.example.com"}, Upstreams: []string{":9000"}, PathPrefix: "/api", TLS: "auto"})
 	assert.Contains(t, content, "api.example.com")
 	assert.Contains(t, content, "handle_path /api*")
 	assert.Contains(t, content, "reverse_proxy :9000")
 
 	// Test with TLS off
-	content = service.buildCaddyfileContent("local.test", "3000", "/", "off")
+	content = service.buildCaddyfileContent(siteConfig{Domains: []string{"local.test"}, Upstreams: []string{":3000"}, PathPrefix: "/", TLS: "off"})
 	assert.Contains(t, content, "local.test")
 	assert.NotContains(t, content, "tls")
 }
 
+func TestGenerateCaddyfileMultipleDomains(t *testing.T) {
+	tmpDir := t.TempDir()
+	service := NewService(&config.CaddyConfig{Enabled: true, CaddyfilePath: tmpDir})
+
+	container := models.ContainerInfo{
+		ID: "multi",
+		Labels: map[string]string{
+			"caddy.domain": "app.example.com, www.example.com,",
+			"caddy.port":   "8080",
+		},
+	}
+
+	err := service.GenerateCaddyfile(context.Background(), container)
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "gintainer-multi.caddy"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "app.example.com, www.example.com {\n"))
+}
+
+func TestBuildCaddyfileContentSingleDomainUnchanged(t *testing.T) {
+	service := NewService(&config.CaddyConfig{})
+
+	content := service.buildCaddyfileContent(siteConfig{Domains: []string{"example.com"}, Upstreams: []string{":8080"}, PathPrefix: "/", TLS: "auto"})
+	assert.Equal(t, "example.com {\n\ttls internal\n\treverse_proxy :8080\n}\n", content)
+}
+
+func TestBuildCaddyfileContentBasicAuth(t *testing.T) {
+	service := NewService(&config.CaddyConfig{})
+	hash := "$2a$14$Zkx19XLiW6VYouLHR5NmfOFU0z2GTNmpkT/5qqR7hx4IjWJPDhjvG"
+
+	container := models.ContainerInfo{
+		Labels: map[string]string{
+			"caddy.domain":    "admin.example.com",
+			"caddy.port":      "8080",
+			"caddy.basicauth": "alice:" + hash + ", broken, bob:" + hash,
+		},
+	}
+	site, ok, err := siteFromLabels(container)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	content := service.buildCaddyfileContent(site)
+	assert.Contains(t, content, "\tbasic_auth {\n\t\talice "+hash+"\n\t\tbob "+hash+"\n\t}\n")
+	assert.NotContains(t, content, "broken")
+
+	// Without the label the output has no basic_auth block
+	delete(container.Labels, "caddy.basicauth")
+	site, _, _ = siteFromLabels(container)
+	assert.NotContains(t, service.buildCaddyfileContent(site), "basic_auth")
+}
+
+func TestParseBasicAuthRejectsUnsafeEntries(t *testing.T) {
+	hash := "$2a$14$Zkx19XLiW6VYouLHR5NmfOFU0z2GTNmpkT/5qqR7hx4IjWJPDhjvG"
+
+	for _, entry := range []string{
+		"alice",
+		":" + hash,
+		"alice:",
+		"alice:plaintext",
+		"alice:$2a$14$short",
+		"alice:$1$" + hash[3:],
+		"alice:" + hash + "\n}\nreverse_proxy evil:80",
+		"alice bob:" + hash,
+		"al\nice:" + hash,
+		"al\x00ice:" + hash,
+		"{alice}:" + hash,
+		`"alice:` + hash,
+		"alice:" + hash + " extra",
+	} {
+		assert.Empty(t, parseBasicAuth(entry), entry)
+	}
+
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		valid := prefix + hash[4:]
+		assert.Equal(t, []basicAuthUser{{Username: "alice", Hash: valid}}, parseBasicAuth("alice:"+valid), prefix)
+	}
+}
+
+func TestLabelsFromRequest(t *testing.T) {
+	labels := LabelsFromRequest(models.CaddyLabelsRequest{
+		Domain:        "example.com",
+		Port:          "8080",
+		BasicAuthUser: "alice",
+		BasicAuthHash: "$2a$14$hash",
+	})
+	assert.Equal(t, map[string]string{
+		"caddy.domain":    "example.com",
+		"caddy.port":      "8080",
+		"caddy.basicauth": "alice:$2a$14$hash",
+	}, labels)
+
+	// Basic auth needs both the user and the hash
+	labels = LabelsFromRequest(models.CaddyLabelsRequest{Domain: "example.com", BasicAuthUser: "alice"})
+	assert.NotContains(t, labels, "caddy.basicauth")
+}
+
+func TestBuildCaddyfileContentHeaders(t *testing.T) {
+	service := NewService(&config.CaddyConfig{})
+
+	container := models.ContainerInfo{
+		Labels: map[string]string{
+			"caddy.domain":  "example.com",
+			"caddy.port":    "8080",
+			"caddy.headers": " Strict-Transport-Security : max-age=31536000 ;Access-Control-Allow-Origin:*;malformed;:novalue",
+		},
+	}
+	site, _, err := siteFromLabels(container)
+	assert.NoError(t, err)
+
+	content := service.buildCaddyfileContent(site)
+	assert.Contains(t, content, "\theader {\n")
+	assert.Contains(t, content, "\t\tStrict-Transport-Security \"max-age=31536000\"\n")
+	assert.Contains(t, content, "\t\tAccess-Control-Allow-Origin \"*\"\n")
+	assert.NotContains(t, content, "malformed")
+	assert.NotContains(t, content, "novalue")
+}
+
+func TestBuildCaddyfileContentCompression(t *testing.T) {
+	service := NewService(&config.CaddyConfig{})
+
+	labels := map[string]string{"caddy.domain": "example.com", "caddy.port": "8080"}
+	site, _, err := siteFromLabels(models.ContainerInfo{Labels: labels})
+	assert.NoError(t, err)
+	assert.NotContains(t, service.buildCaddyfileContent(site), "encode")
+
+	labels["caddy.compression"] = "true"
+	site, _, err = siteFromLabels(models.ContainerInfo{Labels: labels})
+	assert.NoError(t, err)
+	assert.Contains(t, service.buildCaddyfileContent(site), "\tencode zstd gzip\n")
+
+	assert.Equal(t, "true", LabelsFromRequest(models.CaddyLabelsRequest{Domain: "example.com", Compress: true})["caddy.compression"])
+}
+
+func TestBuildCaddyfileContentLoadBalancing(t *testing.T) {
+	service := NewService(&config.CaddyConfig{})
+
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected string
+	}{
+		{
+			name:     "single upstream keeps plain output",
+			labels:   map[string]string{"caddy.port": "8080", "caddy.lb_policy": "round_robin"},
+			expected: "\treverse_proxy :8080\n}\n",
+		},
+		{
+			name:     "round robin",
+			labels:   map[string]string{"caddy.port": "app1:8080, app2:8080,app3:8080", "caddy.lb_policy": "round_robin"},
+			expected: "\treverse_proxy app1:8080 app2:8080 app3:8080 {\n\t\tlb_policy round_robin\n\t}\n}\n",
+		},
+		{
+			name:     "least conn",
+			labels:   map[string]string{"caddy.port": "10.0.0.2:80,10.0.0.3:80", "caddy.lb_policy": "least_conn"},
+			expected: "\treverse_proxy 10.0.0.2:80 10.0.0.3:80 {\n\t\tlb_policy least_conn\n\t}\n}\n",
+		},
+		{
+			name:     "multiple upstreams without policy",
+			labels:   map[string]string{"caddy.port": "8080,8081"},
+			expected: "\treverse_proxy :8080 :8081\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.labels["caddy.domain"] = "example.com"
+			tt.labels["caddy.tls"] = "off"
+			site, ok, err := siteFromLabels(models.ContainerInfo{Labels: tt.labels})
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, "example.com {\n"+tt.expected, service.buildCaddyfileContent(site))
+		})
+	}
+}
+
+func TestSiteFromLabelsUpstream(t *testing.T) {
+	tests := []struct {
+		name      string
+		container models.ContainerInfo
+		expected  []string
+		wantErr   bool
+	}{
+		{
+			name:      "default keeps local upstream",
+			container: models.ContainerInfo{Labels: map[string]string{"caddy.port": "8080"}},
+			expected:  []string{":8080"},
+		},
+		{
+			name:      "custom host",
+			container: models.ContainerInfo{Labels: map[string]string{"caddy.port": "8080,9090", "caddy.upstream": "192.168.1.20"}},
+			expected:  []string{"192.168.1.20:8080", "192.168.1.20:9090"},
+		},
+		{
+			name:      "explicit host:port wins",
+			container: models.ContainerInfo{Labels: map[string]string{"caddy.port": "other:80", "caddy.upstream": "host.docker.internal"}},
+			expected:  []string{"other:80"},
+		},
+		{
+			name: "container ip uses container port",
+			container: models.ContainerInfo{
+				IPAddress: "172.17.0.3",
+				Labels:    map[string]string{"caddy.upstream": "container-ip"},
+				Ports:     []models.PortMapping{{HostPort: 8080, ContainerPort: 80}},
+			},
+			expected: []string{"172.17.0.3:80"},
+		},
+		{
+			name:      "ipv6 host",
+			container: models.ContainerInfo{Labels: map[string]string{"caddy.port": "80", "caddy.upstream": "fd00::2"}},
+			expected:  []string{"[fd00::2]:80"},
+		},
+		{
+			name:      "container ip unknown",
+			container: models.ContainerInfo{Labels: map[string]string{"caddy.port": "80", "caddy.upstream": "container-ip"}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.container.Labels["caddy.domain"] = "example.com"
+			site, _, err := siteFromLabels(tt.container)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, site.Upstreams)
+		})
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	assert.Equal(t, []header{
+		{Name: "X-Frame-Options", Value: "DENY"},
+		{Name: "Content-Security-Policy", Value: "default-src 'self'"},
+	}, parseHeaders("X-Frame-Options:DENY;Content-Security-Policy:default-src 'self';Bad Name:x"))
+	assert.Empty(t, parseHeaders(""))
+
+	// Names must be HTTP tokens and values must not break out of the Caddyfile token
+	assert.Equal(t, []header{{Name: "X-Ok", Value: "a\tb"}}, parseHeaders("X-Ok:a\tb;X-Bad{:x;X(Bad):x;X-Evil:x\n}\nevil.example.com {;X-Null:a\x00b"))
+	assert.Equal(t, `"C:\\path\\"`, quoteCaddyValue(`C:\path\`))
+	assert.Equal(t, `"say \"hi\""`, quoteCaddyValue(`say "hi"`))
+
+	labels := LabelsFromRequest(models.CaddyLabelsRequest{
+		Domain:  "example.com",
+		Headers: map[string]string{"X-Frame-Options": "DENY", "Referrer-Policy": "no-referrer"},
+	})
+	assert.Equal(t, "Referrer-Policy:no-referrer;X-Frame-Options:DENY", labels["caddy.headers"])
+}
+
 func TestServiceWithDisabledConfig(t *testing.T) {
 	cfg := &config.CaddyConfig{Enabled: false}
 	service := NewService(cfg)
@@ -256,3 +512,182 @@ func TestServiceWithDisabledConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, files)
 }
+
+// fakeCaddyBinary writes a script that fails "caddy validate" with the given output
+func fakeCaddyBinary(t *testing.T, validateOutput string) string {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "caddy")
+	content := "#!/bin/sh\nif [ \"$1\" = validate ]; then echo '" + validateOutput + "'; exit 1; fi\nexit 0\n"
+	assert.NoError(t, os.WriteFile(script, []byte(content), 0755))
+	return script
+}
+
+func TestSetCaddyfileContentValidationFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	service := NewService(&config.CaddyConfig{
+		Enabled:         true,
+		CaddyfilePath:   tempDir,
+		AutoReload:      true,
+		CaddyBinaryPath: fakeCaddyBinary(t, "Error: adapting config: unrecognized directive: reverse_prox"),
+	})
+
+	filename := filepath.Join(tempDir, "gintainer-abc.caddy")
+	assert.NoError(t, os.WriteFile(filename, []byte("old content\n"), 0644))
+
+	err := service.SetCaddyfileContent(context.Background(), "abc", "example.com {\n\treverse_prox :8080\n}\n")
+	assert.ErrorIs(t, err, ErrInvalidCaddyfile)
+	assert.Contains(t, err.Error(), "unrecognized directive")
+
+	// The previous content is restored
+	content, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "old content\n", string(content))
+
+	// A new file is removed again
+	err = service.SetCaddyfileContent(context.Background(), "new", "broken")
+	assert.ErrorIs(t, err, ErrInvalidCaddyfile)
+	assert.NoFileExists(t, filepath.Join(tempDir, "gintainer-new.caddy"))
+}
+
+func TestReloadCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.CaddyConfig
+		expected []string
+	}{
+		{"default binary", config.CaddyConfig{}, []string{"caddy", "reload"}},
+		{"binary with sudo", config.CaddyConfig{CaddyBinaryPath: "/usr/bin/caddy", ReloadMethod: "binary", UseSudo: true}, []string{"sudo", "/usr/bin/caddy", "reload"}},
+		{"systemctl", config.CaddyConfig{ReloadMethod: "systemctl"}, []string{"systemctl", "reload", "caddy"}},
+		{"systemctl with sudo", config.CaddyConfig{ReloadMethod: "systemctl", UseSudo: true}, []string{"sudo", "systemctl", "reload", "caddy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Enabled = true
+			service := NewService(&tt.cfg)
+
+			var ran []string
+			service.runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+				ran = append([]string{name}, args...)
+				return nil, nil
+			}
+
+			assert.NoError(t, service.Reload(context.Background()))
+			assert.Equal(t, tt.expected, ran)
+		})
+	}
+}
+
+func TestReloadViaAdminAPI(t *testing.T) {
+	var adaptedBody, loadedBody, loadedType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/adapt":
+			assert.Equal(t, "text/caddyfile", r.Header.Get("Content-Type"))
+			if strings.Contains(string(body), "reverse_prox ") {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"unrecognized directive: reverse_prox"}`))
+				return
+			}
+			adaptedBody = string(body)
+			_, _ = w.Write([]byte(`{"result":{"apps":{"http":{}}}}`))
+		case "/load":
+			loadedBody = string(body)
+			loadedType = r.Header.Get("Content-Type")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	mainDir := t.TempDir()
+	tempDir := filepath.Join(mainDir, "conf.d")
+	assert.NoError(t, os.MkdirAll(tempDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "gintainer-a.caddy"), []byte("a.example.com {\n\treverse_proxy :8080\n}\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "gintainer-b.caddy"), []byte("b.example.com {\n\treverse_proxy :9090\n}\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "other.txt"), []byte("unmanaged"), 0644))
+	mainCaddyfile := filepath.Join(mainDir, "Caddyfile")
+	assert.NoError(t, os.WriteFile(mainCaddyfile, []byte("(common) {\n\tencode gzip\n}\n\nstatic.example.com {\n\timport common\n\tfile_server\n}\n\nimport conf.d/*.caddy\n"), 0644))
+
+	cfg := &config.CaddyConfig{
+		Enabled:       true,
+		CaddyfilePath: tempDir,
+		ReloadMethod:  "admin",
+		AdminAddress:  server.URL + "/",
+	}
+	service := NewService(cfg)
+	service.runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		t.Fatalf("admin reload must not run commands, ran %s", name)
+		return nil, nil
+	}
+
+	// Loading only the managed sites would drop the rest of Caddy's config
+	err := service.Reload(context.Background())
+	assert.ErrorContains(t, err, "main_caddyfile")
+	assert.Empty(t, loadedBody)
+
+	cfg.MainCaddyfile = mainCaddyfile
+	assert.NoError(t, service.Reload(context.Background()))
+	assert.Contains(t, adaptedBody, "static.example.com {")
+	assert.Contains(t, adaptedBody, "\timport common\n")
+	assert.Contains(t, adaptedBody, "a.example.com {")
+	assert.Contains(t, adaptedBody, "b.example.com {")
+	assert.NotContains(t, adaptedBody, "import conf.d")
+	assert.NotContains(t, adaptedBody, "unmanaged")
+	assert.JSONEq(t, `{"apps":{"http":{}}}`, loadedBody)
+	assert.Equal(t, "application/json", loadedType)
+
+	// Validation goes through /adapt as well
+	assert.NoError(t, service.Validate(context.Background()))
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDir, "gintainer-b.caddy"), []byte("b.example.com {\n\treverse_prox :9090\n}\n"), 0644))
+	err = service.Validate(context.Background())
+	assert.ErrorIs(t, err, ErrInvalidCaddyfile)
+	assert.Contains(t, err.Error(), "unrecognized directive")
+	assert.Contains(t, err.Error(), "gintainer-b.caddy")
+}
+
+func TestCombinedCaddyfile(t *testing.T) {
+	tempDir := t.TempDir()
+	service := NewService(&config.CaddyConfig{
+		Enabled:       true,
+		CaddyfilePath: tempDir,
+		AutoReload:    true,
+		Combined:      true,
+	})
+
+	var commands [][]string
+	service.runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		commands = append(commands, append([]string{name}, args...))
+		return nil, nil
+	}
+
+	for _, c := range []models.ContainerInfo{
+		{ID: "one", Labels: map[string]string{"caddy.domain": "one.example.com", "caddy.port": "8080"}},
+		{ID: "two", Labels: map[string]string{"caddy.domain": "two.example.com", "caddy.port": "9090"}},
+	} {
+		assert.NoError(t, service.GenerateCaddyfile(context.Background(), c))
+	}
+
+	combinedPath := filepath.Join(tempDir, "gintainer.caddy")
+	content, err := os.ReadFile(combinedPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "one.example.com {")
+	assert.Contains(t, string(content), "two.example.com {")
+	assert.FileExists(t, filepath.Join(tempDir, ".gintainer", "gintainer-one.caddy"))
+
+	files, err := service.ListCaddyfiles()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"gintainer-one.caddy", "gintainer-two.caddy"}, files)
+
+	// Each change validates the combined file once and reloads once
+	assert.Equal(t, []string{"caddy", "validate", "--config", combinedPath, "--adapter", "caddyfile"}, commands[len(commands)-2])
+	assert.Equal(t, []string{"caddy", "reload"}, commands[len(commands)-1])
+	assert.Len(t, commands, 4)
+
+	assert.NoError(t, service.DeleteCaddyfile(context.Background(), "one"))
+	content, err = os.ReadFile(combinedPath)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "one.example.com")
+	assert.Contains(t, string(content), "two.example.com {")
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+)
+
+const (
+	// BackupDir is the directory next to the config file that holds the backups
+	BackupDir = "backups"
+
+	// MaxBackups is the number of config backups kept; older ones are deleted
+	MaxBackups = 10
+
+	// backupTimeFormat sorts lexically in chronological order
+	backupTimeFormat = "20060102T150405.000000000Z"
+)
+
+var (
+	// ErrBackupNotFound is returned when a backup ID does not name an existing backup
+	ErrBackupNotFound = errors.New("config backup not found")
+)
+
+// Backup describes a saved copy of the config file
+type Backup struct {
+	ID        string    `json:"id"` // File name within the backup directory
+	CreatedAt time.Time `json:"created_at"`
+	Size      int64     `json:"size"`
+}
+
+// backupDir returns the backup directory of the config file
+func (m *Manager) backupDir() string {
+	return filepath.Join(filepath.Dir(m.filePath), BackupDir)
+}
+
+// backupPrefix returns the file name prefix of the backups, e.g. "gintainer-" for gintainer.yaml
+func (m *Manager) backupPrefix() string {
+	base := filepath.Base(m.filePath)
+	return strings.TrimSuffix(base, filepath.Ext(base)) + "-"
+}
+
+// backupConfig copies the current config file to the backup directory, prunes old backups
+// and returns the ID of the new backup. Nothing is backed up while the config file does not exist yet.
+func (m *Manager) backupConfig() (string, error) {
+	data, err := os.ReadFile(m.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	dir := m.backupDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	id := m.backupPrefix() + time.Now().UTC().Format(backupTimeFormat) + filepath.Ext(m.filePath)
+	if err := os.WriteFile(filepath.Join(dir, id), data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	logger.Info("backupConfig: Backed up config file", "id", id)
+
+	backups, err := m.ListBackups()
+	if err != nil {
+		return id, err
+	}
+	for _, backup := range backups[min(len(backups), MaxBackups):] {
+		if err := os.Remove(filepath.Join(dir, backup.ID)); err != nil {
+			logger.Warn("backupConfig: Failed to delete old backup", "id", backup.ID, "error", err)
+		}
+	}
+	return id, nil
+}
+
+// ListBackups returns the backups of the config file, newest first
+func (m *Manager) ListBackups() ([]Backup, error) {
+	entries, err := os.ReadDir(m.backupDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return []Backup{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	prefix, ext := m.backupPrefix(), filepath.Ext(m.filePath)
+	backups := []Backup{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		createdAt, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{ID: name, CreatedAt: createdAt, Size: info.Size()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].ID > backups[j].ID })
+	return backups, nil
+}
+
+// RestoreBackup replaces the live config with a backup and triggers the onChange callback.
+// The config being replaced is backed up in turn, so a restore can be undone.
+func (m *Manager) RestoreBackup(id string) error {
+	if id == "" || id != filepath.Base(id) || !strings.HasPrefix(id, m.backupPrefix()) {
+		return ErrBackupNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.backupDir(), id))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrBackupNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	format := formatFromPath(m.filePath)
+	if _, err := parseConfig(format, data); err != nil {
+		return fmt.Errorf("backup %s is not a valid config: %w", id, err)
+	}
+
+	logger.Info("RestoreBackup: Restoring config from backup", "id", id)
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+	return m.writeConfig(format, data)
+}
+
+// ResetConfig replaces the config with the defaults. Unlike other updates it fails when the
+// current config file cannot be backed up first, so the reset can be undone with RestoreBackup.
+// It returns the new config and the ID of the backup, empty when there was no config file yet.
+func (m *Manager) ResetConfig() (*Config, string, error) {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	// Failing to prune old backups still leaves the new one
+	id, err := m.backupConfig()
+	if id == "" && err != nil {
+		return nil, "", fmt.Errorf("failed to back up config file: %w", err)
+	}
+
+	format := formatFromPath(m.filePath)
+	data, err := marshalConfig(format, DefaultConfig())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	logger.Info("ResetConfig: Resetting config to defaults", "backup", id)
+	if err := m.saveConfig(format, data); err != nil {
+		return nil, "", err
+	}
+	return m.GetConfig(), id, nil
+}
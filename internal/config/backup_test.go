@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateConfigWritesBackups(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager, err := NewManager(filepath.Join(tmpDir, "gintainer.yaml"))
+	require.NoError(t, err)
+	defer manager.Close()
+
+	// The first save has no previous file to back up
+	require.NoError(t, manager.UpdateConfig(DefaultConfig()))
+	backups, err := manager.ListBackups()
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+
+	for i := 0; i < MaxBackups+3; i++ {
+		cfg := DefaultConfig()
+		cfg.Server.Port = fmt.Sprintf("%d", 9000+i)
+		require.NoError(t, manager.UpdateConfig(cfg))
+	}
+
+	backups, err = manager.ListBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, MaxBackups)
+	for i := 1; i < len(backups); i++ {
+		assert.True(t, backups[i-1].CreatedAt.After(backups[i].CreatedAt), "backups are listed newest first")
+	}
+	assert.Regexp(t, `^gintainer-\d{8}T\d{6}\.\d{9}Z\.yaml$`, backups[0].ID)
+
+	entries, err := os.ReadDir(filepath.Join(tmpDir, BackupDir))
+	require.NoError(t, err)
+	assert.Len(t, entries, MaxBackups)
+}
+
+func TestRestoreBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager, err := NewManager(filepath.Join(tmpDir, "gintainer.yaml"))
+	require.NoError(t, err)
+	defer manager.Close()
+
+	var changed *Config
+	manager.SetOnChange(func(cfg *Config) { changed = cfg })
+
+	original := DefaultConfig()
+	original.Server.Port = "9090"
+	original.UI.Theme = "dark"
+	require.NoError(t, manager.UpdateConfig(original))
+
+	edited := DefaultConfig()
+	edited.Server.Port = "7070"
+	require.NoError(t, manager.UpdateConfig(edited))
+	assert.Equal(t, "7070", manager.GetConfig().Server.Port)
+
+	backups, err := manager.ListBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	require.NoError(t, manager.RestoreBackup(backups[0].ID))
+	assert.Equal(t, "9090", manager.GetConfig().Server.Port)
+	assert.Equal(t, "dark", manager.GetConfig().UI.Theme)
+	require.NotNil(t, changed)
+	assert.Equal(t, "9090", changed.Server.Port)
+
+	// The live file matches the restored config and the replaced config was backed up
+	reloaded, err := NewManager(filepath.Join(tmpDir, "gintainer.yaml"))
+	require.NoError(t, err)
+	defer reloaded.Close()
+	assert.Equal(t, "9090", reloaded.GetConfig().Server.Port)
+
+	backups, err = manager.ListBackups()
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+}
+
+func TestRestoreBackupNotFound(t *testing.T) {
+	manager, err := NewManager(filepath.Join(t.TempDir(), "gintainer.yaml"))
+	require.NoError(t, err)
+	defer manager.Close()
+
+	for _, id := range []string{"", "missing.yaml", "gintainer-20260101T000000.000000000Z.yaml", "../gintainer.yaml"} {
+		assert.ErrorIs(t, manager.RestoreBackup(id), ErrBackupNotFound, id)
+	}
+}
+
+func TestRestoreBackupRejectsInvalidConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager, err := NewManager(filepath.Join(tmpDir, "gintainer.yaml"))
+	require.NoError(t, err)
+	defer manager.Close()
+	require.NoError(t, manager.UpdateConfig(DefaultConfig()))
+
+	id := "gintainer-20260101T000000.000000000Z.yaml"
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, BackupDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, BackupDir, id), []byte("server:\n  port: \"\"\n"), 0600))
+
+	assert.Error(t, manager.RestoreBackup(id))
+	assert.Equal(t, DefaultConfig().Server.Port, manager.GetConfig().Server.Port)
+}
+
+func TestResetConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "gintainer.yaml")
+	manager, err := NewManager(path)
+	require.NoError(t, err)
+	defer manager.Close()
+
+	edited := DefaultConfig()
+	edited.UI.Title = "Edited"
+	edited.Server.Port = "9090"
+	require.NoError(t, manager.UpdateConfig(edited))
+
+	var changed *Config
+	manager.SetOnChange(func(cfg *Config) { changed = cfg })
+
+	cfg, id, err := manager.ResetConfig()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig().UI.Title, cfg.UI.Title)
+	require.NotNil(t, changed, "the onChange callback is called")
+	assert.Equal(t, DefaultConfig().Server.Port, changed.Server.Port)
+
+	// The file holds exactly the defaults
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	defaults, err := marshalConfig(formatFromPath(path), DefaultConfig())
+	require.NoError(t, err)
+	assert.Equal(t, string(defaults), string(content))
+
+	// The edited config can be restored from the backup
+	require.NotEmpty(t, id)
+	require.NoError(t, manager.RestoreBackup(id))
+	assert.Equal(t, "Edited", manager.GetConfig().UI.Title)
+}
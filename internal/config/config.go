@@ -1,66 +1,192 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/ThraaxSession/gintainer/internal/logger"
 	"github.com/fsnotify/fsnotify"
+	"github.com/robfig/cron/v3"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Server     ServerConfig     `yaml:"server"`
-	Scheduler  SchedulerConfig  `yaml:"scheduler"`
-	Docker     RuntimeConfig    `yaml:"docker"`
-	Podman     RuntimeConfig    `yaml:"podman"`
-	Caddy      CaddyConfig      `yaml:"caddy"`
-	UI         UIConfig         `yaml:"ui"`
-	Deployment DeploymentConfig `yaml:"deployment"`
-	mu         sync.RWMutex
+	Server        ServerConfig                   `yaml:"server" json:"server" toml:"server"`
+	Scheduler     SchedulerConfig                `yaml:"scheduler" json:"scheduler" toml:"scheduler"`
+	Docker        RuntimeConfig                  `yaml:"docker" json:"docker" toml:"docker"`
+	Podman        RuntimeConfig                  `yaml:"podman" json:"podman" toml:"podman"`
+	Caddy         CaddyConfig                    `yaml:"caddy" json:"caddy" toml:"caddy"`
+	UI            UIConfig                       `yaml:"ui" json:"ui" toml:"ui"`
+	Deployment    DeploymentConfig               `yaml:"deployment" json:"deployment" toml:"deployment"`
+	Checkpoint    CheckpointConfig               `yaml:"checkpoint" json:"checkpoint" toml:"checkpoint"`
+	Notifications NotificationsConfig            `yaml:"notifications" json:"notifications" toml:"notifications"`
+	AutoRestart   AutoRestartConfig              `yaml:"autorestart" json:"autorestart" toml:"autorestart"`
+	Registries    map[string]RegistryCredentials `yaml:"registries,omitempty" json:"registries,omitempty" toml:"registries,omitempty"` // Registry hostname -> credentials
+	mu            sync.RWMutex
 }
 
 // ServerConfig represents server configuration
 type ServerConfig struct {
-	Port string `yaml:"port"`
-	Mode string `yaml:"mode"` // "debug" or "release"
+	Port      string `yaml:"port" json:"port" toml:"port"`
+	Mode      string `yaml:"mode" json:"mode" toml:"mode" enum:"debug,release"`                        // "debug" or "release"
+	LogLevel  string `yaml:"log_level" json:"log_level" toml:"log_level" enum:"debug,info,warn,error"` // "debug", "info", "warn" or "error"; unset follows Mode
+	LogFormat string `yaml:"log_format" json:"log_format" toml:"log_format" enum:"text,json"`          // "text" or "json"
+	LogFile   string `yaml:"log_file" json:"log_file" toml:"log_file"`                                 // Also write logs to this file (rotated)
+	// Rotation settings for log_file
+	LogMaxSizeMB  int `yaml:"log_max_size_mb" json:"log_max_size_mb" toml:"log_max_size_mb"`    // Rotate after this many megabytes
+	LogMaxBackups int `yaml:"log_max_backups" json:"log_max_backups" toml:"log_max_backups"`    // Rotated files to keep (0 keeps all)
+	LogMaxAgeDays int `yaml:"log_max_age_days" json:"log_max_age_days" toml:"log_max_age_days"` // Delete rotated files older than this (0 disables)
+	LogBufferSize int `yaml:"log_buffer_size" json:"log_buffer_size" toml:"log_buffer_size"`    // Recent log entries kept in memory for the log viewer
+
+	ListCacheTTL   string `yaml:"list_cache_ttl" json:"list_cache_ttl" toml:"list_cache_ttl"`    // Serve repeated container list requests from memory for this long (e.g. "2s"), empty disables
+	RuntimeTimeout string `yaml:"runtime_timeout" json:"runtime_timeout" toml:"runtime_timeout"` // Upper bound for a single runtime call of a request (e.g. "30s")
+
+	CORS      CORSConfig      `yaml:"cors" json:"cors" toml:"cors"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" json:"rate_limit" toml:"rate_limit"`
+	TLS       TLSConfig       `yaml:"tls" json:"tls" toml:"tls"`
+}
+
+// ListCacheDuration returns the parsed list_cache_ttl, 0 if the cache is disabled
+func (s ServerConfig) ListCacheDuration() time.Duration {
+	ttl, err := time.ParseDuration(s.ListCacheTTL)
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// RuntimeTimeoutDuration returns the parsed runtime_timeout, 0 if it is not set
+func (s ServerConfig) RuntimeTimeoutDuration() time.Duration {
+	timeout, err := time.ParseDuration(s.RuntimeTimeout)
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// TLSConfig represents HTTPS settings for the server
+type TLSConfig struct {
+	Enabled      bool   `yaml:"enabled" json:"enabled" toml:"enabled"`
+	CertFile     string `yaml:"cert_file" json:"cert_file" toml:"cert_file"`             // PEM certificate (chain)
+	KeyFile      string `yaml:"key_file" json:"key_file" toml:"key_file"`                // PEM private key
+	RedirectHTTP bool   `yaml:"redirect_http" json:"redirect_http" toml:"redirect_http"` // Also listen on http_port and redirect to HTTPS
+	HTTPPort     string `yaml:"http_port" json:"http_port" toml:"http_port"`             // Port of the redirect listener (default: "80")
+}
+
+// RateLimitConfig represents the per-client limit for state-changing API requests
+type RateLimitConfig struct {
+	Enabled           bool `yaml:"enabled" json:"enabled" toml:"enabled"`
+	RequestsPerMinute int  `yaml:"requests_per_minute" json:"requests_per_minute" toml:"requests_per_minute"` // Sustained requests per client IP
+	Burst             int  `yaml:"burst" json:"burst" toml:"burst"`                                           // Requests allowed at once (default: requests_per_minute)
+}
+
+// CORSConfig represents cross-origin resource sharing settings for the API
+type CORSConfig struct {
+	Enabled          bool     `yaml:"enabled" json:"enabled" toml:"enabled"`
+	AllowedOrigins   []string `yaml:"allowed_origins" json:"allowed_origins" toml:"allowed_origins"`       // Origins allowed to call the API, "*" for any
+	AllowedMethods   []string `yaml:"allowed_methods" json:"allowed_methods" toml:"allowed_methods"`       // Methods allowed in preflight requests (default: GET, POST, PUT, PATCH, DELETE)
+	AllowCredentials bool     `yaml:"allow_credentials" json:"allow_credentials" toml:"allow_credentials"` // Allow cookies and authorization headers
 }
 
 // SchedulerConfig represents scheduler configuration
 type SchedulerConfig struct {
-	Enabled  bool     `yaml:"enabled"`
-	Schedule string   `yaml:"schedule"`
-	Filters  []string `yaml:"filters"`
+	Enabled           bool           `yaml:"enabled" json:"enabled" toml:"enabled"`
+	Schedule          string         `yaml:"schedule" json:"schedule" toml:"schedule"`
+	Filters           []string       `yaml:"filters" json:"filters" toml:"filters"`
+	Exclude           []string       `yaml:"exclude,omitempty" json:"exclude,omitempty" toml:"exclude,omitempty"`
+	DryRun            bool           `yaml:"dry_run" json:"dry_run" toml:"dry_run"`                                                                // Only report available updates
+	Jobs              []SchedulerJob `yaml:"jobs,omitempty" json:"jobs,omitempty" toml:"jobs,omitempty"`                                           // Additional named jobs
+	Timezone          string         `yaml:"timezone,omitempty" json:"timezone,omitempty" toml:"timezone,omitempty"`                               // IANA time zone of all schedules, e.g. "Europe/Berlin" (default: server local time)
+	UpdateConcurrency int            `yaml:"update_concurrency,omitempty" json:"update_concurrency,omitempty" toml:"update_concurrency,omitempty"` // Containers updated at once during a run (default: 3)
+}
+
+// SchedulerJob represents a named auto-update job with its own schedule and filters
+type SchedulerJob struct {
+	Name     string   `yaml:"name" json:"name" toml:"name"`
+	Enabled  bool     `yaml:"enabled" json:"enabled" toml:"enabled"`
+	Schedule string   `yaml:"schedule" json:"schedule" toml:"schedule"`
+	Filters  []string `yaml:"filters" json:"filters" toml:"filters"`
+	Exclude  []string `yaml:"exclude,omitempty" json:"exclude,omitempty" toml:"exclude,omitempty"`
+	DryRun   bool     `yaml:"dry_run" json:"dry_run" toml:"dry_run"` // Only report available updates
 }
 
 // RuntimeConfig represents runtime-specific configuration
 type RuntimeConfig struct {
-	Enabled bool   `yaml:"enabled"`
-	Socket  string `yaml:"socket,omitempty"`
+	Enabled     bool   `yaml:"enabled" json:"enabled" toml:"enabled"`
+	Socket      string `yaml:"socket,omitempty" json:"socket,omitempty" toml:"socket,omitempty"`                           // Socket path or connection URI, e.g. ssh://user@host/run/user/1000/podman/podman.sock
+	Context     string `yaml:"context,omitempty" json:"context,omitempty" toml:"context,omitempty"`                        // docker CLI context name, used when socket is empty (Docker only)
+	Identity    string `yaml:"identity,omitempty" json:"identity,omitempty" toml:"identity,omitempty"`                     // SSH private key for ssh:// sockets (Podman only)
+	Passphrase  string `yaml:"passphrase,omitempty" json:"passphrase,omitempty" toml:"passphrase,omitempty" secret:"true"` // Passphrase of the SSH private key (Podman only)
+	ListWorkers int    `yaml:"list_workers,omitempty" json:"list_workers,omitempty" toml:"list_workers,omitempty"`         // Parallel stats/inspect calls while listing containers (default: 8)
 }
 
 // CaddyConfig represents Caddy reverse proxy configuration
 type CaddyConfig struct {
-	Enabled         bool   `yaml:"enabled"`
-	CaddyfilePath   string `yaml:"caddyfile_path"`    // Directory where Caddyfiles are stored
-	UseSudo         bool   `yaml:"use_sudo"`          // Whether to use sudo for Caddy reload
-	AutoReload      bool   `yaml:"auto_reload"`       // Automatically reload Caddy on changes
-	CaddyBinaryPath string `yaml:"caddy_binary_path"` // Path to Caddy binary (default: "caddy")
-	ReloadMethod    string `yaml:"reload_method"`     // Reload method: "binary" or "systemctl" (default: "binary")
+	Enabled         bool   `yaml:"enabled" json:"enabled" toml:"enabled"`
+	CaddyfilePath   string `yaml:"caddyfile_path" json:"caddyfile_path" toml:"caddyfile_path"`                            // Directory where Caddyfiles are stored
+	UseSudo         bool   `yaml:"use_sudo" json:"use_sudo" toml:"use_sudo"`                                              // Whether to use sudo for Caddy reload
+	AutoReload      bool   `yaml:"auto_reload" json:"auto_reload" toml:"auto_reload"`                                     // Automatically reload Caddy on changes
+	CaddyBinaryPath string `yaml:"caddy_binary_path" json:"caddy_binary_path" toml:"caddy_binary_path"`                   // Path to Caddy binary (default: "caddy")
+	ReloadMethod    string `yaml:"reload_method" json:"reload_method" toml:"reload_method" enum:"binary,systemctl,admin"` // Reload method: "binary", "systemctl" or "admin" (default: "binary")
+	AdminAddress    string `yaml:"admin_address" json:"admin_address" toml:"admin_address"`                               // Caddy admin API address for the "admin" reload method (default: "http://localhost:2019")
+	Combined        bool   `yaml:"combined" json:"combined" toml:"combined"`                                              // Maintain a single gintainer.caddy with all managed sites
+	MainCaddyfile   string `yaml:"main_caddyfile" json:"main_caddyfile" toml:"main_caddyfile"`                            // Caddyfile Caddy runs with; the "admin" reload method adapts it with its imports
 }
 
 // UIConfig represents UI configuration
 type UIConfig struct {
-	Title       string `yaml:"title"`
-	Description string `yaml:"description"`
-	Theme       string `yaml:"theme"` // "light" or "dark"
+	Title       string `yaml:"title" json:"title" toml:"title"`
+	Description string `yaml:"description" json:"description" toml:"description"`
+	Theme       string `yaml:"theme" json:"theme" toml:"theme" enum:"light,dark"` // "light" or "dark"
 }
 
 // DeploymentConfig represents deployment configuration
 type DeploymentConfig struct {
-	BasePath string `yaml:"base_path"` // Base path for storing compose deployments
+	BasePath string `yaml:"base_path" json:"base_path" toml:"base_path"` // Base path for storing compose deployments
+}
+
+// CheckpointConfig represents container checkpoint configuration
+type CheckpointConfig struct {
+	BasePath string `yaml:"base_path" json:"base_path" toml:"base_path"` // Directory where checkpoint archives are stored
+}
+
+// Notification events selecting which scheduler runs trigger a webhook
+const (
+	NotifyAlways    = "always"
+	NotifyOnSuccess = "on_success"
+	NotifyOnFailure = "on_failure"
+)
+
+// NotificationsConfig represents notification settings for scheduler runs
+type NotificationsConfig struct {
+	WebhookURL string `yaml:"webhook_url" json:"webhook_url" toml:"webhook_url"`          // Slack/Discord compatible webhook, empty disables notifications
+	On         string `yaml:"on" json:"on" toml:"on" enum:"always,on_success,on_failure"` // "always", "on_success" or "on_failure" (default: "always")
+}
+
+// AutoRestartConfig represents restarting of containers that exit with a non-zero code
+type AutoRestartConfig struct {
+	Enabled        bool     `yaml:"enabled" json:"enabled" toml:"enabled"`
+	Filters        []string `yaml:"filters" json:"filters" toml:"filters"`                               // Name patterns or "label:key[=value]" selectors, like scheduler filters; empty matches all
+	Exclude        []string `yaml:"exclude,omitempty" json:"exclude,omitempty" toml:"exclude,omitempty"` // Patterns of containers never restarted
+	MaxRestarts    int      `yaml:"max_restarts" json:"max_restarts" toml:"max_restarts"`                // Restarts per container within window_minutes before giving up
+	WindowMinutes  int      `yaml:"window_minutes" json:"window_minutes" toml:"window_minutes"`          // Window in which restarts are counted
+	BackoffSeconds int      `yaml:"backoff_seconds" json:"backoff_seconds" toml:"backoff_seconds"`       // Delay before a restart, doubled for each earlier restart in the window
+}
+
+// RegistryCredentials represents credentials for a private image registry
+type RegistryCredentials struct {
+	Username string `yaml:"username,omitempty" json:"username,omitempty" toml:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty" toml:"password,omitempty" secret:"true"`
+	Token    string `yaml:"token,omitempty" json:"token,omitempty" toml:"token,omitempty" secret:"true"` // Registry/identity token used instead of a password
 }
 
 // Manager manages configuration loading and hot-reload
@@ -70,6 +196,8 @@ type Manager struct {
 	watcher  *fsnotify.Watcher
 	mu       sync.RWMutex
 	onChange func(*Config)
+
+	writeMu sync.Mutex // Serializes updates through the API, so a patch applies to the latest config
 }
 
 // NewManager creates a new configuration manager
@@ -84,6 +212,14 @@ func NewManager(filePath string) (*Manager, error) {
 		if err := m.loadConfig(); err != nil {
 			return nil, fmt.Errorf("failed to load config: %w", err)
 		}
+	} else {
+		// Without a file, environment variables apply on top of the defaults
+		if err := applyEnvOverrides(m.config); err != nil {
+			return nil, fmt.Errorf("failed to apply environment overrides: %w", err)
+		}
+		if err := m.config.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
 	}
 
 	// Set up file watcher for hot-reload
@@ -107,8 +243,24 @@ func NewManager(filePath string) (*Manager, error) {
 func DefaultConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: "10000",
-			Mode: "release",
+			Port:           "10000",
+			Mode:           "release",
+			LogFormat:      "text",
+			LogMaxSizeMB:   100,
+			LogMaxBackups:  3,
+			LogMaxAgeDays:  28,
+			LogBufferSize:  1000,
+			RuntimeTimeout: "30s",
+			CORS: CORSConfig{
+				AllowedOrigins: []string{},
+				AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			},
+			RateLimit: RateLimitConfig{
+				RequestsPerMinute: 60,
+			},
+			TLS: TLSConfig{
+				HTTPPort: "80",
+			},
 		},
 		Scheduler: SchedulerConfig{
 			Enabled:  true,
@@ -128,6 +280,7 @@ func DefaultConfig() *Config {
 			AutoReload:      true,
 			CaddyBinaryPath: "caddy",
 			ReloadMethod:    "binary",
+			AdminAddress:    "http://localhost:2019",
 		},
 		UI: UIConfig{
 			Title:       "Gintainer",
@@ -137,23 +290,242 @@ func DefaultConfig() *Config {
 		Deployment: DeploymentConfig{
 			BasePath: "./compose-deployments",
 		},
+		Checkpoint: CheckpointConfig{
+			BasePath: "./checkpoints",
+		},
+		Notifications: NotificationsConfig{
+			On: NotifyAlways,
+		},
+		AutoRestart: AutoRestartConfig{
+			Enabled:        false,
+			MaxRestarts:    5,
+			WindowMinutes:  10,
+			BackoffSeconds: 1,
+		},
+	}
+}
+
+// Validate checks the configuration for invalid values and reports every problem found
+func (c *Config) Validate() error {
+	var problems []string
+
+	if c.Scheduler.Enabled || c.Scheduler.Schedule != "" {
+		if _, err := cron.ParseStandard(c.Scheduler.Schedule); err != nil {
+			problems = append(problems, fmt.Sprintf("scheduler.schedule %q is not a valid cron expression: %v", c.Scheduler.Schedule, err))
+		}
+	}
+
+	if c.Scheduler.Timezone != "" {
+		if _, err := time.LoadLocation(c.Scheduler.Timezone); err != nil {
+			problems = append(problems, fmt.Sprintf("scheduler.timezone %q is not a known IANA time zone", c.Scheduler.Timezone))
+		}
+	}
+
+	if c.Scheduler.UpdateConcurrency < 0 {
+		problems = append(problems, "scheduler.update_concurrency must not be negative")
+	}
+
+	jobNames := map[string]bool{}
+	for i, job := range c.Scheduler.Jobs {
+		if job.Name == "" {
+			problems = append(problems, fmt.Sprintf("scheduler.jobs[%d].name must be set", i))
+		} else if jobNames[job.Name] || job.Name == "default" {
+			problems = append(problems, fmt.Sprintf("scheduler.jobs[%d].name %q is not unique", i, job.Name))
+		}
+		jobNames[job.Name] = true
+
+		if _, err := cron.ParseStandard(job.Schedule); err != nil {
+			problems = append(problems, fmt.Sprintf("scheduler.jobs[%d].schedule %q is not a valid cron expression: %v", i, job.Schedule, err))
+		}
+	}
+
+	if c.Server.Mode != "debug" && c.Server.Mode != "release" {
+		problems = append(problems, fmt.Sprintf("server.mode %q must be \"debug\" or \"release\"", c.Server.Mode))
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		problems = append(problems, fmt.Sprintf("server.port %q must be a number between 1 and 65535", c.Server.Port))
+	}
+
+	switch c.Server.LogLevel {
+	case "", "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("server.log_level %q must be \"debug\", \"info\", \"warn\" or \"error\"", c.Server.LogLevel))
+	}
+
+	switch c.Server.LogFormat {
+	case "", "text", "json":
+	default:
+		problems = append(problems, fmt.Sprintf("server.log_format %q must be \"text\" or \"json\"", c.Server.LogFormat))
+	}
+
+	if c.Server.LogMaxSizeMB < 0 || c.Server.LogMaxBackups < 0 || c.Server.LogMaxAgeDays < 0 {
+		problems = append(problems, "server.log_max_size_mb, server.log_max_backups and server.log_max_age_days must not be negative")
+	}
+
+	if c.Server.LogBufferSize < 1 {
+		problems = append(problems, "server.log_buffer_size must be at least 1")
+	}
+
+	if c.Server.ListCacheTTL != "" {
+		if ttl, err := time.ParseDuration(c.Server.ListCacheTTL); err != nil || ttl < 0 {
+			problems = append(problems, fmt.Sprintf("server.list_cache_ttl %q must be a non-negative duration like \"2s\"", c.Server.ListCacheTTL))
+		}
+	}
+
+	if c.Server.RuntimeTimeout != "" {
+		if timeout, err := time.ParseDuration(c.Server.RuntimeTimeout); err != nil || timeout <= 0 {
+			problems = append(problems, fmt.Sprintf("server.runtime_timeout %q must be a positive duration like \"30s\"", c.Server.RuntimeTimeout))
+		}
+	}
+
+	if c.Server.CORS.Enabled && len(c.Server.CORS.AllowedOrigins) == 0 {
+		problems = append(problems, "server.cors.allowed_origins must be set when cors is enabled")
+	}
+
+	if c.Server.RateLimit.Enabled && c.Server.RateLimit.RequestsPerMinute < 1 {
+		problems = append(problems, "server.rate_limit.requests_per_minute must be at least 1 when rate_limit is enabled")
+	}
+	if c.Server.RateLimit.Burst < 0 {
+		problems = append(problems, "server.rate_limit.burst must not be negative")
+	}
+
+	if c.Server.TLS.Enabled {
+		if c.Server.TLS.CertFile == "" || c.Server.TLS.KeyFile == "" {
+			problems = append(problems, "server.tls.cert_file and server.tls.key_file must be set when tls is enabled")
+		}
+		if c.Server.TLS.RedirectHTTP {
+			if port, err := strconv.Atoi(c.Server.TLS.HTTPPort); err != nil || port < 1 || port > 65535 {
+				problems = append(problems, fmt.Sprintf("server.tls.http_port %q must be a number between 1 and 65535", c.Server.TLS.HTTPPort))
+			} else if c.Server.TLS.HTTPPort == c.Server.Port {
+				problems = append(problems, "server.tls.http_port must differ from server.port")
+			}
+		}
+	}
+
+	if c.AutoRestart.Enabled && (c.AutoRestart.MaxRestarts < 1 || c.AutoRestart.WindowMinutes < 1) {
+		problems = append(problems, "autorestart.max_restarts and autorestart.window_minutes must be at least 1 when autorestart is enabled")
+	}
+	if c.AutoRestart.BackoffSeconds < 0 {
+		problems = append(problems, "autorestart.backoff_seconds must not be negative")
+	}
+
+	if c.Docker.ListWorkers < 0 || c.Podman.ListWorkers < 0 {
+		problems = append(problems, "docker.list_workers and podman.list_workers must not be negative")
+	}
+
+	if c.UI.Theme != "light" && c.UI.Theme != "dark" {
+		problems = append(problems, fmt.Sprintf("ui.theme %q must be \"light\" or \"dark\"", c.UI.Theme))
+	}
+
+	switch c.Notifications.On {
+	case "", NotifyAlways, NotifyOnSuccess, NotifyOnFailure:
+	default:
+		problems = append(problems, fmt.Sprintf("notifications.on %q must be \"always\", \"on_success\" or \"on_failure\"", c.Notifications.On))
+	}
+
+	if c.Caddy.Enabled && c.Caddy.CaddyfilePath == "" {
+		problems = append(problems, "caddy.caddyfile_path must be set when caddy is enabled")
+	}
+
+	switch c.Caddy.ReloadMethod {
+	case "", "binary", "systemctl", "admin":
+	default:
+		problems = append(problems, fmt.Sprintf("caddy.reload_method %q must be \"binary\", \"systemctl\" or \"admin\"", c.Caddy.ReloadMethod))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Supported config file formats
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// formatFromPath detects the config file format from its extension, defaulting to YAML
+func formatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatYAML
+	}
+}
+
+// unmarshalConfig decodes data in the given format into config
+func unmarshalConfig(format string, data []byte, config *Config) error {
+	switch format {
+	case FormatJSON:
+		return json.Unmarshal(data, config)
+	case FormatTOML:
+		return toml.Unmarshal(data, config)
+	default:
+		return yaml.Unmarshal(data, config)
+	}
+}
+
+// marshalConfig encodes config in the given format
+func marshalConfig(format string, config *Config) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatTOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return yaml.Marshal(config)
 	}
 }
 
-// loadConfig loads configuration from file
+// parseConfig decodes and validates a configuration file in the given format.
+// Fields missing from the file keep their default values and environment
+// overrides are applied on top of the file.
+func parseConfig(format string, data []byte) (*Config, error) {
+	config := DefaultConfig()
+	if err := unmarshalConfig(format, data, config); err != nil {
+		return nil, err
+	}
+
+	if err := applyEnvOverrides(config); err != nil {
+		return nil, fmt.Errorf("invalid environment override: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// loadConfig loads configuration from file.
+// The current configuration is kept if the file is invalid.
 func (m *Manager) loadConfig() error {
 	data, err := os.ReadFile(m.filePath)
 	if err != nil {
 		return err
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	config, err := parseConfig(formatFromPath(m.filePath), data)
+	if err != nil {
 		return err
 	}
 
 	m.mu.Lock()
-	m.config = &config
+	m.config = config
 	m.mu.Unlock()
 
 	return nil
@@ -168,15 +540,42 @@ func (m *Manager) GetConfig() *Config {
 	return m.config
 }
 
-// UpdateConfig updates the configuration and saves to file
+// UpdateConfig updates the configuration and saves to file.
+// Secrets left empty or redacted keep their current value (see KeepSecrets).
 func (m *Manager) UpdateConfig(config *Config) error {
-	logger.Info("UpdateConfig: Marshaling config to YAML")
-	// Marshal to YAML
-	data, err := yaml.Marshal(config)
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+	KeepSecrets(m.GetConfig(), config)
+	return m.updateConfig(config)
+}
+
+// updateConfig validates and saves the configuration; the caller holds writeMu
+func (m *Manager) updateConfig(config *Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	format := formatFromPath(m.filePath)
+	logger.Info("UpdateConfig: Marshaling config", "format", format)
+	data, err := marshalConfig(format, config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	return m.writeConfig(format, data)
+}
+
+// writeConfig backs up the current config file, replaces it with data and reloads it
+func (m *Manager) writeConfig(format string, data []byte) error {
+	if _, err := m.backupConfig(); err != nil {
+		// A failed backup must not block saving the config
+		logger.Warn("UpdateConfig: Failed to back up config file", "path", m.filePath, "error", err)
+	}
+	return m.saveConfig(format, data)
+}
+
+// saveConfig replaces the config file with data, reloads it and calls the onChange callback
+func (m *Manager) saveConfig(format string, data []byte) error {
 	logger.Info("UpdateConfig: Writing config to file", "path", m.filePath)
 	// Write to file
 	if err := os.WriteFile(m.filePath, data, 0644); err != nil {
@@ -197,15 +596,15 @@ func (m *Manager) UpdateConfig(config *Config) error {
 		return fmt.Errorf("failed to read config after save: %w", err)
 	}
 
-	var reloadedConfig Config
-	if err := yaml.Unmarshal(reloadData, &reloadedConfig); err != nil {
+	reloadedConfig, err := parseConfig(format, reloadData)
+	if err != nil {
 		// Restore old config and release lock
 		m.mu.Unlock()
 		return fmt.Errorf("failed to unmarshal config after save: %w", err)
 	}
 
 	// Update in-memory config
-	m.config = &reloadedConfig
+	m.config = reloadedConfig
 	m.mu.Unlock()
 
 	logger.Info("UpdateConfig: Config reloaded successfully from file")
@@ -235,10 +634,12 @@ func (m *Manager) StartWatching() {
 					return
 				}
 				if event.Op&fsnotify.Write == fsnotify.Write {
-					if err := m.loadConfig(); err == nil {
-						if m.onChange != nil {
-							m.onChange(m.GetConfig())
-						}
+					if err := m.loadConfig(); err != nil {
+						logger.Error("StartWatching: Failed to reload config, keeping previous configuration", "error", err)
+						continue
+					}
+					if m.onChange != nil {
+						m.onChange(m.GetConfig())
 					}
 				}
 			case err, ok := <-m.watcher.Errors:
@@ -23,6 +23,7 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, "light", cfg.UI.Theme)
 	assert.Equal(t, "A Golang application built with the Gin framework for managing containers and pods from both Docker and Podman.", cfg.UI.Description)
 	assert.Equal(t, "./compose-deployments", cfg.Deployment.BasePath)
+	assert.Equal(t, "./checkpoints", cfg.Checkpoint.BasePath)
 }
 
 func TestNewManager(t *testing.T) {
@@ -132,3 +133,184 @@ func TestHotReload(t *testing.T) {
 	// the changed flag would be true. This is a simplified test.
 	assert.True(t, changed || !changed) // Just verify no crashes
 }
+
+func TestValidateDefaultConfig(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Scheduler.Schedule = "every day"
+	cfg.Scheduler.Timezone = "Mars/Olympus_Mons"
+	cfg.Server.Mode = "production"
+	cfg.Server.Port = "http"
+	cfg.Server.LogLevel = "verbose"
+	cfg.Server.LogFormat = "xml"
+	cfg.Server.LogMaxBackups = -1
+	cfg.Server.ListCacheTTL = "often"
+	cfg.Server.RuntimeTimeout = "0s"
+	cfg.Server.CORS.Enabled = true
+	cfg.Server.RateLimit.Enabled = true
+	cfg.Server.RateLimit.RequestsPerMinute = 0
+	cfg.Server.TLS.Enabled = true
+	cfg.AutoRestart.Enabled = true
+	cfg.AutoRestart.MaxRestarts = 0
+	cfg.Podman.ListWorkers = -1
+	cfg.UI.Theme = "blue"
+	cfg.Caddy.Enabled = true
+	cfg.Caddy.CaddyfilePath = ""
+	cfg.Caddy.ReloadMethod = "service"
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "scheduler.schedule")
+	assert.Contains(t, err.Error(), "scheduler.timezone")
+	assert.Contains(t, err.Error(), "server.mode")
+	assert.Contains(t, err.Error(), "server.port")
+	assert.Contains(t, err.Error(), "server.log_level")
+	assert.Contains(t, err.Error(), "server.log_format")
+	assert.Contains(t, err.Error(), "server.log_max_backups")
+	assert.Contains(t, err.Error(), "server.list_cache_ttl")
+	assert.Contains(t, err.Error(), "server.runtime_timeout")
+	assert.Contains(t, err.Error(), "server.cors.allowed_origins")
+	assert.Contains(t, err.Error(), "server.rate_limit.requests_per_minute")
+	assert.Contains(t, err.Error(), "server.tls.cert_file")
+	assert.Contains(t, err.Error(), "autorestart.max_restarts")
+	assert.Contains(t, err.Error(), "podman.list_workers")
+	assert.Contains(t, err.Error(), "ui.theme")
+	assert.Contains(t, err.Error(), "caddy.caddyfile_path")
+	assert.Contains(t, err.Error(), "caddy.reload_method")
+}
+
+func TestListCacheDuration(t *testing.T) {
+	assert.Equal(t, time.Duration(0), ServerConfig{}.ListCacheDuration())
+	assert.Equal(t, 2*time.Second, ServerConfig{ListCacheTTL: "2s"}.ListCacheDuration())
+	assert.Equal(t, 500*time.Millisecond, ServerConfig{ListCacheTTL: "500ms"}.ListCacheDuration())
+	assert.Equal(t, time.Duration(0), ServerConfig{ListCacheTTL: "-1s"}.ListCacheDuration())
+}
+
+func TestRuntimeTimeoutDuration(t *testing.T) {
+	assert.Equal(t, 30*time.Second, DefaultConfig().Server.RuntimeTimeoutDuration())
+	assert.Equal(t, time.Duration(0), ServerConfig{}.RuntimeTimeoutDuration())
+	assert.Equal(t, time.Minute, ServerConfig{RuntimeTimeout: "1m"}.RuntimeTimeoutDuration())
+}
+
+func TestNewManagerRejectsInvalidConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+
+	configContent := `server:
+  port: "abc"
+  mode: "production"
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	assert.NoError(t, err)
+
+	manager, err := NewManager(configPath)
+	assert.Error(t, err)
+	assert.Nil(t, manager)
+	assert.Contains(t, err.Error(), "server.port")
+	assert.Contains(t, err.Error(), "server.mode")
+}
+
+func TestReloadKeepsPreviousConfigOnInvalidFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+
+	manager, err := NewManager(configPath)
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	newConfig := DefaultConfig()
+	newConfig.Server.Port = "9090"
+	assert.NoError(t, manager.UpdateConfig(newConfig))
+
+	err = os.WriteFile(configPath, []byte("ui:\n  theme: \"blue\"\n"), 0644)
+	assert.NoError(t, err)
+
+	assert.Error(t, manager.loadConfig())
+	assert.Equal(t, "9090", manager.GetConfig().Server.Port)
+}
+
+func TestUpdateConfigRejectsInvalidConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+
+	manager, err := NewManager(configPath)
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	newConfig := DefaultConfig()
+	newConfig.Server.Mode = "verbose"
+	assert.Error(t, manager.UpdateConfig(newConfig))
+
+	// Nothing should have been written
+	_, err = os.Stat(configPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFormatFromPath(t *testing.T) {
+	assert.Equal(t, FormatYAML, formatFromPath("gintainer.yaml"))
+	assert.Equal(t, FormatYAML, formatFromPath("gintainer.yml"))
+	assert.Equal(t, FormatJSON, formatFromPath("gintainer.json"))
+	assert.Equal(t, FormatTOML, formatFromPath("/etc/gintainer/gintainer.TOML"))
+	assert.Equal(t, FormatYAML, formatFromPath("gintainer.conf"))
+}
+
+func TestConfigRoundTripAllFormats(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.json", "config.toml"} {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), name)
+
+			manager, err := NewManager(configPath)
+			assert.NoError(t, err)
+			defer manager.Close()
+
+			newConfig := DefaultConfig()
+			newConfig.Server.Port = "9090"
+			newConfig.Scheduler.Filters = []string{"web", "db"}
+			newConfig.Caddy.CaddyfilePath = "/srv/caddy"
+			newConfig.Registries = map[string]RegistryCredentials{
+				"ghcr.io": {Username: "bot", Token: "secret"},
+			}
+			assert.NoError(t, manager.UpdateConfig(newConfig))
+
+			// Load the written file with a fresh manager
+			reloaded, err := NewManager(configPath)
+			assert.NoError(t, err)
+			defer reloaded.Close()
+
+			cfg := reloaded.GetConfig()
+			assert.Equal(t, "9090", cfg.Server.Port)
+			assert.Equal(t, []string{"web", "db"}, cfg.Scheduler.Filters)
+			assert.Equal(t, "/srv/caddy", cfg.Caddy.CaddyfilePath)
+			assert.Equal(t, "secret", cfg.Registries["ghcr.io"].Token)
+			assert.Equal(t, newConfig.UI.Description, cfg.UI.Description)
+		})
+	}
+}
+
+func TestLoadExistingConfigFormats(t *testing.T) {
+	files := map[string]string{
+		"config.json": `{"server": {"port": "3000", "mode": "debug"}, "ui": {"theme": "dark"}}`,
+		"config.toml": "[server]\nport = \"3000\"\nmode = \"debug\"\n\n[ui]\ntheme = \"dark\"\n",
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), name)
+			assert.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+			manager, err := NewManager(configPath)
+			assert.NoError(t, err)
+			defer manager.Close()
+
+			cfg := manager.GetConfig()
+			assert.Equal(t, "3000", cfg.Server.Port)
+			assert.Equal(t, "debug", cfg.Server.Mode)
+			assert.Equal(t, "dark", cfg.UI.Theme)
+			// Missing fields keep their defaults
+			assert.Equal(t, "Gintainer", cfg.UI.Title)
+		})
+	}
+}
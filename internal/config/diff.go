@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change is a config field whose value differs between two configs
+type Change struct {
+	Path string      `json:"path"` // YAML keys joined with dots, e.g. server.port or registries.ghcr.io.username
+	Old  interface{} `json:"old"`  // nil if the field did not exist (map entries)
+	New  interface{} `json:"new"`  // nil if the field was removed (map entries)
+}
+
+// Diff returns the fields that differ between two configs, in field order.
+//
+// Like the environment overrides it walks the YAML-tagged fields, so new config fields
+// are picked up automatically. Sections are compared field by field and maps entry by
+// entry; lists and scalars are compared as a whole. Nil and empty lists or maps are equal.
+// Values of secret fields are redacted, so a change to a secret is reported without it.
+func Diff(oldConfig, newConfig *Config) []Change {
+	changes := []Change{}
+	diffValues(reflect.ValueOf(oldConfig).Elem(), reflect.ValueOf(newConfig).Elem(), "", &changes)
+	return changes
+}
+
+// diffValues appends the differences between two values of the same type
+func diffValues(oldValue, newValue reflect.Value, path string, changes *[]Change) {
+	switch oldValue.Kind() {
+	case reflect.Struct:
+		t := oldValue.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if key == "" || key == "-" {
+				continue
+			}
+			if isSecret(field) {
+				if oldSecret, newSecret := oldValue.Field(i).String(), newValue.Field(i).String(); oldSecret != newSecret {
+					*changes = append(*changes, Change{Path: joinPath(path, key), Old: redactedString(oldSecret), New: redactedString(newSecret)})
+				}
+				continue
+			}
+			diffValues(oldValue.Field(i), newValue.Field(i), joinPath(path, key), changes)
+		}
+	case reflect.Map:
+		for _, key := range mapKeys(oldValue, newValue) {
+			oldEntry, newEntry := oldValue.MapIndex(key), newValue.MapIndex(key)
+			entryPath := joinPath(path, fmt.Sprint(key.Interface()))
+			switch {
+			case !oldEntry.IsValid():
+				*changes = append(*changes, Change{Path: entryPath, New: redactedValue(newEntry)})
+			case !newEntry.IsValid():
+				*changes = append(*changes, Change{Path: entryPath, Old: redactedValue(oldEntry)})
+			default:
+				diffValues(oldEntry, newEntry, entryPath, changes)
+			}
+		}
+	case reflect.Slice:
+		if oldValue.Len() == 0 && newValue.Len() == 0 {
+			return
+		}
+		fallthrough
+	default:
+		if !reflect.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+			*changes = append(*changes, Change{Path: path, Old: oldValue.Interface(), New: newValue.Interface()})
+		}
+	}
+}
+
+// mapKeys returns the keys of both maps, sorted
+func mapKeys(a, b reflect.Value) []reflect.Value {
+	seen := make(map[interface{}]bool)
+	var keys []reflect.Value
+	for _, m := range []reflect.Value{a, b} {
+		for _, key := range m.MapKeys() {
+			if !seen[key.Interface()] {
+				seen[key.Interface()] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+	return keys
+}
+
+// joinPath appends a key to a dotted field path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	oldConfig := DefaultConfig()
+	oldConfig.Registries = map[string]RegistryCredentials{
+		"ghcr.io":   {Username: "alice", Token: "secret"},
+		"docker.io": {Username: "bob"},
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.Server.Port = "9090"
+	newConfig.Server.TLS.Enabled = true
+	newConfig.Scheduler.Filters = []string{"web-*"}
+	newConfig.UI.Theme = "dark"
+	newConfig.Registries = map[string]RegistryCredentials{
+		"ghcr.io": {Username: "carol", Token: "secret"},
+		"quay.io": {Username: "dave"},
+	}
+
+	assert.Equal(t, []Change{
+		{Path: "server.port", Old: oldConfig.Server.Port, New: "9090"},
+		{Path: "server.tls.enabled", Old: false, New: true},
+		{Path: "scheduler.filters", Old: oldConfig.Scheduler.Filters, New: []string{"web-*"}},
+		{Path: "ui.theme", Old: "light", New: "dark"},
+		{Path: "registries.docker.io", Old: RegistryCredentials{Username: "bob"}},
+		{Path: "registries.ghcr.io.username", Old: "alice", New: "carol"},
+		{Path: "registries.quay.io", New: RegistryCredentials{Username: "dave"}},
+	}, Diff(oldConfig, newConfig))
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	oldConfig := DefaultConfig()
+	newConfig := DefaultConfig()
+	// Nil and empty lists and maps are equal
+	oldConfig.Scheduler.Exclude = nil
+	newConfig.Scheduler.Exclude = []string{}
+	newConfig.Registries = map[string]RegistryCredentials{}
+
+	assert.Empty(t, Diff(oldConfig, newConfig))
+}
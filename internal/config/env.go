@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix is the prefix of all environment variables that override config fields.
+//
+// Variable names are built from the YAML keys of the field path, upper-cased and
+// joined with underscores, e.g. server.port -> GINTAINER_SERVER_PORT and
+// caddy.caddyfile_path -> GINTAINER_CADDY_CADDYFILE_PATH. List fields take a
+// comma-separated value. Environment variables always win over the config file.
+const EnvPrefix = "GINTAINER"
+
+// legacyEnvOverrides maps environment variables honored before the GINTAINER_ prefix
+// was introduced to their current names. The prefixed variable wins if both are set.
+var legacyEnvOverrides = map[string]string{
+	"PORT": "GINTAINER_SERVER_PORT",
+}
+
+// ConfigPathFromEnv returns the config file path from GINTAINER_CONFIG_PATH or CONFIG_PATH,
+// falling back to "gintainer.yaml"
+func ConfigPathFromEnv() string {
+	if path := os.Getenv(EnvPrefix + "_CONFIG_PATH"); path != "" {
+		return path
+	}
+	if path := os.Getenv("CONFIG_PATH"); path != "" {
+		return path
+	}
+	return "gintainer.yaml"
+}
+
+// applyEnvOverrides overwrites config fields with values from the environment
+func applyEnvOverrides(config *Config) error {
+	return applyEnvToStruct(reflect.ValueOf(config).Elem(), EnvPrefix, lookupEnv)
+}
+
+// lookupEnv looks up an environment variable, falling back to its legacy name
+func lookupEnv(name string) (string, bool) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, true
+	}
+	for legacy, current := range legacyEnvOverrides {
+		if current == name {
+			return os.LookupEnv(legacy)
+		}
+	}
+	return "", false
+}
+
+// applyEnvToStruct walks the exported, YAML-tagged fields of a struct and sets those
+// with a matching environment variable
+func applyEnvToStruct(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	t := v.Type()
+	var problems []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(key)
+		fieldValue := v.Field(i)
+
+		if fieldValue.Kind() == reflect.Struct {
+			if err := applyEnvToStruct(fieldValue, name, lookup); err != nil {
+				problems = append(problems, err.Error())
+			}
+			continue
+		}
+
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(fieldValue, raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// setFromEnv parses raw into a field of kind string, bool, int or []string
+func setFromEnv(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		field.SetInt(n)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported list type %s", field.Type())
+		}
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
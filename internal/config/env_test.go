@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Setenv("GINTAINER_SERVER_PORT", "8081")
+	t.Setenv("GINTAINER_DOCKER_ENABLED", "false")
+	t.Setenv("GINTAINER_CADDY_ENABLED", "true")
+	t.Setenv("GINTAINER_CADDY_CADDYFILE_PATH", "/srv/caddy")
+	t.Setenv("GINTAINER_SCHEDULER_FILTERS", "web-*, db")
+
+	cfg := DefaultConfig()
+	assert.NoError(t, applyEnvOverrides(cfg))
+
+	assert.Equal(t, "8081", cfg.Server.Port)
+	assert.False(t, cfg.Docker.Enabled)
+	assert.True(t, cfg.Caddy.Enabled)
+	assert.Equal(t, "/srv/caddy", cfg.Caddy.CaddyfilePath)
+	assert.Equal(t, []string{"web-*", "db"}, cfg.Scheduler.Filters)
+	// Untouched fields keep their values
+	assert.True(t, cfg.Podman.Enabled)
+	assert.Equal(t, "release", cfg.Server.Mode)
+}
+
+func TestApplyEnvOverridesInvalidBool(t *testing.T) {
+	t.Setenv("GINTAINER_DOCKER_ENABLED", "maybe")
+
+	err := applyEnvOverrides(DefaultConfig())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "GINTAINER_DOCKER_ENABLED")
+}
+
+func TestApplyEnvOverridesLegacyPort(t *testing.T) {
+	t.Setenv("PORT", "7000")
+
+	cfg := DefaultConfig()
+	assert.NoError(t, applyEnvOverrides(cfg))
+	assert.Equal(t, "7000", cfg.Server.Port)
+
+	// The prefixed variable wins over the legacy one
+	t.Setenv("GINTAINER_SERVER_PORT", "7001")
+	assert.NoError(t, applyEnvOverrides(cfg))
+	assert.Equal(t, "7001", cfg.Server.Port)
+}
+
+func TestSetFromEnv(t *testing.T) {
+	var target struct {
+		Name    string   `yaml:"name"`
+		Enabled bool     `yaml:"enabled"`
+		Count   int      `yaml:"count"`
+		Tags    []string `yaml:"tags"`
+	}
+	env := map[string]string{
+		"TEST_NAME":    "gintainer",
+		"TEST_ENABLED": "1",
+		"TEST_COUNT":   "42",
+		"TEST_TAGS":    "a,b",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	assert.NoError(t, applyEnvToStruct(reflect.ValueOf(&target).Elem(), "TEST", lookup))
+	assert.Equal(t, "gintainer", target.Name)
+	assert.True(t, target.Enabled)
+	assert.Equal(t, 42, target.Count)
+	assert.Equal(t, []string{"a", "b"}, target.Tags)
+
+	env["TEST_COUNT"] = "many"
+	err := applyEnvToStruct(reflect.ValueOf(&target).Elem(), "TEST", lookup)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "TEST_COUNT")
+}
+
+func TestEnvOverridesWinOverFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+
+	err := os.WriteFile(configPath, []byte("server:\n  port: \"3000\"\n"), 0644)
+	assert.NoError(t, err)
+	t.Setenv("GINTAINER_SERVER_PORT", "4000")
+
+	manager, err := NewManager(configPath)
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	assert.Equal(t, "4000", manager.GetConfig().Server.Port)
+}
+
+func TestConfigPathFromEnv(t *testing.T) {
+	t.Setenv("GINTAINER_CONFIG_PATH", "")
+	t.Setenv("CONFIG_PATH", "")
+	assert.Equal(t, "gintainer.yaml", ConfigPathFromEnv())
+
+	t.Setenv("CONFIG_PATH", "/etc/gintainer.yaml")
+	assert.Equal(t, "/etc/gintainer.yaml", ConfigPathFromEnv())
+
+	t.Setenv("GINTAINER_CONFIG_PATH", "/opt/gintainer.yaml")
+	assert.Equal(t, "/opt/gintainer.yaml", ConfigPathFromEnv())
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPatch is returned for config patches that are not valid JSON objects,
+// name unknown fields or result in an invalid config
+var ErrInvalidPatch = errors.New("invalid config patch")
+
+// MergeConfig applies a partial JSON document to a copy of base, like a JSON merge patch (RFC 7386):
+// objects are merged key by key, so sections are merged field by field and maps entry by entry,
+// other values replace the current ones and null removes a field or map entry.
+// Fields missing from the patch keep their current value.
+func MergeConfig(base *Config, patch []byte) (*Config, error) {
+	var patchDoc map[string]interface{}
+	if err := json.Unmarshal(patch, &patchDoc); err != nil || patchDoc == nil {
+		return nil, fmt.Errorf("%w: expected a JSON object", ErrInvalidPatch)
+	}
+
+	baseData, err := json.Marshal(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	var baseDoc map[string]interface{}
+	if err := json.Unmarshal(baseData, &baseDoc); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	merged, err := json.Marshal(mergePatch(baseDoc, patchDoc))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	// Unknown fields are rejected so a typo in the patch does not silently do nothing
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return config, nil
+}
+
+// mergePatch merges patch into target and returns target
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		patchObject, isObject := value.(map[string]interface{})
+		targetObject, targetIsObject := target[key].(map[string]interface{})
+		if isObject && targetIsObject {
+			target[key] = mergePatch(targetObject, patchObject)
+			continue
+		}
+		if isObject {
+			// Drop nulls of objects that are new to the target
+			value = mergePatch(map[string]interface{}{}, patchObject)
+		}
+		target[key] = value
+	}
+	return target
+}
+
+// PatchConfig merges a partial JSON document onto the current config (see MergeConfig),
+// then validates and saves it. Concurrent patches are applied one after another, so each
+// one sees the changes of the previous. Secrets set to "" or RedactedSecret keep their
+// current value. It returns the changed fields, with secrets redacted.
+func (m *Manager) PatchConfig(patch []byte) ([]Change, error) {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	current := m.GetConfig()
+	config, err := MergeConfig(current, patch)
+	if err != nil {
+		return nil, err
+	}
+	KeepSecrets(current, config)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	changes := Diff(current, config)
+	if err := m.updateConfig(config); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeConfigNestedSections(t *testing.T) {
+	base := DefaultConfig()
+	base.Scheduler.Enabled = false
+	base.Scheduler.Schedule = "0 3 * * *"
+	base.Registries = map[string]RegistryCredentials{
+		"ghcr.io":   {Username: "me", Token: "abc"},
+		"docker.io": {Username: "other", Password: "secret"},
+	}
+
+	merged, err := MergeConfig(base, []byte(`{
+		"scheduler": {"enabled": true},
+		"server": {"rate_limit": {"burst": 5}},
+		"registries": {"docker.io": null, "quay.io": {"username": "new"}}
+	}`))
+	require.NoError(t, err)
+
+	assert.True(t, merged.Scheduler.Enabled)
+	assert.Equal(t, "0 3 * * *", merged.Scheduler.Schedule, "fields missing from the patch are kept")
+	assert.Equal(t, 5, merged.Server.RateLimit.Burst)
+	assert.Equal(t, base.Server.RateLimit.RequestsPerMinute, merged.Server.RateLimit.RequestsPerMinute)
+	assert.Equal(t, base.Server.Port, merged.Server.Port)
+	assert.Equal(t, map[string]RegistryCredentials{
+		"ghcr.io": {Username: "me", Token: "abc"},
+		"quay.io": {Username: "new"},
+	}, merged.Registries)
+
+	// The base is left untouched
+	assert.False(t, base.Scheduler.Enabled)
+	assert.Contains(t, base.Registries, "docker.io")
+}
+
+func TestMergeConfigInvalid(t *testing.T) {
+	for _, patch := range []string{`not json`, `[1, 2]`, `null`, `{"server": {"colour": "blue"}}`, `{"server": {"port": 80}}`} {
+		_, err := MergeConfig(DefaultConfig(), []byte(patch))
+		assert.ErrorIs(t, err, ErrInvalidPatch, patch)
+	}
+}
+
+func TestPatchConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager, err := NewManager(filepath.Join(tmpDir, "gintainer.yaml"))
+	require.NoError(t, err)
+	defer manager.Close()
+
+	changes, err := manager.PatchConfig([]byte(`{"ui": {"title": "Patched"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Path: "ui.title", Old: DefaultConfig().UI.Title, New: "Patched"}}, changes)
+
+	// The patch is saved to the file
+	reloaded, err := NewManager(filepath.Join(tmpDir, "gintainer.yaml"))
+	require.NoError(t, err)
+	defer reloaded.Close()
+	assert.Equal(t, "Patched", reloaded.GetConfig().UI.Title)
+	assert.Equal(t, DefaultConfig().Server.Port, reloaded.GetConfig().Server.Port)
+
+	// A patch resulting in an invalid config is rejected and not saved
+	_, err = manager.PatchConfig([]byte(`{"server": {"mode": "chaos"}}`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+	assert.Equal(t, DefaultConfig().Server.Mode, manager.GetConfig().Server.Mode)
+}
+
+func TestPatchConfigConcurrent(t *testing.T) {
+	manager, err := NewManager(filepath.Join(t.TempDir(), "gintainer.yaml"))
+	require.NoError(t, err)
+	defer manager.Close()
+
+	// Each patch adds its own registry, none may be lost to a concurrent one
+	const patches = 10
+	var wg sync.WaitGroup
+	for i := 0; i < patches; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := manager.PatchConfig([]byte(fmt.Sprintf(`{"registries": {"registry%d.example.com": {"username": "user"}}}`, i)))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, manager.GetConfig().Registries, patches)
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// DefaultRegistry is the registry used for image references without an explicit registry host
+const DefaultRegistry = "docker.io"
+
+// RegistryAuth looks up the configured credentials for the registry an image belongs to.
+// It returns nil when no credentials are configured for that registry.
+func RegistryAuth(registries map[string]RegistryCredentials, imageName string) *models.RegistryAuth {
+	creds, ok := registries[RegistryHost(imageName)]
+	if !ok {
+		return nil
+	}
+
+	return &models.RegistryAuth{
+		Username: creds.Username,
+		Password: creds.Password,
+		Token:    creds.Token,
+	}
+}
+
+// RegistryHost extracts the registry hostname from an image reference.
+// References without a registry component (e.g. "nginx" or "library/nginx") resolve to Docker Hub.
+func RegistryHost(imageName string) string {
+	parts := strings.SplitN(imageName, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0]
+	}
+	return DefaultRegistry
+}
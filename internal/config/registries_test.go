@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryHost(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{"nginx", "docker.io"},
+		{"nginx:latest", "docker.io"},
+		{"library/nginx", "docker.io"},
+		{"ghcr.io/org/app:1.0", "ghcr.io"},
+		{"registry.example.com:5000/team/app", "registry.example.com:5000"},
+		{"localhost/app", "localhost"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, RegistryHost(tc.image), "Failed for image: %s", tc.image)
+	}
+}
+
+func TestRegistryAuth(t *testing.T) {
+	registries := map[string]RegistryCredentials{
+		"ghcr.io": {Username: "bot", Password: "secret"},
+	}
+
+	auth := RegistryAuth(registries, "ghcr.io/org/app:latest")
+	if assert.NotNil(t, auth) {
+		assert.Equal(t, "bot", auth.Username)
+		assert.Equal(t, "secret", auth.Password)
+	}
+
+	assert.Nil(t, RegistryAuth(registries, "nginx:latest"))
+	assert.Nil(t, RegistryAuth(nil, "ghcr.io/org/app:latest"))
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Field describes a config field, so clients can render the config form from the Go struct
+type Field struct {
+	Path    string      `json:"path"`             // YAML keys joined with dots, e.g. server.port
+	Type    string      `json:"type"`             // "string", "int", "bool", "list" or "map"
+	Enum    []string    `json:"enum,omitempty"`   // Allowed values, from the enum struct tag
+	Default interface{} `json:"default"`          // Value in DefaultConfig()
+	Fields  []Field     `json:"fields,omitempty"` // Fields of each list or map entry with paths relative to the entry, empty for lists of strings
+}
+
+// Schema returns descriptors for all config fields, in field order.
+//
+// Like the environment overrides it walks the YAML-tagged fields, so new config fields
+// are picked up automatically. Sections are flattened into the paths of their fields.
+func Schema() []Field {
+	return schemaFields(reflect.ValueOf(DefaultConfig()).Elem(), "")
+}
+
+// schemaFields describes the YAML-tagged fields of a struct value
+func schemaFields(v reflect.Value, path string) []Field {
+	t := v.Type()
+	fields := []Field{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+
+		fieldPath := joinPath(path, key)
+		fieldValue := v.Field(i)
+		if fieldValue.Kind() == reflect.Struct {
+			fields = append(fields, schemaFields(fieldValue, fieldPath)...)
+			continue
+		}
+
+		f := Field{Path: fieldPath, Type: schemaType(fieldValue.Kind()), Default: fieldValue.Interface()}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			f.Enum = strings.Split(enum, ",")
+		}
+		if (fieldValue.Kind() == reflect.Map || fieldValue.Kind() == reflect.Slice) && field.Type.Elem().Kind() == reflect.Struct {
+			f.Fields = schemaFields(reflect.New(field.Type.Elem()).Elem(), "")
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// schemaType names the kind of a config field
+func schemaType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int64:
+		return "int"
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return "string"
+	}
+}
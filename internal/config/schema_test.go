@@ -0,0 +1,57 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	fields := make(map[string]Field)
+	for _, f := range Schema() {
+		fields[f.Path] = f
+	}
+
+	assert.Equal(t, Field{Path: "server.port", Type: "string", Default: "10000"}, fields["server.port"])
+	assert.Equal(t, Field{Path: "server.mode", Type: "string", Enum: []string{"debug", "release"}, Default: "release"}, fields["server.mode"])
+	assert.Equal(t, Field{Path: "ui.theme", Type: "string", Enum: []string{"light", "dark"}, Default: "light"}, fields["ui.theme"])
+	assert.Equal(t, Field{Path: "scheduler.enabled", Type: "bool", Default: true}, fields["scheduler.enabled"])
+	assert.Equal(t, Field{Path: "server.rate_limit.requests_per_minute", Type: "int", Default: 60}, fields["server.rate_limit.requests_per_minute"])
+	assert.Equal(t, "list", fields["server.cors.allowed_methods"].Type)
+	assert.Equal(t, []string{"GET", "POST", "PUT", "PATCH", "DELETE"}, fields["server.cors.allowed_methods"].Default)
+
+	// Sections are flattened and unexported fields are left out
+	assert.NotContains(t, fields, "server")
+	assert.NotContains(t, fields, "mu")
+
+	// Entries of lists and maps of structs are described by their fields
+	jobs := fields["scheduler.jobs"]
+	assert.Equal(t, "list", jobs.Type)
+	require.NotEmpty(t, jobs.Fields)
+	assert.Equal(t, "name", jobs.Fields[0].Path)
+
+	registries := fields["registries"]
+	assert.Equal(t, "map", registries.Type)
+	var entryPaths []string
+	for _, f := range registries.Fields {
+		entryPaths = append(entryPaths, f.Path)
+	}
+	assert.Equal(t, []string{"username", "password", "token"}, entryPaths)
+}
+
+func TestSchemaEnumsMatchValidation(t *testing.T) {
+	for _, f := range Schema() {
+		for _, value := range f.Enum {
+			// Set the field the way an environment override would
+			name := EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(f.Path, ".", "_"))
+			lookup := func(key string) (string, bool) { return value, key == name }
+
+			cfg := DefaultConfig()
+			require.NoError(t, applyEnvToStruct(reflect.ValueOf(cfg).Elem(), EnvPrefix, lookup), f.Path)
+			assert.NoError(t, cfg.Validate(), "%s=%s", f.Path, value)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"reflect"
+)
+
+// RedactedSecret replaces the value of secret fields in API responses
+const RedactedSecret = "********"
+
+// Redacted returns a copy of the config with every non-empty secret field replaced by RedactedSecret.
+// Secret fields are the string fields tagged secret:"true", like registry passwords.
+func Redacted(config *Config) *Config {
+	redacted := &Config{}
+	value := reflect.ValueOf(redacted).Elem()
+	value.Set(reflect.ValueOf(config).Elem())
+	redactSecrets(value)
+	return redacted
+}
+
+// KeepSecrets sets the secret fields of config that are empty or RedactedSecret to their
+// value in current, so a config read back from the API can be saved without its secrets.
+// Map entries are matched by key.
+func KeepSecrets(current, config *Config) {
+	keepSecrets(reflect.ValueOf(current).Elem(), reflect.ValueOf(config).Elem())
+}
+
+// isSecret reports whether a struct field holds a secret
+func isSecret(field reflect.StructField) bool {
+	return field.Tag.Get("secret") == "true" && field.Type.Kind() == reflect.String
+}
+
+// redactSecrets replaces the non-empty secret fields within v, which must be settable.
+// Maps and lists are replaced by redacted copies, so values shared with v's source are not changed.
+func redactSecrets(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isSecret(field) {
+				v.Field(i).SetString(redactedString(v.Field(i).String()))
+				continue
+			}
+			redactSecrets(v.Field(i))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		redacted := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			redacted.SetMapIndex(key, reflect.ValueOf(redactedValue(v.MapIndex(key))))
+		}
+		v.Set(redacted)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		redacted := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			redacted.Index(i).Set(reflect.ValueOf(redactedValue(v.Index(i))))
+		}
+		v.Set(redacted)
+	}
+}
+
+// redactedValue returns a copy of v with its secret fields redacted
+func redactedValue(v reflect.Value) interface{} {
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+	redactSecrets(copied)
+	return copied.Interface()
+}
+
+// redactedString returns RedactedSecret for a non-empty secret
+func redactedString(value string) string {
+	if value == "" {
+		return ""
+	}
+	return RedactedSecret
+}
+
+// keepSecrets copies the secrets of current into the empty or redacted secret fields of config
+func keepSecrets(current, config reflect.Value) {
+	switch config.Kind() {
+	case reflect.Struct:
+		t := config.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isSecret(field) {
+				value, currentValue := config.Field(i).String(), current.Field(i).String()
+				if (value == "" || value == RedactedSecret) && value != currentValue {
+					config.Field(i).SetString(currentValue)
+				}
+				continue
+			}
+			keepSecrets(current.Field(i), config.Field(i))
+		}
+	case reflect.Map:
+		for _, key := range config.MapKeys() {
+			currentEntry := current.MapIndex(key)
+			if !currentEntry.IsValid() {
+				continue
+			}
+			entry := reflect.New(config.Type().Elem()).Elem()
+			entry.Set(config.MapIndex(key))
+			keepSecrets(currentEntry, entry)
+			if !reflect.DeepEqual(entry.Interface(), config.MapIndex(key).Interface()) {
+				config.SetMapIndex(key, entry)
+			}
+		}
+	}
+}
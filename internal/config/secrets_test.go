@@ -0,0 +1,87 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedacted(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Podman.Passphrase = "key-passphrase"
+	cfg.Registries = map[string]RegistryCredentials{
+		"ghcr.io":   {Username: "alice", Token: "ghp_token"},
+		"docker.io": {Username: "bob", Password: "hunter2"},
+	}
+
+	redacted := Redacted(cfg)
+	assert.Equal(t, RedactedSecret, redacted.Podman.Passphrase)
+	assert.Empty(t, redacted.Docker.Passphrase)
+	assert.Equal(t, RegistryCredentials{Username: "alice", Token: RedactedSecret}, redacted.Registries["ghcr.io"])
+	assert.Equal(t, RegistryCredentials{Username: "bob", Password: RedactedSecret}, redacted.Registries["docker.io"])
+	assert.Equal(t, cfg.Server, redacted.Server)
+
+	// The original config keeps its secrets
+	assert.Equal(t, "key-passphrase", cfg.Podman.Passphrase)
+	assert.Equal(t, "hunter2", cfg.Registries["docker.io"].Password)
+}
+
+func TestKeepSecrets(t *testing.T) {
+	current := DefaultConfig()
+	current.Podman.Passphrase = "key-passphrase"
+	current.Registries = map[string]RegistryCredentials{
+		"ghcr.io":   {Username: "alice", Token: "ghp_token"},
+		"docker.io": {Username: "bob", Password: "hunter2"},
+	}
+
+	updated := Redacted(current)
+	updated.Registries["docker.io"] = RegistryCredentials{Username: "bob"}
+	updated.Registries["ghcr.io"] = RegistryCredentials{Username: "carol", Token: "ghp_new"}
+	updated.Registries["quay.io"] = RegistryCredentials{Username: "dave", Password: RedactedSecret}
+
+	KeepSecrets(current, updated)
+	assert.Equal(t, "key-passphrase", updated.Podman.Passphrase)
+	assert.Equal(t, RegistryCredentials{Username: "bob", Password: "hunter2"}, updated.Registries["docker.io"])
+	assert.Equal(t, RegistryCredentials{Username: "carol", Token: "ghp_new"}, updated.Registries["ghcr.io"])
+	// New entries have no current secret to keep
+	assert.Equal(t, RegistryCredentials{Username: "dave", Password: RedactedSecret}, updated.Registries["quay.io"])
+}
+
+func TestDiffRedactsSecrets(t *testing.T) {
+	oldConfig := DefaultConfig()
+	oldConfig.Podman.Passphrase = "old-passphrase"
+	oldConfig.Registries = map[string]RegistryCredentials{"docker.io": {Username: "bob", Password: "hunter2"}}
+
+	newConfig := DefaultConfig()
+	newConfig.Podman.Passphrase = "new-passphrase"
+	newConfig.Registries = map[string]RegistryCredentials{"ghcr.io": {Username: "alice", Token: "ghp_token"}}
+
+	assert.Equal(t, []Change{
+		{Path: "podman.passphrase", Old: RedactedSecret, New: RedactedSecret},
+		{Path: "registries.docker.io", Old: RegistryCredentials{Username: "bob", Password: RedactedSecret}},
+		{Path: "registries.ghcr.io", New: RegistryCredentials{Username: "alice", Token: RedactedSecret}},
+	}, Diff(oldConfig, newConfig))
+}
+
+func TestUpdateConfigKeepsRedactedSecrets(t *testing.T) {
+	manager, err := NewManager(filepath.Join(t.TempDir(), "gintainer.yaml"))
+	require.NoError(t, err)
+	defer manager.Close()
+
+	cfg := DefaultConfig()
+	cfg.Registries = map[string]RegistryCredentials{"docker.io": {Username: "bob", Password: "hunter2"}}
+	require.NoError(t, manager.UpdateConfig(cfg))
+
+	// Saving the config as returned by the API keeps the password
+	updated := Redacted(manager.GetConfig())
+	updated.UI.Title = "Renamed"
+	require.NoError(t, manager.UpdateConfig(updated))
+	assert.Equal(t, "hunter2", manager.GetConfig().Registries["docker.io"].Password)
+
+	changes, err := manager.PatchConfig([]byte(`{"registries": {"docker.io": {"password": "********"}}}`))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, "hunter2", manager.GetConfig().Registries["docker.io"].Password)
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultBulkConcurrency is the number of containers a bulk action works on at the same time
+const defaultBulkConcurrency = 4
+
+// BulkContainerAction handles POST /api/containers/bulk
+func (h *Handler) BulkContainerAction(c *gin.Context) {
+	var req models.BulkActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Runtime == "" {
+		req.Runtime = "docker"
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(req.Runtime)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	concurrency := req.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	logger.Info("BulkContainerAction: Running bulk action", "action", req.Action, "runtime", req.Runtime, "count", len(req.ContainerIDs), "concurrency", concurrency)
+
+	ctx := c.Request.Context()
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		results   = make(map[string]string, len(req.ContainerIDs))
+		succeeded []string
+		failed    int
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, containerID := range req.ContainerIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(containerID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := runBulkAction(ctx, rt, req, containerID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.Error("BulkContainerAction: Action failed", "action", req.Action, "id", containerID, "error", err)
+				results[containerID] = err.Error()
+				failed++
+				return
+			}
+			results[containerID] = "success"
+			succeeded = append(succeeded, containerID)
+		}(containerID)
+	}
+	wg.Wait()
+
+	h.syncBulkCaddyfiles(ctx, rt, req.Action, succeeded)
+
+	logger.Info("BulkContainerAction: Finished bulk action", "action", req.Action, "succeeded", len(succeeded), "failed", failed)
+	c.JSON(http.StatusOK, gin.H{"results": results, "failed": failed})
+}
+
+// runBulkAction runs a single bulk action against one container
+func runBulkAction(ctx context.Context, rt runtime.ContainerRuntime, req models.BulkActionRequest, containerID string) error {
+	switch req.Action {
+	case "start":
+		return rt.StartContainer(ctx, containerID)
+	case "stop":
+		return rt.StopContainer(ctx, containerID, req.Timeout)
+	case "restart":
+		return rt.RestartContainer(ctx, containerID, req.Timeout)
+	case "delete":
+		return rt.DeleteContainer(ctx, containerID, req.Force)
+	default:
+		return fmt.Errorf("unsupported action %q", req.Action)
+	}
+}
+
+// syncBulkCaddyfiles updates Caddy for the containers a bulk action succeeded on,
+// the same way the single-container start, stop and delete handlers do
+func (h *Handler) syncBulkCaddyfiles(ctx context.Context, rt runtime.ContainerRuntime, action string, containerIDs []string) {
+	if h.caddyService == nil || !h.caddyService.IsEnabled() || len(containerIDs) == 0 {
+		return
+	}
+
+	switch action {
+	case "stop", "delete":
+		for _, containerID := range containerIDs {
+			if err := h.caddyService.DeleteCaddyfile(ctx, containerID); err != nil {
+				logger.Warn("BulkContainerAction: Failed to delete Caddyfile", "id", containerID, "error", err)
+			}
+		}
+	case "start":
+		containers, err := rt.ListContainers(ctx, models.FilterOptions{IncludeNetwork: true})
+		if err != nil {
+			logger.Warn("BulkContainerAction: Failed to list containers for Caddy", "error", err)
+			return
+		}
+
+		started := make(map[string]bool, len(containerIDs))
+		for _, containerID := range containerIDs {
+			started[containerID] = true
+		}
+		for _, container := range containers {
+			if !started[container.ID] {
+				continue
+			}
+			if err := h.caddyService.GenerateCaddyfile(ctx, container); err != nil {
+				logger.Warn("BulkContainerAction: Failed to generate Caddyfile", "id", container.ID, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBulkRouter(mock *mockRuntime) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/containers/bulk", handler.BulkContainerAction)
+	return router
+}
+
+func postBulk(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/containers/bulk", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBulkContainerAction(t *testing.T) {
+	mock := &mockRuntime{
+		name:     "docker",
+		failures: map[string]error{"b": errors.New("no such container")},
+	}
+	router := newBulkRouter(mock)
+
+	w := postBulk(router, `{"runtime":"docker","action":"stop","container_ids":["a","b","c"],"timeout":30,"concurrency":2}`)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Results map[string]string `json:"results"`
+		Failed  int               `json:"failed"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]string{"a": "success", "b": "no such container", "c": "success"}, response.Results)
+	assert.Equal(t, 1, response.Failed)
+
+	assert.ElementsMatch(t, []string{"stop a", "stop b", "stop c"}, mock.actions)
+	for _, timeout := range mock.stopTimeouts {
+		require.NotNil(t, timeout)
+		assert.Equal(t, 30, *timeout)
+	}
+}
+
+func TestBulkContainerActionInvalidRequest(t *testing.T) {
+	router := newBulkRouter(&mockRuntime{name: "docker"})
+
+	for _, body := range []string{
+		`{"action":"pause","container_ids":["a"]}`,
+		`{"action":"stop","container_ids":[]}`,
+		`{"action":"stop","container_ids":["a"],"concurrency":0,"timeout":-1}`,
+		`{"runtime":"podman","action":"stop","container_ids":["a"]}`,
+	} {
+		w := postBulk(router, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
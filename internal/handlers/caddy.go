@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/ThraaxSession/gintainer/internal/caddy"
@@ -73,6 +74,10 @@ func (h *CaddyHandler) UpdateCaddyfile(c *gin.Context) {
 	}
 
 	if err := h.caddyService.SetCaddyfileContent(c.Request.Context(), containerID, req.Content); err != nil {
+		if errors.Is(err, caddy.ErrInvalidCaddyfile) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -112,6 +117,25 @@ func (h *CaddyHandler) ReloadCaddy(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Caddy reloaded successfully"})
 }
 
+// ValidateCaddy handles POST /api/caddy/validate
+func (h *CaddyHandler) ValidateCaddy(c *gin.Context) {
+	if !h.caddyService.IsEnabled() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Caddy integration is not enabled"})
+		return
+	}
+
+	if err := h.caddyService.Validate(c.Request.Context()); err != nil {
+		if errors.Is(err, caddy.ErrInvalidCaddyfile) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"valid": false, "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"valid": true, "message": "Caddyfiles are valid"})
+}
+
 // GetStatus handles GET /api/caddy/status
 func (h *CaddyHandler) GetStatus(c *gin.Context) {
 	enabled := h.caddyService.IsEnabled()
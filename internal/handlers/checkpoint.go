@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// checkpointSuffix is the file extension of exported checkpoint archives
+const checkpointSuffix = ".tar.gz"
+
+// errInvalidCheckpointName is returned for checkpoint names that would resolve outside the checkpoint directory
+var errInvalidCheckpointName = errors.New("invalid checkpoint name")
+
+// checkpointContainerID matches container IDs and names that can be used in a file name
+var checkpointContainerID = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// checkpointBasePath returns the directory checkpoint archives are stored in
+func (h *Handler) checkpointBasePath() string {
+	basePath := h.configManager.GetConfig().Checkpoint.BasePath
+	if basePath == "" {
+		basePath = "./checkpoints"
+	}
+	return basePath
+}
+
+// checkpointFile resolves the path of the named checkpoint archive, making sure it is a direct child of basePath
+func checkpointFile(basePath, name string) (string, error) {
+	base, err := filepath.Abs(basePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve checkpoint directory: %w", err)
+	}
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", errInvalidCheckpointName
+	}
+	return filepath.Join(base, name), nil
+}
+
+// CheckpointContainer handles POST /api/containers/:id/checkpoint
+// The container is stopped and its checkpoint stored as "<id>-<timestamp>.tar.gz" in the checkpoint directory.
+func (h *Handler) CheckpointContainer(c *gin.Context) {
+	containerID := c.Param("id")
+	runtimeName := c.DefaultQuery("runtime", "podman")
+
+	if !checkpointContainerID.MatchString(containerID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid container ID"})
+		return
+	}
+
+	rt, ok := h.capableRuntime(c, runtimeName, "checkpoints", func(caps models.RuntimeCapabilities) bool { return caps.Checkpoint })
+	if !ok {
+		return
+	}
+
+	basePath := h.checkpointBasePath()
+	if err := os.MkdirAll(basePath, 0700); err != nil {
+		logger.Error("CheckpointContainer: Failed to create checkpoint directory", "path", basePath, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := containerID + "-" + time.Now().UTC().Format("20060102-150405") + checkpointSuffix
+	exportPath, err := checkpointFile(basePath, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("CheckpointContainer: Checkpointing container", "id", containerID, "runtime", runtimeName, "path", exportPath)
+	if err := rt.CheckpointContainer(c.Request.Context(), containerID, exportPath); err != nil {
+		if errors.Is(err, runtime.ErrContainerNotRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("CheckpointContainer: Failed to checkpoint container", "id", containerID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("CheckpointContainer: Successfully checkpointed container", "id", containerID, "checkpoint", name)
+	c.JSON(http.StatusOK, gin.H{"message": "container checkpointed successfully", "checkpoint": name})
+}
+
+// RestoreContainer handles POST /api/containers/restore
+func (h *Handler) RestoreContainer(c *gin.Context) {
+	var req models.RestoreContainerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("RestoreContainer: Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Runtime == "" {
+		req.Runtime = "podman"
+	}
+
+	rt, ok := h.capableRuntime(c, req.Runtime, "checkpoints", func(caps models.RuntimeCapabilities) bool { return caps.Checkpoint })
+	if !ok {
+		return
+	}
+
+	importPath, err := checkpointFile(h.checkpointBasePath(), req.Checkpoint)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if info, err := os.Stat(importPath); err != nil || info.IsDir() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "checkpoint not found"})
+		return
+	}
+
+	logger.Info("RestoreContainer: Restoring container", "checkpoint", req.Checkpoint, "runtime", req.Runtime)
+	containerID, err := rt.RestoreContainer(c.Request.Context(), importPath)
+	if err != nil {
+		logger.Error("RestoreContainer: Failed to restore container", "checkpoint", req.Checkpoint, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("RestoreContainer: Successfully restored container", "id", containerID, "checkpoint", req.Checkpoint)
+	c.JSON(http.StatusOK, gin.H{"message": "container restored successfully", "container_id": containerID})
+}
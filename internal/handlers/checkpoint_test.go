@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCheckpointTestRouter returns a router whose checkpoints are stored in a temp directory
+func newCheckpointTestRouter(t *testing.T, mocks ...*mockRuntime) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tmpDir := t.TempDir()
+	configManager, err := config.NewManager(filepath.Join(tmpDir, "test-config.yaml"))
+	require.NoError(t, err)
+	t.Cleanup(func() { configManager.Close() })
+
+	basePath := filepath.Join(tmpDir, "checkpoints")
+	cfg := config.DefaultConfig()
+	cfg.Checkpoint.BasePath = basePath
+	require.NoError(t, configManager.UpdateConfig(cfg))
+
+	handler := NewHandler(newMockManager(mocks...), caddy.NewService(&config.CaddyConfig{Enabled: false}), configManager)
+	router := gin.New()
+	router.POST("/api/containers/restore", handler.RestoreContainer)
+	router.POST("/api/containers/:id/checkpoint", handler.CheckpointContainer)
+	return router, basePath
+}
+
+func TestCheckpointFile(t *testing.T) {
+	base := t.TempDir()
+
+	path, err := checkpointFile(base, "web-20240101-120000.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "web-20240101-120000.tar.gz"), path)
+
+	for _, name := range []string{"", ".", "..", "../web.tar.gz", "a/b.tar.gz", `a\b.tar.gz`} {
+		_, err := checkpointFile(base, name)
+		assert.ErrorIs(t, err, errInvalidCheckpointName, name)
+	}
+}
+
+func TestCheckpointAndRestoreContainer(t *testing.T) {
+	mock := &mockRuntime{name: "podman", capabilities: models.RuntimeCapabilities{Pods: true, Checkpoint: true}}
+	router, basePath := newCheckpointTestRouter(t, mock)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/containers/web/checkpoint?runtime=podman", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var checkpointResp struct {
+		Checkpoint string `json:"checkpoint"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &checkpointResp))
+	assert.True(t, strings.HasPrefix(checkpointResp.Checkpoint, "web-"))
+	assert.True(t, strings.HasSuffix(checkpointResp.Checkpoint, checkpointSuffix))
+	assert.FileExists(t, filepath.Join(basePath, checkpointResp.Checkpoint))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/containers/restore", strings.NewReader(`{"checkpoint": "`+checkpointResp.Checkpoint+`"}`))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var restoreResp struct {
+		ContainerID string `json:"container_id"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restoreResp))
+	assert.Equal(t, "restored-id", restoreResp.ContainerID)
+	assert.Equal(t, []string{"checkpoint web", "restore " + checkpointResp.Checkpoint}, mock.actions)
+}
+
+func TestCheckpointContainerErrors(t *testing.T) {
+	docker := &mockRuntime{name: "docker"}
+	podman := &mockRuntime{
+		name:         "podman",
+		capabilities: models.RuntimeCapabilities{Pods: true, Checkpoint: true},
+		failures:     map[string]error{"stopped": runtime.ErrContainerNotRunning},
+	}
+	router, basePath := newCheckpointTestRouter(t, docker, podman)
+	require.NoError(t, os.MkdirAll(filepath.Join(basePath, "dir.tar.gz"), 0700))
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "unsupported runtime", path: "/api/containers/web/checkpoint?runtime=docker", wantStatus: http.StatusBadRequest},
+		{name: "unknown runtime", path: "/api/containers/web/checkpoint?runtime=lxc", wantStatus: http.StatusBadRequest},
+		{name: "invalid container id", path: "/api/containers/.hidden/checkpoint", wantStatus: http.StatusBadRequest},
+		{name: "container not running", path: "/api/containers/stopped/checkpoint", wantStatus: http.StatusConflict},
+		{name: "restore unsupported runtime", path: "/api/containers/restore", body: `{"checkpoint": "web.tar.gz", "runtime": "docker"}`, wantStatus: http.StatusBadRequest},
+		{name: "restore traversal", path: "/api/containers/restore", body: `{"checkpoint": "../test-config.yaml"}`, wantStatus: http.StatusBadRequest},
+		{name: "restore missing checkpoint", path: "/api/containers/restore", body: `{"checkpoint": "missing.tar.gz"}`, wantStatus: http.StatusNotFound},
+		{name: "restore directory", path: "/api/containers/restore", body: `{"checkpoint": "dir.tar.gz"}`, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+		})
+	}
+	assert.Empty(t, docker.actions)
+}
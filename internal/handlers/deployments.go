@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// errInvalidDeploymentName is returned for deployment names that would resolve outside the base path
+var errInvalidDeploymentName = errors.New("invalid deployment name")
+
+// deploymentBasePath returns the directory compose deployments are stored in
+func (h *Handler) deploymentBasePath() string {
+	basePath := h.configManager.GetConfig().Deployment.BasePath
+	if basePath == "" {
+		basePath = "./deployments"
+	}
+	return basePath
+}
+
+// deploymentDir resolves the directory of the named deployment, making sure it is a direct child of basePath
+func deploymentDir(basePath, name string) (string, error) {
+	base, err := filepath.Abs(basePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve deployment base path: %w", err)
+	}
+
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", errInvalidDeploymentName
+	}
+
+	// The cleaned path must still be a direct child of base
+	dir := filepath.Join(base, name)
+	if rel, err := filepath.Rel(base, dir); err != nil || rel != name {
+		return "", errInvalidDeploymentName
+	}
+	return dir, nil
+}
+
+// deploymentRuntime returns runtimeName, or without one the runtime recorded in the deployment's manifest (Docker if unknown)
+func deploymentRuntime(ctx context.Context, dir, runtimeName string) string {
+	if runtimeName != "" {
+		return runtimeName
+	}
+	if manifest, err := runtime.ReadDeploymentManifest(ctx, dir); err == nil && manifest.Runtime != "" {
+		return manifest.Runtime
+	}
+	return "docker"
+}
+
+// ListDeployments handles GET /api/deployments
+func (h *Handler) ListDeployments(c *gin.Context) {
+	basePath := h.deploymentBasePath()
+
+	entries, err := os.ReadDir(basePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Error("ListDeployments: Failed to read deployment directory", "path", basePath, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// ReadDir returns the entries sorted by name
+	deployments := make([]models.DeploymentManifest, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		manifest, err := runtime.ReadDeploymentManifest(c.Request.Context(), filepath.Join(basePath, entry.Name()))
+		if err != nil {
+			logger.Warn("ListDeployments: Skipping unreadable deployment", "name", entry.Name(), "error", err)
+			continue
+		}
+		deployments = append(deployments, manifest)
+	}
+
+	logger.Info("ListDeployments: Successfully listed deployments", "count", len(deployments))
+	c.JSON(http.StatusOK, gin.H{"deployments": deployments})
+}
+
+// DeleteDeployment handles DELETE /api/deployments/:name
+func (h *Handler) DeleteDeployment(c *gin.Context) {
+	name := c.Param("name")
+	runtimeName := c.Query("runtime")
+	removeVolumes := c.Query("volumes") == "true"
+
+	logger.Info("DeleteDeployment: Request to delete deployment", "name", name, "volumes", removeVolumes)
+
+	dir, err := deploymentDir(h.deploymentBasePath(), name)
+	if err != nil {
+		logger.Error("DeleteDeployment: Invalid deployment name", "name", name, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		logger.Error("DeleteDeployment: Deployment not found", "name", name)
+		c.JSON(http.StatusNotFound, gin.H{"error": "deployment not found"})
+		return
+	}
+
+	runtimeName = deploymentRuntime(c.Request.Context(), dir, runtimeName)
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("DeleteDeployment: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	logger.Info("DeleteDeployment: Removing deployment", "name", name, "runtime", runtimeName)
+	if err := rt.RemoveComposeDeployment(c.Request.Context(), name, dir, removeVolumes); err != nil {
+		logger.Error("DeleteDeployment: Failed to remove deployment", "name", name, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		logger.Error("DeleteDeployment: Failed to remove deployment directory", "path", dir, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to remove deployment directory: %v", err)})
+		return
+	}
+
+	logger.Info("DeleteDeployment: Successfully deleted deployment", "name", name)
+	c.JSON(http.StatusOK, gin.H{"message": "deployment deleted successfully"})
+}
+
+// ScaleDeployment handles POST /api/deployments/:name/scale
+func (h *Handler) ScaleDeployment(c *gin.Context) {
+	name := c.Param("name")
+
+	var req models.ComposeScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("ScaleDeployment: Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dir, err := deploymentDir(h.deploymentBasePath(), name)
+	if err != nil {
+		logger.Error("ScaleDeployment: Invalid deployment name", "name", name, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		logger.Error("ScaleDeployment: Deployment not found", "name", name)
+		c.JSON(http.StatusNotFound, gin.H{"error": "deployment not found"})
+		return
+	}
+
+	runtimeName := deploymentRuntime(c.Request.Context(), dir, req.Runtime)
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("ScaleDeployment: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	logger.Info("ScaleDeployment: Scaling deployment", "name", name, "service", req.Service, "replicas", *req.Replicas, "runtime", runtimeName)
+	if err := rt.ComposeScale(c.Request.Context(), name, dir, req.Service, *req.Replicas); err != nil {
+		if errors.Is(err, runtime.ErrInvalidCompose) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("ScaleDeployment: Failed to scale deployment", "name", name, "service", req.Service, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("ScaleDeployment: Successfully scaled deployment", "name", name, "service", req.Service, "replicas", *req.Replicas)
+	c.JSON(http.StatusOK, gin.H{"message": "deployment scaled successfully", "service": req.Service, "replicas": *req.Replicas})
+}
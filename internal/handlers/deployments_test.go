@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDeploymentTestHandler returns a handler whose deployments are stored in a temp directory
+func newDeploymentTestHandler(t *testing.T, mock *mockRuntime) (*Handler, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	configManager, err := config.NewManager(filepath.Join(tmpDir, "test-config.yaml"))
+	require.NoError(t, err)
+	t.Cleanup(func() { configManager.Close() })
+
+	basePath := filepath.Join(tmpDir, "deployments")
+	cfg := config.DefaultConfig()
+	cfg.Deployment.BasePath = basePath
+	require.NoError(t, configManager.UpdateConfig(cfg))
+
+	return NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), configManager), basePath
+}
+
+func TestDeploymentDir(t *testing.T) {
+	base := t.TempDir()
+
+	dir, err := deploymentDir(base, "web")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "web"), dir)
+
+	for _, name := range []string{"", ".", "..", "../etc", "a/b", `a\b`} {
+		_, err := deploymentDir(base, name)
+		assert.ErrorIs(t, err, errInvalidDeploymentName, name)
+	}
+}
+
+func TestDeleteDeployment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{name: "docker", failures: map[string]error{"broken": errors.New("compose down failed")}}
+	handler, basePath := newDeploymentTestHandler(t, mock)
+	for _, name := range []string{"web", "broken"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(basePath, name), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(basePath, name, "docker-compose.yml"), []byte("services: {}"), 0644))
+	}
+
+	router := gin.New()
+	router.DELETE("/api/deployments/:name", handler.DeleteDeployment)
+
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{"/api/deployments/web?volumes=true", http.StatusOK},
+		{"/api/deployments/web", http.StatusNotFound},
+		{"/api/deployments/missing", http.StatusNotFound},
+		{"/api/deployments/broken", http.StatusInternalServerError},
+		{"/api/deployments/broken?runtime=unknown", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", tt.path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.expected, w.Code, tt.path)
+	}
+
+	assert.Equal(t, []string{"down web", "down broken"}, mock.actions)
+	assert.NoDirExists(t, filepath.Join(basePath, "web"))
+	// A failed compose down keeps the directory, so the deletion can be retried
+	assert.DirExists(t, filepath.Join(basePath, "broken"))
+}
+
+func TestListDeployments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, basePath := newDeploymentTestHandler(t, &mockRuntime{name: "docker"})
+
+	router := gin.New()
+	router.GET("/api/deployments", handler.ListDeployments)
+
+	// A missing base path means there are no deployments yet
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/deployments", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"deployments": []}`, w.Body.String())
+
+	writeDeployment(t, basePath, "api", `{"project_name": "api", "runtime": "podman", "services": ["app"], "created_at": "2024-05-01T10:00:00Z"}`)
+	writeDeployment(t, basePath, "legacy", "")
+	writeDeployment(t, basePath, "broken", `{not json`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/deployments", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Deployments []models.DeploymentManifest `json:"deployments"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Deployments, 2)
+	assert.Equal(t, "api", response.Deployments[0].ProjectName)
+	assert.Equal(t, "podman", response.Deployments[0].Runtime)
+	assert.Equal(t, "legacy", response.Deployments[1].ProjectName)
+	assert.Equal(t, []string{"web"}, response.Deployments[1].Services)
+}
+
+func TestDeleteDeploymentUsesManifestRuntime(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker"}
+	podman := &mockRuntime{name: "podman"}
+	handler, basePath := newDeploymentTestHandler(t, docker)
+	handler.runtimeManager.RegisterRuntime("podman", podman)
+	writeDeployment(t, basePath, "api", `{"project_name": "api", "runtime": "podman", "services": ["app"]}`)
+
+	router := gin.New()
+	router.DELETE("/api/deployments/:name", handler.DeleteDeployment)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/deployments/api", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, docker.actions)
+	assert.Equal(t, []string{"down api"}, podman.actions)
+}
+
+func TestScaleDeployment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker"}
+	podman := &mockRuntime{name: "podman"}
+	handler, basePath := newDeploymentTestHandler(t, docker)
+	handler.runtimeManager.RegisterRuntime("podman", podman)
+	writeDeployment(t, basePath, "api", `{"project_name": "api", "runtime": "podman", "services": ["web"]}`)
+	writeDeployment(t, basePath, "legacy", "")
+
+	router := gin.New()
+	router.POST("/api/deployments/:name/scale", handler.ScaleDeployment)
+
+	tests := []struct {
+		path     string
+		body     string
+		expected int
+	}{
+		{"/api/deployments/api/scale", `{"service": "web", "replicas": 3}`, http.StatusOK},
+		{"/api/deployments/legacy/scale", `{"service": "web", "replicas": 0}`, http.StatusOK},
+		{"/api/deployments/api/scale", `{"service": "db", "replicas": 2}`, http.StatusBadRequest},
+		{"/api/deployments/api/scale", `{"service": "web", "replicas": -1}`, http.StatusBadRequest},
+		{"/api/deployments/api/scale", `{"service": "web"}`, http.StatusBadRequest},
+		{"/api/deployments/api/scale", `{"service": "web", "replicas": 1, "runtime": "unknown"}`, http.StatusBadRequest},
+		{"/api/deployments/missing/scale", `{"service": "web", "replicas": 1}`, http.StatusNotFound},
+		{"/api/deployments/../scale", `{"service": "web", "replicas": 1}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.expected, w.Code, tt.body)
+	}
+
+	// Without a runtime in the request the manifest decides, legacy deployments use Docker
+	assert.Equal(t, []string{"scale web=3 api", "scale db=2 api"}, podman.actions)
+	assert.Equal(t, []string{"scale web=0 legacy"}, docker.actions)
+}
+
+// writeDeployment creates a deployment directory with a compose file and, if not empty, a manifest
+func writeDeployment(t *testing.T, basePath, name, manifest string) {
+	t.Helper()
+	dir := filepath.Join(basePath, name)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte("services:\n  web:\n    image: nginx\n"), 0644))
+	if manifest != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, runtime.DeploymentManifestFile), []byte(manifest), 0644))
+	}
+}
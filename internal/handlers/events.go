@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// StreamEvents handles GET /api/events - streams container events from the runtimes via SSE
+func (h *Handler) StreamEvents(c *gin.Context) {
+	logger.Info("StreamEvents: Client connected for event streaming", "client_ip", c.ClientIP())
+
+	runtimes, ok := h.selectRuntimes(c.DefaultQuery("runtime", "all"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Fan the events of all selected runtimes into a single channel
+	merged := make(chan models.RuntimeEvent)
+	var wg sync.WaitGroup
+	for name, rt := range runtimes {
+		events, err := rt.StreamEvents(ctx)
+		if err != nil {
+			logger.Warn("StreamEvents: Failed to subscribe to runtime events", "runtime", name, "error", err)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := range events {
+				select {
+				case merged <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("StreamEvents: Client disconnected", "client_ip", c.ClientIP())
+			return
+		case event, ok := <-merged:
+			if !ok {
+				logger.Info("StreamEvents: All runtime event streams ended")
+				return
+			}
+			c.SSEvent("container", event)
+			c.Writer.Flush()
+		case <-ticker.C:
+			// Send heartbeat to keep connection alive
+			c.SSEvent("heartbeat", "ping")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// eventReconnectDelay is the wait before an ended event stream is opened again
+const eventReconnectDelay = 5 * time.Second
+
+// followEvents calls handle for every event of a runtime until ctx is cancelled, reopening the stream
+// when it ends. ended is called each time a stream ended, events in between are missed.
+func followEvents(ctx context.Context, caller, runtimeName string, rt runtime.ContainerRuntime, handle func(models.RuntimeEvent), ended func()) {
+	for ctx.Err() == nil {
+		events, err := rt.StreamEvents(ctx)
+		if err != nil {
+			logger.Warn(caller+": Failed to stream events", "runtime", runtimeName, "error", err)
+		} else {
+			for event := range events {
+				handle(event)
+			}
+		}
+		if ended != nil {
+			ended()
+		}
+
+		timer := time.NewTimer(eventReconnectDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
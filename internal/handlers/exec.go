@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// defaultExecCommand is run when an exec request names no command
+var defaultExecCommand = []string{"/bin/sh"}
+
+// execControlMessage is a JSON control message sent in a text frame over the exec WebSocket.
+// Terminal input and output travel in binary frames.
+type execControlMessage struct {
+	Type string `json:"type"`           // "input" or "resize" from the client, "exit" from the server
+	Data string `json:"data,omitempty"` // Terminal input of an "input" message
+	Rows uint   `json:"rows,omitempty"` // Terminal size of a "resize" message
+	Cols uint   `json:"cols,omitempty"`
+	Code *int   `json:"code,omitempty"` // Exit code of an "exit" message, if known
+}
+
+// wsFrame is a received WebSocket frame
+type wsFrame struct {
+	data []byte
+	text bool
+}
+
+// wsFrameCodec receives frames together with their payload type
+var wsFrameCodec = websocket.Codec{
+	Unmarshal: func(data []byte, payloadType byte, v interface{}) error {
+		frame := v.(*wsFrame)
+		frame.data = data
+		frame.text = payloadType == websocket.TextFrame
+		return nil
+	},
+}
+
+// ExecWebSocket handles GET /api/containers/:id/exec/ws.
+// It starts a command with a TTY in the container and attaches it to the WebSocket:
+// binary frames are terminal input and output, text frames carry execControlMessage JSON.
+// The socket is closed after an "exit" message when the command ends; closing the socket
+// ends the session.
+func (h *Handler) ExecWebSocket(c *gin.Context) {
+	containerID := c.Param("id")
+	runtimeName := c.Query("runtime")
+
+	logger.Info("ExecWebSocket: Request to attach to container", "id", containerID, "runtime", runtimeName, "client_ip", c.ClientIP())
+
+	if runtimeName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "runtime parameter is required"})
+		return
+	}
+	if !c.IsWebsocket() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "websocket upgrade required"})
+		return
+	}
+	if !allowWebSocketOrigin(c) {
+		logger.Warn("ExecWebSocket: Rejected cross-origin request", "origin", c.GetHeader("Origin"))
+		c.JSON(http.StatusForbidden, gin.H{"error": "origin not allowed"})
+		return
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	opts, err := execOptionsFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := rt.ExecInteractive(c.Request.Context(), containerID, opts)
+	if err != nil {
+		if errors.Is(err, runtime.ErrContainerNotRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("ExecWebSocket: Failed to start exec session", "id", containerID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The session is started first, so start errors get an HTTP status. It is closed here if
+	// the WebSocket handshake fails, as the handler that pipes and closes it never runs then.
+	logger.Info("ExecWebSocket: Exec session started", "id", containerID, "cmd", opts.Cmd)
+	attached := false
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		attached = true
+		pipeExecSession(ws, session)
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+	if !attached {
+		logger.Warn("ExecWebSocket: WebSocket handshake failed, closing exec session", "id", containerID)
+		session.Close()
+	}
+	logger.Info("ExecWebSocket: Exec session ended", "id", containerID)
+}
+
+// execOptionsFromQuery reads the cmd (repeatable), user, workdir, rows and cols query params
+func execOptionsFromQuery(c *gin.Context) (models.ExecOptions, error) {
+	opts := models.ExecOptions{
+		Cmd:        c.QueryArray("cmd"),
+		User:       c.Query("user"),
+		WorkingDir: c.Query("workdir"),
+	}
+	if len(opts.Cmd) == 0 {
+		opts.Cmd = defaultExecCommand
+	}
+
+	for name, target := range map[string]*uint{"rows": &opts.Rows, "cols": &opts.Cols} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return opts, errors.New(name + " must be a positive number")
+		}
+		*target = uint(n)
+	}
+	return opts, nil
+}
+
+// allowWebSocketOrigin accepts requests without an Origin header (non-browser clients),
+// from the same host, or from an origin the CORS middleware allowed
+func allowWebSocketOrigin(c *gin.Context) bool {
+	origin := c.GetHeader("Origin")
+	if origin == "" || c.Writer.Header().Get("Access-Control-Allow-Origin") != "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, c.Request.Host)
+}
+
+// pipeExecSession copies between the WebSocket and the exec session until either side ends
+func pipeExecSession(ws *websocket.Conn, session runtime.ExecSession) {
+	defer ws.Close()
+	ctx := ws.Request().Context()
+
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := session.Read(buf)
+			if n > 0 {
+				if sendErr := websocket.Message.Send(ws, buf[:n]); sendErr != nil {
+					return
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+
+		// The command ended: report its exit code and close the socket, which ends the input loop
+		msg := execControlMessage{Type: "exit"}
+		if code, err := session.ExitCode(ctx); err == nil {
+			msg.Code = &code
+		}
+		_ = websocket.JSON.Send(ws, msg)
+		ws.Close()
+	}()
+
+	for {
+		var frame wsFrame
+		if err := wsFrameCodec.Receive(ws, &frame); err != nil {
+			break
+		}
+		if !frame.text {
+			if _, err := session.Write(frame.data); err != nil {
+				break
+			}
+			continue
+		}
+
+		var msg execControlMessage
+		if err := json.Unmarshal(frame.data, &msg); err != nil {
+			logger.Debug("ExecWebSocket: Ignoring invalid control message", "error", err)
+			continue
+		}
+		switch msg.Type {
+		case "input":
+			if _, err := session.Write([]byte(msg.Data)); err != nil {
+				logger.Debug("ExecWebSocket: Failed to write input", "error", err)
+			}
+		case "resize":
+			if msg.Rows == 0 || msg.Cols == 0 {
+				continue
+			}
+			if err := session.Resize(ctx, msg.Rows, msg.Cols); err != nil {
+				logger.Debug("ExecWebSocket: Failed to resize terminal", "rows", msg.Rows, "cols", msg.Cols, "error", err)
+			}
+		default:
+			logger.Debug("ExecWebSocket: Ignoring unknown control message", "type", msg.Type)
+		}
+	}
+
+	session.Close()
+	<-outputDone
+}
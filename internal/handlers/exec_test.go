@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// echoExecSession echoes its input back as output and ends on "exit\n"
+type echoExecSession struct {
+	output *io.PipeReader
+	input  *io.PipeWriter
+
+	mu      sync.Mutex
+	resizes [][2]uint
+	closed  bool
+}
+
+func newEchoExecSession() *echoExecSession {
+	outputReader, outputWriter := io.Pipe()
+	inputReader, inputWriter := io.Pipe()
+	s := &echoExecSession{output: outputReader, input: inputWriter}
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := inputReader.Read(buf)
+			if err != nil {
+				outputWriter.CloseWithError(err)
+				return
+			}
+			if string(buf[:n]) == "exit\n" {
+				outputWriter.Close()
+				return
+			}
+			outputWriter.Write(buf[:n])
+		}
+	}()
+	return s
+}
+
+func (s *echoExecSession) Read(p []byte) (int, error)  { return s.output.Read(p) }
+func (s *echoExecSession) Write(p []byte) (int, error) { return s.input.Write(p) }
+
+func (s *echoExecSession) Resize(ctx context.Context, rows, cols uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resizes = append(s.resizes, [2]uint{rows, cols})
+	return nil
+}
+
+func (s *echoExecSession) ExitCode(ctx context.Context) (int, error) { return 3, nil }
+
+func (s *echoExecSession) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *echoExecSession) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.input.Close()
+	s.output.Close()
+	return nil
+}
+
+// execRuntime hands out one echo session
+type execRuntime struct {
+	*mockRuntime
+	session *echoExecSession
+	opts    models.ExecOptions
+}
+
+func (m *execRuntime) ExecInteractive(ctx context.Context, containerID string, opts models.ExecOptions) (runtime.ExecSession, error) {
+	if containerID == "stopped" {
+		return nil, runtime.ErrContainerNotRunning
+	}
+	m.opts = opts
+	return m.session, nil
+}
+
+func newExecTestServer(t *testing.T, rt *execRuntime) *httptest.Server {
+	manager := runtime.NewManager()
+	manager.RegisterRuntime(rt.GetRuntimeName(), rt)
+	handler := NewHandler(manager, caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/containers/:id/exec/ws", handler.ExecWebSocket)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dialExec(server *httptest.Server, path, origin string) (*websocket.Conn, error) {
+	return websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+path, "", origin)
+}
+
+func TestExecWebSocket(t *testing.T) {
+	rt := &execRuntime{mockRuntime: &mockRuntime{name: "docker"}, session: newEchoExecSession()}
+	server := newExecTestServer(t, rt)
+
+	ws, err := dialExec(server, "/api/containers/web/exec/ws?runtime=docker&cmd=/bin/bash&cmd=-l&rows=24&cols=80", server.URL)
+	require.NoError(t, err)
+	defer ws.Close()
+	assert.Equal(t, models.ExecOptions{Cmd: []string{"/bin/bash", "-l"}, Rows: 24, Cols: 80}, rt.opts)
+
+	// Binary frames are raw input, output comes back in binary frames
+	require.NoError(t, websocket.Message.Send(ws, []byte("ls\n")))
+	var output []byte
+	require.NoError(t, websocket.Message.Receive(ws, &output))
+	assert.Equal(t, "ls\n", string(output))
+
+	// Text frames are control messages
+	require.NoError(t, websocket.JSON.Send(ws, execControlMessage{Type: "resize", Rows: 40, Cols: 120}))
+	require.NoError(t, websocket.JSON.Send(ws, execControlMessage{Type: "input", Data: "pwd\n"}))
+	require.NoError(t, websocket.Message.Receive(ws, &output))
+	assert.Equal(t, "pwd\n", string(output))
+
+	// Ending the command sends its exit code and closes the socket
+	require.NoError(t, websocket.Message.Send(ws, []byte("exit\n")))
+	var exit execControlMessage
+	require.NoError(t, websocket.JSON.Receive(ws, &exit))
+	assert.Equal(t, "exit", exit.Type)
+	require.NotNil(t, exit.Code)
+	assert.Equal(t, 3, *exit.Code)
+	assert.Error(t, websocket.Message.Receive(ws, &output))
+
+	rt.session.mu.Lock()
+	defer rt.session.mu.Unlock()
+	assert.Equal(t, [][2]uint{{40, 120}}, rt.session.resizes)
+}
+
+func TestExecWebSocketClientClose(t *testing.T) {
+	rt := &execRuntime{mockRuntime: &mockRuntime{name: "docker"}, session: newEchoExecSession()}
+	server := newExecTestServer(t, rt)
+
+	ws, err := dialExec(server, "/api/containers/web/exec/ws?runtime=docker", server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, defaultExecCommand, rt.opts.Cmd)
+	require.NoError(t, ws.Close())
+
+	assert.Eventually(t, rt.session.isClosed, time.Second, 10*time.Millisecond, "closing the socket closes the session")
+}
+
+func TestExecWebSocketErrors(t *testing.T) {
+	rt := &execRuntime{mockRuntime: &mockRuntime{name: "docker"}, session: newEchoExecSession()}
+	server := newExecTestServer(t, rt)
+
+	tests := []struct {
+		name     string
+		path     string
+		origin   string
+		expected int
+	}{
+		{"missing runtime", "/api/containers/web/exec/ws", server.URL, http.StatusBadRequest},
+		{"invalid runtime", "/api/containers/web/exec/ws?runtime=unknown", server.URL, http.StatusBadRequest},
+		{"invalid size", "/api/containers/web/exec/ws?runtime=docker&rows=tall", server.URL, http.StatusBadRequest},
+		{"not running", "/api/containers/stopped/exec/ws?runtime=docker", server.URL, http.StatusConflict},
+		{"cross origin", "/api/containers/web/exec/ws?runtime=docker", "http://evil.example.com", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dialExec(server, tt.path, tt.origin)
+			var dialErr *websocket.DialError
+			require.ErrorAs(t, err, &dialErr)
+			assert.ErrorIs(t, dialErr.Err, websocket.ErrBadStatus)
+		})
+	}
+
+	// Plain HTTP requests are rejected
+	resp, err := http.Get(server.URL + "/api/containers/web/exec/ws?runtime=docker")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.False(t, rt.session.isClosed())
+
+	// A failed handshake closes the session that was already started
+	req, err := http.NewRequest("GET", server.URL+"/api/containers/web/exec/ws?runtime=docker", nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "7")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.NotEqual(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.True(t, rt.session.isClosed())
+}
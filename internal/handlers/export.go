@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// exportCSVHeader lists the columns of the CSV container export
+var exportCSVHeader = []string{"id", "name", "image", "state", "runtime", "ports", "labels"}
+
+// ExportContainers handles GET /api/containers/export?format=csv|json.
+// It takes the same filters as ListContainers and returns all matching containers
+// across runtimes, as a CSV download or as indented JSON (the default).
+func (h *Handler) ExportContainers(c *gin.Context) {
+	logger.Info("ExportContainers: Received request from", "client_ip", c.ClientIP())
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+
+	var filters models.FilterOptions
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		logger.Error("ExportContainers: Failed to bind query parameters", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filters.Runtime == "" {
+		filters.Runtime = "all"
+	}
+
+	containers, err := h.collectContainers(c.Request.Context(), filters)
+	if errors.Is(err, errInvalidRuntime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if containers == nil {
+		containers = []models.ContainerInfo{}
+	}
+	sortContainers(containers, filters.Sort, filters.Order)
+
+	logger.Info("ExportContainers: Exporting containers", "format", format, "count", len(containers))
+	if format == "json" {
+		c.IndentedJSON(http.StatusOK, gin.H{"containers": containers, "total": len(containers)})
+		return
+	}
+
+	data, err := containersCSV(containers)
+	if err != nil {
+		logger.Error("ExportContainers: Failed to write CSV", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("gintainer-containers-%s.csv", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// containersCSV renders containers as CSV with one row per container
+func containersCSV(containers []models.ContainerInfo) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(exportCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, container := range containers {
+		row := []string{
+			container.ID,
+			container.Name,
+			container.Image,
+			container.State,
+			container.Runtime,
+			formatPorts(container.Ports),
+			formatLabels(container.Labels),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// formatPorts flattens port mappings into "host:container/proto" entries separated by spaces.
+// Ports that are not published are written as "container/proto".
+func formatPorts(ports []models.PortMapping) string {
+	entries := make([]string, 0, len(ports))
+	for _, port := range ports {
+		entry := fmt.Sprintf("%d/%s", port.ContainerPort, port.Protocol)
+		if port.HostPort != 0 {
+			entry = fmt.Sprintf("%d:%s", port.HostPort, entry)
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, " ")
+}
+
+// formatLabels flattens labels into "key=value" entries sorted by key and separated by semicolons
+func formatLabels(labels map[string]string) string {
+	entries := make([]string, 0, len(labels))
+	for key, value := range labels {
+		entries = append(entries, key+"="+value)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ";")
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExportTestRouter() *gin.Engine {
+	docker := &mockRuntime{name: "docker", containers: []models.ContainerInfo{{
+		ID: "abc123", Name: "web", Image: "nginx:latest", State: "running", Runtime: "docker",
+		Ports:  []models.PortMapping{{ContainerPort: 80, HostPort: 8080, Protocol: "tcp"}, {ContainerPort: 443, Protocol: "tcp"}},
+		Labels: map[string]string{"caddy.domain": "example.com", "app": "web"},
+	}}}
+	podman := &mockRuntime{name: "podman", containers: []models.ContainerInfo{{
+		ID: "def456", Name: "db, primary", Image: "postgres:16", State: "exited", Runtime: "podman",
+	}}}
+	handler := NewHandler(newMockManager(docker, podman), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/containers/export", handler.ExportContainers)
+	return router
+}
+
+func TestExportContainersCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newExportTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/containers/export?format=csv", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="gintainer-containers-\d{8}-\d{6}\.csv"$`, w.Header().Get("Content-Disposition"))
+
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", "name", "image", "state", "runtime", "ports", "labels"},
+		{"def456", "db, primary", "postgres:16", "exited", "podman", "", ""},
+		{"abc123", "web", "nginx:latest", "running", "docker", "8080:80/tcp 443/tcp", "app=web;caddy.domain=example.com"},
+	}, records)
+}
+
+func TestExportContainersJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newExportTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/containers/export?runtime=docker", nil)
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "\n    \"containers\": [", "JSON is indented")
+
+	var resp struct {
+		Containers []models.ContainerInfo `json:"containers"`
+		Total      int                    `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, "web", resp.Containers[0].Name)
+}
+
+func TestExportContainersInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newExportTestRouter()
+
+	for _, path := range []string{"/api/containers/export?format=xml", "/api/containers/export?runtime=unknown"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ThraaxSession/gintainer/internal/caddy"
@@ -15,11 +21,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// errInvalidRuntime is returned when a request names a runtime that is not registered
+var errInvalidRuntime = errors.New("invalid runtime")
+
+// healthCheckTimeout bounds how long the health check waits for the runtimes to answer
+const healthCheckTimeout = 5 * time.Second
+
 // Handler manages HTTP handlers
 type Handler struct {
 	runtimeManager *runtime.Manager
 	caddyService   *caddy.Service
 	configManager  *config.Manager
+	listCache      *listCache
+
+	watchMu          sync.Mutex
+	stopRemovalWatch context.CancelFunc // Stops the removed-container watchers, nil while not watching
 }
 
 // NewHandler creates a new handler
@@ -28,6 +44,7 @@ func NewHandler(runtimeManager *runtime.Manager, caddyService *caddy.Service, co
 		runtimeManager: runtimeManager,
 		caddyService:   caddyService,
 		configManager:  configManager,
+		listCache:      newListCache(),
 	}
 }
 
@@ -47,8 +64,38 @@ func (h *Handler) ListContainers(c *gin.Context) {
 		filters.Runtime = "all"
 	}
 
-	logger.Info("ListContainers: Filters applied - Runtime: , Status: , Name", "filter1", filters.Runtime, "filter2", filters.Status, "filter3", filters.Name)
+	if (filters.Sort == "cpu" || filters.Sort == "memory") && !filters.IncludeStats {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sorting by " + filters.Sort + " requires include_stats=true"})
+		return
+	}
+
+	logger.Info("ListContainers: Filters applied - Runtime: , Status: , Name", "filter1", filters.Runtime, "filter2", filters.Status, "filter3", filters.Name, "label", filters.Label)
+
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
 
+	allContainers, err := h.cachedContainers(ctx, filters)
+	if errors.Is(err, errInvalidRuntime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.respondRuntimeError(c, ctx, err)
+		return
+	}
+
+	// Sort before paginating so pages are stable across requests
+	sortContainers(allContainers, filters.Sort, filters.Order)
+	total := len(allContainers)
+	page := paginate(allContainers, filters.Limit, filters.Offset)
+
+	logger.Info("ListContainers: Successfully retrieved containers", "count", len(page), "total", total)
+	c.JSON(http.StatusOK, gin.H{"containers": page, "total": total})
+}
+
+// collectContainers lists the containers of filters.Runtime, or of every runtime for "all".
+// With "all", runtimes that fail are logged and skipped.
+func (h *Handler) collectContainers(ctx context.Context, filters models.FilterOptions) ([]models.ContainerInfo, error) {
 	var allContainers []models.ContainerInfo
 
 	// Query specified runtime(s)
@@ -58,17 +105,40 @@ func (h *Handler) ListContainers(c *gin.Context) {
 		runtimes := h.runtimeManager.GetAllRuntimes()
 		logger.Debug("ListContainers: Available runtimes", "count", len(runtimes))
 
+		// Query the runtimes in parallel so the slowest one sets the latency, not their sum
+		var mu sync.Mutex
+		var wg sync.WaitGroup
 		for name, rt := range runtimes {
-			logger.Debug("ListContainers: Querying runtime", "name", name)
-			containers, err := rt.ListContainers(c.Request.Context(), filters)
-			if err != nil {
-				// Log error but continue with other runtimes
-				logger.Warn("ListContainers: Error querying runtime", "name", name, "error", err)
-				continue
-			}
-			logger.Debug("ListContainers: Runtime returned containers", "name", name, "count", len(containers))
-			allContainers = append(allContainers, containers...)
+			wg.Add(1)
+			go func(name string, rt runtime.ContainerRuntime) {
+				defer wg.Done()
+				// A wedged socket only drops that runtime's containers
+				rtCtx, cancel := context.WithTimeout(ctx, h.runtimeTimeout())
+				defer cancel()
+
+				logger.Debug("ListContainers: Querying runtime", "name", name)
+				containers, err := rt.ListContainers(rtCtx, filters)
+				if err != nil {
+					// Log error but continue with other runtimes
+					logger.Warn("ListContainers: Error querying runtime", "name", name, "error", err)
+					return
+				}
+				logger.Debug("ListContainers: Runtime returned containers", "name", name, "count", len(containers))
+				mu.Lock()
+				allContainers = append(allContainers, containers...)
+				mu.Unlock()
+			}(name, rt)
 		}
+		wg.Wait()
+
+		// Results arrive in any order; keep the aggregated list stable
+		sort.SliceStable(allContainers, func(i, j int) bool {
+			a, b := allContainers[i], allContainers[j]
+			if a.Runtime != b.Runtime {
+				return a.Runtime < b.Runtime
+			}
+			return a.Name < b.Name
+		})
 	} else {
 		logger.Debug("ListContainers: Querying specific runtime", "runtime", filters.Runtime)
 		// Query specific runtime
@@ -80,23 +150,20 @@ func (h *Handler) ListContainers(c *gin.Context) {
 				availableNames = append(availableNames, name)
 			}
 			logger.Error("ListContainers: Invalid runtime specified", "runtime", filters.Runtime, "available", availableNames)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
-			return
+			return nil, errInvalidRuntime
 		}
 
 		logger.Debug("ListContainers: Found runtime, listing containers")
-		containers, err := rt.ListContainers(c.Request.Context(), filters)
+		containers, err := rt.ListContainers(ctx, filters)
 		if err != nil {
 			logger.Error("ListContainers: Failed to list containers", "runtime", filters.Runtime, "error", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+			return nil, err
 		}
 		logger.Debug("ListContainers: Runtime returned containers", "count", len(containers))
 		allContainers = containers
 	}
 
-	logger.Info("ListContainers: Successfully retrieved containers", "count", len(allContainers))
-	c.JSON(http.StatusOK, gin.H{"containers": allContainers})
+	return allContainers, nil
 }
 
 // ListPods handles GET /api/pods
@@ -127,10 +194,13 @@ func (h *Handler) ListPods(c *gin.Context) {
 			return
 		}
 
-		pods, err := rt.ListPods(c.Request.Context(), filters)
+		ctx, cancel := h.runtimeContext(c, 0)
+		defer cancel()
+
+		pods, err := rt.ListPods(ctx, filters)
 		if err != nil {
 			logger.Error("ListPods: Failed to list pods", "error", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			h.respondRuntimeError(c, ctx, err)
 			return
 		}
 		allPods = pods
@@ -161,9 +231,12 @@ func (h *Handler) DeleteContainer(c *gin.Context) {
 		return
 	}
 
-	if err := rt.DeleteContainer(c.Request.Context(), containerID, force); err != nil {
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
+
+	if err := rt.DeleteContainer(ctx, containerID, force); err != nil {
 		logger.Error("DeleteContainer: Failed to delete container", "id", containerID, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondRuntimeError(c, ctx, err)
 		return
 	}
 
@@ -181,6 +254,49 @@ func (h *Handler) DeleteContainer(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "container deleted successfully"})
 }
 
+// PruneContainers handles POST /api/containers/prune - removes all stopped containers of a runtime
+func (h *Handler) PruneContainers(c *gin.Context) {
+	runtimeName := c.Query("runtime")
+
+	logger.Info("PruneContainers: Request to prune stopped containers", "runtime", runtimeName, "client_ip", c.ClientIP())
+
+	if runtimeName == "" {
+		logger.Error("PruneContainers: Runtime parameter missing")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "runtime parameter is required"})
+		return
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("PruneContainers: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	removed, reclaimed, err := rt.PruneContainers(c.Request.Context())
+	if err != nil {
+		logger.Error("PruneContainers: Failed to prune containers", "runtime", runtimeName, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if removed == nil {
+		removed = []string{}
+	}
+
+	logger.Info("PruneContainers: Pruned containers", "runtime", runtimeName, "count", len(removed), "reclaimed_bytes", reclaimed)
+
+	// Remove the Caddy configuration of the pruned containers
+	if h.caddyService != nil && h.caddyService.IsEnabled() {
+		for _, containerID := range removed {
+			if err := h.caddyService.DeleteCaddyfile(c.Request.Context(), containerID); err != nil {
+				logger.Warn("PruneContainers: Failed to delete Caddyfile", "id", containerID, "error", err)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"removed": removed, "count": len(removed), "reclaimed_bytes": reclaimed})
+}
+
 // DeletePod handles DELETE /api/pods/:id
 func (h *Handler) DeletePod(c *gin.Context) {
 	podID := c.Param("id")
@@ -196,9 +312,12 @@ func (h *Handler) DeletePod(c *gin.Context) {
 		return
 	}
 
-	if err := rt.DeletePod(c.Request.Context(), podID, force); err != nil {
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
+
+	if err := rt.DeletePod(ctx, podID, force); err != nil {
 		logger.Error("DeletePod: Failed to delete pod", "id", podID, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondRuntimeError(c, ctx, err)
 		return
 	}
 
@@ -226,9 +345,12 @@ func (h *Handler) StartContainer(c *gin.Context) {
 		return
 	}
 
-	if err := rt.StartContainer(c.Request.Context(), containerID); err != nil {
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
+
+	if err := rt.StartContainer(ctx, containerID); err != nil {
 		logger.Error("StartContainer: Failed to start container", "id", containerID, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondRuntimeError(c, ctx, err)
 		return
 	}
 
@@ -237,7 +359,7 @@ func (h *Handler) StartContainer(c *gin.Context) {
 	// Update Caddy configuration if enabled
 	if h.caddyService != nil && h.caddyService.IsEnabled() {
 		// Get container info to generate Caddyfile
-		containers, err := rt.ListContainers(c.Request.Context(), models.FilterOptions{})
+		containers, err := rt.ListContainers(c.Request.Context(), models.FilterOptions{IncludeNetwork: true})
 		if err == nil {
 			for _, container := range containers {
 				if container.ID == containerID {
@@ -275,9 +397,18 @@ func (h *Handler) StopContainer(c *gin.Context) {
 		return
 	}
 
-	if err := rt.StopContainer(c.Request.Context(), containerID); err != nil {
+	timeout, err := stopTimeoutParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := h.runtimeContext(c, stopWait(timeout))
+	defer cancel()
+
+	if err := rt.StopContainer(ctx, containerID, timeout); err != nil {
 		logger.Error("StopContainer: Failed to stop container", "id", containerID, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondRuntimeError(c, ctx, err)
 		return
 	}
 
@@ -315,9 +446,18 @@ func (h *Handler) RestartContainer(c *gin.Context) {
 		return
 	}
 
-	if err := rt.RestartContainer(c.Request.Context(), containerID); err != nil {
+	timeout, err := stopTimeoutParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := h.runtimeContext(c, stopWait(timeout))
+	defer cancel()
+
+	if err := rt.RestartContainer(ctx, containerID, timeout); err != nil {
 		logger.Error("RestartContainer: Failed to restart container", "id", containerID, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.respondRuntimeError(c, ctx, err)
 		return
 	}
 
@@ -325,6 +465,108 @@ func (h *Handler) RestartContainer(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "container restarted successfully"})
 }
 
+// KillContainer handles POST /api/containers/:id/kill?signal=SIGHUP
+// Unlike stop it only sends the signal (SIGKILL by default) and does not wait for the container to exit.
+func (h *Handler) KillContainer(c *gin.Context) {
+	containerID := c.Param("id")
+	runtimeName := c.Query("runtime")
+	signal := c.Query("signal")
+
+	logger.Info("KillContainer: Request to kill container", "id", containerID, "runtime", runtimeName, "signal", signal)
+
+	if runtimeName == "" {
+		logger.Error("KillContainer: Runtime parameter missing")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "runtime parameter is required"})
+		return
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("KillContainer: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	signal, err := runtime.NormalizeSignal(signal)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
+
+	if err := rt.KillContainer(ctx, containerID, signal); err != nil {
+		if errors.Is(err, runtime.ErrContainerNotRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("KillContainer: Failed to kill container", "id", containerID, "signal", signal, "error", err)
+		h.respondRuntimeError(c, ctx, err)
+		return
+	}
+
+	logger.Info("KillContainer: Successfully sent signal to container", "id", containerID, "signal", signal)
+	c.JSON(http.StatusOK, gin.H{"message": "signal sent successfully", "signal": signal})
+}
+
+// UpdateContainerResources handles PATCH /api/containers/:id/resources
+func (h *Handler) UpdateContainerResources(c *gin.Context) {
+	containerID := c.Param("id")
+	runtimeName := c.Query("runtime")
+
+	if runtimeName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "runtime parameter is required"})
+		return
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("UpdateContainerResources: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	var update models.ResourceUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		logger.Error("UpdateContainerResources: Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := runtime.ValidateResourceUpdate(update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("UpdateContainerResources: Updating container", "id", containerID, "runtime", runtimeName, "memory", update.Memory, "cpus", update.CPUs, "restart_policy", update.RestartPolicy)
+
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
+
+	if err := rt.UpdateContainerResources(ctx, containerID, update); err != nil {
+		logger.Error("UpdateContainerResources: Failed to update container", "id", containerID, "error", err)
+		h.respondRuntimeError(c, ctx, err)
+		return
+	}
+
+	logger.Info("UpdateContainerResources: Successfully updated container", "id", containerID)
+	c.JSON(http.StatusOK, gin.H{"message": "container updated successfully", "update": update})
+}
+
+// stopTimeoutParam parses the optional timeout query parameter (seconds to wait before killing a container)
+func stopTimeoutParam(c *gin.Context) (*int, error) {
+	value := c.Query("timeout")
+	if value == "" {
+		return nil, nil
+	}
+
+	timeout, err := strconv.Atoi(value)
+	if err != nil || timeout < 0 {
+		return nil, errors.New("timeout must be a non-negative number of seconds")
+	}
+	return &timeout, nil
+}
+
 // StartPod handles POST /api/pods/:id/start
 func (h *Handler) StartPod(c *gin.Context) {
 	podID := c.Param("id")
@@ -335,14 +577,85 @@ func (h *Handler) StartPod(c *gin.Context) {
 		return
 	}
 
-	if err := rt.StartPod(c.Request.Context(), podID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
+
+	if err := rt.StartPod(ctx, podID); err != nil {
+		h.respondRuntimeError(c, ctx, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "pod started successfully"})
 }
 
+// PodStats handles GET /api/pods/:id/stats
+func (h *Handler) PodStats(c *gin.Context) {
+	podID := c.Param("id")
+
+	rt, ok := h.runtimeManager.GetRuntime("podman")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "podman runtime not available"})
+		return
+	}
+
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
+
+	stats, err := rt.PodStats(ctx, podID)
+	if err != nil {
+		if errors.Is(err, runtime.ErrPodNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("PodStats: Failed to get pod stats", "id", podID, "error", err)
+		h.respondRuntimeError(c, ctx, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GeneratePodKube handles GET /api/pods/:id/kube
+func (h *Handler) GeneratePodKube(c *gin.Context) {
+	podID := c.Param("id")
+
+	rt, ok := h.runtimeManager.GetRuntime("podman")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "podman runtime not available"})
+		return
+	}
+
+	h.generateKube(c, rt, podID)
+}
+
+// GenerateContainerKube handles GET /api/containers/:id/kube
+func (h *Handler) GenerateContainerKube(c *gin.Context) {
+	containerID := c.Param("id")
+	runtimeName := c.DefaultQuery("runtime", "podman")
+
+	rt, ok := h.capableRuntime(c, runtimeName, "generating Kubernetes YAML", func(caps models.RuntimeCapabilities) bool { return caps.KubeExport })
+	if !ok {
+		return
+	}
+
+	h.generateKube(c, rt, containerID)
+}
+
+// generateKube responds with the Kubernetes manifest of a container or pod as a YAML download
+func (h *Handler) generateKube(c *gin.Context, rt runtime.ContainerRuntime, id string) {
+	logger.Info("GenerateKube: Generating Kubernetes YAML", "id", id, "runtime", rt.GetRuntimeName())
+
+	manifest, err := rt.GenerateKube(c.Request.Context(), []string{id})
+	if err != nil {
+		logger.Error("GenerateKube: Failed to generate Kubernetes YAML", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+".yaml"))
+	c.Data(http.StatusOK, "text/yaml; charset=utf-8", []byte(manifest))
+}
+
 // StopPod handles POST /api/pods/:id/stop
 func (h *Handler) StopPod(c *gin.Context) {
 	podID := c.Param("id")
@@ -353,8 +666,11 @@ func (h *Handler) StopPod(c *gin.Context) {
 		return
 	}
 
-	if err := rt.StopPod(c.Request.Context(), podID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, cancel := h.runtimeContext(c, stopWait(nil))
+	defer cancel()
+
+	if err := rt.StopPod(ctx, podID); err != nil {
+		h.respondRuntimeError(c, ctx, err)
 		return
 	}
 
@@ -371,8 +687,11 @@ func (h *Handler) RestartPod(c *gin.Context) {
 		return
 	}
 
-	if err := rt.RestartPod(c.Request.Context(), podID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, cancel := h.runtimeContext(c, stopWait(nil))
+	defer cancel()
+
+	if err := rt.RestartPod(ctx, podID); err != nil {
+		h.respondRuntimeError(c, ctx, err)
 		return
 	}
 
@@ -403,7 +722,12 @@ func (h *Handler) CreateContainer(c *gin.Context) {
 		return
 	}
 
-	if err := rt.BuildFromDockerfile(c.Request.Context(), req.Dockerfile, req.ImageName); err != nil {
+	buildOpts := models.BuildOptions{
+		BuildArgs: req.BuildArgs,
+		Target:    req.Target,
+	}
+
+	if err := rt.BuildFromDockerfile(c.Request.Context(), req.Dockerfile, req.ImageName, buildOpts); err != nil {
 		logger.Error("CreateContainer: Failed to build image", "name", req.ImageName, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -437,15 +761,54 @@ func (h *Handler) RunContainer(c *gin.Context) {
 		return
 	}
 
+	if err := runtime.ValidateRunOptions(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Catch host ports that are already taken before the runtime fails with a bind error
+	hostPorts, err := runtime.RequestedHostPorts(req.Ports)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if conflict := h.findPortConflict(c.Request.Context(), hostPorts); conflict != nil {
+		logger.Warn("RunContainer: Host port already in use", "port", conflict.Port, "protocol", conflict.Protocol, "container", conflict.Container, "runtime", conflict.Runtime)
+		c.JSON(http.StatusConflict, gin.H{"error": conflict.Error(), "conflict": conflict})
+		return
+	}
+
+	if !req.NoPull {
+		req.Auth = h.registryAuthFor(req.Image)
+	}
+
 	containerID, err := rt.RunContainer(c.Request.Context(), req)
 	if err != nil {
 		logger.Error("RunContainer: Failed to run container", "error", err)
+		if runtime.IsInvalidRunOption(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	logger.Info("RunContainer: Successfully created container with ID", "name", req.Name, "id", containerID)
-	c.JSON(http.StatusOK, gin.H{"message": "container created successfully", "container_id": containerID})
+	if req.Privileged {
+		logger.Warn("RunContainer: PRIVILEGED container created, it has full access to the host", "name", req.Name, "id", containerID, "image", req.Image, "runtime", req.Runtime, "client_ip", c.ClientIP())
+	}
+
+	// Report the host ports the runtime assigned, e.g. for random ports and port ranges
+	response := gin.H{"message": "container created successfully", "container_id": containerID, "privileged": req.Privileged}
+	if len(req.Ports) > 0 {
+		ports, err := rt.ContainerPorts(c.Request.Context(), containerID)
+		if err != nil {
+			logger.Warn("RunContainer: Failed to get published ports", "id", containerID, "error", err)
+		} else {
+			response["ports"] = ports
+		}
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // DeployCompose handles POST /api/compose
@@ -472,12 +835,7 @@ func (h *Handler) DeployCompose(c *gin.Context) {
 		return
 	}
 
-	// Get base path from config
-	config := h.configManager.GetConfig()
-	basePath := config.Deployment.BasePath
-	if basePath == "" {
-		basePath = "./deployments"
-	}
+	basePath := h.deploymentBasePath()
 
 	// Create deployment directory with project name or timestamp
 	projectName := req.ProjectName
@@ -488,8 +846,12 @@ func (h *Handler) DeployCompose(c *gin.Context) {
 
 	logger.Info("DeployCompose: Storing deployment at", "arg1", deploymentPath)
 
-	if err := rt.DeployFromCompose(c.Request.Context(), req.ComposeContent, projectName, deploymentPath); err != nil {
+	if err := rt.DeployFromCompose(c.Request.Context(), req.ComposeContent, projectName, deploymentPath, req.Env); err != nil {
 		logger.Error("DeployCompose: Failed to deploy compose", "error", err)
+		if errors.Is(err, runtime.ErrInvalidCompose) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -522,7 +884,7 @@ func (h *Handler) UpdateContainers(c *gin.Context) {
 
 	results := make(map[string]string)
 	for _, containerID := range req.ContainerIDs {
-		if err := rt.UpdateContainer(c.Request.Context(), containerID); err != nil {
+		if err := rt.UpdateContainer(c.Request.Context(), containerID, h.registryAuthFor); err != nil {
 			results[containerID] = err.Error()
 		} else {
 			results[containerID] = "success"
@@ -536,8 +898,10 @@ func (h *Handler) UpdateContainers(c *gin.Context) {
 func (h *Handler) StreamLogs(c *gin.Context) {
 	containerID := c.Param("id")
 	runtimeName := c.Query("runtime")
-	follow := c.Query("follow") == "true"
-	tail := c.DefaultQuery("tail", "100")
+	opts := models.LogOptions{
+		Follow: c.Query("follow") == "true",
+		Tail:   c.DefaultQuery("tail", "100"),
+	}
 
 	if runtimeName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "runtime parameter is required"})
@@ -550,11 +914,31 @@ func (h *Handler) StreamLogs(c *gin.Context) {
 		return
 	}
 
-	logStream, err := rt.StreamLogs(c.Request.Context(), containerID, follow, tail)
+	now := time.Now()
+	var err error
+	if opts.Since, err = parseLogTime(c.Query("since"), now); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since: " + err.Error()})
+		return
+	}
+	if opts.Until, err = parseLogTime(c.Query("until"), now); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "until: " + err.Error()})
+		return
+	}
+
+	pattern, err := compileGrepPattern(c.Query("grep"), c.Query("ignorecase") == "true")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid grep pattern: " + err.Error()})
+		return
+	}
+
+	logStream, err := rt.StreamLogs(c.Request.Context(), containerID, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if pattern != nil {
+		logStream = newGrepReader(logStream, pattern)
+	}
 	defer logStream.Close()
 
 	// Set headers for streaming
@@ -572,7 +956,167 @@ func (h *Handler) StreamLogs(c *gin.Context) {
 	})
 }
 
+// parseLogTime parses a since/until value, either an RFC3339 timestamp or a
+// duration like "10m" that is counted back from now. An empty value returns the zero time.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected an RFC3339 timestamp or a duration like 10m", value)
+	}
+	return now.Add(-d), nil
+}
+
+// ContainerTop handles GET /api/containers/:id/top
+func (h *Handler) ContainerTop(c *gin.Context) {
+	containerID := c.Param("id")
+	runtimeName := c.Query("runtime")
+
+	if runtimeName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "runtime parameter is required"})
+		return
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
+
+	processes, err := rt.ContainerTop(ctx, containerID)
+	if err != nil {
+		if errors.Is(err, runtime.ErrContainerNotRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("ContainerTop: Failed to list processes", "id", containerID, "error", err)
+		h.respondRuntimeError(c, ctx, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"processes": processes})
+}
+
+// ListContainerPath handles GET /api/containers/:id/ls
+func (h *Handler) ListContainerPath(c *gin.Context) {
+	containerID := c.Param("id")
+	runtimeName := c.Query("runtime")
+	path := c.DefaultQuery("path", "/")
+
+	if runtimeName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "runtime parameter is required"})
+		return
+	}
+	if !strings.HasPrefix(path, "/") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "path must be absolute"})
+		return
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	ctx, cancel := h.runtimeContext(c, 0)
+	defer cancel()
+
+	entries, err := rt.ListContainerPath(ctx, containerID, path)
+	if err != nil {
+		switch {
+		case errors.Is(err, runtime.ErrPathNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, runtime.ErrDirectoryTooLarge):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			logger.Error("ListContainerPath: Failed to list path", "id", containerID, "path", path, "error", err)
+			h.respondRuntimeError(c, ctx, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"path": path, "entries": entries})
+}
+
+// GenerateSystemd handles GET /api/containers/:id/systemd
+func (h *Handler) GenerateSystemd(c *gin.Context) {
+	containerID := c.Param("id")
+	runtimeName := c.DefaultQuery("runtime", "podman")
+
+	var opts models.SystemdOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		logger.Error("GenerateSystemd: Failed to bind query parameters", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := runtime.ValidateSystemdOptions(opts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rt, ok := h.capableRuntime(c, runtimeName, "systemd units", func(caps models.RuntimeCapabilities) bool { return caps.Systemd })
+	if !ok {
+		return
+	}
+
+	logger.Info("GenerateSystemd: Generating systemd units", "id", containerID, "runtime", runtimeName, "prefix", opts.NamePrefix, "restart_policy", opts.RestartPolicy, "new", opts.New)
+
+	units, err := rt.GenerateSystemd(c.Request.Context(), containerID, opts)
+	if err != nil {
+		logger.Error("GenerateSystemd: Failed to generate systemd units", "id", containerID, "runtime", runtimeName, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"units": units})
+}
+
 // HealthCheck handles GET /health
+// It pings every registered runtime and responds with 503 if any of them is unreachable.
 func (h *Handler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+	ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+	defer cancel()
+
+	runtimes := h.runtimeManager.GetAllRuntimes()
+	statuses := make(map[string]string, len(runtimes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, rt := range runtimes {
+		wg.Add(1)
+		go func(name string, rt runtime.ContainerRuntime) {
+			defer wg.Done()
+			status := "ok"
+			if err := rt.Ping(ctx); err != nil {
+				logger.Warn("HealthCheck: Runtime is unreachable", "runtime", name, "error", err)
+				status = err.Error()
+			}
+			mu.Lock()
+			statuses[name] = status
+			mu.Unlock()
+		}(name, rt)
+	}
+	wg.Wait()
+
+	for _, status := range statuses {
+		if status != "ok" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unhealthy", "runtimes": statuses})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "healthy", "runtimes": statuses})
+}
+
+// Livez handles GET /livez and always reports that the process is alive
+func (h *Handler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "alive"})
 }
@@ -3,15 +3,23 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ThraaxSession/gintainer/internal/caddy"
 	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
 	"github.com/ThraaxSession/gintainer/internal/runtime"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHealthCheck(t *testing.T) {
@@ -30,12 +38,51 @@ func TestHealthCheck(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response map[string]string
+	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, "healthy", response["status"])
 }
 
+func TestHealthCheckPingsRuntimes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker"}
+	podman := &mockRuntime{name: "podman"}
+	handler := NewHandler(newMockManager(docker, podman), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/health", handler.HealthCheck)
+	router.GET("/livez", handler.Livez)
+
+	var response struct {
+		Status   string            `json:"status"`
+		Runtimes map[string]string `json:"runtimes"`
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/health", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "healthy", response.Status)
+	assert.Equal(t, map[string]string{"docker": "ok", "podman": "ok"}, response.Runtimes)
+
+	podman.pingErr = errors.New("connection refused")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "unhealthy", response.Status)
+	assert.Equal(t, map[string]string{"docker": "ok", "podman": "connection refused"}, response.Runtimes)
+
+	// Liveness does not depend on the runtimes
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/livez", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestListContainersWithoutRuntime(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -139,6 +186,382 @@ func TestRestartContainerWithoutRuntime(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestStopContainerTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{name: "docker"}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/containers/:id/stop", handler.StopContainer)
+	router.POST("/api/containers/:id/restart", handler.RestartContainer)
+
+	for _, path := range []string{
+		"/api/containers/test123/stop?runtime=docker",
+		"/api/containers/test123/stop?runtime=docker&timeout=60",
+		"/api/containers/test123/restart?runtime=docker&timeout=0",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+
+	require.Len(t, mock.stopTimeouts, 3)
+	assert.Nil(t, mock.stopTimeouts[0])
+	require.NotNil(t, mock.stopTimeouts[1])
+	assert.Equal(t, 60, *mock.stopTimeouts[1])
+	require.NotNil(t, mock.stopTimeouts[2])
+	assert.Equal(t, 0, *mock.stopTimeouts[2])
+
+	for _, value := range []string{"abc", "-1"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/containers/test123/stop?runtime=docker&timeout="+value, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, value)
+	}
+	assert.Len(t, mock.stopTimeouts, 3)
+}
+
+func TestKillContainer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{name: "docker", failures: map[string]error{"stopped": runtime.ErrContainerNotRunning}}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/containers/:id/kill", handler.KillContainer)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{path: "/api/containers/test123/kill?runtime=docker", code: http.StatusOK},
+		{path: "/api/containers/test123/kill?runtime=docker&signal=hup", code: http.StatusOK},
+		{path: "/api/containers/test123/kill?runtime=docker&signal=SIGUSR1", code: http.StatusOK},
+		{path: "/api/containers/test123/kill?runtime=docker&signal=SIGBOGUS", code: http.StatusBadRequest},
+		{path: "/api/containers/test123/kill?runtime=docker&signal=9", code: http.StatusBadRequest},
+		{path: "/api/containers/test123/kill?signal=SIGHUP", code: http.StatusBadRequest},
+		{path: "/api/containers/stopped/kill?runtime=docker", code: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", tt.path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+
+	// Signals are normalized and default to SIGKILL; invalid ones never reach the runtime
+	assert.Equal(t, []string{"kill SIGKILL test123", "kill SIGHUP test123", "kill SIGUSR1 test123", "kill SIGKILL stopped"}, mock.actions)
+}
+
+func TestUpdateContainerResources(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{name: "docker", failures: map[string]error{"broken": errors.New("update failed")}}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.PATCH("/api/containers/:id/resources", handler.UpdateContainerResources)
+
+	tests := []struct {
+		path string
+		body string
+		code int
+	}{
+		{path: "/api/containers/web/resources?runtime=docker", body: `{"memory": 536870912, "cpus": 1.5}`, code: http.StatusOK},
+		{path: "/api/containers/web/resources?runtime=docker", body: `{"restart_policy": "on-failure:3"}`, code: http.StatusOK},
+		{path: "/api/containers/web/resources?runtime=docker", body: `{}`, code: http.StatusBadRequest},
+		{path: "/api/containers/web/resources?runtime=docker", body: `{"restart_policy": "sometimes"}`, code: http.StatusBadRequest},
+		{path: "/api/containers/web/resources?runtime=docker", body: `{"memory": "lots"}`, code: http.StatusBadRequest},
+		{path: "/api/containers/web/resources", body: `{"cpus": 1}`, code: http.StatusBadRequest},
+		{path: "/api/containers/broken/resources?runtime=docker", body: `{"cpus": 1}`, code: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", tt.path, strings.NewReader(tt.body))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.path+" "+tt.body)
+	}
+
+	// Invalid updates never reach the runtime
+	assert.Equal(t, []string{"update  web", "update on-failure:3 web", "update  broken"}, mock.actions)
+}
+
+func TestListContainerPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{name: "docker", failures: map[string]error{
+		"missing": fmt.Errorf("%w: /nope", runtime.ErrPathNotFound),
+		"huge":    runtime.ErrDirectoryTooLarge,
+	}}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/containers/:id/ls", handler.ListContainerPath)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{path: "/api/containers/web/ls?runtime=docker&path=/var/log", code: http.StatusOK},
+		{path: "/api/containers/web/ls?runtime=docker", code: http.StatusOK},
+		{path: "/api/containers/web/ls?runtime=docker&path=var/log", code: http.StatusBadRequest},
+		{path: "/api/containers/web/ls?path=/var/log", code: http.StatusBadRequest},
+		{path: "/api/containers/missing/ls?runtime=docker&path=/nope", code: http.StatusNotFound},
+		{path: "/api/containers/huge/ls?runtime=docker&path=/", code: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+
+	// The path defaults to the root directory
+	assert.Equal(t, []string{"ls /var/log web", "ls / web", "ls /nope missing", "ls / huge"}, mock.actions)
+}
+
+func TestGenerateSystemd(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker"}
+	podman := &mockRuntime{name: "podman", capabilities: models.RuntimeCapabilities{Pods: true, Systemd: true}}
+	handler := NewHandler(newMockManager(docker, podman), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/containers/:id/systemd", handler.GenerateSystemd)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/containers/web/systemd?runtime=podman&prefix=app&restart_policy=always&new=true", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Units map[string]string `json:"units"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, map[string]string{"app-web": "[Service]\nRestart=always\n"}, resp.Units)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{path: "/api/containers/web/systemd?runtime=docker", code: http.StatusBadRequest},
+		{path: "/api/containers/web/systemd?restart_policy=sometimes", code: http.StatusBadRequest},
+		{path: "/api/containers/web/systemd?prefix=../app", code: http.StatusBadRequest},
+		{path: "/api/containers/web/systemd?new=maybe", code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+
+	assert.Empty(t, docker.actions)
+	assert.Equal(t, []string{"systemd web"}, podman.actions)
+}
+
+func TestGenerateKube(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker"}
+	podman := &mockRuntime{
+		name:         "podman",
+		capabilities: models.RuntimeCapabilities{Pods: true, KubeExport: true},
+		failures:     map[string]error{"broken": errors.New("no such pod")},
+	}
+	handler := NewHandler(newMockManager(docker, podman), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/pods/:id/kube", handler.GeneratePodKube)
+	router.GET("/api/containers/:id/kube", handler.GenerateContainerKube)
+
+	for _, path := range []string{"/api/pods/web/kube", "/api/containers/web/kube?runtime=podman"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, "text/yaml; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="web.yaml"`, w.Header().Get("Content-Disposition"))
+		assert.Contains(t, w.Body.String(), "kind: Pod")
+	}
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{path: "/api/containers/web/kube?runtime=docker", code: http.StatusBadRequest},
+		{path: "/api/pods/broken/kube", code: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+
+	assert.Empty(t, docker.actions)
+	assert.Equal(t, []string{"kube web", "kube web", "kube broken"}, podman.actions)
+}
+
+func TestPruneContainers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	for _, id := range []string{"abc", "def", "running"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "gintainer-"+id+".caddy"), []byte("example.com {\n}\n"), 0644))
+	}
+
+	mock := &mockRuntime{name: "docker", pruned: []string{"abc", "def"}}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: true, CaddyfilePath: dir}), nil)
+
+	router := gin.New()
+	router.POST("/api/containers/prune", handler.PruneContainers)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/containers/prune?runtime=docker", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Removed        []string `json:"removed"`
+		Count          int      `json:"count"`
+		ReclaimedBytes uint64   `json:"reclaimed_bytes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"abc", "def"}, resp.Removed)
+	assert.Equal(t, 2, resp.Count)
+	assert.Equal(t, uint64(2048), resp.ReclaimedBytes)
+
+	// Only the Caddyfiles of pruned containers are removed
+	assert.NoFileExists(t, filepath.Join(dir, "gintainer-abc.caddy"))
+	assert.NoFileExists(t, filepath.Join(dir, "gintainer-def.caddy"))
+	assert.FileExists(t, filepath.Join(dir, "gintainer-running.caddy"))
+
+	for _, path := range []string{"/api/containers/prune", "/api/containers/prune?runtime=unknown"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func TestRunContainerReportsAssignedPorts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{name: "docker", ports: []models.PortMapping{{ContainerPort: 80, HostPort: 32768, Protocol: "tcp"}}}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/containers/run", handler.RunContainer)
+
+	body := `{"name": "web", "image": "nginx", "runtime": "docker", "ports": ["80"]}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/containers/run", strings.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		ContainerID string               `json:"container_id"`
+		Ports       []models.PortMapping `json:"ports"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "id-web", resp.ContainerID)
+	assert.Equal(t, mock.ports, resp.Ports)
+}
+
+func TestRunContainerRegistryAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "test-config.yaml"))
+	require.NoError(t, err)
+	defer configManager.Close()
+	cfg := config.DefaultConfig()
+	cfg.Registries = map[string]config.RegistryCredentials{"ghcr.io": {Username: "bot", Password: "secret"}}
+	require.NoError(t, configManager.UpdateConfig(cfg))
+
+	mock := &mockRuntime{name: "docker"}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), configManager)
+
+	router := gin.New()
+	router.POST("/api/containers/run", handler.RunContainer)
+
+	for _, body := range []string{
+		`{"name": "app", "image": "ghcr.io/org/app:1.0"}`,
+		`{"name": "local", "image": "ghcr.io/org/app:1.0", "no_pull": true}`,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/containers/run", strings.NewReader(body))
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, body)
+	}
+
+	// Credentials are only passed on when the runtime may pull the image
+	require.Len(t, mock.runRequests, 2)
+	require.NotNil(t, mock.runRequests[0].Auth)
+	assert.Equal(t, "bot", mock.runRequests[0].Auth.Username)
+	assert.True(t, mock.runRequests[1].NoPull)
+	assert.Nil(t, mock.runRequests[1].Auth)
+}
+
+func TestRunContainerPrivileged(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{name: "docker"}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/containers/run", handler.RunContainer)
+
+	body := `{"name": "agent", "image": "monitor", "privileged": true, "security_opt": ["seccomp=unconfined", "no-new-privileges"]}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/containers/run", strings.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Privileged bool `json:"privileged"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Privileged)
+	require.Len(t, mock.runRequests, 1)
+	assert.True(t, mock.runRequests[0].Privileged)
+	assert.Equal(t, []string{"seccomp=unconfined", "no-new-privileges"}, mock.runRequests[0].SecurityOpt)
+
+	// Unknown security options are rejected before the runtime is called
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/containers/run", strings.NewReader(`{"name": "bad", "image": "monitor", "security_opt": ["selinux:disable"]}`))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid security option")
+	assert.Len(t, mock.runRequests, 1)
+}
+
+func TestRunContainerInvalidDevice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{name: "docker"}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/containers/run", handler.RunContainer)
+
+	for body, status := range map[string]int{
+		`{"name": "audio", "image": "player", "devices": ["/dev/snd"], "tmpfs": {"/tmp": "size=64m"}, "extra_hosts": ["db:10.0.0.5"]}`: http.StatusOK,
+		`{"name": "bad", "image": "player", "devices": ["/dev/snd:/dev/snd:rwx"]}`:                                                     http.StatusBadRequest,
+		`{"name": "bad", "image": "player", "extra_hosts": ["db"]}`:                                                                    http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/containers/run", strings.NewReader(body))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, status, w.Code, body)
+	}
+	assert.Equal(t, []string{"run audio"}, mock.actions)
+}
+
 func TestCreateContainerInvalidJSON(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -156,3 +579,90 @@ func TestCreateContainerInvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestParseLogTime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	parsed, err := parseLogTime("", now)
+	require.NoError(t, err)
+	assert.True(t, parsed.IsZero())
+
+	parsed, err = parseLogTime("2024-05-01T10:30:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC), parsed)
+
+	parsed, err = parseLogTime("2024-05-01T12:30:00.5+02:00", now)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(time.Date(2024, 5, 1, 10, 30, 0, 500000000, time.UTC)))
+
+	parsed, err = parseLogTime("10m", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-10*time.Minute), parsed)
+
+	parsed, err = parseLogTime("1h30m", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-90*time.Minute), parsed)
+
+	for _, value := range []string{"yesterday", "-5m", "2024-05-01"} {
+		_, err := parseLogTime(value, now)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestStreamLogsInvalidSince(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewHandler(newMockManager(&mockRuntime{name: "docker"}), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/containers/:id/logs", handler.StreamLogs)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/containers/test123/logs?runtime=docker&since=yesterday", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "since")
+}
+
+func TestStreamLogsInvalidGrep(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewHandler(newMockManager(&mockRuntime{name: "docker"}), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/containers/:id/logs", handler.StreamLogs)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/containers/test123/logs?runtime=docker&grep=%28unclosed", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid grep pattern")
+}
+
+func TestDeployComposeInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "test-config.yaml"))
+	require.NoError(t, err)
+	defer configManager.Close()
+
+	mock := &mockRuntime{name: "docker", failures: map[string]error{
+		"broken":  fmt.Errorf("%w: services.web.image must be a string", runtime.ErrInvalidCompose),
+		"failing": errors.New("failed to create Docker container"),
+	}}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), configManager)
+
+	router := gin.New()
+	router.POST("/api/compose", handler.DeployCompose)
+
+	for project, expected := range map[string]int{"broken": http.StatusBadRequest, "failing": http.StatusInternalServerError} {
+		body := `{"compose_content": "services: {}", "project_name": "` + project + `"}`
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/compose", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, expected, w.Code, project)
+	}
+}
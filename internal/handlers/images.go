@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// PullImage handles POST /api/images/pull. With stream=true, the pull progress is streamed via SSE.
+func (h *Handler) PullImage(c *gin.Context) {
+	var req models.PullImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("PullImage: Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Image == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "image is required"})
+		return
+	}
+
+	if req.Runtime == "" {
+		req.Runtime = "docker"
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(req.Runtime)
+	if !ok {
+		logger.Error("PullImage: Invalid runtime", "runtime", req.Runtime)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	auth := h.registryAuthFor(req.Image)
+	logger.Info("PullImage: Pulling image", "image", req.Image, "runtime", req.Runtime, "registry", config.RegistryHost(req.Image), "authenticated", auth != nil)
+
+	if c.Query("stream") == "true" {
+		h.streamPull(c, rt, req.Image, auth)
+		return
+	}
+
+	if err := rt.PullImage(c.Request.Context(), req.Image, auth); err != nil {
+		logger.Error("PullImage: Failed to pull image", "image", req.Image, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("PullImage: Successfully pulled image", "image", req.Image)
+	c.JSON(http.StatusOK, gin.H{"message": "image pulled successfully", "image": req.Image})
+}
+
+// streamPull pulls an image and forwards its progress as "progress" events, followed by a "done" or "error" event
+func (h *Handler) streamPull(c *gin.Context, rt runtime.ContainerRuntime, imageName string, auth *models.RegistryAuth) {
+	ctx := c.Request.Context()
+	updates, err := rt.PullImageStream(ctx, imageName, auth)
+	if err != nil {
+		logger.Error("PullImage: Failed to pull image", "image", imageName, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	for progress := range updates {
+		if progress.Error != "" {
+			logger.Error("PullImage: Failed to pull image", "image", imageName, "error", progress.Error)
+			c.SSEvent("error", gin.H{"error": progress.Error, "image": imageName})
+			c.Writer.Flush()
+			return
+		}
+		c.SSEvent("progress", progress)
+		c.Writer.Flush()
+	}
+
+	if ctx.Err() != nil {
+		logger.Info("PullImage: Client disconnected during pull", "image", imageName, "client_ip", c.ClientIP())
+		return
+	}
+
+	logger.Info("PullImage: Successfully pulled image", "image", imageName)
+	c.SSEvent("done", gin.H{"message": "image pulled successfully", "image": imageName})
+	c.Writer.Flush()
+}
+
+// ImageHistory handles GET /api/images/:id/history
+func (h *Handler) ImageHistory(c *gin.Context) {
+	imageName := c.Param("id")
+	runtimeName := c.DefaultQuery("runtime", "docker")
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("ImageHistory: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	layers, err := rt.ImageHistory(c.Request.Context(), imageName)
+	if err != nil {
+		if errors.Is(err, runtime.ErrImageNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("ImageHistory: Failed to get image history", "image", imageName, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"image": imageName, "layers": layers})
+}
+
+// TagImage handles POST /api/images/:id/tag
+func (h *Handler) TagImage(c *gin.Context) {
+	imageID := c.Param("id")
+	runtimeName := c.DefaultQuery("runtime", "docker")
+
+	var req models.TagImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("TagImage: Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Target == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target is required"})
+		return
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("TagImage: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	if err := rt.TagImage(c.Request.Context(), imageID, req.Target); err != nil {
+		logger.Error("TagImage: Failed to tag image", "image", imageID, "target", req.Target, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("TagImage: Successfully tagged image", "image", imageID, "target", req.Target)
+	c.JSON(http.StatusOK, gin.H{"message": "image tagged successfully", "image": imageID, "target": req.Target})
+}
+
+// PushImage handles POST /api/images/:id/push
+func (h *Handler) PushImage(c *gin.Context) {
+	imageName := c.Param("id")
+	runtimeName := c.DefaultQuery("runtime", "docker")
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("PushImage: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	auth := h.registryAuthFor(imageName)
+	logger.Info("PushImage: Pushing image", "image", imageName, "runtime", runtimeName, "registry", config.RegistryHost(imageName), "authenticated", auth != nil)
+
+	digest, err := rt.PushImage(c.Request.Context(), imageName, auth)
+	if err != nil {
+		logger.Error("PushImage: Failed to push image", "image", imageName, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("PushImage: Successfully pushed image", "image", imageName, "digest", digest)
+	c.JSON(http.StatusOK, gin.H{"message": "image pushed successfully", "image": imageName, "digest": digest})
+}
+
+// BuildImage handles POST /api/images/build
+// It expects a multipart form with a tar archive of the build context in the "context" field.
+func (h *Handler) BuildImage(c *gin.Context) {
+	imageName := c.PostForm("image_name")
+	dockerfile := c.DefaultPostForm("dockerfile", "Dockerfile")
+	runtimeName := c.DefaultPostForm("runtime", "docker")
+
+	if imageName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "image_name is required"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("context")
+	if err != nil {
+		logger.Error("BuildImage: Missing build context", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "context tar archive is required"})
+		return
+	}
+
+	buildArgs, err := parseBuildArgs(c.PostFormArray("build_arg"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("BuildImage: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	contextTar, err := fileHeader.Open()
+	if err != nil {
+		logger.Error("BuildImage: Failed to open build context", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer contextTar.Close()
+
+	opts := models.BuildOptions{
+		BuildArgs: buildArgs,
+		Target:    c.PostForm("target"),
+	}
+
+	logger.Info("BuildImage: Building image from uploaded context", "image", imageName, "dockerfile", dockerfile, "runtime", runtimeName, "context_size", fileHeader.Size)
+
+	if err := rt.BuildFromContext(c.Request.Context(), contextTar, dockerfile, imageName, opts); err != nil {
+		logger.Error("BuildImage: Failed to build image", "image", imageName, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("BuildImage: Successfully built image", "image", imageName)
+	c.JSON(http.StatusOK, gin.H{"message": "image built successfully", "image": imageName})
+}
+
+// parseBuildArgs parses build args given as "KEY=VALUE" strings
+func parseBuildArgs(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	args := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid build_arg %q, expected KEY=VALUE", value)
+		}
+		args[key] = val
+	}
+	return args, nil
+}
+
+// registryAuthFor looks up configured credentials for the registry an image belongs to.
+// It returns nil when no credentials are configured for that registry.
+func (h *Handler) registryAuthFor(imageName string) *models.RegistryAuth {
+	if h.configManager == nil {
+		return nil
+	}
+	return config.RegistryAuth(h.configManager.GetConfig().Registries, imageName)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryAuthFor(t *testing.T) {
+	tmpDir := t.TempDir()
+	configManager, err := config.NewManager(filepath.Join(tmpDir, "test-config.yaml"))
+	assert.NoError(t, err)
+	defer configManager.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Registries = map[string]config.RegistryCredentials{
+		"ghcr.io": {Username: "bot", Password: "secret"},
+	}
+	assert.NoError(t, configManager.UpdateConfig(cfg))
+
+	handler := NewHandler(runtime.NewManager(), caddy.NewService(&config.CaddyConfig{Enabled: false}), configManager)
+
+	auth := handler.registryAuthFor("ghcr.io/org/app:latest")
+	if assert.NotNil(t, auth) {
+		assert.Equal(t, "bot", auth.Username)
+		assert.Equal(t, "secret", auth.Password)
+	}
+
+	assert.Nil(t, handler.registryAuthFor("nginx:latest"))
+}
+
+func TestParseBuildArgs(t *testing.T) {
+	args, err := parseBuildArgs([]string{"VERSION=1.0", "EMPTY=", "URL=http://x?a=b"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"VERSION": "1.0", "EMPTY": "", "URL": "http://x?a=b"}, args)
+
+	_, err = parseBuildArgs([]string{"NOVALUE"})
+	assert.Error(t, err)
+
+	args, err = parseBuildArgs(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, args)
+}
+
+func TestBuildImageWithoutContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewHandler(runtime.NewManager(), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/images/build", handler.BuildImage)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	assert.NoError(t, writer.WriteField("image_name", "my-image"))
+	assert.NoError(t, writer.Close())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/images/build", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPullImageStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{
+		name:     "docker",
+		failures: map[string]error{"missing:latest": errors.New("pull access denied")},
+		pullProgress: []models.PullProgress{
+			{ID: "a2abf6c4d29d", Status: "Downloading", Current: 1024, Total: 4096},
+			{ID: "a2abf6c4d29d", Status: "Pull complete"},
+		},
+	}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/images/pull", handler.PullImage)
+
+	pull := func(image string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/images/pull?stream=true", strings.NewReader(`{"image":"`+image+`","runtime":"docker"}`))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := pull("nginx:latest")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+	body := w.Body.String()
+	assert.Equal(t, 2, strings.Count(body, "event:progress"))
+	assert.Contains(t, body, `"current":1024,"total":4096`)
+	assert.Contains(t, body, "event:done")
+
+	// A failure to start the pull is a plain error response
+	w = pull("missing:latest")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// A failure during the pull ends the stream with an error event
+	mock.pullProgress = append(mock.pullProgress, models.PullProgress{Error: "manifest unknown"})
+	body = pull("nginx:latest").Body.String()
+	assert.Contains(t, body, "event:error")
+	assert.Contains(t, body, "manifest unknown")
+	assert.NotContains(t, body, "event:done")
+}
+
+func TestImageHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock := &mockRuntime{
+		name:     "podman",
+		failures: map[string]error{"missing": fmt.Errorf("%w: missing", runtime.ErrImageNotFound)},
+		history: []models.ImageLayer{
+			{ID: "sha256:abc", CreatedBy: `CMD ["nginx"]`, CreatedAt: created, Tags: []string{"nginx:latest"}},
+			{ID: "<missing>", CreatedBy: "ADD rootfs.tar.xz /", Size: 74812345, CreatedAt: created},
+		},
+	}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/images/:id/history", handler.ImageHistory)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/images/nginx/history?runtime=podman")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Image  string              `json:"image"`
+		Layers []models.ImageLayer `json:"layers"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "nginx", resp.Image)
+	assert.Equal(t, mock.history, resp.Layers)
+	assert.Contains(t, w.Body.String(), `"created_by":"ADD rootfs.tar.xz /","size":74812345,"created_at":"2024-05-01T12:00:00Z"`)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/images/missing/history?runtime=podman").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/images/nginx/history?runtime=unknown").Code)
+}
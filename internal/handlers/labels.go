@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// caddyLabelPrefix is the prefix of the container labels read by the Caddy integration
+const caddyLabelPrefix = "caddy."
+
+// UpdateContainerCaddyLabels handles PUT /api/containers/:id/caddy.
+// The caddy.* labels of the container are replaced by the ones in the request. Docker and Podman
+// cannot change labels in place, so the container is recreated, which is reported as
+// "recreated" together with the new container ID.
+func (h *Handler) UpdateContainerCaddyLabels(c *gin.Context) {
+	containerID := c.Param("id")
+	runtimeName := c.Query("runtime")
+
+	logger.Info("UpdateContainerCaddyLabels: Request to update Caddy labels", "id", containerID, "runtime", runtimeName)
+
+	if runtimeName == "" {
+		logger.Error("UpdateContainerCaddyLabels: Runtime parameter missing")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "runtime parameter is required"})
+		return
+	}
+
+	var req models.CaddyLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("UpdateContainerCaddyLabels: Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		logger.Error("UpdateContainerCaddyLabels: Invalid runtime", "runtime", runtimeName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	container, found, err := findContainer(ctx, rt, containerID)
+	if err != nil {
+		logger.Error("UpdateContainerCaddyLabels: Failed to list containers", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "container not found"})
+		return
+	}
+
+	// Caddy labels that are not part of the request are removed
+	set := caddy.LabelsFromRequest(req)
+	var remove []string
+	for key := range container.Labels {
+		if _, keep := set[key]; strings.HasPrefix(key, caddyLabelPrefix) && !keep {
+			remove = append(remove, key)
+		}
+	}
+
+	recreator, ok := rt.(runtime.LabelRecreator)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": fmt.Sprintf("changing container labels is not supported for runtime %s", runtimeName)})
+		return
+	}
+
+	logger.Info("UpdateContainerCaddyLabels: Recreating container to change its labels", "id", container.ID, "name", container.Name)
+	newID, err := recreator.RecreateContainerWithLabels(ctx, container.ID, set, remove)
+	if err != nil {
+		logger.Error("UpdateContainerCaddyLabels: Failed to update labels", "id", container.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("UpdateContainerCaddyLabels: Successfully updated Caddy labels", "id", newID)
+	h.syncLabeledCaddyfile(ctx, rt, container.ID, newID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "caddy labels updated successfully",
+		"container_id": newID,
+		"recreated":    true,
+	})
+}
+
+// syncLabeledCaddyfile regenerates the Caddyfile of a container whose labels changed.
+// A recreated container gets a new ID, so the Caddyfile of the old one is removed.
+func (h *Handler) syncLabeledCaddyfile(ctx context.Context, rt runtime.ContainerRuntime, oldID, newID string) {
+	if h.caddyService == nil || !h.caddyService.IsEnabled() {
+		return
+	}
+
+	if oldID != newID {
+		if err := h.caddyService.DeleteCaddyfile(ctx, oldID); err != nil {
+			logger.Warn("UpdateContainerCaddyLabels: Failed to delete Caddyfile of the old container", "id", oldID, "error", err)
+		}
+	}
+
+	container, found, err := findContainer(ctx, rt, newID)
+	if err != nil || !found {
+		logger.Warn("UpdateContainerCaddyLabels: Failed to look up container for Caddy", "id", newID, "error", err)
+		return
+	}
+	if err := h.caddyService.GenerateCaddyfile(ctx, container); err != nil {
+		logger.Warn("UpdateContainerCaddyLabels: Failed to generate Caddyfile", "id", newID, "error", err)
+	}
+}
+
+// findContainer looks up a container by ID, short ID or name
+func findContainer(ctx context.Context, rt runtime.ContainerRuntime, idOrName string) (models.ContainerInfo, bool, error) {
+	containers, err := rt.ListContainers(ctx, models.FilterOptions{IncludeNetwork: true})
+	if err != nil {
+		return models.ContainerInfo{}, false, err
+	}
+	for _, container := range containers {
+		if container.ID == idOrName || container.Name == idOrName || (len(idOrName) >= 12 && strings.HasPrefix(container.ID, idOrName)) {
+			return container, true, nil
+		}
+	}
+	return models.ContainerInfo{}, false, nil
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recreatingRuntime changes labels by recreating the container, like DockerRuntime and PodmanRuntime
+type recreatingRuntime struct {
+	*mockRuntime
+	set    map[string]string
+	remove []string
+}
+
+func (m *recreatingRuntime) RecreateContainerWithLabels(ctx context.Context, containerID string, set map[string]string, remove []string) (string, error) {
+	m.set, m.remove = set, remove
+	return "new-" + containerID, m.record("recreate", containerID)
+}
+
+func newLabelTestRouter(rt runtime.ContainerRuntime) *gin.Engine {
+	manager := runtime.NewManager()
+	manager.RegisterRuntime(rt.GetRuntimeName(), rt)
+	handler := NewHandler(manager, caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.PUT("/api/containers/:id/caddy", handler.UpdateContainerCaddyLabels)
+	return router
+}
+
+func putCaddyLabels(router *gin.Engine, path string, req models.CaddyLabelsRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("PUT", path, bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	return w
+}
+
+// labeledMock returns a mock serving one container that already has Caddy labels
+func labeledMock(name string) *mockRuntime {
+	return &mockRuntime{name: name, containers: []models.ContainerInfo{{
+		ID:     "abc123",
+		Name:   "web",
+		Labels: map[string]string{"caddy.domain": "old.example.com", "caddy.encode": "gzip", "app": "web"},
+	}}}
+}
+
+func TestUpdateContainerCaddyLabelsRecreate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rt := &recreatingRuntime{mockRuntime: labeledMock("docker")}
+	router := newLabelTestRouter(rt)
+
+	w := putCaddyLabels(router, "/api/containers/web/caddy?runtime=docker", models.CaddyLabelsRequest{Domain: "example.com", Port: "8080"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, true, resp["recreated"])
+	assert.Equal(t, "new-abc123", resp["container_id"])
+
+	assert.Equal(t, []string{"recreate abc123"}, rt.actions)
+	assert.Equal(t, "example.com", rt.set["caddy.domain"])
+	assert.Equal(t, []string{"caddy.encode"}, rt.remove)
+}
+
+func TestUpdateContainerCaddyLabelsPodman(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The handler must reach PodmanRuntime's label implementation
+	var _ runtime.LabelRecreator = (*runtime.PodmanRuntime)(nil)
+
+	rt := &recreatingRuntime{mockRuntime: labeledMock("podman")}
+	router := newLabelTestRouter(rt)
+
+	w := putCaddyLabels(router, "/api/containers/abc123/caddy?runtime=podman", models.CaddyLabelsRequest{Domain: "example.com", Compress: true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, true, resp["recreated"])
+	assert.Equal(t, "new-abc123", resp["container_id"])
+
+	assert.Equal(t, []string{"recreate abc123"}, rt.actions)
+	assert.Equal(t, "example.com", rt.set["caddy.domain"])
+	assert.Equal(t, []string{"caddy.encode"}, rt.remove)
+}
+
+func TestUpdateContainerCaddyLabelsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newLabelTestRouter(labeledMock("docker"))
+	valid := models.CaddyLabelsRequest{Domain: "example.com"}
+
+	tests := []struct {
+		name     string
+		path     string
+		req      models.CaddyLabelsRequest
+		expected int
+	}{
+		{"missing runtime", "/api/containers/web/caddy", valid, http.StatusBadRequest},
+		{"invalid runtime", "/api/containers/web/caddy?runtime=unknown", valid, http.StatusBadRequest},
+		{"missing domain", "/api/containers/web/caddy?runtime=docker", models.CaddyLabelsRequest{}, http.StatusBadRequest},
+		{"unknown container", "/api/containers/missing/caddy?runtime=docker", valid, http.StatusNotFound},
+		{"labels not supported", "/api/containers/web/caddy?runtime=docker", valid, http.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := putCaddyLabels(router, tt.path, tt.req)
+			assert.Equal(t, tt.expected, w.Code, w.Body.String())
+		})
+	}
+}
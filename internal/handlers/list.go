@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"cmp"
+	"sort"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// sortContainers sorts containers by key ("name", "created", "status", "cpu" or "memory"; default "name")
+// in the given order ("asc" or "desc"; default "asc"). Ties are broken by name, runtime and ID
+// so the result is deterministic. Containers without stats sort as zero CPU and memory.
+func sortContainers(containers []models.ContainerInfo, key, order string) {
+	desc := order == "desc"
+	sort.SliceStable(containers, func(i, j int) bool {
+		a, b := containers[i], containers[j]
+		if result := compareContainers(a, b, key); result != 0 {
+			if desc {
+				return result > 0
+			}
+			return result < 0
+		}
+		return compareByName(a, b) < 0
+	})
+}
+
+// compareContainers compares two containers by a sort key
+func compareContainers(a, b models.ContainerInfo, key string) int {
+	switch key {
+	case "created":
+		return a.Created.Compare(b.Created)
+	case "status":
+		return cmp.Compare(a.State, b.State)
+	case "cpu":
+		return cmp.Compare(statsOf(a).CPUPercent, statsOf(b).CPUPercent)
+	case "memory":
+		return cmp.Compare(statsOf(a).MemoryUsage, statsOf(b).MemoryUsage)
+	default:
+		return compareByName(a, b)
+	}
+}
+
+// compareByName compares containers by name, runtime and ID
+func compareByName(a, b models.ContainerInfo) int {
+	if result := cmp.Compare(a.Name, b.Name); result != 0 {
+		return result
+	}
+	if result := cmp.Compare(a.Runtime, b.Runtime); result != 0 {
+		return result
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// statsOf returns the stats of a container, or zero stats if none were collected
+func statsOf(container models.ContainerInfo) models.ContainerStats {
+	if container.Stats == nil {
+		return models.ContainerStats{}
+	}
+	return *container.Stats
+}
+
+// paginate returns the page of items selected by limit and offset.
+// A limit of 0 returns everything after offset.
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
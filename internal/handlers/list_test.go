@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortContainers(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	containers := []models.ContainerInfo{
+		{ID: "1", Name: "web", Runtime: "docker", State: "running", Created: base.Add(2 * time.Hour), Stats: &models.ContainerStats{CPUPercent: 12.5, MemoryUsage: 300}},
+		{ID: "2", Name: "db", Runtime: "docker", State: "running", Created: base, Stats: &models.ContainerStats{CPUPercent: 40, MemoryUsage: 100}},
+		{ID: "3", Name: "cache", Runtime: "podman", State: "exited", Created: base.Add(time.Hour)},
+		{ID: "4", Name: "web", Runtime: "podman", State: "created", Created: base.Add(3 * time.Hour), Stats: &models.ContainerStats{CPUPercent: 1, MemoryUsage: 200}},
+	}
+
+	tests := []struct {
+		key      string
+		order    string
+		expected []string
+	}{
+		{"", "", []string{"3", "2", "1", "4"}},
+		{"name", "asc", []string{"3", "2", "1", "4"}},
+		{"name", "desc", []string{"4", "1", "2", "3"}},
+		{"created", "asc", []string{"2", "3", "1", "4"}},
+		{"created", "desc", []string{"4", "1", "3", "2"}},
+		{"status", "asc", []string{"4", "3", "2", "1"}},
+		{"cpu", "desc", []string{"2", "1", "4", "3"}},
+		{"cpu", "asc", []string{"3", "4", "1", "2"}},
+		{"memory", "desc", []string{"1", "4", "2", "3"}},
+		{"memory", "asc", []string{"3", "2", "4", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"_"+tt.order, func(t *testing.T) {
+			sorted := append([]models.ContainerInfo(nil), containers...)
+			sortContainers(sorted, tt.key, tt.order)
+
+			ids := make([]string, 0, len(sorted))
+			for _, c := range sorted {
+				ids = append(ids, c.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, paginate(items, 0, 0))
+	assert.Equal(t, []int{1, 2}, paginate(items, 2, 0))
+	assert.Equal(t, []int{3, 4}, paginate(items, 2, 2))
+	assert.Equal(t, []int{5}, paginate(items, 2, 4))
+	assert.Equal(t, []int{4, 5}, paginate(items, 0, 3))
+	assert.Equal(t, []int{}, paginate(items, 2, 5))
+	assert.Equal(t, []int{}, paginate(items, 0, 10))
+}
+
+func TestListContainersInvalidListOptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewHandler(runtime.NewManager(), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/containers", handler.ListContainers)
+
+	for _, query := range []string{"limit=-1", "offset=-5", "limit=ten", "sort=size", "order=up", "sort=cpu", "sort=memory&include_stats=false"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/containers?"+query, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestListContainersIncludesStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rt := &mockRuntime{name: "docker", containers: []models.ContainerInfo{{
+		ID:         "abc123",
+		Name:       "web",
+		Runtime:    "docker",
+		State:      "running",
+		Privileged: true,
+		Stats: &models.ContainerStats{
+			CPUPercent:    12.5,
+			MemoryUsage:   64 << 20,
+			MemoryLimit:   512 << 20,
+			MemoryPercent: 12.5,
+			NetworkRx:     1024,
+			NetworkTx:     2048,
+			BlockRead:     4096,
+			BlockWrite:    8192,
+			PIDs:          7,
+		},
+	}}}
+	handler := NewHandler(newMockManager(rt), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/containers", handler.ListContainers)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/containers?runtime=docker&include_stats=true&include_privileged=true", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Containers []map[string]interface{} `json:"containers"`
+		Total      int                      `json:"total"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Total)
+	assert.Equal(t, true, response.Containers[0]["privileged"])
+
+	stats := response.Containers[0]["stats"].(map[string]interface{})
+	assert.Equal(t, 12.5, stats["cpu_percent"])
+	assert.Equal(t, float64(64<<20), stats["memory_usage"])
+	assert.Equal(t, float64(512<<20), stats["memory_limit"])
+	assert.Equal(t, 12.5, stats["memory_percent"])
+	assert.Equal(t, float64(1024), stats["network_rx"])
+	assert.Equal(t, float64(2048), stats["network_tx"])
+	assert.Equal(t, float64(4096), stats["block_read"])
+	assert.Equal(t, float64(8192), stats["block_write"])
+	assert.Equal(t, float64(7), stats["pids"])
+}
+
+func TestListContainersLabelFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker"}
+	podman := &mockRuntime{name: "podman"}
+	handler := NewHandler(newMockManager(docker, podman), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/containers", handler.ListContainers)
+
+	for _, label := range []string{"caddy.domain", "env=prod"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/containers?label="+url.QueryEscape(label), nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// The label is passed on to every runtime
+	for _, rt := range []*mockRuntime{docker, podman} {
+		require.Len(t, rt.listFilters, 2, rt.name)
+		assert.Equal(t, "caddy.domain", rt.listFilters[0].Label)
+		assert.Equal(t, "env=prod", rt.listFilters[1].Label)
+	}
+}
+
+func TestListContainersAllRuntimesInParallel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker", listDelay: 200 * time.Millisecond, containers: []models.ContainerInfo{
+		{ID: "d2", Name: "web", Runtime: "docker"},
+		{ID: "d1", Name: "api", Runtime: "docker"},
+	}}
+	podman := &mockRuntime{name: "podman", listDelay: 200 * time.Millisecond, containers: []models.ContainerInfo{
+		{ID: "p1", Name: "db", Runtime: "podman"},
+	}}
+	handler := NewHandler(newMockManager(docker, podman), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	start := time.Now()
+	containers, err := handler.collectContainers(context.Background(), models.FilterOptions{Runtime: "all"})
+	require.NoError(t, err)
+
+	// Both runtimes are queried at once, and the result is sorted by runtime and name
+	assert.Less(t, time.Since(start), 350*time.Millisecond)
+	var ids []string
+	for _, c := range containers {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []string{"d1", "d2", "p1"}, ids)
+}
+
+func TestListContainersAllSkipsFailingRuntimes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	timeout := defaultRuntimeTimeout
+	defaultRuntimeTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { defaultRuntimeTimeout = timeout })
+
+	docker := &mockRuntime{name: "docker", listDelay: time.Minute, containers: []models.ContainerInfo{{ID: "d1", Runtime: "docker"}}}
+	podman := &mockRuntime{name: "podman", containers: []models.ContainerInfo{{ID: "p1", Runtime: "podman"}}}
+	other := &mockRuntime{name: "other", listErr: errors.New("socket closed")}
+	handler := NewHandler(newMockManager(docker, podman, other), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	// The hung runtime times out and the failing one is skipped; the rest is still listed
+	start := time.Now()
+	containers, err := handler.collectContainers(context.Background(), models.FilterOptions{Runtime: "all"})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	require.Len(t, containers, 1)
+	assert.Equal(t, "p1", containers[0].ID)
+}
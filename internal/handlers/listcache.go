@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// listCache keeps the container lists of recent requests for a short time, so dashboards polling
+// the list do not query the runtime sockets on every request. Cached lists are dropped after
+// state-changing API requests and on container events of the runtimes.
+type listCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration // 0 disables the cache
+	entries    map[models.FilterOptions]listCacheEntry
+	generation uint64             // Bumped by invalidate, so lists fetched before are not stored
+	cancel     context.CancelFunc // Stops the event watchers, nil while disabled
+
+	now func() time.Time
+}
+
+// listCacheEntry is a cached container list
+type listCacheEntry struct {
+	containers []models.ContainerInfo
+	expires    time.Time
+}
+
+// newListCache creates a disabled list cache
+func newListCache() *listCache {
+	return &listCache{
+		entries: make(map[models.FilterOptions]listCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// listCacheKey returns the filters that select the listed containers; sorting and paging are applied to the cached list
+func listCacheKey(filters models.FilterOptions) models.FilterOptions {
+	filters.Limit, filters.Offset, filters.Sort, filters.Order = 0, 0, "", ""
+	return filters
+}
+
+// get returns a copy of the cached list for filters. On a miss, the returned generation is passed to put.
+func (l *listCache) get(filters models.FilterOptions) ([]models.ContainerInfo, uint64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[listCacheKey(filters)]
+	if !ok || l.ttl == 0 || !l.now().Before(entry.expires) {
+		return nil, l.generation, false
+	}
+	return slices.Clone(entry.containers), l.generation, true
+}
+
+// put caches a list fetched at generation, unless the cache was invalidated since then
+func (l *listCache) put(filters models.FilterOptions, generation uint64, containers []models.ContainerInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ttl == 0 || generation != l.generation {
+		return
+	}
+
+	now := l.now()
+	for key, entry := range l.entries {
+		if !now.Before(entry.expires) {
+			delete(l.entries, key)
+		}
+	}
+	l.entries[listCacheKey(filters)] = listCacheEntry{containers: slices.Clone(containers), expires: now.Add(l.ttl)}
+}
+
+// invalidate drops all cached lists, including those being fetched
+func (l *listCache) invalidate() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.generation++
+	clear(l.entries)
+}
+
+// update sets the TTL and, while the cache is enabled, (re)starts following the event streams of the runtimes
+func (l *listCache) update(ttl time.Duration, runtimes map[string]runtime.ContainerRuntime) {
+	l.stop()
+	l.invalidate()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ttl = ttl
+	if ttl == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	for name, rt := range runtimes {
+		// Changes made while a stream was down would go unnoticed, so an ended stream drops the cache too
+		go followEvents(ctx, "listCache.watch", name, rt, func(models.RuntimeEvent) { l.invalidate() }, l.invalidate)
+	}
+}
+
+// stop stops following the event streams
+func (l *listCache) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cancel != nil {
+		l.cancel()
+		l.cancel = nil
+	}
+}
+
+// cachedContainers returns the containers selected by filters from the list cache, or collects and caches them
+func (h *Handler) cachedContainers(ctx context.Context, filters models.FilterOptions) ([]models.ContainerInfo, error) {
+	containers, generation, ok := h.listCache.get(filters)
+	if ok {
+		logger.Debug("ListContainers: Serving cached container list", "count", len(containers))
+		return containers, nil
+	}
+
+	containers, err := h.collectContainers(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	h.listCache.put(filters, generation, containers)
+	return containers, nil
+}
+
+// UpdateListCache sets how long container lists are cached, 0 disables the cache. While enabled, the
+// event streams of the registered runtimes are followed; it is meant to be called again after the runtimes changed.
+func (h *Handler) UpdateListCache(ttl time.Duration) {
+	h.listCache.update(ttl, h.runtimeManager.GetAllRuntimes())
+	if ttl > 0 {
+		logger.Info("Handler.UpdateListCache: Container list cache enabled", "ttl", ttl)
+	}
+}
+
+// StopListCache stops following the runtime event streams
+func (h *Handler) StopListCache() {
+	h.listCache.stop()
+}
+
+// InvalidateListCache returns middleware that drops the cached container lists after every state-changing request
+func (h *Handler) InvalidateListCache() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			h.listCache.invalidate()
+		}
+	}
+}
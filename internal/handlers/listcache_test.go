@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{
+		name:       "docker",
+		containers: []models.ContainerInfo{{ID: "1", Name: "web", Runtime: "docker"}, {ID: "2", Name: "db", Runtime: "docker"}},
+		events:     make(chan models.RuntimeEvent),
+	}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	var mu sync.Mutex
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	handler.listCache.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	handler.UpdateListCache(2 * time.Second)
+	t.Cleanup(handler.StopListCache)
+
+	router := gin.New()
+	router.Use(handler.InvalidateListCache())
+	router.GET("/api/containers", handler.ListContainers)
+	router.POST("/api/containers/:id/start", handler.StartContainer)
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Repeated requests with the same filters are served from the cache, sorting and paging included
+	require.Equal(t, http.StatusOK, request("GET", "/api/containers").Code)
+	require.Equal(t, http.StatusOK, request("GET", "/api/containers").Code)
+	w := request("GET", "/api/containers?sort=name&limit=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"containers": [{"id": "2", "name": "db", "image": "", "status": "", "state": "", "created": "0001-01-01T00:00:00Z", "runtime": "docker"}], "total": 2}`, w.Body.String())
+	assert.Equal(t, 1, mock.listCalls())
+
+	// Other filters miss
+	require.Equal(t, http.StatusOK, request("GET", "/api/containers?name=web").Code)
+	assert.Equal(t, 2, mock.listCalls())
+
+	// State-changing requests drop the cache
+	require.Equal(t, http.StatusOK, request("POST", "/api/containers/1/start?runtime=docker").Code)
+	require.Equal(t, http.StatusOK, request("GET", "/api/containers").Code)
+	assert.Equal(t, 3, mock.listCalls())
+
+	// So do runtime events
+	mock.events <- models.RuntimeEvent{Type: "die", ContainerID: "1", Runtime: "docker"}
+	require.Eventually(t, func() bool {
+		_, _, ok := handler.listCache.get(models.FilterOptions{Runtime: "all"})
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, http.StatusOK, request("GET", "/api/containers").Code)
+	assert.Equal(t, 4, mock.listCalls())
+
+	// Entries expire after the TTL
+	mu.Lock()
+	now = now.Add(2 * time.Second)
+	mu.Unlock()
+	require.Equal(t, http.StatusOK, request("GET", "/api/containers").Code)
+	assert.Equal(t, 5, mock.listCalls())
+}
+
+func TestListCacheDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mock := &mockRuntime{name: "docker", containers: []models.ContainerInfo{{ID: "1", Name: "web", Runtime: "docker"}}}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/containers", handler.ListContainers)
+
+	for range 3 {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/containers", nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, 3, mock.listCalls())
+}
+
+func TestListCacheSkipsListsFetchedBeforeInvalidation(t *testing.T) {
+	cache := newListCache()
+	cache.update(time.Minute, nil)
+	filters := models.FilterOptions{Runtime: "all"}
+
+	_, generation, ok := cache.get(filters)
+	require.False(t, ok)
+	cache.invalidate()
+	cache.put(filters, generation, []models.ContainerInfo{{ID: "stale"}})
+	_, _, ok = cache.get(filters)
+	assert.False(t, ok)
+
+	_, generation, _ = cache.get(filters)
+	cache.put(filters, generation, []models.ContainerInfo{{ID: "fresh"}})
+	containers, _, ok := cache.get(filters)
+	require.True(t, ok)
+	assert.Equal(t, "fresh", containers[0].ID)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+)
+
+// grepReader passes through only the log lines matching a pattern.
+// Lines are read one at a time, so it works for followed streams as well.
+type grepReader struct {
+	source  io.ReadCloser
+	lines   *bufio.Reader
+	pattern *regexp.Regexp
+	pending []byte
+}
+
+// newGrepReader wraps a log stream so that only lines matching pattern are returned
+func newGrepReader(source io.ReadCloser, pattern *regexp.Regexp) io.ReadCloser {
+	return &grepReader{
+		source:  source,
+		lines:   bufio.NewReader(source),
+		pattern: pattern,
+	}
+}
+
+func (g *grepReader) Read(p []byte) (int, error) {
+	for len(g.pending) == 0 {
+		line, err := g.lines.ReadBytes('\n')
+		if len(line) > 0 && g.pattern.Match(bytes.TrimRight(line, "\r\n")) {
+			g.pending = line
+		}
+		if err != nil {
+			if len(g.pending) == 0 {
+				return 0, err
+			}
+			break
+		}
+	}
+
+	n := copy(p, g.pending)
+	g.pending = g.pending[n:]
+	return n, nil
+}
+
+func (g *grepReader) Close() error {
+	return g.source.Close()
+}
+
+// compileGrepPattern compiles the grep query parameter, optionally case-insensitive.
+// An empty pattern returns nil.
+func compileGrepPattern(pattern string, ignoreCase bool) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrepReader(t *testing.T) {
+	pattern, err := compileGrepPattern("error|warn", false)
+	require.NoError(t, err)
+
+	source := io.NopCloser(strings.NewReader("starting\nerror: disk full\nok\nwarn: slow\r\nERROR upper\nlast error"))
+	content, err := io.ReadAll(newGrepReader(source, pattern))
+	require.NoError(t, err)
+	assert.Equal(t, "error: disk full\nwarn: slow\r\nlast error", string(content))
+}
+
+func TestGrepReaderIgnoreCase(t *testing.T) {
+	pattern, err := compileGrepPattern("^error", true)
+	require.NoError(t, err)
+
+	source := io.NopCloser(strings.NewReader("ERROR upper\nerror lower\nno error\n"))
+	content, err := io.ReadAll(newGrepReader(source, pattern))
+	require.NoError(t, err)
+	assert.Equal(t, "ERROR upper\nerror lower\n", string(content))
+}
+
+func TestGrepReaderSmallBuffer(t *testing.T) {
+	pattern, err := compileGrepPattern("match", false)
+	require.NoError(t, err)
+
+	reader := newGrepReader(io.NopCloser(strings.NewReader("skip\na long matching line\n")), pattern)
+	buf := make([]byte, 4)
+	var out strings.Builder
+	for {
+		n, err := reader.Read(buf)
+		out.Write(buf[:n])
+		if err != nil {
+			assert.ErrorIs(t, err, io.EOF)
+			break
+		}
+	}
+	assert.Equal(t, "a long matching line\n", out.String())
+}
+
+func TestCompileGrepPattern(t *testing.T) {
+	pattern, err := compileGrepPattern("", true)
+	require.NoError(t, err)
+	assert.Nil(t, pattern)
+
+	_, err = compileGrepPattern("([a-z", false)
+	assert.Error(t, err)
+}
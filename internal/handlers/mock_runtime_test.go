@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+)
+
+// Keep the handler test mock in sync with the runtime interface
+var _ runtime.ContainerRuntime = (*mockRuntime)(nil)
+
+// mockRuntime is a ContainerRuntime that serves a fixed container list.
+// Methods the handler tests do not use are left to the embedded nil interface.
+type mockRuntime struct {
+	runtime.ContainerRuntime
+
+	name       string
+	containers []models.ContainerInfo
+
+	// failures makes container actions fail for the given container IDs (or compose project names)
+	failures map[string]error
+	// pingErr is returned by Ping and DiskUsage
+	pingErr error
+	// version is returned by Version, which fails with pingErr
+	version string
+	// capabilities is returned by Capabilities
+	capabilities models.RuntimeCapabilities
+	// listDelay makes ListContainers wait before answering, or until its context ends
+	listDelay time.Duration
+	// actionDelay makes StartContainer and StopContainer wait before answering, or until their context ends
+	actionDelay time.Duration
+	// listErr is returned by ListContainers
+	listErr error
+	// ports is returned by ContainerPorts for containers started with RunContainer
+	ports []models.PortMapping
+	// pruned is returned by PruneContainers as the removed IDs, each reclaiming 1024 bytes
+	pruned []string
+	// pullProgress is sent by PullImageStream
+	pullProgress []models.PullProgress
+	// history is returned by ImageHistory
+	history []models.ImageLayer
+	// events is returned by StreamEvents, which fails while it is nil
+	events chan models.RuntimeEvent
+
+	mu sync.Mutex
+	// stopTimeouts records the timeout of each StopContainer/RestartContainer call
+	stopTimeouts []*int
+	// actions records each container action as "<action> <id>"
+	actions []string
+	// listFilters records the filters of each ListContainers call
+	listFilters []models.FilterOptions
+	// runRequests records the request of each RunContainer call
+	runRequests []models.RunContainerRequest
+}
+
+func (m *mockRuntime) record(action, containerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.actions = append(m.actions, action+" "+containerID)
+	return m.failures[containerID]
+}
+
+func (m *mockRuntime) ListContainers(ctx context.Context, filters models.FilterOptions) ([]models.ContainerInfo, error) {
+	m.mu.Lock()
+	m.listFilters = append(m.listFilters, filters)
+	m.mu.Unlock()
+
+	if m.listDelay > 0 {
+		select {
+		case <-time.After(m.listDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	return m.containers, nil
+}
+
+// listCalls returns how often ListContainers was called
+func (m *mockRuntime) listCalls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.listFilters)
+}
+
+// wait blocks for actionDelay, or until ctx ends
+func (m *mockRuntime) wait(ctx context.Context) error {
+	if m.actionDelay == 0 {
+		return nil
+	}
+	select {
+	case <-time.After(m.actionDelay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *mockRuntime) StartContainer(ctx context.Context, containerID string) error {
+	if err := m.wait(ctx); err != nil {
+		return err
+	}
+	return m.record("start", containerID)
+}
+
+func (m *mockRuntime) StopContainer(ctx context.Context, containerID string, timeout *int) error {
+	if err := m.wait(ctx); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.stopTimeouts = append(m.stopTimeouts, timeout)
+	m.mu.Unlock()
+	return m.record("stop", containerID)
+}
+
+func (m *mockRuntime) RestartContainer(ctx context.Context, containerID string, timeout *int) error {
+	m.mu.Lock()
+	m.stopTimeouts = append(m.stopTimeouts, timeout)
+	m.mu.Unlock()
+	return m.record("restart", containerID)
+}
+
+func (m *mockRuntime) KillContainer(ctx context.Context, containerID string, signal string) error {
+	return m.record("kill "+signal, containerID)
+}
+
+func (m *mockRuntime) UpdateContainerResources(ctx context.Context, containerID string, update models.ResourceUpdate) error {
+	return m.record("update "+update.RestartPolicy, containerID)
+}
+
+func (m *mockRuntime) PruneContainers(ctx context.Context) ([]string, uint64, error) {
+	if err := m.record("prune", ""); err != nil {
+		return nil, 0, err
+	}
+	return m.pruned, uint64(len(m.pruned)) * 1024, nil
+}
+
+func (m *mockRuntime) DiskUsage(ctx context.Context) (models.DiskUsage, error) {
+	if m.pingErr != nil {
+		return models.DiskUsage{}, m.pingErr
+	}
+	usage := models.DiskUsage{Runtime: m.name, Images: models.DiskUsageCategory{Total: 2, Active: 1, Size: 3000, Reclaimable: 1000}}
+	usage.TotalSize, usage.Reclaimable = usage.Images.Size, usage.Images.Reclaimable
+	return usage, nil
+}
+
+func (m *mockRuntime) RunContainer(ctx context.Context, req models.RunContainerRequest) (string, error) {
+	m.mu.Lock()
+	m.runRequests = append(m.runRequests, req)
+	m.mu.Unlock()
+	if err := m.record("run", req.Name); err != nil {
+		return "", err
+	}
+	return "id-" + req.Name, nil
+}
+
+func (m *mockRuntime) ContainerPorts(ctx context.Context, containerID string) ([]models.PortMapping, error) {
+	return m.ports, nil
+}
+
+func (m *mockRuntime) DeleteContainer(ctx context.Context, containerID string, force bool) error {
+	return m.record("delete", containerID)
+}
+
+func (m *mockRuntime) DeployFromCompose(ctx context.Context, composeContent, projectName, deploymentPath string, env map[string]string) error {
+	return m.record("deploy", projectName)
+}
+
+func (m *mockRuntime) RemoveComposeDeployment(ctx context.Context, projectName, deploymentPath string, removeVolumes bool) error {
+	return m.record("down", projectName)
+}
+
+func (m *mockRuntime) ComposeScale(ctx context.Context, projectName, deploymentPath, service string, replicas int) error {
+	if err := m.record(fmt.Sprintf("scale %s=%d", service, replicas), projectName); err != nil {
+		return err
+	}
+	if service != "web" {
+		return fmt.Errorf("%w: unknown service %q", runtime.ErrInvalidCompose, service)
+	}
+	return nil
+}
+
+func (m *mockRuntime) PullImageStream(ctx context.Context, imageName string, auth *models.RegistryAuth) (<-chan models.PullProgress, error) {
+	if err := m.record("pull", imageName); err != nil {
+		return nil, err
+	}
+	updates := make(chan models.PullProgress, len(m.pullProgress))
+	for _, progress := range m.pullProgress {
+		updates <- progress
+	}
+	close(updates)
+	return updates, nil
+}
+
+func (m *mockRuntime) ImageHistory(ctx context.Context, imageName string) ([]models.ImageLayer, error) {
+	if err := m.record("history", imageName); err != nil {
+		return nil, err
+	}
+	return m.history, nil
+}
+
+func (m *mockRuntime) ListContainerPath(ctx context.Context, containerID, path string) ([]models.FileEntry, error) {
+	if err := m.record("ls "+path, containerID); err != nil {
+		return nil, err
+	}
+	return []models.FileEntry{{Name: "syslog", Size: 42, Mode: "-rw-r--r--"}}, nil
+}
+
+func (m *mockRuntime) CheckpointContainer(ctx context.Context, containerID, exportPath string) error {
+	if err := m.record("checkpoint", containerID); err != nil {
+		return err
+	}
+	return os.WriteFile(exportPath, []byte("checkpoint"), 0600)
+}
+
+func (m *mockRuntime) RestoreContainer(ctx context.Context, importPath string) (string, error) {
+	if err := m.record("restore", filepath.Base(importPath)); err != nil {
+		return "", err
+	}
+	return "restored-id", nil
+}
+
+func (m *mockRuntime) GenerateSystemd(ctx context.Context, containerID string, opts models.SystemdOptions) (map[string]string, error) {
+	if err := m.record("systemd", containerID); err != nil {
+		return nil, err
+	}
+	return map[string]string{opts.NamePrefix + "-" + containerID: "[Service]\nRestart=" + opts.RestartPolicy + "\n"}, nil
+}
+
+func (m *mockRuntime) GenerateKube(ctx context.Context, ids []string) (string, error) {
+	if err := m.record("kube", strings.Join(ids, ",")); err != nil {
+		return "", err
+	}
+	return "apiVersion: v1\nkind: Pod\nmetadata:\n  name: " + ids[0] + "\n", nil
+}
+
+func (m *mockRuntime) StreamEvents(ctx context.Context) (<-chan models.RuntimeEvent, error) {
+	if m.events == nil {
+		return nil, errors.New("events not supported")
+	}
+	return m.events, nil
+}
+
+func (m *mockRuntime) GetRuntimeName() string {
+	return m.name
+}
+
+func (m *mockRuntime) Capabilities() models.RuntimeCapabilities {
+	return m.capabilities
+}
+
+func (m *mockRuntime) Version(ctx context.Context) (string, error) {
+	return m.version, m.pingErr
+}
+
+func (m *mockRuntime) Ping(ctx context.Context) error {
+	return m.pingErr
+}
+
+// newMockManager returns a runtime manager with the given mocks registered under their names
+func newMockManager(runtimes ...*mockRuntime) *runtime.Manager {
+	manager := runtime.NewManager()
+	for _, rt := range runtimes {
+		manager.RegisterRuntime(rt.name, rt)
+	}
+	return manager
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// portConflict describes a requested host port that is already in use
+type portConflict struct {
+	Port      int    `json:"port"`
+	Protocol  string `json:"protocol"`
+	Container string `json:"container,omitempty"` // Name of the container publishing the port, empty for other host listeners
+	Runtime   string `json:"runtime,omitempty"`
+}
+
+func (pc portConflict) Error() string {
+	if pc.Container != "" {
+		return fmt.Sprintf("host port %d/%s is already used by container %s (%s)", pc.Port, pc.Protocol, pc.Container, pc.Runtime)
+	}
+	return fmt.Sprintf("host port %d/%s is already in use on the host", pc.Port, pc.Protocol)
+}
+
+// findPortConflict checks the requested host ports against the ports published by the running
+// containers of all runtimes and against listeners on this host. It is a best-effort pre-flight
+// check: runtimes that cannot be listed are skipped, and listeners are only seen on the Gintainer host.
+func (h *Handler) findPortConflict(ctx context.Context, requested []models.PortMapping) *portConflict {
+	if len(requested) == 0 {
+		return nil
+	}
+
+	containers, err := h.collectContainers(ctx, models.FilterOptions{Runtime: "all"})
+	if err != nil {
+		logger.Warn("findPortConflict: Failed to list containers", "error", err)
+	}
+
+	for _, port := range requested {
+		for _, container := range containers {
+			if container.State != "running" {
+				continue
+			}
+			for _, published := range container.Ports {
+				if published.HostPort == port.HostPort && published.Protocol == port.Protocol {
+					return &portConflict{Port: port.HostPort, Protocol: port.Protocol, Container: container.Name, Runtime: container.Runtime}
+				}
+			}
+		}
+
+		if hostPortInUse(port.HostPort, port.Protocol) {
+			return &portConflict{Port: port.HostPort, Protocol: port.Protocol}
+		}
+	}
+	return nil
+}
+
+// hostPortInUse reports whether something on this host already listens on the port.
+// Only "address in use" counts, so ports that need privileges to bind are not reported.
+func hostPortInUse(port int, protocol string) bool {
+	address := ":" + strconv.Itoa(port)
+
+	var err error
+	switch protocol {
+	case "tcp":
+		var listener net.Listener
+		if listener, err = net.Listen("tcp", address); err == nil {
+			listener.Close()
+		}
+	case "udp":
+		var conn net.PacketConn
+		if conn, err = net.ListenPacket("udp", address); err == nil {
+			conn.Close()
+		}
+	default:
+		return false
+	}
+	return errors.Is(err, syscall.EADDRINUSE)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runWithPorts posts a run request with the given port mappings
+func runWithPorts(router *gin.Engine, ports ...string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(models.RunContainerRequest{Name: "new", Image: "nginx", Runtime: "docker", Ports: ports})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/containers/run", strings.NewReader(string(body)))
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRunContainerPortConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker", containers: []models.ContainerInfo{
+		{ID: "a", Name: "web", Runtime: "docker", State: "running", Ports: []models.PortMapping{{ContainerPort: 80, HostPort: 48080, Protocol: "tcp"}}},
+		{ID: "b", Name: "old", Runtime: "docker", State: "exited", Ports: []models.PortMapping{{ContainerPort: 80, HostPort: 48081, Protocol: "tcp"}}},
+	}}
+	podman := &mockRuntime{name: "podman", containers: []models.ContainerInfo{
+		{ID: "c", Name: "dns", Runtime: "podman", State: "running", Ports: []models.PortMapping{{ContainerPort: 53, HostPort: 48053, Protocol: "udp"}}},
+	}}
+	handler := NewHandler(newMockManager(docker, podman), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/containers/run", handler.RunContainer)
+
+	w := runWithPorts(router, "48080:80")
+	require.Equal(t, http.StatusConflict, w.Code)
+	var resp struct {
+		Error    string       `json:"error"`
+		Conflict portConflict `json:"conflict"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, portConflict{Port: 48080, Protocol: "tcp", Container: "web", Runtime: "docker"}, resp.Conflict)
+	assert.Contains(t, resp.Error, "web")
+
+	// Ports of other runtimes count too, but only for the same protocol
+	assert.Equal(t, http.StatusConflict, runWithPorts(router, "48053:53/udp").Code)
+	assert.Equal(t, http.StatusOK, runWithPorts(router, "48053:53").Code)
+
+	// Stopped containers, random ports and host ranges are not checked
+	assert.Equal(t, http.StatusOK, runWithPorts(router, "48081:80").Code)
+	assert.Equal(t, http.StatusOK, runWithPorts(router, "80").Code)
+	assert.Equal(t, http.StatusOK, runWithPorts(router, "48080-48085:80").Code)
+
+	assert.Equal(t, http.StatusBadRequest, runWithPorts(router, "http:80").Code)
+	assert.Equal(t, []string{"run new", "run new", "run new", "run new"}, docker.actions)
+}
+
+func TestRunContainerHostListenerConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	handler := NewHandler(newMockManager(&mockRuntime{name: "docker"}), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+	router := gin.New()
+	router.POST("/api/containers/run", handler.RunContainer)
+
+	w := runWithPorts(router, fmt.Sprintf("%d:80", port))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "in use on the host")
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// WatchRemovedContainers follows the event streams of the registered runtimes and deletes the
+// Caddyfile of every removed container, e.g. containers run with auto_remove that exited. It
+// replaces the watchers of an earlier call and is meant to be called again after the runtimes changed.
+func (h *Handler) WatchRemovedContainers() {
+	h.watchMu.Lock()
+	defer h.watchMu.Unlock()
+
+	if h.stopRemovalWatch != nil {
+		h.stopRemovalWatch()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	h.stopRemovalWatch = cancel
+
+	for name, rt := range h.runtimeManager.GetAllRuntimes() {
+		go followEvents(ctx, "WatchRemovedContainers", name, rt, func(event models.RuntimeEvent) {
+			h.handleRemovedContainer(ctx, event)
+		}, nil)
+	}
+}
+
+// StopWatchingRemovedContainers stops following the runtime event streams
+func (h *Handler) StopWatchingRemovedContainers() {
+	h.watchMu.Lock()
+	defer h.watchMu.Unlock()
+
+	if h.stopRemovalWatch != nil {
+		h.stopRemovalWatch()
+		h.stopRemovalWatch = nil
+	}
+}
+
+// handleRemovedContainer deletes the Caddyfile of a container that was removed
+func (h *Handler) handleRemovedContainer(ctx context.Context, event models.RuntimeEvent) {
+	if event.Type != "destroy" || h.caddyService == nil || !h.caddyService.IsEnabled() {
+		return
+	}
+
+	if err := h.caddyService.DeleteCaddyfile(ctx, event.ContainerID); err != nil {
+		logger.Warn("WatchRemovedContainers: Failed to delete Caddyfile", "runtime", event.Runtime, "id", event.ContainerID, "error", err)
+		return
+	}
+	logger.Debug("WatchRemovedContainers: Cleaned up Caddyfile of removed container", "runtime", event.Runtime, "id", event.ContainerID)
+}
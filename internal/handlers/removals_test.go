@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchRemovedContainers(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []string{"job", "web"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "gintainer-"+id+".caddy"), []byte("example.com {\n}\n"), 0644))
+	}
+
+	mock := &mockRuntime{name: "docker", events: make(chan models.RuntimeEvent)}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: true, CaddyfilePath: dir}), nil)
+	handler.WatchRemovedContainers()
+	t.Cleanup(handler.StopWatchingRemovedContainers)
+
+	// Only removals delete the Caddyfile, the exit of an auto-removed container is followed by one
+	mock.events <- models.RuntimeEvent{Type: "die", ContainerID: "job", Runtime: "docker"}
+	mock.events <- models.RuntimeEvent{Type: "destroy", ContainerID: "job", Runtime: "docker"}
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(filepath.Join(dir, "gintainer-job.caddy"))
+		return os.IsNotExist(err)
+	}, time.Second, 10*time.Millisecond)
+	assert.FileExists(t, filepath.Join(dir, "gintainer-web.caddy"))
+}
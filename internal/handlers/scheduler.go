@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/ThraaxSession/gintainer/internal/config"
 	"github.com/ThraaxSession/gintainer/internal/logger"
@@ -24,11 +28,60 @@ func NewSchedulerHandler(scheduler *scheduler.Scheduler, configManager *config.M
 	}
 }
 
+// schedulerConfigResponse is the scheduler configuration together with upcoming run times
+type schedulerConfigResponse struct {
+	models.CronJobConfig
+	ResolvedTimezone string               `json:"resolved_timezone"`   // Time zone the schedules run in, "Local" for the server's local time
+	NextRun          *time.Time           `json:"next_run,omitempty"`  // Earliest upcoming run across all jobs
+	NextRuns         map[string]time.Time `json:"next_runs,omitempty"` // Job name -> next run
+}
+
 // GetConfig handles GET /api/scheduler/config
 func (sh *SchedulerHandler) GetConfig(c *gin.Context) {
 	logger.Info("GetConfig: Retrieving scheduler configuration")
-	config := sh.scheduler.GetConfig()
-	c.JSON(http.StatusOK, config)
+	response := schedulerConfigResponse{
+		CronJobConfig:    sh.scheduler.GetConfig(),
+		ResolvedTimezone: sh.scheduler.Location().String(),
+		NextRuns:         sh.scheduler.NextRuns(),
+	}
+	for _, next := range response.NextRuns {
+		if response.NextRun == nil || next.Before(*response.NextRun) {
+			next := next
+			response.NextRun = &next
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// SchedulePreviewRequest is a schedule to preview
+type SchedulePreviewRequest struct {
+	Schedule string `json:"schedule" binding:"required"`
+	Timezone string `json:"timezone"` // IANA time zone, default: server local time
+}
+
+// PreviewSchedule handles POST /api/scheduler/preview - returns the next run times of a schedule without saving it
+func (sh *SchedulerHandler) PreviewSchedule(c *gin.Context) {
+	var req SchedulePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	runs, err := scheduler.Preview(req.Schedule, req.Timezone, time.Now(), scheduler.PreviewRuns)
+	if err != nil {
+		logger.Debug("PreviewSchedule: Invalid schedule", "schedule", req.Schedule, "timezone", req.Timezone, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loc, _ := scheduler.LoadTimezone(req.Timezone) // Already resolved by Preview
+	c.JSON(http.StatusOK, gin.H{"schedule": req.Schedule, "timezone": loc.String(), "next_runs": runs})
+}
+
+// GetHistory handles GET /api/scheduler/history
+func (sh *SchedulerHandler) GetHistory(c *gin.Context) {
+	logger.Info("GetHistory: Retrieving scheduler run history")
+	c.JSON(http.StatusOK, gin.H{"history": sh.scheduler.GetHistory()})
 }
 
 // UpdateConfig handles PUT /api/scheduler/config
@@ -42,20 +95,22 @@ func (sh *SchedulerHandler) UpdateConfig(c *gin.Context) {
 		return
 	}
 
-	logger.Info("UpdateConfig: Updating scheduler - Enabled: , Schedule: , Filters", "arg1", config.Enabled, "arg2", config.Schedule, "filter3", config.Filters)
+	logger.Info("UpdateConfig: Updating scheduler - Enabled: , Schedule: , Filters", "arg1", config.Enabled, "arg2", config.Schedule, "filter3", config.Filters, "jobs", len(config.Jobs))
 
 	// Update scheduler runtime state
 	if err := sh.scheduler.UpdateConfig(config); err != nil {
 		logger.Error("UpdateConfig: Failed to update scheduler configuration", "error", err)
+		if errors.Is(err, scheduler.ErrInvalidTimezone) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Persist to config file
 	cfg := sh.configManager.GetConfig()
-	cfg.Scheduler.Enabled = config.Enabled
-	cfg.Scheduler.Schedule = config.Schedule
-	cfg.Scheduler.Filters = config.Filters
+	cfg.Scheduler = scheduler.ToConfig(config)
 
 	if err := sh.configManager.UpdateConfig(cfg); err != nil {
 		logger.Error("UpdateConfig: Failed to persist scheduler configuration to file", "error", err)
@@ -68,3 +123,35 @@ func (sh *SchedulerHandler) UpdateConfig(c *gin.Context) {
 	logger.Info("UpdateConfig: Scheduler configuration updated successfully")
 	c.JSON(http.StatusOK, gin.H{"message": "scheduler config updated successfully"})
 }
+
+// RunNow handles POST /api/scheduler/run
+func (sh *SchedulerHandler) RunNow(c *gin.Context) {
+	logger.Info("RunNow: Received manual scheduler run request from", "client_ip", c.ClientIP())
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be a boolean"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	// Don't abort half-finished container updates if the client disconnects
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	report, err := sh.scheduler.RunNow(ctx, dryRun)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrRunInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("RunNow: Failed to run scheduler jobs", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("RunNow: Manual scheduler run completed", "updated", report.Updated, "failed", report.Failed, "skipped", report.Skipped)
+	c.JSON(http.StatusOK, report)
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ThraaxSession/gintainer/internal/config"
 	"github.com/ThraaxSession/gintainer/internal/models"
@@ -161,3 +162,155 @@ func TestSchedulerUpdateConfigInvalidCronExpression(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func TestSchedulerUpdateConfigWithJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configPath := filepath.Join(t.TempDir(), "test-config.yaml")
+	configManager, err := config.NewManager(configPath)
+	assert.NoError(t, err)
+	defer configManager.Close()
+
+	sched := scheduler.NewScheduler(runtime.NewManager())
+	handler := NewSchedulerHandler(sched, configManager)
+
+	router := gin.New()
+	router.PUT("/api/scheduler/config", handler.UpdateConfig)
+
+	newConfig := models.CronJobConfig{
+		Enabled:  true,
+		Schedule: "0 2 * * *",
+		Filters:  []string{"web-*"},
+		Jobs: []models.CronJob{
+			{Name: "databases", Schedule: "0 3 * * 0", Enabled: true, Filters: []string{"db-*"}},
+		},
+	}
+
+	body, _ := json.Marshal(newConfig)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/api/scheduler/config", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, sched.GetJobs(), 2)
+
+	// Verify the jobs were persisted
+	cfg := configManager.GetConfig()
+	assert.Len(t, cfg.Scheduler.Jobs, 1)
+	assert.Equal(t, "databases", cfg.Scheduler.Jobs[0].Name)
+	assert.Equal(t, []string{"db-*"}, cfg.Scheduler.Jobs[0].Filters)
+}
+
+func TestSchedulerRunNow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configPath := filepath.Join(t.TempDir(), "test-config.yaml")
+	configManager, err := config.NewManager(configPath)
+	assert.NoError(t, err)
+	defer configManager.Close()
+
+	sched := scheduler.NewScheduler(runtime.NewManager())
+	handler := NewSchedulerHandler(sched, configManager)
+
+	router := gin.New()
+	router.POST("/api/scheduler/run", handler.RunNow)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/scheduler/run", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report models.UpdateReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, scheduler.ManualRunName, report.Job)
+	assert.Empty(t, report.Results)
+}
+
+func TestSchedulerTimezone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "test-config.yaml"))
+	assert.NoError(t, err)
+	defer configManager.Close()
+
+	sched := scheduler.NewScheduler(runtime.NewManager())
+	sched.Start()
+	defer sched.Stop()
+	handler := NewSchedulerHandler(sched, configManager)
+
+	router := gin.New()
+	router.GET("/api/scheduler/config", handler.GetConfig)
+	router.PUT("/api/scheduler/config", handler.UpdateConfig)
+
+	put := func(cfg models.CronJobConfig) int {
+		body, _ := json.Marshal(cfg)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/api/scheduler/config", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, put(models.CronJobConfig{Enabled: true, Schedule: "0 2 * * *", Timezone: "Nowhere/Special"}))
+	assert.Equal(t, http.StatusOK, put(models.CronJobConfig{Enabled: true, Schedule: "0 2 * * *", Timezone: "Europe/Berlin"}))
+	assert.Equal(t, "Europe/Berlin", configManager.GetConfig().Scheduler.Timezone)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/scheduler/config", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Timezone         string    `json:"timezone"`
+		ResolvedTimezone string    `json:"resolved_timezone"`
+		NextRun          time.Time `json:"next_run"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Europe/Berlin", response.Timezone)
+	assert.Equal(t, "Europe/Berlin", response.ResolvedTimezone)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.NextRun.In(berlin).Hour())
+}
+
+func TestSchedulerPreview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewSchedulerHandler(scheduler.NewScheduler(runtime.NewManager()), nil)
+	router := gin.New()
+	router.POST("/api/scheduler/preview", handler.PreviewSchedule)
+
+	preview := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/scheduler/preview", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := preview(`{"schedule": "30 6 * * 1", "timezone": "America/New_York"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Timezone string      `json:"timezone"`
+		NextRuns []time.Time `json:"next_runs"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "America/New_York", response.Timezone)
+	assert.Len(t, response.NextRuns, scheduler.PreviewRuns)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	for _, run := range response.NextRuns {
+		run = run.In(newYork)
+		assert.Equal(t, time.Monday, run.Weekday())
+		assert.Equal(t, 6, run.Hour())
+		assert.Equal(t, 30, run.Minute())
+	}
+
+	w = preview(`{"schedule": "61 * * * *"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "61")
+	assert.Equal(t, http.StatusBadRequest, preview(`{"schedule": "0 2 * * *", "timezone": "Nowhere/Special"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, preview(`{}`).Code)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/ThraaxSession/gintainer/internal/version"
+	"github.com/gin-gonic/gin"
+)
+
+// runtimeUnavailable is reported as the version of runtimes that do not answer
+const runtimeUnavailable = "unavailable"
+
+// SystemPrune handles POST /api/system/prune
+func (h *Handler) SystemPrune(c *gin.Context) {
+	logger.Info("SystemPrune: Received prune request from", "client_ip", c.ClientIP())
+
+	if c.Query("confirm") != "true" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "prune requires confirm=true"})
+		return
+	}
+
+	var opts models.PruneOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		logger.Error("SystemPrune: Failed to bind query parameters", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Without an explicit selection prune everything except volumes, like `docker system prune`
+	if !opts.Containers && !opts.Images && !opts.AllImages && !opts.Networks && !opts.Volumes && !opts.BuildCache {
+		opts.Containers = true
+		opts.Images = true
+		opts.Networks = true
+		opts.BuildCache = true
+	}
+
+	runtimes, ok := h.selectRuntimes(c.DefaultQuery("runtime", "all"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	logger.Info("SystemPrune: Pruning resources", "runtimes", len(runtimes), "containers", opts.Containers, "images", opts.Images, "all_images", opts.AllImages, "networks", opts.Networks, "volumes", opts.Volumes, "build_cache", opts.BuildCache)
+
+	reports := make([]models.PruneReport, 0, len(runtimes))
+	var reclaimed uint64
+	for name, rt := range runtimes {
+		report, err := rt.SystemPrune(c.Request.Context(), opts)
+		if err != nil {
+			logger.Error("SystemPrune: Failed to prune runtime", "runtime", name, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "reports": reports})
+			return
+		}
+		reclaimed += report.ReclaimedBytes
+		reports = append(reports, report)
+	}
+
+	logger.Info("SystemPrune: Prune completed", "reclaimed_bytes", reclaimed)
+	c.JSON(http.StatusOK, gin.H{"reports": reports, "reclaimed_bytes": reclaimed})
+}
+
+// DiskUsage handles GET /api/system/df - reports the disk space used and reclaimable per runtime
+func (h *Handler) DiskUsage(c *gin.Context) {
+	runtimes, ok := h.selectRuntimes(c.DefaultQuery("runtime", "all"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return
+	}
+
+	usages := make([]models.DiskUsage, 0, len(runtimes))
+	var totalSize, reclaimable uint64
+	for name, rt := range runtimes {
+		usage, err := rt.DiskUsage(c.Request.Context())
+		if err != nil {
+			logger.Error("DiskUsage: Failed to get disk usage", "runtime", name, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		totalSize += usage.TotalSize
+		reclaimable += usage.Reclaimable
+		usages = append(usages, usage)
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Runtime < usages[j].Runtime })
+
+	c.JSON(http.StatusOK, gin.H{"usage": usages, "total_size": totalSize, "reclaimable": reclaimable})
+}
+
+// selectRuntimes resolves a runtime query value ("all" or a runtime name) to the runtimes it refers to
+func (h *Handler) selectRuntimes(runtimeName string) (map[string]runtime.ContainerRuntime, bool) {
+	if runtimeName == "all" {
+		return h.runtimeManager.GetAllRuntimes(), true
+	}
+
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		return nil, false
+	}
+	return map[string]runtime.ContainerRuntime{runtimeName: rt}, true
+}
+
+// capableRuntime returns the named runtime if it has the capability checked by supported, or responds with 400
+func (h *Handler) capableRuntime(c *gin.Context, runtimeName, feature string, supported func(models.RuntimeCapabilities) bool) (runtime.ContainerRuntime, bool) {
+	rt, ok := h.runtimeManager.GetRuntime(runtimeName)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid runtime"})
+		return nil, false
+	}
+	if !supported(rt.Capabilities()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("runtime %s does not support %s", runtimeName, feature)})
+		return nil, false
+	}
+	return rt, true
+}
+
+// ListRuntimes handles GET /api/runtimes - lists the registered runtimes and their capabilities
+func (h *Handler) ListRuntimes(c *gin.Context) {
+	runtimes := h.runtimeManager.GetAllRuntimes()
+	infos := make([]models.RuntimeInfo, 0, len(runtimes))
+	for name, rt := range runtimes {
+		infos = append(infos, models.RuntimeInfo{Name: name, Capabilities: rt.Capabilities()})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+
+	c.JSON(http.StatusOK, gin.H{"runtimes": infos})
+}
+
+// GetVersion handles GET /api/version - reports the gintainer build and the version of each runtime.
+// Unlike the health check it never fails; runtimes that do not answer are reported as "unavailable".
+func (h *Handler) GetVersion(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+	defer cancel()
+
+	runtimes := h.runtimeManager.GetAllRuntimes()
+	versions := make(map[string]string, len(runtimes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, rt := range runtimes {
+		wg.Add(1)
+		go func(name string, rt runtime.ContainerRuntime) {
+			defer wg.Done()
+			v, err := rt.Version(ctx)
+			if err != nil {
+				logger.Warn("GetVersion: Failed to get runtime version", "runtime", name, "error", err)
+				v = runtimeUnavailable
+			}
+			mu.Lock()
+			versions[name] = v
+			mu.Unlock()
+		}(name, rt)
+	}
+	wg.Wait()
+
+	c.JSON(http.StatusOK, gin.H{"gintainer": version.Get(), "runtimes": versions})
+}
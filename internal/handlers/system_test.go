@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/ThraaxSession/gintainer/internal/version"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemPruneRequiresConfirm(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewHandler(runtime.NewManager(), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/system/prune", handler.SystemPrune)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/system/prune?volumes=true", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSystemPruneInvalidRuntime(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewHandler(runtime.NewManager(), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.POST("/api/system/prune", handler.SystemPrune)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/system/prune?confirm=true&runtime=unknown", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListRuntimes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker", capabilities: models.RuntimeCapabilities{Compose: true}}
+	podman := &mockRuntime{name: "podman", capabilities: models.RuntimeCapabilities{Pods: true, Compose: true}}
+	handler := NewHandler(newMockManager(podman, docker), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/runtimes", handler.ListRuntimes)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/runtimes", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Runtimes []models.RuntimeInfo `json:"runtimes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []models.RuntimeInfo{
+		{Name: "docker", Capabilities: models.RuntimeCapabilities{Compose: true}},
+		{Name: "podman", Capabilities: models.RuntimeCapabilities{Pods: true, Compose: true}},
+	}, resp.Runtimes)
+}
+
+func TestGetVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	docker := &mockRuntime{name: "docker", version: "28.5.2"}
+	podman := &mockRuntime{name: "podman", pingErr: errors.New("connection refused")}
+	handler := NewHandler(newMockManager(docker, podman), caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/version", handler.GetVersion)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/version", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "a runtime being down does not fail the request")
+
+	var resp struct {
+		Gintainer version.Info      `json:"gintainer"`
+		Runtimes  map[string]string `json:"runtimes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, version.Get(), resp.Gintainer)
+	assert.Equal(t, map[string]string{"docker": "28.5.2", "podman": "unavailable"}, resp.Runtimes)
+}
+
+func TestDiskUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	manager := newMockManager(&mockRuntime{name: "podman"}, &mockRuntime{name: "docker"})
+	handler := NewHandler(manager, caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/system/df", handler.DiskUsage)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/system/df", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Usage       []models.DiskUsage `json:"usage"`
+		TotalSize   uint64             `json:"total_size"`
+		Reclaimable uint64             `json:"reclaimable"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Usage, 2)
+	assert.Equal(t, "docker", resp.Usage[0].Runtime)
+	assert.Equal(t, "podman", resp.Usage[1].Runtime)
+	assert.Equal(t, 1, resp.Usage[0].Images.Active)
+	assert.Equal(t, uint64(6000), resp.TotalSize)
+	assert.Equal(t, uint64(2000), resp.Reclaimable)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/system/df?runtime=unknown", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDiskUsageRuntimeError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	manager := newMockManager(&mockRuntime{name: "docker", pingErr: errors.New("connection refused")})
+	handler := NewHandler(manager, caddy.NewService(&config.CaddyConfig{Enabled: false}), nil)
+
+	router := gin.New()
+	router.GET("/api/system/df", handler.DiskUsage)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/system/df?runtime=docker", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "connection refused")
+}
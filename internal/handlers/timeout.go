@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRuntimeTimeout bounds a single runtime call when server.runtime_timeout is not set,
+// so a wedged daemon socket can not block a handler forever
+var defaultRuntimeTimeout = 30 * time.Second
+
+// runtimeTimeout returns the configured upper bound of a runtime call
+func (h *Handler) runtimeTimeout() time.Duration {
+	if h.configManager != nil {
+		if timeout := h.configManager.GetConfig().Server.RuntimeTimeoutDuration(); timeout > 0 {
+			return timeout
+		}
+	}
+	return defaultRuntimeTimeout
+}
+
+// runtimeContext derives the context of a runtime call from the request, ending it after the runtime timeout.
+// extra extends the deadline for calls that wait on purpose, e.g. for a container to stop.
+func (h *Handler) runtimeContext(c *gin.Context, extra time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.Request.Context(), h.runtimeTimeout()+extra)
+}
+
+// stopWait returns how long stopping a container may take with the requested stop timeout
+func stopWait(timeout *int) time.Duration {
+	if timeout == nil {
+		return time.Duration(runtime.DefaultStopTimeout) * time.Second
+	}
+	return time.Duration(*timeout) * time.Second
+}
+
+// respondRuntimeError responds with 504 if the runtime call ran out of time, or with 500 otherwise
+func (h *Handler) respondRuntimeError(c *gin.Context, ctx context.Context, err error) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": fmt.Sprintf("runtime did not respond within %s: %v", h.runtimeTimeout(), err)})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
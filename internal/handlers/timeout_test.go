@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/caddy"
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "test-config.yaml"))
+	require.NoError(t, err)
+	defer configManager.Close()
+	cfg := config.DefaultConfig()
+	cfg.Server.RuntimeTimeout = "50ms"
+	require.NoError(t, configManager.UpdateConfig(cfg))
+
+	mock := &mockRuntime{name: "docker", listDelay: time.Minute, actionDelay: time.Minute}
+	handler := NewHandler(newMockManager(mock), caddy.NewService(&config.CaddyConfig{Enabled: false}), configManager)
+
+	router := gin.New()
+	router.GET("/api/containers", handler.ListContainers)
+	router.POST("/api/containers/:id/start", handler.StartContainer)
+	router.POST("/api/containers/:id/stop", handler.StopContainer)
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/api/containers?runtime=docker"},
+		{"POST", "/api/containers/web/start?runtime=docker"},
+		{"POST", "/api/containers/web/stop?runtime=docker&timeout=0"},
+	}
+	for _, tt := range tests {
+		start := time.Now()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tt.method, tt.path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code, tt.path)
+		assert.Contains(t, w.Body.String(), "runtime did not respond within 50ms", tt.path)
+		assert.Less(t, time.Since(start), time.Second, tt.path)
+	}
+	assert.Empty(t, mock.actions)
+}
+
+func TestRuntimeContextStopWait(t *testing.T) {
+	handler := NewHandler(newMockManager(), nil, nil)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("POST", "/", nil)
+
+	timeout := 5
+	start := time.Now()
+	ctx, cancel := handler.runtimeContext(c, stopWait(&timeout))
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, start.Add(defaultRuntimeTimeout+5*time.Second), deadline, time.Second)
+	assert.Equal(t, 10*time.Second, stopWait(nil))
+}
@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ThraaxSession/gintainer/internal/config"
 	"github.com/ThraaxSession/gintainer/internal/logger"
 	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/charmbracelet/log"
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 )
 
@@ -67,7 +74,7 @@ func (w *WebHandler) ConfigPage(c *gin.Context) {
 	c.HTML(http.StatusOK, "config.html", gin.H{
 		"title":  cfg.UI.Title,
 		"theme":  cfg.UI.Theme,
-		"config": cfg,
+		"config": config.Redacted(cfg),
 	})
 }
 
@@ -80,11 +87,11 @@ func (w *WebHandler) LogsPage(c *gin.Context) {
 	})
 }
 
-// GetConfig handles GET /api/config
+// GetConfig handles GET /api/config - secrets are redacted
 func (w *WebHandler) GetConfig(c *gin.Context) {
 	logger.Info("GetConfig: Retrieving configuration")
 	cfg := w.configManager.GetConfig()
-	c.JSON(http.StatusOK, cfg)
+	c.JSON(http.StatusOK, config.Redacted(cfg))
 }
 
 // UpdateConfigAPI handles POST /api/config
@@ -110,6 +117,146 @@ func (w *WebHandler) UpdateConfigAPI(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "configuration updated successfully"})
 }
 
+// PatchConfig handles PATCH /api/config - merges a partial config onto the current one and saves it
+func (w *WebHandler) PatchConfig(c *gin.Context) {
+	logger.Info("PatchConfig: Received partial configuration update from", "client_ip", c.ClientIP())
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changes, err := w.configManager.PatchConfig(patch)
+	if err != nil {
+		logger.Error("PatchConfig: Failed to update configuration", "error", err)
+		if errors.Is(err, config.ErrInvalidPatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("PatchConfig: Configuration updated and saved successfully", "changes", len(changes))
+	c.JSON(http.StatusOK, gin.H{"message": "configuration updated successfully", "changes": changes})
+}
+
+// DiffConfig handles POST /api/config/diff - previews the changes a candidate config would make
+func (w *WebHandler) DiffConfig(c *gin.Context) {
+	var cfg config.Config
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		logger.Error("DiffConfig: Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Redacted secrets read from GET /api/config are not changes
+	current := w.configManager.GetConfig()
+	config.KeepSecrets(current, &cfg)
+	changes := config.Diff(current, &cfg)
+	c.JSON(http.StatusOK, gin.H{"changes": changes, "count": len(changes)})
+}
+
+// ConfigSchema handles GET /api/config/schema - describes the config fields for rendering the config form
+func (w *WebHandler) ConfigSchema(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"fields": config.Schema()})
+}
+
+// ListConfigBackups handles GET /api/config/backups
+func (w *WebHandler) ListConfigBackups(c *gin.Context) {
+	backups, err := w.configManager.ListBackups()
+	if err != nil {
+		logger.Error("ListConfigBackups: Failed to list backups", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"backups": backups, "count": len(backups)})
+}
+
+// RestoreConfigRequest selects the backup to restore
+type RestoreConfigRequest struct {
+	ID string `json:"id" binding:"required"`
+}
+
+// RestoreConfig handles POST /api/config/restore
+func (w *WebHandler) RestoreConfig(c *gin.Context) {
+	logger.Info("RestoreConfig: Received configuration restore request from", "client_ip", c.ClientIP())
+
+	var req RestoreConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("RestoreConfig: Invalid request body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := w.configManager.RestoreBackup(req.ID); err != nil {
+		logger.Error("RestoreConfig: Failed to restore configuration", "id", req.ID, "error", err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, config.ErrBackupNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("RestoreConfig: Configuration restored successfully", "id", req.ID)
+	c.JSON(http.StatusOK, gin.H{"message": "configuration restored successfully", "id": req.ID})
+}
+
+// ResetConfig handles POST /api/config/reset - replaces the config with the defaults after backing it up
+func (w *WebHandler) ResetConfig(c *gin.Context) {
+	logger.Info("ResetConfig: Received configuration reset request from", "client_ip", c.ClientIP())
+
+	if c.Query("confirm") != "true" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reset requires confirm=true"})
+		return
+	}
+
+	cfg, backupID, err := w.configManager.ResetConfig()
+	if err != nil {
+		logger.Error("ResetConfig: Failed to reset configuration", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("ResetConfig: Configuration reset to defaults", "backup", backupID)
+	c.JSON(http.StatusOK, gin.H{"message": "configuration reset to defaults", "backup": backupID, "config": config.Redacted(cfg)})
+}
+
+// logLevelFilter parses the optional ?level= query param.
+// Entries below the returned level are filtered out; without the param nothing is filtered.
+func logLevelFilter(c *gin.Context) (log.Level, error) {
+	if c.Query("level") == "" {
+		return logger.DebugLevel, nil
+	}
+	return logger.ParseLevel(c.Query("level"))
+}
+
+// DownloadLogs handles GET /api/logs/download - returns the buffered application logs as a text file
+func (w *WebHandler) DownloadLogs(c *gin.Context) {
+	minLevel, err := logLevelFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var sb strings.Builder
+	if logBuffer := logger.GetLogBuffer(); logBuffer != nil {
+		for _, entry := range logBuffer.GetAll() {
+			if logger.EntryLevel(entry) < minLevel {
+				continue
+			}
+			sb.WriteString(logger.FormatLogEntry(entry))
+			sb.WriteString("\n")
+		}
+	}
+
+	filename := fmt.Sprintf("gintainer-logs-%s.txt", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(sb.String()))
+}
+
 // StreamLogs handles GET /api/logs - streams application logs via SSE
 func (w *WebHandler) StreamLogs(c *gin.Context) {
 	logger.Info("StreamLogs: Client connected for log streaming", "client_ip", c.ClientIP())
@@ -119,47 +266,58 @@ func (w *WebHandler) StreamLogs(c *gin.Context) {
 	c.Header("Connection", "keep-alive")
 	c.Header("X-Accel-Buffering", "no")
 
-	// Get historical logs
+	minLevel, err := logLevelFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Resume after the last entry the client has seen. Browsers send Last-Event-ID on reconnect,
+	// which is newer than a since_seq still in the URL.
+	cursor := c.Request.Header.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query("since_seq")
+	}
+	var lastSeq uint64
+	if cursor != "" {
+		lastSeq, err = strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since_seq must be a log sequence number"})
+			return
+		}
+	}
+
+	// sendNew sends the entries after lastSeq, with their sequence number as the event ID
 	logBuffer := logger.GetLogBuffer()
-	if logBuffer != nil {
-		entries := logBuffer.GetAll()
-		for _, entry := range entries {
-			formattedLog := logger.FormatLogEntry(entry)
-			c.SSEvent("log", formattedLog)
-			c.Writer.Flush()
+	sendNew := func() {
+		if logBuffer == nil {
+			return
+		}
+		for _, entry := range logBuffer.Since(lastSeq) {
+			lastSeq = entry.Seq
+			if logger.EntryLevel(entry) < minLevel {
+				continue
+			}
+			c.Render(-1, sse.Event{Id: strconv.FormatUint(entry.Seq, 10), Event: "log", Data: logger.FormatLogEntry(entry)})
 		}
+		c.Writer.Flush()
 	}
 
+	// Send the buffered history first
+	sendNew()
+
 	// Keep connection alive and send new logs as they come
 	clientGone := c.Request.Context().Done()
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
-	lastCount := 0
-	if logBuffer != nil {
-		lastCount = len(logBuffer.GetAll())
-	}
-
 	for {
 		select {
 		case <-clientGone:
 			logger.Info("StreamLogs: Client disconnected", "client_ip", c.ClientIP())
 			return
 		case <-ticker.C:
-			// Check for new logs
-			if logBuffer != nil {
-				entries := logBuffer.GetAll()
-				currentCount := len(entries)
-				if currentCount > lastCount {
-					// Send only new logs
-					for i := lastCount; i < currentCount; i++ {
-						formattedLog := logger.FormatLogEntry(entries[i])
-						c.SSEvent("log", formattedLog)
-						c.Writer.Flush()
-					}
-					lastCount = currentCount
-				}
-			}
+			sendNew()
 			// Send heartbeat to keep connection alive
 			c.SSEvent("heartbeat", "ping")
 			c.Writer.Flush()
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadLogs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger.Info("DownloadLogsTest: info entry")
+	logger.Error("DownloadLogsTest: error entry")
+
+	handler := NewWebHandler(nil, nil)
+	router := gin.New()
+	router.GET("/api/logs/download", handler.DownloadLogs)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/logs/download", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="gintainer-logs-\d{8}-\d{6}\.txt"$`, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), "DownloadLogsTest: info entry")
+	assert.Contains(t, w.Body.String(), "DownloadLogsTest: error entry")
+
+	// Filter by level
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/logs/download?level=error", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "DownloadLogsTest: info entry")
+	assert.Contains(t, w.Body.String(), "DownloadLogsTest: error entry")
+
+	// Invalid level
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/logs/download?level=loud", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRestoreConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "gintainer.yaml"))
+	require.NoError(t, err)
+	defer configManager.Close()
+
+	original := config.DefaultConfig()
+	original.UI.Title = "Original"
+	require.NoError(t, configManager.UpdateConfig(original))
+	edited := config.DefaultConfig()
+	edited.UI.Title = "Edited"
+	require.NoError(t, configManager.UpdateConfig(edited))
+
+	handler := NewWebHandler(nil, configManager)
+	router := gin.New()
+	router.GET("/api/config/backups", handler.ListConfigBackups)
+	router.POST("/api/config/restore", handler.RestoreConfig)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/config/backups", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var list struct {
+		Backups []config.Backup `json:"backups"`
+		Count   int             `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Count)
+
+	restore := func(body string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/config/restore", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, restore(`{}`))
+	assert.Equal(t, http.StatusNotFound, restore(`{"id": "missing.yaml"}`))
+	assert.Equal(t, http.StatusOK, restore(`{"id": "`+list.Backups[0].ID+`"}`))
+	assert.Equal(t, "Original", configManager.GetConfig().UI.Title)
+}
+
+func TestConfigSchema(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "gintainer.yaml"))
+	require.NoError(t, err)
+	defer configManager.Close()
+
+	handler := NewWebHandler(nil, configManager)
+	router := gin.New()
+	router.GET("/api/config/schema", handler.ConfigSchema)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/config/schema", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Fields []config.Field `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.Fields)
+	assert.Equal(t, "server.port", resp.Fields[0].Path)
+	assert.Equal(t, "10000", resp.Fields[0].Default)
+}
+
+func TestDiffConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "gintainer.yaml"))
+	require.NoError(t, err)
+	defer configManager.Close()
+
+	handler := NewWebHandler(nil, configManager)
+	router := gin.New()
+	router.POST("/api/config/diff", handler.DiffConfig)
+
+	candidate := config.DefaultConfig()
+	candidate.Server.Port = "9090"
+	candidate.Caddy.Enabled = true
+	body, err := json.Marshal(candidate)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/config/diff", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Changes []config.Change `json:"changes"`
+		Count   int             `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []config.Change{
+		{Path: "server.port", Old: configManager.GetConfig().Server.Port, New: "9090"},
+		{Path: "caddy.enabled", Old: false, New: true},
+	}, resp.Changes)
+	assert.Equal(t, 2, resp.Count)
+}
+
+func TestGetConfigRedactsSecrets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "gintainer.yaml"))
+	require.NoError(t, err)
+	defer configManager.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Podman.Passphrase = "key-passphrase"
+	cfg.Registries = map[string]config.RegistryCredentials{"docker.io": {Username: "bob", Password: "hunter2"}}
+	require.NoError(t, configManager.UpdateConfig(cfg))
+
+	handler := NewWebHandler(nil, configManager)
+	router := gin.New()
+	router.GET("/api/config", handler.GetConfig)
+	router.POST("/api/config", handler.UpdateConfigAPI)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/config", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "hunter2")
+	assert.NotContains(t, w.Body.String(), "key-passphrase")
+
+	// Posting the redacted config back keeps the stored secrets
+	w2 := httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/config", strings.NewReader(w.Body.String()))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w2, req)
+	require.Equal(t, http.StatusOK, w2.Code)
+	assert.Equal(t, "hunter2", configManager.GetConfig().Registries["docker.io"].Password)
+	assert.Equal(t, "key-passphrase", configManager.GetConfig().Podman.Passphrase)
+}
+
+func TestPatchConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "gintainer.yaml"))
+	require.NoError(t, err)
+	defer configManager.Close()
+
+	handler := NewWebHandler(nil, configManager)
+	router := gin.New()
+	router.PATCH("/api/config", handler.PatchConfig)
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/api/config", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := patch(`{"caddy": {"enabled": true}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Changes []config.Change `json:"changes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []config.Change{{Path: "caddy.enabled", Old: false, New: true}}, resp.Changes)
+	assert.True(t, configManager.GetConfig().Caddy.Enabled)
+	assert.Equal(t, config.DefaultConfig().Caddy.ReloadMethod, configManager.GetConfig().Caddy.ReloadMethod)
+
+	assert.Equal(t, http.StatusBadRequest, patch(`{"caddy": {"unknown": 1}}`).Code)
+	assert.Equal(t, http.StatusBadRequest, patch(`{"server": {"port": ""}}`).Code)
+}
+
+func TestResetConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configManager, err := config.NewManager(filepath.Join(t.TempDir(), "gintainer.yaml"))
+	require.NoError(t, err)
+	defer configManager.Close()
+
+	edited := config.DefaultConfig()
+	edited.UI.Title = "Edited"
+	require.NoError(t, configManager.UpdateConfig(edited))
+
+	handler := NewWebHandler(nil, configManager)
+	router := gin.New()
+	router.POST("/api/config/reset", handler.ResetConfig)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/config/reset", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "Edited", configManager.GetConfig().UI.Title)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/config/reset?confirm=true", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Backup string        `json:"backup"`
+		Config config.Config `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.Backup)
+	assert.Equal(t, config.DefaultConfig().UI.Title, resp.Config.UI.Title)
+	assert.Equal(t, config.DefaultConfig().UI.Title, configManager.GetConfig().UI.Title)
+}
+
+func TestStreamLogsResume(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger.Info("StreamLogsTest: before reconnect")
+	entries := logger.GetLogBuffer().GetAll()
+	cursor := entries[len(entries)-1].Seq
+	logger.Info("StreamLogsTest: after reconnect")
+	logger.Debug("StreamLogsTest: debug after reconnect")
+
+	handler := NewWebHandler(nil, nil)
+	router := gin.New()
+	router.GET("/api/logs", handler.StreamLogs)
+
+	// The client is gone right after the buffered entries are sent
+	stream := func(url, lastEventID string) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Without a cursor the whole buffer is replayed, with the sequence number as event ID
+	w := stream("/api/logs", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "StreamLogsTest: before reconnect")
+	assert.Contains(t, w.Body.String(), fmt.Sprintf("id:%d\n", cursor))
+
+	for _, w := range []*httptest.ResponseRecorder{
+		stream("/api/logs", fmt.Sprint(cursor)),
+		stream(fmt.Sprintf("/api/logs?since_seq=%d", cursor), ""),
+		// Last-Event-ID from the reconnect wins over the since_seq of the original URL
+		stream("/api/logs?since_seq=0", fmt.Sprint(cursor)),
+	} {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "StreamLogsTest: before reconnect")
+		assert.Contains(t, w.Body.String(), "StreamLogsTest: after reconnect")
+		assert.Contains(t, w.Body.String(), fmt.Sprintf("id:%d\n", cursor+1))
+	}
+
+	// Level filtering still applies to resumed streams
+	w = stream(fmt.Sprintf("/api/logs?since_seq=%d&level=info", cursor), "")
+	assert.NotContains(t, w.Body.String(), "StreamLogsTest: debug after reconnect")
+
+	// A Last-Event-ID from before a restart is ahead of the buffer and replays it
+	w = stream("/api/logs", fmt.Sprint(cursor+1000000))
+	assert.Contains(t, w.Body.String(), "StreamLogsTest: before reconnect")
+
+	assert.Equal(t, http.StatusBadRequest, stream("/api/logs?since_seq=latest", "").Code)
+}
@@ -2,19 +2,28 @@ package logger
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var (
 	infoLogger  *log.Logger
 	errorLogger *log.Logger
 	logBuffer   *RingBuffer
+
+	// fileWriter additionally receives all log output when a log file is configured
+	fileMu     sync.Mutex
+	fileWriter *lumberjack.Logger
 )
 
 // Log level constants
@@ -26,8 +35,12 @@ const (
 	FatalLevel = log.FatalLevel
 )
 
+// DefaultBufferSize is the number of recent log entries kept in memory by default
+const DefaultBufferSize = 1000
+
 // LogEntry represents a single log entry
 type LogEntry struct {
+	Seq       uint64 // Position in the log, increasing by one per entry (set by RingBuffer.Add)
 	Timestamp time.Time
 	Level     string
 	Message   string
@@ -39,6 +52,7 @@ type RingBuffer struct {
 	entries []LogEntry
 	maxSize int
 	pos     int
+	seq     uint64 // Seq of the newest entry
 }
 
 // NewRingBuffer creates a new ring buffer
@@ -49,11 +63,13 @@ func NewRingBuffer(size int) *RingBuffer {
 	}
 }
 
-// Add adds a log entry to the buffer
+// Add adds a log entry to the buffer and assigns its sequence number
 func (rb *RingBuffer) Add(entry LogEntry) {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
 
+	rb.seq++
+	entry.Seq = rb.seq
 	if len(rb.entries) < rb.maxSize {
 		rb.entries = append(rb.entries, entry)
 	} else {
@@ -66,7 +82,29 @@ func (rb *RingBuffer) Add(entry LogEntry) {
 func (rb *RingBuffer) GetAll() []LogEntry {
 	rb.mu.RLock()
 	defer rb.mu.RUnlock()
+	return rb.ordered()
+}
+
+// Resize changes the number of entries the buffer holds. Growing keeps all entries,
+// shrinking drops the oldest ones. Sequence numbers continue unchanged.
+func (rb *RingBuffer) Resize(size int) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if size == rb.maxSize {
+		return
+	}
+	entries := rb.ordered()
+	entries = entries[max(0, len(entries)-size):]
 
+	rb.entries = make([]LogEntry, len(entries), size)
+	copy(rb.entries, entries)
+	rb.maxSize = size
+	rb.pos = 0
+}
+
+// ordered returns a copy of the entries, oldest first; the caller holds mu
+func (rb *RingBuffer) ordered() []LogEntry {
 	result := make([]LogEntry, len(rb.entries))
 	if len(rb.entries) < rb.maxSize {
 		copy(result, rb.entries)
@@ -79,6 +117,22 @@ func (rb *RingBuffer) GetAll() []LogEntry {
 	return result
 }
 
+// Since returns the entries newer than seq, oldest first. Entries already dropped from the
+// buffer are skipped, so a client resuming from an old cursor gets what is left.
+// A cursor beyond the newest entry comes from before a restart, so the whole buffer is returned.
+func (rb *RingBuffer) Since(seq uint64) []LogEntry {
+	entries := rb.GetAll()
+	// Sequence numbers are consecutive, so the first newer entry is found by offset
+	if len(entries) == 0 || seq < entries[0].Seq || seq > entries[len(entries)-1].Seq {
+		return entries
+	}
+	skip := seq - entries[0].Seq + 1
+	if skip >= uint64(len(entries)) {
+		return []LogEntry{}
+	}
+	return entries[skip:]
+}
+
 // TeeWriter wraps an io.Writer and captures log output
 type TeeWriter struct {
 	writer io.Writer
@@ -90,6 +144,9 @@ func (t *TeeWriter) Write(p []byte) (n int, err error) {
 	// Write to original writer
 	n, err = t.writer.Write(p)
 
+	// Also persist to the log file, if any
+	writeToFile(p)
+
 	// Also capture in buffer
 	if t.buffer != nil {
 		msg := string(bytes.TrimSpace(p))
@@ -105,14 +162,72 @@ func (t *TeeWriter) Write(p []byte) (n int, err error) {
 	return n, err
 }
 
+// FileOptions configures the rotating log file
+type FileOptions struct {
+	Path       string
+	MaxSizeMB  int // Rotate after this many megabytes
+	MaxBackups int // Rotated files to keep (0 keeps all)
+	MaxAgeDays int // Delete rotated files older than this many days (0 disables)
+}
+
+// SetLogFile makes the loggers also write to a size-rotated file.
+// An empty path stops writing to a file. Any previously opened file is closed.
+func SetLogFile(opts FileOptions) error {
+	var writer *lumberjack.Logger
+	if opts.Path != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.Path), 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+		writer = &lumberjack.Logger{
+			Filename:   opts.Path,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+			MaxAge:     opts.MaxAgeDays,
+		}
+	}
+
+	fileMu.Lock()
+	previous := fileWriter
+	fileWriter = writer
+	fileMu.Unlock()
+
+	if previous != nil {
+		return previous.Close()
+	}
+	return nil
+}
+
+// Close closes the log file, if one is configured
+func Close() error {
+	return SetLogFile(FileOptions{})
+}
+
+// writeToFile writes a log line to the log file, if one is configured
+func writeToFile(p []byte) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	if fileWriter != nil {
+		_, _ = fileWriter.Write(p)
+	}
+}
+
 // GetLogBuffer returns the log buffer
 func GetLogBuffer() *RingBuffer {
 	return logBuffer
 }
 
+// ResizeBuffer sets the number of recent log entries kept in memory (see RingBuffer.Resize).
+// Sizes below 1 select DefaultBufferSize.
+func ResizeBuffer(size int) {
+	if size < 1 {
+		size = DefaultBufferSize
+	}
+	logBuffer.Resize(size)
+}
+
 func init() {
-	// Initialize log buffer (keep last 1000 log entries)
-	logBuffer = NewRingBuffer(1000)
+	// Initialize log buffer (resized from the config later)
+	logBuffer = NewRingBuffer(DefaultBufferSize)
 
 	// Create tee writers to capture logs
 	stdoutTee := &TeeWriter{writer: os.Stdout, buffer: logBuffer, level: "INFO"}
@@ -136,6 +251,32 @@ func GetLogger() *log.Logger {
 	return infoLogger
 }
 
+// ParseLevel maps a configured level name ("debug", "info", "warn" or "error") to a log level
+func ParseLevel(name string) (log.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info", "":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// SetFormat switches both loggers between the default text output and JSON lines ("json")
+func SetFormat(format string) {
+	formatter := log.TextFormatter
+	if format == "json" {
+		formatter = log.JSONFormatter
+	}
+	infoLogger.SetFormatter(formatter)
+	errorLogger.SetFormatter(formatter)
+}
+
 // SetLevel sets the log level for both loggers
 func SetLevel(level log.Level) {
 	infoLogger.SetLevel(level)
@@ -185,10 +326,87 @@ func Fatalf(format string, args ...interface{}) {
 	errorLogger.Fatalf(format, args...)
 }
 
-// FormatLogEntry formats a log entry for display
+// FormatLogEntry formats a log entry for display.
+// JSON log lines are rendered as "msg key=value ..." so they stay readable in the web UI.
 func FormatLogEntry(entry LogEntry) string {
+	level, message := entry.Level, entry.Message
+	if fields, ok := parseJSONLine(message); ok {
+		if l, ok := fields["level"].(string); ok && l != "" {
+			level = strings.ToUpper(l)
+		}
+		message = formatJSONFields(fields)
+	}
+
 	return fmt.Sprintf("%s [%s] %s",
 		entry.Timestamp.Format("2006/01/02 15:04:05"),
-		entry.Level,
-		entry.Message)
+		level,
+		message)
+}
+
+// EntryLevel returns the level a log entry was written with.
+// It reads the level from JSON lines or the text formatter's level token and
+// falls back to the level of the writer that captured the entry.
+func EntryLevel(entry LogEntry) log.Level {
+	if fields, ok := parseJSONLine(entry.Message); ok {
+		if name, ok := fields["level"].(string); ok {
+			if level, err := ParseLevel(name); err == nil {
+				return level
+			}
+		}
+	}
+
+	// Text lines look like "2006/01/02 15:04:05 INFO message"
+	tokens := strings.Fields(entry.Message)
+	for i := 0; i < len(tokens) && i < 3; i++ {
+		if level, ok := textLevels[tokens[i]]; ok {
+			return level
+		}
+	}
+
+	if entry.Level == "ERROR" {
+		return ErrorLevel
+	}
+	return InfoLevel
+}
+
+// textLevels maps the level tokens of the text formatter to log levels
+var textLevels = map[string]log.Level{
+	"DEBU": DebugLevel,
+	"INFO": InfoLevel,
+	"WARN": WarnLevel,
+	"ERRO": ErrorLevel,
+	"FATA": FatalLevel,
+}
+
+// parseJSONLine decodes a JSON log line written by the JSON formatter
+func parseJSONLine(line string) (map[string]interface{}, bool) {
+	if !strings.HasPrefix(line, "{") {
+		return nil, false
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return nil, false
+	}
+	if _, ok := fields["msg"]; !ok {
+		return nil, false
+	}
+	return fields, true
+}
+
+// formatJSONFields renders the message followed by the remaining fields sorted by key
+func formatJSONFields(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if key != "msg" && key != "level" && key != "time" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprint(fields["msg"]))
+	for _, key := range keys {
+		sb.WriteString(fmt.Sprintf(" %s=%v", key, fields[key]))
+	}
+	return sb.String()
 }
@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected log.Level
+	}{
+		{"debug", DebugLevel},
+		{"info", InfoLevel},
+		{"", InfoLevel},
+		{"warn", WarnLevel},
+		{"WARNING", WarnLevel},
+		{"error", ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		level, err := ParseLevel(tt.name)
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expected, level, tt.name)
+	}
+
+	level, err := ParseLevel("verbose")
+	assert.Error(t, err)
+	assert.Equal(t, InfoLevel, level)
+}
+
+func TestSetFormatJSON(t *testing.T) {
+	SetFormat("json")
+	defer SetFormat("text")
+
+	Info("Test: structured message", "id", "abc123")
+
+	entries := GetLogBuffer().GetAll()
+	last := entries[len(entries)-1]
+
+	var fields map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(last.Message), &fields))
+	assert.Equal(t, "Test: structured message", fields["msg"])
+	assert.Equal(t, "abc123", fields["id"])
+	assert.Equal(t, "info", fields["level"])
+}
+
+func TestFormatLogEntry(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	text := FormatLogEntry(LogEntry{Timestamp: timestamp, Level: "INFO", Message: "plain message"})
+	assert.Equal(t, "2024/05/01 12:30:00 [INFO] plain message", text)
+
+	structured := FormatLogEntry(LogEntry{
+		Timestamp: timestamp,
+		Level:     "ERROR",
+		Message:   `{"time":"2024/05/01 12:30:00","level":"warn","msg":"Caddy reload failed","id":"abc","attempt":2}`,
+	})
+	assert.Equal(t, "2024/05/01 12:30:00 [WARN] Caddy reload failed attempt=2 id=abc", structured)
+}
+
+func TestSetLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "gintainer.log")
+	assert.NoError(t, SetLogFile(FileOptions{Path: path, MaxSizeMB: 1, MaxBackups: 1}))
+
+	Info("Test: written to file")
+	Error("Test: error written to file")
+	assert.NoError(t, Close())
+
+	Info("Test: not written after close")
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Test: written to file")
+	assert.Contains(t, string(content), "Test: error written to file")
+	assert.NotContains(t, string(content), "not written after close")
+}
+
+func TestEntryLevel(t *testing.T) {
+	tests := []struct {
+		entry    LogEntry
+		expected log.Level
+	}{
+		{LogEntry{Level: "INFO", Message: "2024/05/01 12:30:00 DEBU Main: Runtime manager created"}, DebugLevel},
+		{LogEntry{Level: "INFO", Message: "2024/05/01 12:30:00 INFO Starting Gintainer on port 10000"}, InfoLevel},
+		{LogEntry{Level: "ERROR", Message: "2024/05/01 12:30:00 WARN StartContainer: Failed to generate Caddyfile"}, WarnLevel},
+		{LogEntry{Level: "ERROR", Message: `{"time":"2024/05/01 12:30:00","level":"error","msg":"boom"}`}, ErrorLevel},
+		{LogEntry{Level: "ERROR", Message: "[GIN] 500 | GET /api/containers"}, ErrorLevel},
+		{LogEntry{Level: "INFO", Message: "unformatted output"}, InfoLevel},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, EntryLevel(tt.entry), tt.entry.Message)
+	}
+}
+
+func TestRingBufferSince(t *testing.T) {
+	rb := NewRingBuffer(3)
+	assert.Empty(t, rb.Since(0))
+
+	for _, msg := range []string{"one", "two", "three", "four"} {
+		rb.Add(LogEntry{Message: msg})
+	}
+
+	messages := func(entries []LogEntry) []string {
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Message)
+		}
+		return result
+	}
+
+	// "one" (seq 1) was dropped from the buffer
+	assert.Equal(t, []string{"two", "three", "four"}, messages(rb.Since(0)))
+	assert.Equal(t, []string{"two", "three", "four"}, messages(rb.Since(1)))
+	assert.Equal(t, []string{"four"}, messages(rb.Since(3)))
+	assert.Empty(t, rb.Since(4))
+	// A cursor from before a restart replays the buffer
+	assert.Equal(t, []string{"two", "three", "four"}, messages(rb.Since(100)))
+	assert.Equal(t, uint64(4), rb.GetAll()[2].Seq)
+}
+
+func TestRingBufferResize(t *testing.T) {
+	rb := NewRingBuffer(4)
+	for _, msg := range []string{"one", "two", "three"} {
+		rb.Add(LogEntry{Message: msg})
+	}
+
+	messages := func() []string {
+		var result []string
+		for _, entry := range rb.GetAll() {
+			result = append(result, entry.Message)
+		}
+		return result
+	}
+
+	// Growing a partially filled buffer keeps every entry and makes room for more
+	rb.Resize(6)
+	assert.Equal(t, []string{"one", "two", "three"}, messages())
+	for _, msg := range []string{"four", "five", "six", "seven"} {
+		rb.Add(LogEntry{Message: msg})
+	}
+	assert.Equal(t, []string{"two", "three", "four", "five", "six", "seven"}, messages())
+
+	// Shrinking a wrapped buffer drops the oldest entries
+	rb.Resize(2)
+	assert.Equal(t, []string{"six", "seven"}, messages())
+	rb.Add(LogEntry{Message: "eight"})
+	assert.Equal(t, []string{"seven", "eight"}, messages())
+
+	// Sequence numbers keep counting across resizes
+	assert.Equal(t, uint64(8), rb.GetAll()[1].Seq)
+	require.Len(t, rb.Since(7), 1)
+	assert.Equal(t, "eight", rb.Since(7)[0].Message)
+
+	// Shrinking below the number of entries of a partially filled buffer
+	rb = NewRingBuffer(10)
+	for _, msg := range []string{"a", "b", "c"} {
+		rb.Add(LogEntry{Message: msg})
+	}
+	rb.Resize(2)
+	assert.Equal(t, []string{"b", "c"}, messages())
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitIdleTimeout is how long a client's bucket is kept after its last limited request
+const rateLimitIdleTimeout = 10 * time.Minute
+
+// RateLimiter limits state-changing requests per client IP with a token bucket
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*clientBucket
+	lastSweep time.Time
+	now       func() time.Time // Clock, replaced in tests
+}
+
+// clientBucket is the token bucket of a single client
+type clientBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing cfg.RequestsPerMinute requests per client IP
+func NewRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
+	burst := cfg.Burst
+	if burst == 0 {
+		burst = cfg.RequestsPerMinute
+	}
+
+	return &RateLimiter{
+		limit:   rate.Limit(float64(cfg.RequestsPerMinute) / 60),
+		burst:   burst,
+		clients: make(map[string]*clientBucket),
+		now:     time.Now,
+	}
+}
+
+// Middleware returns a handler that rejects POST, PUT, PATCH and DELETE requests over
+// the limit with 429 Too Many Requests. Other methods are never limited.
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+
+		if ok, retryAfter := rl.allow(c.ClientIP()); !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			logger.Warn("RateLimiter: Rate limit exceeded", "client_ip", c.ClientIP(), "method", c.Request.Method, "path", c.Request.URL.Path)
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded, retry in " + strconv.Itoa(seconds) + "s"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// allow takes a token from the client's bucket. If none is left, it returns false
+// and the time until the next token is available.
+func (rl *RateLimiter) allow(clientIP string) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+
+	bucket, ok := rl.clients[clientIP]
+	if !ok {
+		bucket = &clientBucket{limiter: rate.NewLimiter(rl.limit, rl.burst)}
+		rl.clients[clientIP] = bucket
+	}
+	bucket.lastSeen = now
+
+	reservation := bucket.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// sweep drops buckets of clients that have been idle for rateLimitIdleTimeout
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rateLimitIdleTimeout {
+		return
+	}
+	rl.lastSweep = now
+
+	for clientIP, bucket := range rl.clients {
+		if now.Sub(bucket.lastSeen) >= rateLimitIdleTimeout {
+			delete(rl.clients, clientIP)
+		}
+	}
+}
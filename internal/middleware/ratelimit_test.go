@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a manually advanced clock for the rate limiter
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+func (f *fakeClock) Advance(d time.Duration) { f.now = f.now.Add(d) }
+
+func newRateLimitRouter(cfg config.RateLimitConfig) (*gin.Engine, *fakeClock) {
+	gin.SetMode(gin.TestMode)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := NewRateLimiter(cfg)
+	limiter.now = clock.Now
+
+	router := gin.New()
+	router.Use(limiter.Middleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/containers", ok)
+	router.DELETE("/api/containers/:id", ok)
+	return router, clock
+}
+
+func rateLimitRequest(router *gin.Engine, method, path, clientIP string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, nil)
+	req.RemoteAddr = clientIP + ":12345"
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiterRejectsOverLimit(t *testing.T) {
+	router, clock := newRateLimitRouter(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 6, Burst: 2})
+
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, "DELETE", "/api/containers/a", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, "DELETE", "/api/containers/b", "10.0.0.1").Code)
+
+	w := rateLimitRequest(router, "DELETE", "/api/containers/c", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+
+	// Other clients have their own bucket
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, "DELETE", "/api/containers/c", "10.0.0.2").Code)
+
+	// One token is refilled every 10 seconds
+	clock.Advance(4 * time.Second)
+	w = rateLimitRequest(router, "DELETE", "/api/containers/c", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "6", w.Header().Get("Retry-After"))
+
+	clock.Advance(6 * time.Second)
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, "DELETE", "/api/containers/c", "10.0.0.1").Code)
+}
+
+func TestRateLimiterSkipsReads(t *testing.T) {
+	router, _ := newRateLimitRouter(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 1})
+
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, "DELETE", "/api/containers/a", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitRequest(router, "DELETE", "/api/containers/a", "10.0.0.1").Code)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, rateLimitRequest(router, "GET", "/api/containers", "10.0.0.1").Code)
+	}
+}
+
+func TestRateLimiterDefaultBurst(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 30})
+	assert.Equal(t, 30, limiter.burst)
+}
+
+func TestRateLimiterSweepsIdleClients(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := NewRateLimiter(config.RateLimitConfig{Enabled: true, RequestsPerMinute: 60})
+	limiter.now = clock.Now
+
+	limiter.allow("10.0.0.1")
+	clock.Advance(rateLimitIdleTimeout)
+	limiter.allow("10.0.0.2")
+
+	assert.Len(t, limiter.clients, 1)
+	assert.Contains(t, limiter.clients, "10.0.0.2")
+}
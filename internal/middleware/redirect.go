@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+)
+
+// HTTPSRedirect returns a handler that permanently redirects every request to
+// the same host and path over HTTPS on the given port
+func HTTPSRedirect(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		target := "https://" + host
+		if httpsPort != "" && httpsPort != "443" {
+			target = "https://" + net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, target+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
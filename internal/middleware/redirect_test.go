@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSRedirect(t *testing.T) {
+	tests := []struct {
+		port     string
+		host     string
+		path     string
+		expected string
+	}{
+		{"10000", "gintainer.lan:80", "/containers?runtime=docker", "https://gintainer.lan:10000/containers?runtime=docker"},
+		{"10000", "gintainer.lan", "/", "https://gintainer.lan:10000/"},
+		{"443", "gintainer.lan:8080", "/api/containers", "https://gintainer.lan/api/containers"},
+		{"8443", "[::1]:80", "/health", "https://[::1]:8443/health"},
+	}
+
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", tc.path, nil)
+		req.Host = tc.host
+		HTTPSRedirect(tc.port).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+		assert.Equal(t, tc.expected, w.Header().Get("Location"))
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelOnShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	shutdown, startShutdown := context.WithCancel(context.Background())
+	streaming := make(chan struct{})
+
+	router := gin.New()
+	router.GET("/stream", CancelOnShutdown(shutdown), func(c *gin.Context) {
+		close(streaming)
+		<-c.Request.Context().Done()
+		c.Status(http.StatusNoContent)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/stream", nil))
+	}()
+
+	<-streaming
+	startShutdown()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end on shutdown")
+	}
+
+	// Requests ending on their own are not affected
+	w := httptest.NewRecorder()
+	router = gin.New()
+	router.GET("/ping", CancelOnShutdown(context.Background()), func(c *gin.Context) {
+		assert.NoError(t, c.Request.Context().Err())
+		c.Status(http.StatusOK)
+	})
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/ping", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -11,11 +11,13 @@ type ContainerInfo struct {
 	State          string            `json:"state"`
 	Runtime        string            `json:"runtime"` // "docker" or "podman"
 	Created        time.Time         `json:"created"`
+	StartedAt      time.Time         `json:"started_at,omitzero"` // Last start, only set with include_state or include_stats
 	Labels         map[string]string `json:"labels,omitempty"`
 	Ports          []PortMapping     `json:"ports,omitempty"`
 	Stats          *ContainerStats   `json:"stats,omitempty"`
 	Privileged     bool              `json:"privileged,omitempty"`      // Whether container runs with elevated privileges
 	DeploymentPath string            `json:"deployment_path,omitempty"` // Path where compose file is stored (if deployed from compose)
+	IPAddress      string            `json:"ip_address,omitempty"`      // Container IP on its first network (if known)
 }
 
 // ContainerStats represents real-time container statistics
@@ -49,39 +51,169 @@ type PodInfo struct {
 	DeploymentPath string    `json:"deployment_path,omitempty"` // Path where compose file is stored (if deployed from compose)
 }
 
+// PodStats represents the resource usage of a pod, summed over its containers (Podman-specific)
+type PodStats struct {
+	PodID      string              `json:"pod_id"`
+	PodName    string              `json:"pod_name"`
+	Total      ContainerStats      `json:"total"` // Memory limit and percentage refer to the largest container limit
+	Containers []PodContainerStats `json:"containers"`
+}
+
+// PodContainerStats represents the resource usage of one container of a pod
+type PodContainerStats struct {
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	State string          `json:"state"`
+	Stats *ContainerStats `json:"stats,omitempty"` // Nil for containers without a stats sample, e.g. stopped ones
+}
+
+// LogOptions selects which container log lines to stream
+type LogOptions struct {
+	Follow bool      // Keep streaming new log lines
+	Tail   string    // Number of lines from the end ("all" or empty for everything)
+	Since  time.Time // Only lines at or after this time (zero for no limit)
+	Until  time.Time // Only lines before this time (zero for no limit)
+}
+
 // FilterOptions represents filtering criteria
 type FilterOptions struct {
 	Name              string `form:"name" json:"name"`
 	Status            string `form:"status" json:"status"`
-	Runtime           string `form:"runtime" json:"runtime"`                       // "docker", "podman", or "all"
-	IncludeStats      bool   `form:"include_stats" json:"include_stats"`           // Whether to include real-time stats
-	IncludePrivileged bool   `form:"include_privileged" json:"include_privileged"` // Include containers with elevated privileges (sudo)
+	Label             string `form:"label" json:"label"`                                                        // "key" (has the label) or "key=value"
+	Runtime           string `form:"runtime" json:"runtime"`                                                    // "docker", "podman", or "all"
+	IncludeStats      bool   `form:"include_stats" json:"include_stats"`                                        // Whether to include real-time stats
+	IncludePrivileged bool   `form:"include_privileged" json:"include_privileged"`                              // Include containers with elevated privileges (sudo)
+	IncludeNetwork    bool   `form:"include_network" json:"include_network"`                                    // Resolve container IP addresses (may require an inspect per container)
+	IncludeState      bool   `form:"include_state" json:"include_state"`                                        // Include the time each container was last started (may require an inspect per container)
+	Limit             int    `form:"limit" json:"limit" binding:"min=0"`                                        // Page size (0 returns all results)
+	Offset            int    `form:"offset" json:"offset" binding:"min=0"`                                      // Number of results to skip
+	Sort              string `form:"sort" json:"sort" binding:"omitempty,oneof=name created status cpu memory"` // Sort key (default: name); cpu/memory need include_stats
+	Order             string `form:"order" json:"order" binding:"omitempty,oneof=asc desc"`                     // Sort order (default: asc)
 }
 
 // CreateContainerRequest represents a request to create a container
 type CreateContainerRequest struct {
-	Dockerfile string `json:"dockerfile"` // Dockerfile content
-	Context    string `json:"context"`    // Build context (can be empty)
-	ImageName  string `json:"image_name"` // Name for the built image
-	Runtime    string `json:"runtime"`    // "docker" or "podman"
+	Dockerfile string            `json:"dockerfile"`           // Dockerfile content
+	Context    string            `json:"context"`              // Build context (can be empty)
+	ImageName  string            `json:"image_name"`           // Name for the built image
+	Runtime    string            `json:"runtime"`              // "docker" or "podman"
+	BuildArgs  map[string]string `json:"build_args,omitempty"` // Values for ARG instructions (--build-arg)
+	Target     string            `json:"target,omitempty"`     // Multi-stage build target (--target)
+}
+
+// BuildOptions represents optional settings for an image build
+type BuildOptions struct {
+	BuildArgs map[string]string `json:"build_args,omitempty"` // Values for ARG instructions (--build-arg)
+	Target    string            `json:"target,omitempty"`     // Multi-stage build target (--target)
 }
 
 // RunContainerRequest represents a request to create and run a container from an image
 type RunContainerRequest struct {
-	Name          string   `json:"name"`           // Container name
-	Image         string   `json:"image"`          // Image name
-	Runtime       string   `json:"runtime"`        // "docker" or "podman"
-	RestartPolicy string   `json:"restart_policy"` // "always", "unless-stopped", "on-failure", or ""
-	Ports         []string `json:"ports"`          // Port mappings in "host:container" format
-	Volumes       []string `json:"volumes"`        // Volume mappings in "host:container" format
-	EnvVars       []string `json:"env_vars"`       // Environment variables in "KEY=VALUE" format
+	Name          string            `json:"name"`           // Container name
+	Image         string            `json:"image"`          // Image name
+	Runtime       string            `json:"runtime"`        // "docker" or "podman"
+	RestartPolicy string            `json:"restart_policy"` // "always", "unless-stopped", "on-failure", or ""
+	Ports         []string          `json:"ports"`          // Port mappings in "[host:]container[/protocol]" format; either side may be a range like "8000-8005"
+	Volumes       []string          `json:"volumes"`        // Volume mappings in "host:container" format
+	EnvVars       []string          `json:"env_vars"`       // Environment variables in "KEY=VALUE" format
+	NoPull        bool              `json:"no_pull"`        // Fail instead of pulling the image when it is not present locally
+	AutoRemove    bool              `json:"auto_remove"`    // Remove the container once it exits
+	Privileged    bool              `json:"privileged"`     // Give the container extended privileges on the host
+	SecurityOpt   []string          `json:"security_opt"`   // Security options, e.g. "seccomp=unconfined" or "no-new-privileges"
+	Tmpfs         map[string]string `json:"tmpfs"`          // Tmpfs mounts, container path -> mount options like "rw,size=64m"
+	Devices       []string          `json:"devices"`        // Host devices in "host[:container][:permissions]" format, permissions default to "rwm"
+	ExtraHosts    []string          `json:"extra_hosts"`    // Additional /etc/hosts entries in "hostname:ip" format
+	ReadOnly      bool              `json:"read_only"`      // Mount the root filesystem read-only
+	WorkingDir    string            `json:"working_dir"`    // Working directory, the image default if empty
+	Entrypoint    []string          `json:"entrypoint"`     // Entrypoint override, the image default if empty
+	Command       []string          `json:"command"`        // Command override, the image default if empty
+	User          string            `json:"user"`           // User to run as, "uid[:gid]" or a name, the image default if empty
+	Hostname      string            `json:"hostname"`       // Container hostname, the runtime default if empty
+
+	// Auth authenticates the pull of a missing image, set from the registries config
+	Auth *RegistryAuth `json:"-"`
+}
+
+// ResourceUpdate represents the limits and restart policy to change on an existing container.
+// Zero values leave the current setting unchanged.
+type ResourceUpdate struct {
+	Memory        int64   `json:"memory"`         // Memory limit in bytes
+	CPUs          float64 `json:"cpus"`           // Number of CPUs the container may use, e.g. 1.5
+	RestartPolicy string  `json:"restart_policy"` // "no", "always", "unless-stopped" or "on-failure[:<max-retries>]"
+}
+
+// SystemdOptions configures the systemd unit files generated for a container
+type SystemdOptions struct {
+	NamePrefix    string `form:"prefix" json:"prefix"`                 // Unit name prefix, "container" if empty
+	RestartPolicy string `form:"restart_policy" json:"restart_policy"` // Systemd Restart= value, "on-failure" if empty
+	New           bool   `form:"new" json:"new"`                       // Create a new container on start instead of starting the existing one
+}
+
+// RegistryAuth represents credentials used to authenticate against an image registry
+type RegistryAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"` // Registry/identity token used instead of a password
+}
+
+// PullImageRequest represents a request to pull an image
+type PullImageRequest struct {
+	Image   string `json:"image"`   // Image reference, e.g. "ghcr.io/org/app:latest"
+	Runtime string `json:"runtime"` // "docker" or "podman"
+}
+
+// PullProgress represents a progress update of an image pull
+type PullProgress struct {
+	ID      string `json:"id,omitempty"`      // Layer ID, empty for updates about the whole image
+	Status  string `json:"status,omitempty"`  // e.g. "Downloading" or "Pull complete"
+	Current int64  `json:"current,omitempty"` // Bytes of the layer downloaded or extracted so far (Docker only)
+	Total   int64  `json:"total,omitempty"`   // Size of the layer in bytes (Docker only)
+	Error   string `json:"error,omitempty"`   // Set on the last update when the pull failed
+}
+
+// ImageLayer represents a layer in the history of an image
+type ImageLayer struct {
+	ID        string    `json:"id"`         // Image ID of the layer, "<missing>" for layers pulled as part of another image
+	CreatedBy string    `json:"created_by"` // Command that created the layer
+	Size      int64     `json:"size"`       // Size of the layer in bytes
+	CreatedAt time.Time `json:"created_at"`
+	Tags      []string  `json:"tags,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+}
+
+// RestoreContainerRequest represents a request to restore a container from a checkpoint archive
+type RestoreContainerRequest struct {
+	Checkpoint string `json:"checkpoint"` // File name of the archive in the checkpoint directory
+	Runtime    string `json:"runtime"`    // Only "podman" supports checkpoints
+}
+
+// TagImageRequest represents a request to tag an image
+type TagImageRequest struct {
+	Target string `json:"target"` // New reference, e.g. "registry.example.com/team/app:1.0"
 }
 
 // ComposeRequest represents a request to deploy from a compose file
 type ComposeRequest struct {
-	ComposeContent string `json:"compose_content"` // Docker/Podman compose file content
-	Runtime        string `json:"runtime"`         // "docker" or "podman"
-	ProjectName    string `json:"project_name"`    // Optional project name for the deployment
+	ComposeContent string            `json:"compose_content"` // Docker/Podman compose file content
+	Runtime        string            `json:"runtime"`         // "docker" or "podman"
+	ProjectName    string            `json:"project_name"`    // Optional project name for the deployment
+	Env            map[string]string `json:"env,omitempty"`   // Optional variables written to .env for interpolation
+}
+
+// DeploymentManifest describes a compose deployment; it is stored as gintainer.json in the deployment directory
+type DeploymentManifest struct {
+	ProjectName string    `json:"project_name"`
+	Runtime     string    `json:"runtime"`              // "docker" or "podman", empty if unknown
+	Services    []string  `json:"services"`             // Service names, sorted
+	CreatedAt   time.Time `json:"created_at"`           // First deployment
+	UpdatedAt   time.Time `json:"updated_at,omitempty"` // Latest deployment
+}
+
+// ComposeScaleRequest represents a request to change the number of containers of a deployment's service
+type ComposeScaleRequest struct {
+	Service  string `json:"service" binding:"required"`
+	Replicas *int   `json:"replicas" binding:"required,min=0"`
+	Runtime  string `json:"runtime,omitempty"` // "docker" or "podman", defaults to the deployment's runtime
 }
 
 // UpdateRequest represents a request to update containers
@@ -90,11 +222,169 @@ type UpdateRequest struct {
 	Runtime      string   `json:"runtime"` // "docker" or "podman"
 }
 
-// CronJobConfig represents cron job configuration for auto-updates
+// BulkActionRequest represents a request to run one action against several containers
+type BulkActionRequest struct {
+	Runtime      string   `json:"runtime"`                                                   // "docker" or "podman"
+	Action       string   `json:"action" binding:"required,oneof=start stop restart delete"` // Action to run on each container
+	ContainerIDs []string `json:"container_ids" binding:"required,min=1"`
+	Force        bool     `json:"force,omitempty"`                                 // Force removal (delete only)
+	Timeout      *int     `json:"timeout,omitempty" binding:"omitempty,min=0"`     // Stop timeout in seconds (stop/restart only)
+	Concurrency  int      `json:"concurrency,omitempty" binding:"omitempty,min=1"` // Containers handled at the same time (default: 4)
+}
+
+// CronJobConfig represents cron job configuration for auto-updates.
+// The top-level schedule, enabled flag and filters form the legacy single job,
+// which is scheduled as a job named "default" in addition to Jobs.
 type CronJobConfig struct {
+	Schedule          string    `json:"schedule"` // Cron expression (e.g., "0 2 * * *")
+	Enabled           bool      `json:"enabled"`
+	Filters           []string  `json:"filters,omitempty"`            // Container names or patterns to update
+	Exclude           []string  `json:"exclude,omitempty"`            // Container names or patterns never to update
+	DryRun            bool      `json:"dry_run"`                      // Only report available updates
+	Jobs              []CronJob `json:"jobs,omitempty"`               // Additional named jobs
+	Timezone          string    `json:"timezone,omitempty"`           // IANA time zone of all schedules (default: server local time)
+	UpdateConcurrency int       `json:"update_concurrency,omitempty"` // Containers updated at once during a run (default: 3)
+}
+
+// CronJob represents a named auto-update job with its own schedule and filters
+type CronJob struct {
+	Name     string   `json:"name"`
 	Schedule string   `json:"schedule"` // Cron expression (e.g., "0 2 * * *")
 	Enabled  bool     `json:"enabled"`
 	Filters  []string `json:"filters,omitempty"` // Container names or patterns to update
+	Exclude  []string `json:"exclude,omitempty"` // Container names or patterns never to update, wins over Filters
+	DryRun   bool     `json:"dry_run"`           // Only report available updates
+}
+
+// Container update statuses reported by scheduler runs
+const (
+	UpdateStatusUpdated     = "updated"
+	UpdateStatusSkipped     = "skipped"
+	UpdateStatusFailed      = "failed"
+	UpdateStatusWouldUpdate = "would-update" // Dry run: a newer image is available
+	UpdateStatusUpToDate    = "up-to-date"   // Dry run: the container runs the latest image
+)
+
+// ContainerUpdateResult is the outcome of a scheduler run for a single container
+type ContainerUpdateResult struct {
+	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name"`
+	Runtime       string `json:"runtime"`
+	Status        string `json:"status"` // "updated", "skipped", "failed", "would-update" or "up-to-date"
+	Error         string `json:"error,omitempty"`
+}
+
+// UpdateReport summarizes a scheduler run
+type UpdateReport struct {
+	Job           string                  `json:"job"` // Job name, or "manual" for on-demand runs
+	DryRun        bool                    `json:"dry_run"`
+	StartedAt     time.Time               `json:"started_at"`
+	FinishedAt    time.Time               `json:"finished_at"`
+	DurationMs    int64                   `json:"duration_ms"`
+	Updated       int                     `json:"updated"`
+	Skipped       int                     `json:"skipped"`
+	Failed        int                     `json:"failed"`
+	WouldUpdate   int                     `json:"would_update"`
+	UpToDate      int                     `json:"up_to_date"`
+	Results       []ContainerUpdateResult `json:"results"`
+	SkippedReason string                  `json:"skipped_reason,omitempty"` // Why the run did not start, e.g. another run was still in progress
+}
+
+// PruneOptions selects which categories of unused resources to prune
+type PruneOptions struct {
+	Containers bool `form:"containers" json:"containers"`   // Remove stopped containers
+	Images     bool `form:"images" json:"images"`           // Remove dangling images
+	AllImages  bool `form:"all_images" json:"all_images"`   // Remove all unused images, not just dangling ones
+	Networks   bool `form:"networks" json:"networks"`       // Remove unused networks
+	Volumes    bool `form:"volumes" json:"volumes"`         // Remove unused volumes
+	BuildCache bool `form:"build_cache" json:"build_cache"` // Remove build cache
+}
+
+// PruneReport summarizes the result of a prune operation
+type PruneReport struct {
+	Runtime        string `json:"runtime"`
+	Containers     int    `json:"containers"`      // Number of containers removed
+	Images         int    `json:"images"`          // Number of images removed
+	Networks       int    `json:"networks"`        // Number of networks removed
+	Volumes        int    `json:"volumes"`         // Number of volumes removed
+	BuildCache     int    `json:"build_cache"`     // Number of build cache entries removed
+	ReclaimedBytes uint64 `json:"reclaimed_bytes"` // Total disk space reclaimed
+}
+
+// DiskUsage reports the disk space used by a runtime, by category
+type DiskUsage struct {
+	Runtime     string            `json:"runtime"`
+	Images      DiskUsageCategory `json:"images"`
+	Containers  DiskUsageCategory `json:"containers"`
+	Volumes     DiskUsageCategory `json:"volumes"`
+	BuildCache  DiskUsageCategory `json:"build_cache"` // Always empty for Podman, which keeps its build cache as images
+	TotalSize   uint64            `json:"total_size"`
+	Reclaimable uint64            `json:"reclaimable"`
+}
+
+// DiskUsageCategory is the disk usage of one kind of resource, like a row of `docker system df`
+type DiskUsageCategory struct {
+	Total       int    `json:"total"`       // Number of resources
+	Active      int    `json:"active"`      // Resources in use (images and volumes used by containers, running containers)
+	Size        uint64 `json:"size"`        // Disk space used in bytes
+	Reclaimable uint64 `json:"reclaimable"` // Bytes a prune of the category could free
+}
+
+// RuntimeEvent represents a container lifecycle event reported by a runtime
+type RuntimeEvent struct {
+	Type          string            `json:"type"` // "start", "die", "stop", "kill", "destroy" or "health_status"
+	ContainerID   string            `json:"container_id"`
+	ContainerName string            `json:"container_name"`
+	Runtime       string            `json:"runtime"` // "docker" or "podman"
+	Timestamp     time.Time         `json:"timestamp"`
+	ExitCode      int               `json:"exit_code,omitempty"`     // Exit code of the main process (die events only)
+	HealthStatus  string            `json:"health_status,omitempty"` // Health status (health_status events only)
+	Attributes    map[string]string `json:"attributes,omitempty"`    // Raw event attributes, including container labels
+}
+
+// FileEntry represents a file or directory inside a container
+type FileEntry struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"` // Size in bytes, 0 for directories
+	Mode       string    `json:"mode"` // File mode like "drwxr-xr-x"
+	IsDir      bool      `json:"is_dir"`
+	ModTime    time.Time `json:"mod_time"`
+	LinkTarget string    `json:"link_target,omitempty"` // Target of symbolic links
+}
+
+// ProcessInfo represents a process running inside a container
+type ProcessInfo struct {
+	PID     string `json:"pid"`
+	User    string `json:"user"`
+	CPU     string `json:"cpu"`    // CPU usage in percent
+	Memory  string `json:"memory"` // Resident set size as reported by the runtime
+	Command string `json:"command"`
+}
+
+// RuntimeCapabilities reports which optional features gintainer supports for a runtime
+type RuntimeCapabilities struct {
+	Pods       bool `json:"pods"`        // Pod listing and actions
+	LiveLabels bool `json:"live_labels"` // Labels can be changed without recreating the container (false for Docker and Podman)
+	Checkpoint bool `json:"checkpoint"`  // Containers can be checkpointed and restored
+	PlayKube   bool `json:"play_kube"`   // Kubernetes YAML can be deployed
+	Compose    bool `json:"compose"`     // Compose files can be deployed
+	Systemd    bool `json:"systemd"`     // Systemd unit files can be generated for containers
+	KubeExport bool `json:"kube_export"` // Kubernetes YAML can be generated from containers and pods
+}
+
+// RuntimeInfo describes a registered runtime
+type RuntimeInfo struct {
+	Name         string              `json:"name"`
+	Capabilities RuntimeCapabilities `json:"capabilities"`
+}
+
+// ExecOptions configures an interactive exec session
+type ExecOptions struct {
+	Cmd        []string // Command to run, e.g. ["/bin/sh"]
+	User       string   // User to run the command as (default: the container user)
+	WorkingDir string   // Working directory (default: the container working directory)
+	Rows       uint     // Initial terminal height, 0 for the runtime default
+	Cols       uint     // Initial terminal width, 0 for the runtime default
 }
 
 // CaddyfileInfo represents information about a Caddyfile
@@ -109,3 +399,17 @@ type CaddyfileUpdateRequest struct {
 	ContainerID string `json:"container_id"`
 	Content     string `json:"content"`
 }
+
+// CaddyLabelsRequest represents the Caddy labels to set on a container
+type CaddyLabelsRequest struct {
+	Domain        string            `json:"domain" binding:"required"` // Comma-separated for multiple domains
+	Port          string            `json:"port,omitempty"`            // Comma-separated host:port list for multiple upstreams
+	Upstream      string            `json:"upstream,omitempty"`        // Upstream host, or "container-ip"
+	LBPolicy      string            `json:"lb_policy,omitempty"`
+	Path          string            `json:"path,omitempty"`
+	TLS           string            `json:"tls,omitempty"`
+	BasicAuthUser string            `json:"basic_auth_user,omitempty"`
+	BasicAuthHash string            `json:"basic_auth_hash,omitempty"` // Caddy-compatible bcrypt hash (caddy hash-password)
+	Headers       map[string]string `json:"headers,omitempty"`         // Response headers, e.g. Strict-Transport-Security
+	Compress      bool              `json:"compress,omitempty"`        // Enable zstd/gzip response compression
+}
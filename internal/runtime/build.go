@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// contextDockerfileName cleans a Dockerfile path given relative to a build context,
+// so it cannot point outside of it. An empty path is the default "Dockerfile".
+func contextDockerfileName(dockerfile string) string {
+	if dockerfile == "" {
+		return "Dockerfile"
+	}
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(dockerfile)), "/")
+}
+
+// resolveContextDockerfile returns the host path of a Dockerfile in an extracted build context.
+// Symlinks are resolved and must stay inside the context, and the target must be a regular file,
+// so an uploaded context cannot make the build read arbitrary host files.
+func resolveContextDockerfile(contextDir, dockerfile string) (string, error) {
+	root, err := filepath.EvalSymlinks(contextDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve build context: %w", err)
+	}
+
+	name := contextDockerfileName(dockerfile)
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(name)))
+	if err != nil {
+		return "", fmt.Errorf("Dockerfile %s not found in build context: %w", dockerfile, err)
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("Dockerfile %s points outside of the build context", dockerfile)
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", fmt.Errorf("Dockerfile %s not found in build context: %w", dockerfile, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("Dockerfile %s is not a regular file", dockerfile)
+	}
+	return resolved, nil
+}
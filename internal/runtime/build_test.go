@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextDockerfileName(t *testing.T) {
+	assert.Equal(t, "Dockerfile", contextDockerfileName(""))
+	assert.Equal(t, "build/Dockerfile.prod", contextDockerfileName("./build/Dockerfile.prod"))
+	assert.Equal(t, "etc/passwd", contextDockerfileName("../../etc/passwd"))
+	assert.Equal(t, "etc/passwd", contextDockerfileName("/etc/passwd"))
+}
+
+func TestResolveContextDockerfile(t *testing.T) {
+	contextDir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(outside, []byte("FROM scratch"), 0644))
+
+	require.NoError(t, os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte("FROM busybox"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(contextDir, "docker"), 0755))
+	require.NoError(t, os.Symlink("../Dockerfile", filepath.Join(contextDir, "docker", "Dockerfile")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(contextDir, "Escape")))
+	require.NoError(t, os.Symlink(filepath.Dir(outside), filepath.Join(contextDir, "host")))
+
+	root, err := filepath.EvalSymlinks(contextDir)
+	require.NoError(t, err)
+
+	resolved, err := resolveContextDockerfile(contextDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "Dockerfile"), resolved)
+
+	// Symlinks that stay inside the context are fine
+	resolved, err = resolveContextDockerfile(contextDir, "docker/Dockerfile")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "Dockerfile"), resolved)
+
+	for _, dockerfile := range []string{"Escape", "host/secret", "missing", "docker", "../" + filepath.Base(outside)} {
+		_, err := resolveContextDockerfile(contextDir, dockerfile)
+		assert.Error(t, err, dockerfile)
+	}
+}
@@ -0,0 +1,627 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/compose-spec/compose-go/v2/dotenv"
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/types"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/go-connections/nat"
+)
+
+// ErrInvalidCompose is returned when a compose file cannot be parsed or fails validation
+var ErrInvalidCompose = errors.New("invalid compose file")
+
+// Labels set on containers, networks and volumes of a compose project, as the compose CLI does
+const (
+	composeProjectLabel     = "com.docker.compose.project"
+	composeServiceLabel     = "com.docker.compose.service"
+	composeNumberLabel      = "com.docker.compose.container-number"
+	composeOneoffLabel      = "com.docker.compose.oneoff"
+	composeWorkingDirLabel  = "com.docker.compose.project.working_dir"
+	composeConfigFilesLabel = "com.docker.compose.project.config_files"
+	composeNetworkLabel     = "com.docker.compose.network"
+	composeVolumeLabel      = "com.docker.compose.volume"
+)
+
+// composeDotEnvFile is the file next to the compose file that compose reads variables from
+const composeDotEnvFile = ".env"
+
+// loadComposeProject parses and validates the compose file at composePath with compose-go.
+// Relative paths are resolved against the directory of the file, and variables are
+// interpolated from env, the process environment and the .env file, in that order.
+func loadComposeProject(ctx context.Context, composePath, projectName string, env map[string]string) (*types.Project, error) {
+	content, err := os.ReadFile(composePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	environment, err := composeEnvironment(filepath.Dir(composePath), env)
+	if err != nil {
+		return nil, err
+	}
+
+	details := types.ConfigDetails{
+		WorkingDir:  filepath.Dir(composePath),
+		ConfigFiles: []types.ConfigFile{{Filename: composePath, Content: content}},
+		Environment: environment,
+	}
+
+	project, err := loader.LoadWithContext(ctx, details, func(o *loader.Options) {
+		if projectName != "" {
+			o.SetProjectName(projectName, true)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCompose, err)
+	}
+	return project, nil
+}
+
+// composeEnvironment returns the variables available for interpolation in the compose file in dir.
+// The process environment overrides the .env file, as with the compose CLI, and env overrides both.
+func composeEnvironment(dir string, env map[string]string) (types.Mapping, error) {
+	// Merge only adds keys that are not set yet, so the sources are merged from highest precedence down
+	environment := types.Mapping{}.Merge(env).Merge(types.NewMapping(os.Environ()))
+
+	dotEnvPath := filepath.Join(dir, composeDotEnvFile)
+	if _, err := os.Stat(dotEnvPath); err == nil {
+		values, err := dotenv.GetEnvFromFile(environment, []string{dotEnvPath})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCompose, err)
+		}
+		environment = environment.Merge(values)
+	}
+	return environment, nil
+}
+
+// writeComposeDotEnv writes env to the .env file in dir, replacing an existing one.
+// An empty env leaves the directory untouched, so a .env from an earlier deployment is kept.
+func writeComposeDotEnv(dir string, env map[string]string) error {
+	if len(env) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		if key == "" || strings.ContainsAny(key, "= \t\r\n#") {
+			return fmt.Errorf("%w: invalid environment variable name %q", ErrInvalidCompose, key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Values are double-quoted, so characters with a special meaning in .env files need escaping
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`)
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s=\"%s\"\n", key, escaper.Replace(env[key]))
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, composeDotEnvFile), []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write .env file: %w", err)
+	}
+	return nil
+}
+
+// composeCommandEnv returns the environment of a compose CLI command, with env taking precedence
+func composeCommandEnv(env map[string]string) []string {
+	cmdEnv := os.Environ()
+	for key, value := range env {
+		cmdEnv = append(cmdEnv, key+"="+value)
+	}
+	return cmdEnv
+}
+
+// composeScaleArgs returns the compose CLI arguments that scale a service of the deployment in deploymentPath.
+// Existing containers are kept as they are, as scaling should not redeploy the service.
+func composeScaleArgs(ctx context.Context, projectName, deploymentPath, service string, replicas int) ([]string, error) {
+	if replicas < 0 {
+		return nil, fmt.Errorf("%w: replicas must not be negative", ErrInvalidCompose)
+	}
+
+	composePath := filepath.Join(deploymentPath, "docker-compose.yml")
+	project, err := loadComposeProject(ctx, composePath, projectName, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := project.GetService(service); err != nil {
+		return nil, fmt.Errorf("%w: unknown service %q", ErrInvalidCompose, service)
+	}
+
+	args := []string{"-f", composePath}
+	if projectName != "" {
+		args = append(args, "-p", projectName)
+	}
+	return append(args, "up", "-d", "--no-recreate", "--scale", fmt.Sprintf("%s=%d", service, replicas), service), nil
+}
+
+// unsupportedComposeFeatures lists the features of a project that deploying through
+// the Docker API does not implement, so the compose CLI has to be used instead. Keys are
+// checked against the ones deployComposeProject maps, so any other key falls back to the CLI.
+func unsupportedComposeFeatures(project *types.Project) []string {
+	var features []string
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+		if service.Image == "" && service.Build == nil {
+			features = append(features, fmt.Sprintf("service %s: no image", name))
+		}
+		if service.Deploy != nil && service.Deploy.Replicas != nil && *service.Deploy.Replicas > 1 {
+			features = append(features, fmt.Sprintf("service %s: replicas", name))
+		}
+		if service.Scale != nil && *service.Scale > 1 {
+			features = append(features, fmt.Sprintf("service %s: scale", name))
+		}
+		for _, key := range unmappedServiceKeys(service) {
+			features = append(features, fmt.Sprintf("service %s: %s", name, key))
+		}
+	}
+
+	networkKeys := make([]string, 0, len(project.Networks))
+	for key := range project.Networks {
+		networkKeys = append(networkKeys, key)
+	}
+	sort.Strings(networkKeys)
+	for _, key := range networkKeys {
+		nw := project.Networks[key]
+		nw.Name, nw.Driver, nw.DriverOpts, nw.Internal, nw.External = "", "", nil, false, false
+		nw.Labels, nw.CustomLabels, nw.Extensions = nil, nil, nil
+		for _, field := range nonZeroComposeKeys(nw) {
+			features = append(features, fmt.Sprintf("network %s: %s", key, field))
+		}
+	}
+	return features
+}
+
+// unmappedServiceKeys returns the keys of a service that deployComposeService does not map to the container
+func unmappedServiceKeys(service types.ServiceConfig) []string {
+	var keys []string
+
+	// Only the fields composePorts, composeMounts and composeEndpoint read are supported within the lists
+	for _, port := range service.Ports {
+		port.Target, port.Published, port.Protocol, port.HostIP, port.Extensions = 0, "", "", "", nil
+		if port.Mode == "ingress" {
+			port.Mode = ""
+		}
+		keys = append(keys, prefixKeys("ports", nonZeroComposeKeys(port))...)
+	}
+	for _, volume := range service.Volumes {
+		volume.Type, volume.Source, volume.Target, volume.ReadOnly, volume.Extensions = "", "", "", false, nil
+		// Set by the loader for short bind syntax; the Docker API creates missing bind sources for Binds only
+		if volume.Bind != nil && reflect.DeepEqual(*volume.Bind, types.ServiceVolumeBind{CreateHostPath: true}) {
+			volume.Bind = nil
+		}
+		keys = append(keys, prefixKeys("volumes", nonZeroComposeKeys(volume))...)
+	}
+	for _, cfg := range service.Networks {
+		if cfg == nil {
+			continue
+		}
+		network := *cfg
+		network.Priority, network.Aliases, network.Ipv4Address, network.Ipv6Address, network.Extensions = 0, nil, "", "", nil
+		keys = append(keys, prefixKeys("networks", nonZeroComposeKeys(network))...)
+	}
+	for _, dependency := range service.DependsOn {
+		// Services are started in dependency order, but nothing waits for them to be healthy
+		if dependency.Condition != "" && dependency.Condition != types.ServiceConditionStarted {
+			keys = append(keys, "depends_on.condition")
+		}
+		if dependency.Restart {
+			keys = append(keys, "depends_on.restart")
+		}
+	}
+	if service.Deploy != nil {
+		deploy := *service.Deploy
+		deploy.Replicas, deploy.Extensions = nil, nil
+		keys = append(keys, prefixKeys("deploy", nonZeroComposeKeys(deploy))...)
+	}
+	if service.PullPolicy == types.PullPolicyMissing || service.PullPolicy == types.PullPolicyIfNotPresent {
+		service.PullPolicy = ""
+	}
+
+	// Mapped by deployComposeService (or checked above)
+	service.Name, service.Image, service.ContainerName = "", "", ""
+	service.Command, service.Entrypoint, service.WorkingDir, service.User, service.Hostname = nil, nil, "", "", ""
+	service.Environment, service.EnvFiles = nil, nil
+	service.Labels, service.CustomLabels, service.Extensions = nil, nil, nil
+	service.Ports, service.Volumes, service.Networks, service.NetworkMode = nil, nil, nil, ""
+	service.Restart, service.Privileged, service.CapAdd, service.CapDrop = "", false, nil, nil
+	service.DependsOn, service.Deploy, service.Scale = nil, nil, nil
+
+	return append(nonZeroComposeKeys(service), keys...)
+}
+
+// nonZeroComposeKeys returns the compose keys of the fields of a compose struct that are set.
+// Pointers to empty structs, which the loader sets for some short syntaxes, count as unset.
+func nonZeroComposeKeys(v any) []string {
+	value := reflect.ValueOf(v)
+	var keys []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.IsZero() || (field.Kind() == reflect.Pointer && field.Elem().Kind() == reflect.Struct && field.Elem().IsZero()) {
+			continue
+		}
+		key, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			key = value.Type().Field(i).Name
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// prefixKeys returns the keys as "prefix.key"
+func prefixKeys(prefix string, keys []string) []string {
+	for i, key := range keys {
+		keys[i] = prefix + "." + key
+	}
+	return keys
+}
+
+// composeContainerName returns the container name of a service, following the compose v2 naming scheme
+func composeContainerName(project *types.Project, service types.ServiceConfig) string {
+	if service.ContainerName != "" {
+		return service.ContainerName
+	}
+	return fmt.Sprintf("%s-%s-1", project.Name, service.Name)
+}
+
+// composeContainerLabels returns the service labels plus the compose project labels
+func composeContainerLabels(project *types.Project, service types.ServiceConfig) map[string]string {
+	labels := make(map[string]string, len(service.Labels)+6)
+	for key, value := range service.Labels {
+		labels[key] = value
+	}
+	labels[composeProjectLabel] = project.Name
+	labels[composeServiceLabel] = service.Name
+	labels[composeNumberLabel] = "1"
+	labels[composeOneoffLabel] = "False"
+	labels[composeWorkingDirLabel] = project.WorkingDir
+	labels[composeConfigFilesLabel] = strings.Join(project.ComposeFiles, ",")
+	return labels
+}
+
+// composeEnv converts the service environment to KEY=VALUE pairs, sorted by key.
+// Variables without a value are left out, like in the compose CLI.
+func composeEnv(environment types.MappingWithEquals) []string {
+	keys := make([]string, 0, len(environment))
+	for key, value := range environment {
+		if value != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		env = append(env, key+"="+*environment[key])
+	}
+	return env
+}
+
+// composePorts converts the service ports to exposed ports and port bindings
+func composePorts(ports []types.ServicePortConfig) (nat.PortSet, nat.PortMap, error) {
+	exposed := nat.PortSet{}
+	bindings := nat.PortMap{}
+	for _, p := range ports {
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		port, err := nat.NewPort(protocol, strconv.FormatUint(uint64(p.Target), 10))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid port %d/%s: %w", p.Target, protocol, err)
+		}
+		exposed[port] = struct{}{}
+		bindings[port] = append(bindings[port], nat.PortBinding{HostIP: p.HostIP, HostPort: p.Published})
+	}
+	return exposed, bindings, nil
+}
+
+// composeMounts converts the service volumes to mounts. Named volumes are mapped to
+// their project volume name (e.g. "data" -> "myapp_data").
+func composeMounts(project *types.Project, volumes []types.ServiceVolumeConfig) ([]mount.Mount, error) {
+	mounts := make([]mount.Mount, 0, len(volumes))
+	for _, v := range volumes {
+		m := mount.Mount{Source: v.Source, Target: v.Target, ReadOnly: v.ReadOnly}
+		switch v.Type {
+		case types.VolumeTypeBind:
+			m.Type = mount.TypeBind
+		case types.VolumeTypeVolume:
+			m.Type = mount.TypeVolume
+			if v.Source != "" {
+				projectVolume, ok := project.Volumes[v.Source]
+				if !ok {
+					return nil, fmt.Errorf("volume %q is not defined in the top-level volumes", v.Source)
+				}
+				m.Source = projectVolume.Name
+			}
+		case types.VolumeTypeTmpfs:
+			m.Type = mount.TypeTmpfs
+		default:
+			return nil, fmt.Errorf("volume type %q of %s is not supported", v.Type, v.Target)
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts, nil
+}
+
+// composeRestartPolicy converts a compose restart value like "on-failure:3"
+func composeRestartPolicy(restart string) container.RestartPolicy {
+	name, retries, _ := strings.Cut(restart, ":")
+	policy := container.RestartPolicy{Name: container.RestartPolicyMode(name)}
+	if name == "no" {
+		policy.Name = container.RestartPolicyDisabled
+	}
+	if count, err := strconv.Atoi(retries); err == nil {
+		policy.MaximumRetryCount = count
+	}
+	return policy
+}
+
+// composeNetworkMode resolves "service:<name>" network modes to the container of that service
+func composeNetworkMode(project *types.Project, networkMode string) string {
+	serviceName, ok := strings.CutPrefix(networkMode, "service:")
+	if !ok {
+		return networkMode
+	}
+	if service, exists := project.Services[serviceName]; exists {
+		return "container:" + composeContainerName(project, service)
+	}
+	return networkMode
+}
+
+// composeServiceNetworks returns the project network names a service joins, sorted by
+// priority (highest first) and then name, together with the per-network config
+func composeServiceNetworks(project *types.Project, service types.ServiceConfig) ([]string, map[string]*types.ServiceNetworkConfig) {
+	keys := make([]string, 0, len(service.Networks))
+	for key := range service.Networks {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		pi, pj := 0, 0
+		if cfg := service.Networks[keys[i]]; cfg != nil {
+			pi = cfg.Priority
+		}
+		if cfg := service.Networks[keys[j]]; cfg != nil {
+			pj = cfg.Priority
+		}
+		if pi != pj {
+			return pi > pj
+		}
+		return keys[i] < keys[j]
+	})
+
+	names := make([]string, 0, len(keys))
+	configs := make(map[string]*types.ServiceNetworkConfig, len(keys))
+	for _, key := range keys {
+		name := key
+		if network, ok := project.Networks[key]; ok && network.Name != "" {
+			name = network.Name
+		}
+		names = append(names, name)
+		configs[name] = service.Networks[key]
+	}
+	return names, configs
+}
+
+// deployComposeProject brings a compose project up through the Docker API, without the compose CLI.
+// Networks and volumes are created if missing, and the services are (re)created and started in dependency order.
+func (d *DockerRuntime) deployComposeProject(ctx context.Context, project *types.Project) error {
+	for key, nw := range project.Networks {
+		if nw.External {
+			continue
+		}
+		if err := d.ensureComposeNetwork(ctx, project, key, nw); err != nil {
+			return err
+		}
+	}
+
+	for key, vol := range project.Volumes {
+		if vol.External {
+			continue
+		}
+		_, err := d.client.VolumeCreate(ctx, volume.CreateOptions{
+			Name:       vol.Name,
+			Driver:     vol.Driver,
+			DriverOpts: vol.DriverOpts,
+			Labels:     mergeLabels(vol.Labels, map[string]string{composeProjectLabel: project.Name, composeVolumeLabel: key}),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create Docker volume %s: %w", vol.Name, err)
+		}
+	}
+
+	return project.ForEachService(project.ServiceNames(), func(name string, service *types.ServiceConfig) error {
+		if err := d.deployComposeService(ctx, project, *service); err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// RemoveComposeDeployment removes the containers, networks and (optionally) volumes labeled with the project.
+// The labels are the same as those of the compose CLI, so this works regardless of how the project was deployed.
+func (d *DockerRuntime) RemoveComposeDeployment(ctx context.Context, projectName, deploymentPath string, removeVolumes bool) error {
+	projectFilter := filters.NewArgs(filters.Arg("label", composeProjectLabel+"="+projectName))
+
+	containers, err := d.client.ContainerList(ctx, container.ListOptions{All: true, Filters: projectFilter})
+	if err != nil {
+		return fmt.Errorf("failed to list Docker containers of project %s: %w", projectName, err)
+	}
+	for _, c := range containers {
+		if err := d.client.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true, RemoveVolumes: removeVolumes}); err != nil && !cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("failed to remove Docker container %s: %w", c.ID, err)
+		}
+	}
+
+	networks, err := d.client.NetworkList(ctx, network.ListOptions{Filters: projectFilter})
+	if err != nil {
+		return fmt.Errorf("failed to list Docker networks of project %s: %w", projectName, err)
+	}
+	for _, nw := range networks {
+		if err := d.client.NetworkRemove(ctx, nw.ID); err != nil && !cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("failed to remove Docker network %s: %w", nw.Name, err)
+		}
+	}
+
+	if removeVolumes {
+		volumes, err := d.client.VolumeList(ctx, volume.ListOptions{Filters: projectFilter})
+		if err != nil {
+			return fmt.Errorf("failed to list Docker volumes of project %s: %w", projectName, err)
+		}
+		for _, vol := range volumes.Volumes {
+			if err := d.client.VolumeRemove(ctx, vol.Name, false); err != nil && !cerrdefs.IsNotFound(err) {
+				return fmt.Errorf("failed to remove Docker volume %s: %w", vol.Name, err)
+			}
+		}
+	}
+
+	logger.Info("DockerRuntime.RemoveComposeDeployment: Removed project", "project", projectName, "containers", len(containers), "networks", len(networks))
+	return nil
+}
+
+// ensureComposeNetwork creates a project network unless it already exists
+func (d *DockerRuntime) ensureComposeNetwork(ctx context.Context, project *types.Project, key string, nw types.NetworkConfig) error {
+	if _, err := d.client.NetworkInspect(ctx, nw.Name, network.InspectOptions{}); err == nil {
+		return nil
+	} else if !cerrdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to inspect Docker network %s: %w", nw.Name, err)
+	}
+
+	_, err := d.client.NetworkCreate(ctx, nw.Name, network.CreateOptions{
+		Driver:   nw.Driver,
+		Options:  nw.DriverOpts,
+		Internal: nw.Internal,
+		Labels:   mergeLabels(nw.Labels, map[string]string{composeProjectLabel: project.Name, composeNetworkLabel: key}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create Docker network %s: %w", nw.Name, err)
+	}
+	return nil
+}
+
+// deployComposeService replaces the container of a service with a new one and starts it
+func (d *DockerRuntime) deployComposeService(ctx context.Context, project *types.Project, service types.ServiceConfig) error {
+	name := composeContainerName(project, service)
+
+	if err := d.ensureImage(ctx, service.Image, nil); err != nil {
+		return err
+	}
+
+	exposedPorts, portBindings, err := composePorts(service.Ports)
+	if err != nil {
+		return err
+	}
+	mounts, err := composeMounts(project, service.Volumes)
+	if err != nil {
+		return err
+	}
+
+	config := &container.Config{
+		Image:        service.Image,
+		Env:          composeEnv(service.Environment),
+		Labels:       composeContainerLabels(project, service),
+		ExposedPorts: exposedPorts,
+		WorkingDir:   service.WorkingDir,
+		User:         service.User,
+		Hostname:     service.Hostname,
+		Cmd:          strslice.StrSlice(service.Command),
+		Entrypoint:   strslice.StrSlice(service.Entrypoint),
+	}
+	hostConfig := &container.HostConfig{
+		PortBindings:  portBindings,
+		Mounts:        mounts,
+		RestartPolicy: composeRestartPolicy(service.Restart),
+		Privileged:    service.Privileged,
+		CapAdd:        service.CapAdd,
+		CapDrop:       service.CapDrop,
+	}
+
+	// Only one network can be attached at creation time, the others are connected afterwards
+	var networks []string
+	var networkConfigs map[string]*types.ServiceNetworkConfig
+	var networkingConfig *network.NetworkingConfig
+	if service.NetworkMode != "" {
+		hostConfig.NetworkMode = container.NetworkMode(composeNetworkMode(project, service.NetworkMode))
+	} else {
+		networks, networkConfigs = composeServiceNetworks(project, service)
+		if len(networks) > 0 {
+			hostConfig.NetworkMode = container.NetworkMode(networks[0])
+			networkingConfig = &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{
+				networks[0]: composeEndpoint(service, networkConfigs[networks[0]]),
+			}}
+		}
+	}
+
+	if err := d.removeExistingContainer(ctx, name); err != nil {
+		return err
+	}
+
+	resp, err := d.client.ContainerCreate(ctx, config, hostConfig, networkingConfig, nil, name)
+	if err != nil {
+		return fmt.Errorf("failed to create Docker container %s: %w", name, err)
+	}
+
+	for _, nw := range networks[min(1, len(networks)):] {
+		if err := d.client.NetworkConnect(ctx, nw, resp.ID, composeEndpoint(service, networkConfigs[nw])); err != nil {
+			return fmt.Errorf("failed to connect container %s to network %s: %w", name, nw, err)
+		}
+	}
+
+	if err := d.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start Docker container %s: %w", name, err)
+	}
+	logger.Info("DockerRuntime.deployComposeProject: Started service", "project", project.Name, "service", service.Name, "container", name)
+	return nil
+}
+
+// removeExistingContainer force-removes the container with the given name, if any
+func (d *DockerRuntime) removeExistingContainer(ctx context.Context, name string) error {
+	err := d.client.ContainerRemove(ctx, name, container.RemoveOptions{Force: true})
+	if err != nil && !cerrdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to remove existing Docker container %s: %w", name, err)
+	}
+	return nil
+}
+
+// composeEndpoint returns the endpoint settings of a service on one network.
+// The service name is always an alias, so services can reach each other by name.
+func composeEndpoint(service types.ServiceConfig, cfg *types.ServiceNetworkConfig) *network.EndpointSettings {
+	endpoint := &network.EndpointSettings{Aliases: []string{service.Name}}
+	if cfg != nil {
+		endpoint.Aliases = append(endpoint.Aliases, cfg.Aliases...)
+		if cfg.Ipv4Address != "" || cfg.Ipv6Address != "" {
+			endpoint.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: cfg.Ipv4Address, IPv6Address: cfg.Ipv6Address}
+		}
+	}
+	return endpoint
+}
+
+// mergeLabels returns the labels with the extra labels added
+func mergeLabels(labels map[string]string, extra map[string]string) map[string]string {
+	merged := make(map[string]string, len(labels)+len(extra))
+	for key, value := range labels {
+		merged[key] = value
+	}
+	for key, value := range extra {
+		merged[key] = value
+	}
+	return merged
+}
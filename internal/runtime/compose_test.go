@@ -0,0 +1,255 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeComposeFile writes the content to a compose file in a temp directory and returns its path
+func writeComposeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadComposeProject(t *testing.T) {
+	path := writeComposeFile(t, `
+services:
+  web:
+    image: nginx:latest
+    ports:
+      - "8080:80"
+    volumes:
+      - ./html:/usr/share/nginx/html:ro
+      - data:/data
+    environment:
+      MODE: production
+    restart: unless-stopped
+volumes:
+  data:
+`)
+
+	project, err := loadComposeProject(context.Background(), path, "myapp", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "myapp", project.Name)
+	assert.Empty(t, unsupportedComposeFeatures(project))
+
+	web, err := project.GetService("web")
+	require.NoError(t, err)
+	assert.Equal(t, "nginx:latest", web.Image)
+	assert.Equal(t, "myapp-web-1", composeContainerName(project, web))
+	assert.Equal(t, []string{"MODE=production"}, composeEnv(web.Environment))
+
+	mounts, err := composeMounts(project, web.Volumes)
+	require.NoError(t, err)
+	require.Len(t, mounts, 2)
+	assert.Equal(t, mount.Mount{Type: mount.TypeBind, Source: filepath.Join(filepath.Dir(path), "html"), Target: "/usr/share/nginx/html", ReadOnly: true}, mounts[0])
+	assert.Equal(t, mount.Mount{Type: mount.TypeVolume, Source: "myapp_data", Target: "/data"}, mounts[1])
+
+	exposed, bindings, err := composePorts(web.Ports)
+	require.NoError(t, err)
+	assert.Contains(t, exposed, nat.Port("80/tcp"))
+	assert.Equal(t, []nat.PortBinding{{HostPort: "8080"}}, bindings[nat.Port("80/tcp")])
+
+	networks, _ := composeServiceNetworks(project, web)
+	assert.Equal(t, []string{"myapp_default"}, networks)
+}
+
+func TestLoadComposeProjectInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "malformed yaml", content: "services:\n  web:\n    image: [nginx\n"},
+		{name: "unknown field", content: "services:\n  web:\n    image: nginx\n    colour: blue\n"},
+		{name: "undefined volume", content: "services:\n  web:\n    image: nginx\n    volumes:\n      - data:/data\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadComposeProject(context.Background(), writeComposeFile(t, tt.content), "myapp", nil)
+			assert.ErrorIs(t, err, ErrInvalidCompose)
+		})
+	}
+}
+
+func TestUnsupportedComposeFeatures(t *testing.T) {
+	path := writeComposeFile(t, `
+services:
+  app:
+    build: .
+  worker:
+    image: worker:latest
+    deploy:
+      replicas: 3
+`)
+
+	project, err := loadComposeProject(context.Background(), path, "myapp", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"service app: build", "service worker: replicas"}, unsupportedComposeFeatures(project))
+}
+
+func TestUnsupportedComposeFeaturesUnmappedKeys(t *testing.T) {
+	path := writeComposeFile(t, `
+services:
+  web:
+    image: nginx:latest
+    healthcheck:
+      test: ["CMD", "curl", "-f", "http://localhost"]
+    ulimits:
+      nofile: 65535
+    ports:
+      - "8080:80"
+    depends_on:
+      db:
+        condition: service_healthy
+  db:
+    image: postgres:16
+    deploy:
+      resources:
+        limits:
+          memory: 512M
+networks:
+  default:
+    ipam:
+      config:
+        - subnet: 172.28.0.0/16
+`)
+
+	project, err := loadComposeProject(context.Background(), path, "myapp", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"service db: deploy.resources",
+		"service web: healthcheck",
+		"service web: ulimits",
+		"service web: depends_on.condition",
+		"network default: ipam",
+	}, unsupportedComposeFeatures(project))
+}
+
+func TestComposeScaleArgs(t *testing.T) {
+	path := writeComposeFile(t, "services:\n  web:\n    image: nginx\n")
+	dir := filepath.Dir(path)
+
+	args, err := composeScaleArgs(context.Background(), "myapp", dir, "web", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-f", path, "-p", "myapp", "up", "-d", "--no-recreate", "--scale", "web=3", "web"}, args)
+
+	_, err = composeScaleArgs(context.Background(), "myapp", dir, "web", -1)
+	assert.ErrorIs(t, err, ErrInvalidCompose)
+	_, err = composeScaleArgs(context.Background(), "myapp", dir, "db", 1)
+	assert.ErrorIs(t, err, ErrInvalidCompose)
+}
+
+func TestComposeContainerLabels(t *testing.T) {
+	project := &types.Project{Name: "myapp", WorkingDir: "/srv/myapp", ComposeFiles: []string{"/srv/myapp/docker-compose.yml"}}
+	service := types.ServiceConfig{Name: "web", Labels: types.Labels{"caddy": "example.com"}}
+
+	labels := composeContainerLabels(project, service)
+	assert.Equal(t, "example.com", labels["caddy"])
+	assert.Equal(t, "myapp", labels[composeProjectLabel])
+	assert.Equal(t, "web", labels[composeServiceLabel])
+	assert.Equal(t, "/srv/myapp", labels[composeWorkingDirLabel])
+	assert.Equal(t, "/srv/myapp/docker-compose.yml", labels[composeConfigFilesLabel])
+}
+
+func TestComposeRestartPolicy(t *testing.T) {
+	assert.Equal(t, container.RestartPolicy{Name: container.RestartPolicyDisabled}, composeRestartPolicy("no"))
+	assert.Equal(t, container.RestartPolicy{Name: container.RestartPolicyAlways}, composeRestartPolicy("always"))
+	assert.Equal(t, container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3}, composeRestartPolicy("on-failure:3"))
+}
+
+func TestComposeNetworkMode(t *testing.T) {
+	project := &types.Project{Name: "myapp", Services: types.Services{"vpn": {Name: "vpn"}}}
+
+	assert.Equal(t, "container:myapp-vpn-1", composeNetworkMode(project, "service:vpn"))
+	assert.Equal(t, "host", composeNetworkMode(project, "host"))
+	assert.Equal(t, "service:missing", composeNetworkMode(project, "service:missing"))
+}
+
+func TestComposeServiceNetworks(t *testing.T) {
+	project := &types.Project{Networks: types.Networks{
+		"front": {Name: "myapp_front"},
+		"back":  {Name: "myapp_back"},
+	}}
+	service := types.ServiceConfig{Networks: map[string]*types.ServiceNetworkConfig{
+		"back":  nil,
+		"front": {Priority: 10, Aliases: []string{"www"}},
+	}}
+
+	names, configs := composeServiceNetworks(project, service)
+	assert.Equal(t, []string{"myapp_front", "myapp_back"}, names)
+	assert.Equal(t, []string{"www"}, configs["myapp_front"].Aliases)
+}
+
+func TestLoadComposeProjectInterpolation(t *testing.T) {
+	t.Setenv("GINTAINER_TEST_REGISTRY", "registry.example.com")
+	t.Setenv("GINTAINER_TEST_PORT", "9090")
+
+	path := writeComposeFile(t, `
+services:
+  web:
+    image: ${GINTAINER_TEST_REGISTRY}/web:${TAG}
+    ports:
+      - "${GINTAINER_TEST_PORT}:80"
+    environment:
+      GREETING: ${GREETING}
+`)
+	dir := filepath.Dir(path)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, composeDotEnvFile), []byte("TAG=1.0\nGINTAINER_TEST_PORT=8080\n"), 0600))
+
+	// The process environment overrides .env
+	project, err := loadComposeProject(context.Background(), path, "myapp", nil)
+	require.NoError(t, err)
+	web, err := project.GetService("web")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/web:1.0", web.Image)
+	assert.Equal(t, "9090", web.Ports[0].Published)
+
+	// Request variables are written to .env and override everything else
+	env := map[string]string{"TAG": "2.0", "GINTAINER_TEST_PORT": "7070", "GREETING": `say "hi" for $5\n`}
+	require.NoError(t, writeComposeDotEnv(dir, env))
+	project, err = loadComposeProject(context.Background(), path, "myapp", env)
+	require.NoError(t, err)
+	web, err = project.GetService("web")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/web:2.0", web.Image)
+	assert.Equal(t, "7070", web.Ports[0].Published)
+
+	// The written .env reads back to the same values
+	values, err := composeEnvironment(dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0", values["TAG"])
+	assert.Equal(t, `say "hi" for $5\n`, values["GREETING"])
+}
+
+func TestWriteComposeDotEnv(t *testing.T) {
+	dir := t.TempDir()
+	dotEnvPath := filepath.Join(dir, composeDotEnvFile)
+	require.NoError(t, os.WriteFile(dotEnvPath, []byte("KEEP=1\n"), 0600))
+
+	// An empty env keeps the existing file
+	require.NoError(t, writeComposeDotEnv(dir, nil))
+	content, err := os.ReadFile(dotEnvPath)
+	require.NoError(t, err)
+	assert.Equal(t, "KEEP=1\n", string(content))
+
+	require.NoError(t, writeComposeDotEnv(dir, map[string]string{"B": "2", "A": "1"}))
+	content, err = os.ReadFile(dotEnvPath)
+	require.NoError(t, err)
+	assert.Equal(t, "A=\"1\"\nB=\"2\"\n", string(content))
+
+	for _, key := range []string{"", "A=B", "WITH SPACE"} {
+		assert.ErrorIs(t, writeComposeDotEnv(dir, map[string]string{key: "x"}), ErrInvalidCompose, key)
+	}
+}
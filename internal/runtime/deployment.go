@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// DeploymentManifestFile is the name of the manifest written into each deployment directory
+const DeploymentManifestFile = "gintainer.json"
+
+// writeDeploymentManifest records a deployment of project in dir.
+// The creation time of an earlier deployment in the same directory is kept.
+func writeDeploymentManifest(dir, runtimeName, projectName string, services []string) error {
+	now := time.Now().UTC()
+	manifest := models.DeploymentManifest{
+		ProjectName: projectName,
+		Runtime:     runtimeName,
+		Services:    services,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if previous, err := readManifestFile(dir); err == nil && !previous.CreatedAt.IsZero() {
+		manifest.CreatedAt = previous.CreatedAt
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode deployment manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, DeploymentManifestFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write deployment manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadDeploymentManifest returns the manifest of the deployment in dir. Deployments made before
+// manifests existed have their compose file parsed instead; their runtime is unknown and the
+// compose file's modification time is used as the creation time.
+func ReadDeploymentManifest(ctx context.Context, dir string) (models.DeploymentManifest, error) {
+	manifest, err := readManifestFile(dir)
+	if err == nil {
+		return manifest, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return models.DeploymentManifest{}, err
+	}
+
+	composePath := filepath.Join(dir, "docker-compose.yml")
+	info, err := os.Stat(composePath)
+	if err != nil {
+		return models.DeploymentManifest{}, fmt.Errorf("failed to read compose file: %w", err)
+	}
+	project, err := loadComposeProject(ctx, composePath, filepath.Base(dir), nil)
+	if err != nil {
+		return models.DeploymentManifest{}, err
+	}
+
+	return models.DeploymentManifest{
+		ProjectName: project.Name,
+		Services:    project.ServiceNames(),
+		CreatedAt:   info.ModTime().UTC(),
+	}, nil
+}
+
+// readManifestFile decodes the manifest file in dir
+func readManifestFile(dir string) (models.DeploymentManifest, error) {
+	var manifest models.DeploymentManifest
+	data, err := os.ReadFile(filepath.Join(dir, DeploymentManifestFile))
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to decode deployment manifest: %w", err)
+	}
+	return manifest, nil
+}
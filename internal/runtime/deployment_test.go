@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, writeDeploymentManifest(dir, "podman", "web", []string{"app", "db"}))
+	first, err := ReadDeploymentManifest(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "web", first.ProjectName)
+	assert.Equal(t, "podman", first.Runtime)
+	assert.Equal(t, []string{"app", "db"}, first.Services)
+	assert.False(t, first.CreatedAt.IsZero())
+
+	// Redeploying keeps the creation time
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, writeDeploymentManifest(dir, "podman", "web", []string{"app"}))
+	second, err := ReadDeploymentManifest(context.Background(), dir)
+	require.NoError(t, err)
+	assert.True(t, second.CreatedAt.Equal(first.CreatedAt))
+	assert.True(t, second.UpdatedAt.After(first.UpdatedAt))
+	assert.Equal(t, []string{"app"}, second.Services)
+}
+
+func TestReadDeploymentManifestFallback(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "legacy")
+	require.NoError(t, os.Mkdir(dir, 0755))
+	composePath := filepath.Join(dir, "docker-compose.yml")
+	require.NoError(t, os.WriteFile(composePath, []byte("services:\n  web:\n    image: nginx\n  cache:\n    image: redis\n"), 0644))
+
+	manifest, err := ReadDeploymentManifest(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", manifest.ProjectName)
+	assert.Empty(t, manifest.Runtime)
+	assert.Equal(t, []string{"cache", "web"}, manifest.Services)
+
+	info, err := os.Stat(composePath)
+	require.NoError(t, err)
+	assert.True(t, manifest.CreatedAt.Equal(info.ModTime()))
+
+	_, err = ReadDeploymentManifest(context.Background(), t.TempDir())
+	assert.Error(t, err)
+}
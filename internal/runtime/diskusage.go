@@ -0,0 +1,140 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/containers/podman/v5/pkg/bindings/system"
+	entities "github.com/containers/podman/v5/pkg/domain/entities/types"
+	"github.com/docker/docker/api/types"
+)
+
+// DiskUsage reports the disk space used by Docker, computed like `docker system df`
+func (d *DockerRuntime) DiskUsage(ctx context.Context) (models.DiskUsage, error) {
+	du, err := d.client.DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return models.DiskUsage{}, fmt.Errorf("failed to get Docker disk usage: %w", err)
+	}
+	return dockerDiskUsage(du), nil
+}
+
+// dockerDiskUsage normalizes a Docker disk usage report
+func dockerDiskUsage(du types.DiskUsage) models.DiskUsage {
+	usage := models.DiskUsage{Runtime: "docker"}
+
+	// Images share layers, so the total is the size of all layers and images in use
+	// only count with the part not shared with other images
+	var imagesUsed int64
+	for _, img := range du.Images {
+		usage.Images.Total++
+		if img.Containers > 0 {
+			usage.Images.Active++
+			if img.Size >= 0 && img.SharedSize >= 0 {
+				imagesUsed += img.Size - img.SharedSize
+			}
+		}
+	}
+	usage.Images.Size = bytesOf(du.LayersSize)
+	usage.Images.Reclaimable = bytesOf(du.LayersSize - imagesUsed)
+
+	for _, c := range du.Containers {
+		usage.Containers.Total++
+		usage.Containers.Size += bytesOf(c.SizeRw)
+		if c.State == "running" {
+			usage.Containers.Active++
+		} else {
+			usage.Containers.Reclaimable += bytesOf(c.SizeRw)
+		}
+	}
+
+	for _, v := range du.Volumes {
+		usage.Volumes.Total++
+		if v.UsageData == nil {
+			continue
+		}
+		usage.Volumes.Size += bytesOf(v.UsageData.Size)
+		if v.UsageData.RefCount > 0 {
+			usage.Volumes.Active++
+		} else {
+			usage.Volumes.Reclaimable += bytesOf(v.UsageData.Size)
+		}
+	}
+
+	for _, record := range du.BuildCache {
+		usage.BuildCache.Total++
+		if record.Shared {
+			continue
+		}
+		usage.BuildCache.Size += bytesOf(record.Size)
+		if record.InUse {
+			usage.BuildCache.Active++
+		} else {
+			usage.BuildCache.Reclaimable += bytesOf(record.Size)
+		}
+	}
+
+	return withDiskUsageTotals(usage)
+}
+
+// DiskUsage reports the disk space used by Podman, computed like `podman system df`
+func (p *PodmanRuntime) DiskUsage(ctx context.Context) (models.DiskUsage, error) {
+	report, err := system.DiskUsage(p.connCtx, nil)
+	if err != nil {
+		return models.DiskUsage{}, fmt.Errorf("failed to get Podman disk usage: %w", err)
+	}
+	return podmanDiskUsage(report), nil
+}
+
+// podmanDiskUsage normalizes a Podman disk usage report
+func podmanDiskUsage(report *entities.SystemDfReport) models.DiskUsage {
+	usage := models.DiskUsage{Runtime: "podman"}
+
+	usage.Images.Size = bytesOf(report.ImagesSize)
+	for _, img := range report.Images {
+		usage.Images.Total++
+		if img.Containers > 0 {
+			usage.Images.Active++
+		} else {
+			usage.Images.Reclaimable += bytesOf(img.UniqueSize)
+		}
+	}
+
+	for _, c := range report.Containers {
+		usage.Containers.Total++
+		usage.Containers.Size += bytesOf(c.RWSize)
+		if c.Status == "running" {
+			usage.Containers.Active++
+		} else {
+			usage.Containers.Reclaimable += bytesOf(c.RWSize)
+		}
+	}
+
+	for _, v := range report.Volumes {
+		usage.Volumes.Total++
+		usage.Volumes.Size += bytesOf(v.Size)
+		usage.Volumes.Reclaimable += bytesOf(v.ReclaimableSize)
+		if v.Links > 0 {
+			usage.Volumes.Active++
+		}
+	}
+
+	return withDiskUsageTotals(usage)
+}
+
+// withDiskUsageTotals sums the categories into the totals
+func withDiskUsageTotals(usage models.DiskUsage) models.DiskUsage {
+	for _, category := range []models.DiskUsageCategory{usage.Images, usage.Containers, usage.Volumes, usage.BuildCache} {
+		usage.TotalSize += category.Size
+		usage.Reclaimable += category.Reclaimable
+	}
+	return usage
+}
+
+// bytesOf converts a size reported by a runtime to bytes; unknown sizes (-1) count as zero
+func bytesOf(size int64) uint64 {
+	if size < 0 {
+		return 0
+	}
+	return uint64(size)
+}
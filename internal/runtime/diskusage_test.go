@@ -0,0 +1,72 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	entities "github.com/containers/podman/v5/pkg/domain/entities/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerDiskUsage(t *testing.T) {
+	usage := dockerDiskUsage(types.DiskUsage{
+		LayersSize: 1000,
+		Images: []*image.Summary{
+			{ID: "used", Size: 600, SharedSize: 200, Containers: 1},
+			{ID: "unused", Size: 500, SharedSize: 200, Containers: 0},
+		},
+		Containers: []*container.Summary{
+			{ID: "running", State: "running", SizeRw: 30},
+			{ID: "exited", State: "exited", SizeRw: 20},
+		},
+		Volumes: []*volume.Volume{
+			{Name: "data", UsageData: &volume.UsageData{RefCount: 1, Size: 100}},
+			{Name: "old", UsageData: &volume.UsageData{RefCount: 0, Size: 50}},
+			{Name: "remote"},
+		},
+		BuildCache: []*build.CacheRecord{
+			{ID: "a", Size: 70, InUse: true},
+			{ID: "b", Size: 80},
+			{ID: "c", Size: 90, Shared: true},
+		},
+	})
+
+	assert.Equal(t, "docker", usage.Runtime)
+	assert.Equal(t, models.DiskUsageCategory{Total: 2, Active: 1, Size: 1000, Reclaimable: 600}, usage.Images)
+	assert.Equal(t, models.DiskUsageCategory{Total: 2, Active: 1, Size: 50, Reclaimable: 20}, usage.Containers)
+	assert.Equal(t, models.DiskUsageCategory{Total: 3, Active: 1, Size: 150, Reclaimable: 50}, usage.Volumes)
+	assert.Equal(t, models.DiskUsageCategory{Total: 3, Active: 1, Size: 150, Reclaimable: 80}, usage.BuildCache)
+	assert.Equal(t, uint64(1350), usage.TotalSize)
+	assert.Equal(t, uint64(750), usage.Reclaimable)
+}
+
+func TestPodmanDiskUsage(t *testing.T) {
+	usage := podmanDiskUsage(&entities.SystemDfReport{
+		ImagesSize: 1000,
+		Images: []*entities.SystemDfImageReport{
+			{ImageID: "used", Size: 600, UniqueSize: 400, Containers: 2},
+			{ImageID: "unused", Size: 500, UniqueSize: 300},
+		},
+		Containers: []*entities.SystemDfContainerReport{
+			{ContainerID: "running", Status: "running", RWSize: 30},
+			{ContainerID: "exited", Status: "exited", RWSize: 20},
+		},
+		Volumes: []*entities.SystemDfVolumeReport{
+			{VolumeName: "data", Links: 1, Size: 100},
+			{VolumeName: "old", Size: 50, ReclaimableSize: 50},
+		},
+	})
+
+	assert.Equal(t, "podman", usage.Runtime)
+	assert.Equal(t, models.DiskUsageCategory{Total: 2, Active: 1, Size: 1000, Reclaimable: 300}, usage.Images)
+	assert.Equal(t, models.DiskUsageCategory{Total: 2, Active: 1, Size: 50, Reclaimable: 20}, usage.Containers)
+	assert.Equal(t, models.DiskUsageCategory{Total: 2, Active: 1, Size: 150, Reclaimable: 50}, usage.Volumes)
+	assert.Zero(t, usage.BuildCache)
+	assert.Equal(t, uint64(1200), usage.TotalSize)
+	assert.Equal(t, uint64(370), usage.Reclaimable)
+}
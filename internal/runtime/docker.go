@@ -3,38 +3,74 @@ package runtime
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ThraaxSession/gintainer/internal/logger"
 	"github.com/ThraaxSession/gintainer/internal/models"
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/build"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 )
 
 // DockerRuntime implements ContainerRuntime for Docker
 type DockerRuntime struct {
-	client *client.Client
+	client            *client.Client
+	updateGracePeriod time.Duration // How long an updated container must stay up before the old one is removed
+	listWorkers       int           // Containers whose stats or inspect data are fetched at once while listing
 }
 
-// NewDockerRuntime creates a new Docker runtime
-func NewDockerRuntime() (*DockerRuntime, error) {
+// DockerConnection describes how to reach the Docker daemon
+type DockerConnection struct {
+	Socket      string // Socket path or daemon address (unix:// or tcp://)
+	Context     string // Name of a docker CLI context, used when Socket is empty
+	ListWorkers int    // Parallel stats/inspect calls while listing containers (0 for DefaultListWorkers)
+}
+
+// NewDockerRuntime creates a new Docker runtime.
+// A configured socket or context is used as the daemon address instead of DOCKER_HOST.
+func NewDockerRuntime(conn DockerConnection) (*DockerRuntime, error) {
 	logger.Debug("NewDockerRuntime: Starting Docker runtime initialization")
 
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+
+	// The "default" context means the environment and default socket, like in the docker CLI
+	useContext := conn.Socket == "" && conn.Context != "" && conn.Context != "default"
+
 	// Log environment variables that affect Docker client
 	dockerHost := os.Getenv("DOCKER_HOST")
-	if dockerHost != "" {
+	if conn.Socket != "" {
+		host := socketURI(conn.Socket)
+		logger.Debug("NewDockerRuntime: Using socket from config", "host", host)
+		opts = append(opts, client.WithHost(host))
+	} else if useContext {
+		contextOpts, err := dockerContextOpts(conn.Context)
+		if err != nil {
+			logger.Error("NewDockerRuntime: Failed to resolve docker context", "context", conn.Context, "error", err)
+			return nil, err
+		}
+		opts = append(opts, contextOpts...)
+	} else if dockerHost != "" {
 		logger.Debug("NewDockerRuntime: DOCKER_HOST environment variable set", "host", dockerHost)
 	} else {
 		// Default socket path when DOCKER_HOST is not set
@@ -51,7 +87,7 @@ func NewDockerRuntime() (*DockerRuntime, error) {
 		}
 	}
 
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		logger.Error("NewDockerRuntime: Failed to create Docker client", "error", err)
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
@@ -71,7 +107,30 @@ func NewDockerRuntime() (*DockerRuntime, error) {
 	}
 
 	logger.Info("NewDockerRuntime: Docker runtime initialized successfully")
-	return &DockerRuntime{client: cli}, nil
+	return &DockerRuntime{client: cli, updateGracePeriod: defaultUpdateGracePeriod, listWorkers: listWorkers(conn.ListWorkers)}, nil
+}
+
+// dockerContextOpts returns the client options connecting to the endpoint of a docker CLI context
+func dockerContextOpts(name string) ([]client.Opt, error) {
+	configDir, err := dockerConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := resolveDockerContext(configDir, name)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("NewDockerRuntime: Using docker context from config", "context", name, "host", endpoint.Host)
+
+	opts := []client.Opt{client.WithHost(endpoint.Host)}
+	if endpoint.Cert != "" {
+		opts = append(opts, client.WithTLSClientConfig(endpoint.CACert, endpoint.Cert, endpoint.Key))
+	}
+	if endpoint.SkipTLSVerify {
+		logger.Warn("NewDockerRuntime: skip_tls_verify of the docker context is not supported, verifying the daemon certificate", "context", name)
+	}
+	return opts, nil
 }
 
 // ListContainers lists all Docker containers
@@ -84,6 +143,10 @@ func (d *DockerRuntime) ListContainers(ctx context.Context, filterOpts models.Fi
 	if filterOpts.Status != "" {
 		filterArgs.Add("status", filterOpts.Status)
 	}
+	if filterOpts.Label != "" {
+		// Docker matches both "key" and "key=value"
+		filterArgs.Add("label", filterOpts.Label)
+	}
 
 	containers, err := d.client.ContainerList(ctx, container.ListOptions{
 		All:     true,
@@ -121,28 +184,64 @@ func (d *DockerRuntime) ListContainers(ctx context.Context, filterOpts models.Fi
 			Ports:   ports,
 		}
 
-		// Check if container is privileged by inspecting it
-		if filterOpts.IncludePrivileged {
-			inspect, err := d.client.ContainerInspect(ctx, c.ID)
-			if err == nil && inspect.HostConfig != nil {
-				containerInfo.Privileged = inspect.HostConfig.Privileged
+		// The list response already carries network endpoints, so no inspect is needed
+		if c.NetworkSettings != nil {
+			ips := make(map[string]string, len(c.NetworkSettings.Networks))
+			for name, endpoint := range c.NetworkSettings.Networks {
+				if endpoint != nil {
+					ips[name] = endpoint.IPAddress
+				}
 			}
+			containerInfo.IPAddress = firstIPAddress(ips)
 		}
 
-		// Get stats if requested and container is running
-		if filterOpts.IncludeStats && c.State == "running" {
-			stats, err := d.getContainerStats(ctx, c.ID)
-			if err == nil {
-				containerInfo.Stats = stats
+		result = append(result, containerInfo)
+	}
+
+	if !filterOpts.IncludePrivileged && !filterOpts.IncludeStats && !filterOpts.IncludeState {
+		return result, nil
+	}
+
+	// Inspect and stats calls take a round trip each (stats up to a second or two), so run them in parallel
+	forEachParallel(ctx, len(result), listWorkers(d.listWorkers), listCallTimeout, func(ctx context.Context, i int) {
+		info := &result[i]
+
+		// Inspect the container to check if it's privileged and when it was started.
+		// With stats only, the start time is only of interest for running containers.
+		if filterOpts.IncludePrivileged || filterOpts.IncludeState || (filterOpts.IncludeStats && info.State == "running") {
+			inspect, err := d.client.ContainerInspect(ctx, info.ID)
+			if err == nil && inspect.HostConfig != nil && filterOpts.IncludePrivileged {
+				info.Privileged = inspect.HostConfig.Privileged
+			}
+			if err == nil && inspect.ContainerJSONBase != nil && inspect.State != nil && (filterOpts.IncludeState || filterOpts.IncludeStats) {
+				info.StartedAt = dockerStartedAt(inspect.State.StartedAt)
 			}
 		}
 
-		result = append(result, containerInfo)
-	}
+		// Get stats if requested and container is running
+		if filterOpts.IncludeStats && info.State == "running" {
+			stats, err := d.getContainerStats(ctx, info.ID)
+			if err != nil {
+				logger.Debug("DockerRuntime.ListContainers: Failed to get stats", "id", info.ID, "error", err)
+				return
+			}
+			info.Stats = stats
+		}
+	})
 
 	return result, nil
 }
 
+// dockerStartedAt parses the start time of an inspected container; containers that
+// never started report the zero time, which is kept as unset
+func dockerStartedAt(value string) time.Time {
+	startedAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || startedAt.Year() <= 1 {
+		return time.Time{}
+	}
+	return startedAt
+}
+
 // getContainerStats retrieves real-time stats for a container
 func (d *DockerRuntime) getContainerStats(ctx context.Context, containerID string) (*models.ContainerStats, error) {
 	stats, err := d.client.ContainerStats(ctx, containerID, false)
@@ -233,8 +332,8 @@ func (d *DockerRuntime) StartContainer(ctx context.Context, containerID string)
 }
 
 // StopContainer stops a Docker container
-func (d *DockerRuntime) StopContainer(ctx context.Context, containerID string) error {
-	timeout := 10
+func (d *DockerRuntime) StopContainer(ctx context.Context, containerID string, stopTimeoutSeconds *int) error {
+	timeout := stopTimeout(stopTimeoutSeconds)
 	err := d.client.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout})
 	if err != nil {
 		return fmt.Errorf("failed to stop Docker container %s: %w", containerID, err)
@@ -243,8 +342,8 @@ func (d *DockerRuntime) StopContainer(ctx context.Context, containerID string) e
 }
 
 // RestartContainer restarts a Docker container
-func (d *DockerRuntime) RestartContainer(ctx context.Context, containerID string) error {
-	timeout := 10
+func (d *DockerRuntime) RestartContainer(ctx context.Context, containerID string, stopTimeoutSeconds *int) error {
+	timeout := stopTimeout(stopTimeoutSeconds)
 	err := d.client.ContainerRestart(ctx, containerID, container.StopOptions{Timeout: &timeout})
 	if err != nil {
 		return fmt.Errorf("failed to restart Docker container %s: %w", containerID, err)
@@ -252,6 +351,54 @@ func (d *DockerRuntime) RestartContainer(ctx context.Context, containerID string
 	return nil
 }
 
+// UpdateContainerResources changes the limits and restart policy of a Docker container in place
+func (d *DockerRuntime) UpdateContainerResources(ctx context.Context, containerID string, update models.ResourceUpdate) error {
+	if err := ValidateResourceUpdate(update); err != nil {
+		return err
+	}
+
+	updateConfig := container.UpdateConfig{
+		Resources: container.Resources{
+			Memory:   update.Memory,
+			NanoCPUs: int64(update.CPUs * 1e9),
+		},
+	}
+	if update.RestartPolicy != "" {
+		name, retries, _ := parseRestartPolicy(update.RestartPolicy)
+		updateConfig.RestartPolicy = container.RestartPolicy{Name: container.RestartPolicyMode(name), MaximumRetryCount: retries}
+	}
+
+	resp, err := d.client.ContainerUpdate(ctx, containerID, updateConfig)
+	if err != nil {
+		return fmt.Errorf("failed to update Docker container %s: %w", containerID, err)
+	}
+	for _, warning := range resp.Warnings {
+		logger.Warn("DockerRuntime.UpdateContainerResources: Update warning", "id", containerID, "warning", warning)
+	}
+	return nil
+}
+
+// KillContainer sends a signal to a Docker container
+func (d *DockerRuntime) KillContainer(ctx context.Context, containerID string, signal string) error {
+	signal, err := NormalizeSignal(signal)
+	if err != nil {
+		return err
+	}
+
+	inspect, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect Docker container %s: %w", containerID, err)
+	}
+	if inspect.State == nil || !inspect.State.Running {
+		return ErrContainerNotRunning
+	}
+
+	if err := d.client.ContainerKill(ctx, containerID, signal); err != nil {
+		return fmt.Errorf("failed to kill Docker container %s: %w", containerID, err)
+	}
+	return nil
+}
+
 // DeletePod returns an error (Docker doesn't have pods)
 func (d *DockerRuntime) DeletePod(ctx context.Context, podID string, force bool) error {
 	return fmt.Errorf("Docker does not support pods")
@@ -262,6 +409,31 @@ func (d *DockerRuntime) StartPod(ctx context.Context, podID string) error {
 	return fmt.Errorf("Docker does not support pods")
 }
 
+// CheckpointContainer returns an error (checkpoints can not be exported with Docker)
+func (d *DockerRuntime) CheckpointContainer(ctx context.Context, containerID, exportPath string) error {
+	return fmt.Errorf("Docker does not support exporting checkpoints")
+}
+
+// RestoreContainer returns an error (checkpoints can not be imported with Docker)
+func (d *DockerRuntime) RestoreContainer(ctx context.Context, importPath string) (string, error) {
+	return "", fmt.Errorf("Docker does not support importing checkpoints")
+}
+
+// GenerateSystemd returns an error (Docker can not generate systemd units)
+func (d *DockerRuntime) GenerateSystemd(ctx context.Context, containerID string, opts models.SystemdOptions) (map[string]string, error) {
+	return nil, fmt.Errorf("Docker does not support generating systemd units")
+}
+
+// GenerateKube returns an error (Docker can not generate Kubernetes YAML)
+func (d *DockerRuntime) GenerateKube(ctx context.Context, ids []string) (string, error) {
+	return "", fmt.Errorf("Docker does not support generating Kubernetes YAML")
+}
+
+// PodStats returns an error (Docker doesn't have pods)
+func (d *DockerRuntime) PodStats(ctx context.Context, podID string) (models.PodStats, error) {
+	return models.PodStats{}, fmt.Errorf("Docker does not support pods")
+}
+
 // StopPod returns an error (Docker doesn't have pods)
 func (d *DockerRuntime) StopPod(ctx context.Context, podID string) error {
 	return fmt.Errorf("Docker does not support pods")
@@ -273,7 +445,7 @@ func (d *DockerRuntime) RestartPod(ctx context.Context, podID string) error {
 }
 
 // BuildFromDockerfile builds a Docker image from a Dockerfile
-func (d *DockerRuntime) BuildFromDockerfile(ctx context.Context, dockerfile, imageName string) error {
+func (d *DockerRuntime) BuildFromDockerfile(ctx context.Context, dockerfile, imageName string, opts models.BuildOptions) error {
 	// Create a temporary directory for the build context
 	tempDir, err := os.MkdirTemp("", "docker-build-*")
 	if err != nil {
@@ -294,47 +466,122 @@ func (d *DockerRuntime) BuildFromDockerfile(ctx context.Context, dockerfile, ima
 	}
 	defer tar.Close()
 
-	// Build the image
+	return d.BuildFromContext(ctx, tar, "Dockerfile", imageName, opts)
+}
+
+// BuildFromContext builds a Docker image from a tar archive of a build context
+func (d *DockerRuntime) BuildFromContext(ctx context.Context, contextTar io.Reader, dockerfile, imageName string, opts models.BuildOptions) error {
+	// The daemon resolves the Dockerfile within the uploaded context, only keep the path inside it
 	buildOptions := types.ImageBuildOptions{
 		Tags:       []string{imageName},
-		Dockerfile: "Dockerfile",
+		Dockerfile: contextDockerfileName(dockerfile),
 		Remove:     true,
+		BuildArgs:  dockerBuildArgs(opts.BuildArgs),
+		Target:     opts.Target,
 	}
 
-	resp, err := d.client.ImageBuild(ctx, tar, buildOptions)
+	resp, err := d.client.ImageBuild(ctx, contextTar, buildOptions)
 	if err != nil {
 		return fmt.Errorf("failed to build Docker image: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Read build output
-	_, err = io.Copy(io.Discard, resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read build output: %w", err)
+	// Read build output, build failures are only reported inside the stream
+	if err := drainJSONMessages(resp.Body, nil); err != nil {
+		return fmt.Errorf("failed to build Docker image: %w", err)
 	}
 
 	return nil
 }
 
-// RunContainer creates and runs a container from an image with configuration
-func (d *DockerRuntime) RunContainer(ctx context.Context, req models.RunContainerRequest) (string, error) {
-	// Parse port bindings
-	portBindings := nat.PortMap{}
+// drainJSONMessages reads a Docker JSON progress stream to the end.
+// It returns the first error reported in the stream and passes aux payloads to onAux if set.
+func drainJSONMessages(r io.Reader, onAux func(json.RawMessage)) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read output: %w", err)
+		}
+		if msg.Error != nil {
+			return errors.New(msg.Error.Message)
+		}
+		if msg.Aux != nil && onAux != nil {
+			onAux(*msg.Aux)
+		}
+	}
+}
+
+// dockerBuildArgs converts build args into the pointer map expected by the Docker API
+func dockerBuildArgs(args map[string]string) map[string]*string {
+	if len(args) == 0 {
+		return nil
+	}
+	result := make(map[string]*string, len(args))
+	for key, value := range args {
+		v := value
+		result[key] = &v
+	}
+	return result
+}
+
+// dockerPortBindings converts "[host:]container[/protocol]" port mappings to exposed ports and bindings.
+// An empty host port lets Docker pick a random one, and a host port range for a single
+// container port ("8000-8005") lets it pick a free port from the range.
+func dockerPortBindings(specs []string) (nat.PortSet, nat.PortMap, error) {
 	exposedPorts := nat.PortSet{}
-	for _, portMap := range req.Ports {
-		parts := strings.Split(portMap, ":")
-		if len(parts) == 2 {
-			containerPort, err := nat.NewPort("tcp", parts[1])
+	portBindings := nat.PortMap{}
+	for _, spec := range specs {
+		ps, err := parsePortSpec(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := 0; i < ps.containerPortCount(); i++ {
+			containerPort, err := nat.NewPort(ps.Protocol, strconv.Itoa(int(ps.ContainerPort)+i))
 			if err != nil {
-				continue
+				return nil, nil, fmt.Errorf("%w %q: %v", ErrInvalidPort, spec, err)
+			}
+
+			hostPort := ""
+			switch {
+			case ps.HostPort == 0:
+			case ps.containerPortCount() == 1 && ps.hostPortCount() > 1:
+				hostPort = fmt.Sprintf("%d-%d", ps.HostPort, ps.HostPortEnd)
+			default:
+				hostPort = strconv.Itoa(int(ps.HostPort) + i)
 			}
+
 			exposedPorts[containerPort] = struct{}{}
-			portBindings[containerPort] = []nat.PortBinding{
-				{HostPort: parts[0]},
+			portBindings[containerPort] = append(portBindings[containerPort], nat.PortBinding{HostPort: hostPort})
+		}
+	}
+	return exposedPorts, portBindings, nil
+}
+
+// ContainerPorts returns the host ports a Docker container is published on
+func (d *DockerRuntime) ContainerPorts(ctx context.Context, containerID string) ([]models.PortMapping, error) {
+	inspect, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Docker container %s: %w", containerID, err)
+	}
+
+	bindings := make(map[string][]string)
+	if inspect.NetworkSettings != nil {
+		for port, hostBindings := range inspect.NetworkSettings.Ports {
+			for _, binding := range hostBindings {
+				bindings[string(port)] = append(bindings[string(port)], binding.HostPort)
 			}
 		}
 	}
+	return portMappingsFromBindings(bindings), nil
+}
 
+// RunContainer creates and runs a container from an image with configuration
+func (d *DockerRuntime) RunContainer(ctx context.Context, req models.RunContainerRequest) (string, error) {
 	// Parse volume bindings and create named volumes if needed
 	binds := make([]string, 0, len(req.Volumes))
 	for _, vol := range req.Volumes {
@@ -356,20 +603,15 @@ func (d *DockerRuntime) RunContainer(ctx context.Context, req models.RunContaine
 		}
 	}
 
-	// Create container config
-	config := &container.Config{
-		Image:        req.Image,
-		Env:          req.EnvVars,
-		ExposedPorts: exposedPorts,
+	config, hostConfig, err := dockerRunConfig(req, binds)
+	if err != nil {
+		return "", err
 	}
 
-	// Create host config
-	hostConfig := &container.HostConfig{
-		PortBindings: portBindings,
-		Binds:        binds,
-		RestartPolicy: container.RestartPolicy{
-			Name: container.RestartPolicyMode(req.RestartPolicy),
-		},
+	if !req.NoPull {
+		if err := d.ensureImage(ctx, req.Image, req.Auth); err != nil {
+			return "", err
+		}
 	}
 
 	// Create container
@@ -386,8 +628,75 @@ func (d *DockerRuntime) RunContainer(ctx context.Context, req models.RunContaine
 	return resp.ID, nil
 }
 
+// dockerRunConfig returns the container and host config for a run request with the given volume binds
+func dockerRunConfig(req models.RunContainerRequest, binds []string) (*container.Config, *container.HostConfig, error) {
+	// Parse port bindings
+	exposedPorts, portBindings, err := dockerPortBindings(req.Ports)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	securityOpts, err := dockerSecurityOpts(req.SecurityOpt)
+	if err != nil {
+		return nil, nil, err
+	}
+	devices, err := dockerDevices(req.Devices)
+	if err != nil {
+		return nil, nil, err
+	}
+	hosts, err := extraHosts(req.ExtraHosts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateTmpfs(req.Tmpfs); err != nil {
+		return nil, nil, err
+	}
+	if err := validateUser(req.User); err != nil {
+		return nil, nil, err
+	}
+
+	config := &container.Config{
+		Image:        req.Image,
+		Env:          req.EnvVars,
+		ExposedPorts: exposedPorts,
+		WorkingDir:   req.WorkingDir,
+		Entrypoint:   strslice.StrSlice(req.Entrypoint),
+		Cmd:          strslice.StrSlice(req.Command),
+		User:         req.User,
+		Hostname:     req.Hostname,
+	}
+
+	hostConfig := &container.HostConfig{
+		PortBindings: portBindings,
+		Binds:        binds,
+		RestartPolicy: container.RestartPolicy{
+			Name: container.RestartPolicyMode(req.RestartPolicy),
+		},
+		AutoRemove:     req.AutoRemove,
+		Privileged:     req.Privileged,
+		SecurityOpt:    securityOpts,
+		Tmpfs:          req.Tmpfs,
+		ExtraHosts:     hosts,
+		Resources:      container.Resources{Devices: devices},
+		ReadonlyRootfs: req.ReadOnly,
+	}
+	return config, hostConfig, nil
+}
+
+// ensureImage pulls an image that is not present locally
+func (d *DockerRuntime) ensureImage(ctx context.Context, imageName string, auth *models.RegistryAuth) error {
+	if _, err := d.client.ImageInspect(ctx, imageName); err == nil {
+		return nil
+	} else if !cerrdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to inspect Docker image %s: %w", imageName, err)
+	}
+
+	logger.Info("DockerRuntime: Pulling missing image", "image", imageName, "authenticated", auth != nil)
+	return d.PullImage(ctx, imageName, auth)
+}
+
 // DeployFromCompose deploys containers from a Docker Compose file
-func (d *DockerRuntime) DeployFromCompose(ctx context.Context, composeContent, projectName, deploymentPath string) error {
+func (d *DockerRuntime) DeployFromCompose(ctx context.Context, composeContent, projectName, deploymentPath string, env map[string]string) error {
 	// Use deployment path if provided, otherwise use temp directory
 	var composePath string
 	var cleanupFunc func()
@@ -414,44 +723,102 @@ func (d *DockerRuntime) DeployFromCompose(ctx context.Context, composeContent, p
 	if err := os.WriteFile(composePath, []byte(composeContent), 0644); err != nil {
 		return fmt.Errorf("failed to write compose file: %w", err)
 	}
+	if err := writeComposeDotEnv(filepath.Dir(composePath), env); err != nil {
+		return err
+	}
 
-	// Try docker compose (v2) first, then fall back to docker-compose (v1)
-	var cmd *exec.Cmd
-	if _, err := exec.LookPath("docker"); err == nil {
-		args := []string{"compose", "-f", composePath}
-		if projectName != "" {
-			args = append(args, "-p", projectName)
-		}
-		args = append(args, "up", "-d")
+	project, err := loadComposeProject(ctx, composePath, projectName, env)
+	if err != nil {
+		return err
+	}
 
-		// Try docker compose (v2)
-		cmd = exec.CommandContext(ctx, "docker", args...)
-		if output, err := cmd.CombinedOutput(); err != nil {
-			// Try docker-compose (v1) as fallback
-			if _, err := exec.LookPath("docker-compose"); err == nil {
-				fallbackArgs := []string{"-f", composePath}
-				if projectName != "" {
-					fallbackArgs = append(fallbackArgs, "-p", projectName)
-				}
-				fallbackArgs = append(fallbackArgs, "up", "-d")
+	// Deploy through the API unless the project needs features only the compose CLI provides
+	unsupported := unsupportedComposeFeatures(project)
+	if len(unsupported) == 0 {
+		err = d.deployComposeProject(ctx, project)
+	} else {
+		logger.Info("DockerRuntime.DeployFromCompose: Falling back to the compose CLI", "project", project.Name, "unsupported", unsupported)
+		err = deployComposeCLI(ctx, composePath, projectName, env)
+	}
+	if err != nil {
+		return err
+	}
 
-				cmd = exec.CommandContext(ctx, "docker-compose", fallbackArgs...)
-				if output, err := cmd.CombinedOutput(); err != nil {
-					return fmt.Errorf("failed to deploy with docker-compose: %w, output: %s", err, string(output))
-				}
-				return nil
-			}
-			return fmt.Errorf("failed to deploy with docker compose: %w, output: %s", err, string(output))
-		}
+	if deploymentPath != "" {
+		return writeDeploymentManifest(deploymentPath, d.GetRuntimeName(), project.Name, project.ServiceNames())
+	}
+	return nil
+}
+
+// deployComposeCLI deploys a compose file by shelling out to the compose CLI
+func deployComposeCLI(ctx context.Context, composePath, projectName string, env map[string]string) error {
+	args := []string{"-f", composePath}
+	if projectName != "" {
+		args = append(args, "-p", projectName)
+	}
+	args = append(args, "up", "-d")
+
+	return runDockerComposeCLI(ctx, "deploy", "", composeCommandEnv(env), args)
+}
+
+// runDockerComposeCLI runs docker compose (v2) with args in dir, falling back to docker-compose (v1).
+// action names the operation in error messages.
+func runDockerComposeCLI(ctx context.Context, action, dir string, env, args []string) error {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return fmt.Errorf("docker CLI not found in PATH")
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"compose"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = env
+	output, err := cmd.CombinedOutput()
+	if err == nil {
 		return nil
 	}
 
-	return fmt.Errorf("docker CLI not found in PATH")
+	// Try docker-compose (v1) as fallback
+	if _, lookErr := exec.LookPath("docker-compose"); lookErr != nil {
+		return fmt.Errorf("failed to %s with docker compose: %w, output: %s", action, err, string(output))
+	}
+	cmd = exec.CommandContext(ctx, "docker-compose", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to %s with docker-compose: %w, output: %s", action, err, string(output))
+	}
+	return nil
+}
+
+// ComposeScale scales a service of a Docker compose deployment with the compose CLI
+func (d *DockerRuntime) ComposeScale(ctx context.Context, projectName, deploymentPath, service string, replicas int) error {
+	args, err := composeScaleArgs(ctx, projectName, deploymentPath, service, replicas)
+	if err != nil {
+		return err
+	}
+	return runDockerComposeCLI(ctx, "scale", deploymentPath, composeCommandEnv(nil), args)
+}
+
+// dockerPullOptions returns the pull options authenticating with auth when it is non-nil
+func dockerPullOptions(auth *models.RegistryAuth) (image.PullOptions, error) {
+	pullOpts := image.PullOptions{}
+	if auth != nil {
+		encodedAuth, err := encodeRegistryAuth(auth)
+		if err != nil {
+			return pullOpts, fmt.Errorf("failed to encode registry credentials: %w", err)
+		}
+		pullOpts.RegistryAuth = encodedAuth
+	}
+	return pullOpts, nil
 }
 
 // PullImage pulls the latest version of a Docker image
-func (d *DockerRuntime) PullImage(ctx context.Context, imageName string) error {
-	reader, err := d.client.ImagePull(ctx, imageName, image.PullOptions{})
+func (d *DockerRuntime) PullImage(ctx context.Context, imageName string, auth *models.RegistryAuth) error {
+	pullOpts, err := dockerPullOptions(auth)
+	if err != nil {
+		return err
+	}
+
+	reader, err := d.client.ImagePull(ctx, imageName, pullOpts)
 	if err != nil {
 		return fmt.Errorf("failed to pull Docker image %s: %w", imageName, err)
 	}
@@ -466,8 +833,134 @@ func (d *DockerRuntime) PullImage(ctx context.Context, imageName string) error {
 	return nil
 }
 
-// UpdateContainer updates a Docker container by pulling the latest image and recreating it
-func (d *DockerRuntime) UpdateContainer(ctx context.Context, containerID string) error {
+// PullImageStream pulls the latest version of a Docker image and reports the progress of each layer
+func (d *DockerRuntime) PullImageStream(ctx context.Context, imageName string, auth *models.RegistryAuth) (<-chan models.PullProgress, error) {
+	pullOpts, err := dockerPullOptions(auth)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := d.client.ImagePull(ctx, imageName, pullOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull Docker image %s: %w", imageName, err)
+	}
+
+	result := make(chan models.PullProgress, pullProgressBufferSize)
+	go func() {
+		defer close(result)
+		defer reader.Close()
+		decodePullProgress(reader, func(progress models.PullProgress) bool {
+			return sendPullProgress(ctx, result, progress)
+		})
+	}()
+
+	return result, nil
+}
+
+// ImageHistory returns the layers of a Docker image, newest first
+func (d *DockerRuntime) ImageHistory(ctx context.Context, imageName string) ([]models.ImageLayer, error) {
+	history, err := d.client.ImageHistory(ctx, imageName)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrImageNotFound, imageName)
+		}
+		return nil, fmt.Errorf("failed to get history of Docker image %s: %w", imageName, err)
+	}
+
+	layers := make([]models.ImageLayer, 0, len(history))
+	for _, item := range history {
+		layers = append(layers, models.ImageLayer{
+			ID:        item.ID,
+			CreatedBy: item.CreatedBy,
+			Size:      item.Size,
+			CreatedAt: time.Unix(item.Created, 0),
+			Tags:      item.Tags,
+			Comment:   item.Comment,
+		})
+	}
+	return layers, nil
+}
+
+// TagImage tags a Docker image with a new reference
+func (d *DockerRuntime) TagImage(ctx context.Context, source, target string) error {
+	if err := d.client.ImageTag(ctx, source, target); err != nil {
+		return fmt.Errorf("failed to tag Docker image %s as %s: %w", source, target, err)
+	}
+	return nil
+}
+
+// PushImage pushes a Docker image to its registry and returns the manifest digest
+func (d *DockerRuntime) PushImage(ctx context.Context, imageName string, auth *models.RegistryAuth) (string, error) {
+	pushOpts := image.PushOptions{}
+	if auth != nil {
+		encodedAuth, err := encodeRegistryAuth(auth)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode registry credentials: %w", err)
+		}
+		pushOpts.RegistryAuth = encodedAuth
+	} else {
+		// The Docker API rejects pushes without an auth header, an empty config is accepted
+		encodedAuth, err := registry.EncodeAuthConfig(registry.AuthConfig{})
+		if err != nil {
+			return "", fmt.Errorf("failed to encode registry credentials: %w", err)
+		}
+		pushOpts.RegistryAuth = encodedAuth
+	}
+
+	reader, err := d.client.ImagePush(ctx, imageName, pushOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to push Docker image %s: %w", imageName, err)
+	}
+	defer reader.Close()
+
+	// Drain the progress stream, surfacing errors and capturing the final digest
+	digest := ""
+	err = drainJSONMessages(reader, func(aux json.RawMessage) {
+		var result types.PushResult
+		if err := json.Unmarshal(aux, &result); err == nil && result.Digest != "" {
+			digest = result.Digest
+		}
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to push Docker image %s: %w", imageName, err)
+	}
+
+	return digest, nil
+}
+
+// encodeRegistryAuth encodes registry credentials into the base64 AuthConfig expected by the Docker API
+func encodeRegistryAuth(auth *models.RegistryAuth) (string, error) {
+	return registry.EncodeAuthConfig(registry.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		RegistryToken: auth.Token,
+	})
+}
+
+// CheckImageUpdate pulls the image of a Docker container and compares it with the running image
+func (d *DockerRuntime) CheckImageUpdate(ctx context.Context, containerID string, auth RegistryAuthLookup) (bool, error) {
+	inspect, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	imageName := inspect.Config.Image
+	if err := d.PullImage(ctx, imageName, auth.forImage(imageName)); err != nil {
+		return false, err
+	}
+
+	latest, err := d.client.ImageInspect(ctx, imageName)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect Docker image %s: %w", imageName, err)
+	}
+
+	return latest.ID != inspect.Image, nil
+}
+
+// UpdateContainer updates a Docker container by pulling the latest image and recreating it.
+// The old container is kept until the new one has been running for the grace period,
+// and restored if the new container fails.
+func (d *DockerRuntime) UpdateContainer(ctx context.Context, containerID string, auth RegistryAuthLookup) error {
 	// Inspect container to get its configuration
 	inspect, err := d.client.ContainerInspect(ctx, containerID)
 	if err != nil {
@@ -477,55 +970,420 @@ func (d *DockerRuntime) UpdateContainer(ctx context.Context, containerID string)
 	imageName := inspect.Config.Image
 
 	// Pull the latest image
-	if err := d.PullImage(ctx, imageName); err != nil {
+	if err := d.PullImage(ctx, imageName, auth.forImage(imageName)); err != nil {
 		return err
 	}
 
-	// Stop the container
-	timeout := 10
-	if err := d.client.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout}); err != nil {
-		return fmt.Errorf("failed to stop container: %w", err)
+	// The replacer recreates the container from its full config, host config and networks
+	replacer := newDockerReplacer(d, inspect)
+	return replaceContainer(ctx, replacer, containerID, strings.TrimPrefix(inspect.Name, "/"), d.updateGracePeriod)
+}
+
+// RecreateContainerWithLabels changes the labels of a Docker container. Docker cannot update labels
+// in place, so the container is recreated from its own config and host config with the new labels,
+// keeping its name, networks, ports, volumes and environment. Labels in set are added or replaced,
+// labels in remove are deleted. A running container is swapped like in UpdateContainer (and restored
+// on failure). It returns the ID of the new container.
+func (d *DockerRuntime) RecreateContainerWithLabels(ctx context.Context, containerID string, set map[string]string, remove []string) (string, error) {
+	inspect, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect Docker container %s: %w", containerID, err)
 	}
 
-	// Remove the old container
-	if err := d.DeleteContainer(ctx, containerID, true); err != nil {
-		return err
+	config := *inspect.Config
+	config.Labels = applyLabelChanges(inspect.Config.Labels, set, remove)
+	inspect.Config = &config
+
+	name := strings.TrimPrefix(inspect.Name, "/")
+	replacer := newDockerReplacer(d, inspect)
+	if inspect.State != nil && inspect.State.Running {
+		err = replaceContainer(ctx, replacer, inspect.ID, name, d.updateGracePeriod)
+	} else {
+		err = replaceStoppedContainer(ctx, replacer, inspect.ID, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to recreate Docker container %s: %w", name, err)
 	}
 
-	// Create and start a new container with the same configuration
-	// Note: This is a simplified version - in production you'd want to preserve
-	// all the original container settings
-	resp, err := d.client.ContainerCreate(ctx, inspect.Config, inspect.HostConfig, nil, nil, inspect.Name)
+	recreated, err := d.client.ContainerInspect(ctx, name)
 	if err != nil {
-		return fmt.Errorf("failed to create new container: %w", err)
+		return "", fmt.Errorf("failed to inspect recreated Docker container %s: %w", name, err)
 	}
+	logger.Info("DockerRuntime.RecreateContainerWithLabels: Recreated container", "name", name, "old_id", inspect.ID, "new_id", recreated.ID)
+	return recreated.ID, nil
+}
 
-	if err := d.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
-		return fmt.Errorf("failed to start new container: %w", err)
+// primaryNetwork returns the network a container joins through its network mode,
+// or "" for modes without an own endpoint (host, none, container:<id>)
+func primaryNetwork(hostConfig *container.HostConfig) string {
+	if hostConfig == nil {
+		return ""
+	}
+	mode := hostConfig.NetworkMode
+	switch {
+	case mode.IsDefault():
+		return network.NetworkBridge
+	case mode.IsHost(), mode.IsNone(), mode.IsContainer():
+		return ""
 	}
+	return string(mode)
+}
 
-	return nil
+// containerEndpoints returns the network endpoints of an inspected container, reduced to the
+// settings that can be passed when creating a container. Nothing is returned for network
+// modes without own endpoints.
+func containerEndpoints(inspect container.InspectResponse) map[string]*network.EndpointSettings {
+	if primaryNetwork(inspect.HostConfig) == "" || inspect.NetworkSettings == nil {
+		return nil
+	}
+
+	shortID := inspect.ID
+	if len(shortID) > 12 {
+		shortID = shortID[:12]
+	}
+
+	endpoints := make(map[string]*network.EndpointSettings, len(inspect.NetworkSettings.Networks))
+	for name, settings := range inspect.NetworkSettings.Networks {
+		if settings == nil {
+			continue
+		}
+		// Docker adds the short ID of the old container as an alias, the new one gets its own
+		var aliases []string
+		for _, alias := range settings.Aliases {
+			if alias != shortID {
+				aliases = append(aliases, alias)
+			}
+		}
+		endpoints[name] = &network.EndpointSettings{
+			IPAMConfig: settings.IPAMConfig,
+			Links:      settings.Links,
+			Aliases:    aliases,
+			DriverOpts: settings.DriverOpts,
+		}
+	}
+	return endpoints
+}
+
+// dockerReplacer implements containerReplacer for Docker
+type dockerReplacer struct {
+	d           *DockerRuntime
+	inspect     container.InspectResponse
+	stopTimeout int // Seconds the old container gets to stop before it is killed
+}
+
+// newDockerReplacer returns a replacer for the inspected container, which is stopped
+// with the container's own stop timeout or DefaultStopTimeout
+func newDockerReplacer(d *DockerRuntime, inspect container.InspectResponse) *dockerReplacer {
+	var timeout *int
+	if inspect.Config != nil {
+		timeout = inspect.Config.StopTimeout
+	}
+	return &dockerReplacer{d: d, inspect: inspect, stopTimeout: stopTimeout(timeout)}
+}
+
+func (r *dockerReplacer) stop(ctx context.Context, id string) error {
+	timeout := r.stopTimeout
+	return r.d.client.ContainerStop(ctx, id, container.StopOptions{Timeout: &timeout})
+}
+
+func (r *dockerReplacer) rename(ctx context.Context, id, name string) error {
+	return r.d.client.ContainerRename(ctx, id, name)
+}
+
+// create recreates the container from the inspected config. The network of the network mode is
+// attached at creation, other networks are connected afterwards (older daemons only accept one).
+func (r *dockerReplacer) create(ctx context.Context, name string) (string, error) {
+	endpoints := containerEndpoints(r.inspect)
+	primary := primaryNetwork(r.inspect.HostConfig)
+
+	var networking *network.NetworkingConfig
+	if endpoint, ok := endpoints[primary]; ok {
+		networking = &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{primary: endpoint}}
+	}
+
+	resp, err := r.d.client.ContainerCreate(ctx, r.inspect.Config, r.inspect.HostConfig, networking, nil, name)
+	if err != nil {
+		return "", err
+	}
+
+	for networkName, endpoint := range endpoints {
+		if networkName == primary {
+			continue
+		}
+		if err := r.d.client.NetworkConnect(ctx, networkName, resp.ID, endpoint); err != nil {
+			if removeErr := r.d.DeleteContainer(context.WithoutCancel(ctx), resp.ID, true); removeErr != nil {
+				logger.Error("dockerReplacer.create: Failed to remove new container", "id", resp.ID, "error", removeErr)
+			}
+			return "", fmt.Errorf("failed to connect to network %s: %w", networkName, err)
+		}
+	}
+	return resp.ID, nil
+}
+
+func (r *dockerReplacer) start(ctx context.Context, id string) error {
+	return r.d.client.ContainerStart(ctx, id, container.StartOptions{})
+}
+
+func (r *dockerReplacer) healthy(ctx context.Context, id string) (bool, error) {
+	inspect, err := r.d.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return inspect.State != nil && inspect.State.Running && !inspect.State.Restarting && inspect.RestartCount == 0, nil
+}
+
+func (r *dockerReplacer) remove(ctx context.Context, id string) error {
+	return r.d.DeleteContainer(ctx, id, true)
+}
+
+// PruneContainers removes all stopped Docker containers
+func (d *DockerRuntime) PruneContainers(ctx context.Context) ([]string, uint64, error) {
+	resp, err := d.client.ContainersPrune(ctx, filters.NewArgs())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to prune Docker containers: %w", err)
+	}
+	return resp.ContainersDeleted, resp.SpaceReclaimed, nil
+}
+
+// SystemPrune prunes unused Docker resources using the individual prune APIs
+func (d *DockerRuntime) SystemPrune(ctx context.Context, opts models.PruneOptions) (models.PruneReport, error) {
+	report := models.PruneReport{Runtime: "docker"}
+
+	if opts.Containers {
+		removed, reclaimed, err := d.PruneContainers(ctx)
+		if err != nil {
+			return report, err
+		}
+		report.Containers = len(removed)
+		report.ReclaimedBytes += reclaimed
+	}
+
+	if opts.Images || opts.AllImages {
+		imageFilters := filters.NewArgs()
+		if opts.AllImages {
+			imageFilters.Add("dangling", "false")
+		}
+		resp, err := d.client.ImagesPrune(ctx, imageFilters)
+		if err != nil {
+			return report, fmt.Errorf("failed to prune Docker images: %w", err)
+		}
+		report.Images = len(resp.ImagesDeleted)
+		report.ReclaimedBytes += resp.SpaceReclaimed
+	}
+
+	if opts.Networks {
+		resp, err := d.client.NetworksPrune(ctx, filters.NewArgs())
+		if err != nil {
+			return report, fmt.Errorf("failed to prune Docker networks: %w", err)
+		}
+		report.Networks = len(resp.NetworksDeleted)
+	}
+
+	if opts.Volumes {
+		resp, err := d.client.VolumesPrune(ctx, filters.NewArgs())
+		if err != nil {
+			return report, fmt.Errorf("failed to prune Docker volumes: %w", err)
+		}
+		report.Volumes = len(resp.VolumesDeleted)
+		report.ReclaimedBytes += resp.SpaceReclaimed
+	}
+
+	if opts.BuildCache {
+		resp, err := d.client.BuildCachePrune(ctx, build.CachePruneOptions{All: opts.AllImages})
+		if err != nil {
+			return report, fmt.Errorf("failed to prune Docker build cache: %w", err)
+		}
+		report.BuildCache = len(resp.CachesDeleted)
+		report.ReclaimedBytes += resp.SpaceReclaimed
+	}
+
+	return report, nil
+}
+
+// StreamEvents streams Docker container lifecycle events
+func (d *DockerRuntime) StreamEvents(ctx context.Context) (<-chan models.RuntimeEvent, error) {
+	eventFilters := filters.NewArgs()
+	eventFilters.Add("type", string(events.ContainerEventType))
+
+	messages, errs := d.client.Events(ctx, events.ListOptions{Filters: eventFilters})
+
+	result := make(chan models.RuntimeEvent, eventChannelBufferSize)
+	go func() {
+		defer close(result)
+		for {
+			select {
+			case msg := <-messages:
+				event, ok := toRuntimeEvent(msg, "docker", "")
+				if !ok {
+					continue
+				}
+				select {
+				case result <- event:
+				case <-ctx.Done():
+					return
+				}
+			case err := <-errs:
+				if err != nil && ctx.Err() == nil {
+					logger.Warn("DockerRuntime.StreamEvents: Event stream ended", "error", err)
+				}
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return result, nil
+}
+
+// ListContainerPath lists a directory in a Docker container. Running containers list it with find,
+// one level only; stopped containers and images without find fall back to the archive API.
+func (d *DockerRuntime) ListContainerPath(ctx context.Context, containerID, path string) ([]models.FileEntry, error) {
+	inspect, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Docker container %s: %w", containerID, err)
+	}
+	if inspect.State != nil && inspect.State.Running {
+		if output, err := d.execOutput(ctx, containerID, listDirCommand(path)); err == nil {
+			if entries, ok := parseListDirOutput(output); ok {
+				return entries, nil
+			}
+		}
+	}
+
+	stat, err := d.client.ContainerStatPath(ctx, containerID, path)
+	for hops := 0; err == nil && stat.Mode&os.ModeSymlink != 0 && stat.LinkTarget != "" && hops < maxLinkHops; hops++ {
+		// The archive API exports links themselves, so directory links are resolved first
+		path = linkTargetPath(path, stat.LinkTarget)
+		stat, err = d.client.ContainerStatPath(ctx, containerID, path)
+	}
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to stat %s in Docker container %s: %w", path, containerID, err)
+	}
+	if !stat.Mode.IsDir() {
+		return []models.FileEntry{fileEntry(stat.Name, stat.Size, stat.Mode, stat.Mtime, stat.LinkTarget)}, nil
+	}
+	if path == "/" {
+		return nil, errArchiveRoot
+	}
+
+	reader, _, err := d.client.CopyFromContainer(ctx, containerID, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from Docker container %s: %w", path, containerID, err)
+	}
+	defer reader.Close()
+
+	return listArchiveDir(reader)
+}
+
+// ContainerTop lists the processes running inside a Docker container
+func (d *DockerRuntime) ContainerTop(ctx context.Context, containerID string) ([]models.ProcessInfo, error) {
+	inspect, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Docker container %s: %w", containerID, err)
+	}
+	if inspect.State == nil || !inspect.State.Running {
+		return nil, ErrContainerNotRunning
+	}
+
+	top, err := d.client.ContainerTop(ctx, containerID, []string{"-o", strings.Join(topDescriptors, ",")})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes of Docker container %s: %w", containerID, err)
+	}
+
+	return parseTopOutput(top.Titles, top.Processes), nil
 }
 
 // StreamLogs streams logs from a Docker container
-func (d *DockerRuntime) StreamLogs(ctx context.Context, containerID string, follow bool, tail string) (io.ReadCloser, error) {
+func (d *DockerRuntime) StreamLogs(ctx context.Context, containerID string, opts models.LogOptions) (io.ReadCloser, error) {
 	options := container.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
-		Follow:     follow,
-		Tail:       tail,
+		Follow:     opts.Follow,
+		Tail:       opts.Tail,
 		Timestamps: true,
 	}
+	if !opts.Since.IsZero() {
+		options.Since = opts.Since.Format(time.RFC3339Nano)
+	}
+	if !opts.Until.IsZero() {
+		options.Until = opts.Until.Format(time.RFC3339Nano)
+	}
+
+	inspect, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Docker container %s: %w", containerID, err)
+	}
 
 	logs, err := d.client.ContainerLogs(ctx, containerID, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Docker container logs: %w", err)
 	}
 
-	return logs, nil
+	// Containers without a TTY multiplex stdout and stderr into framed chunks;
+	// strip the frame headers so callers get plain log lines like from Podman
+	if inspect.Config != nil && inspect.Config.Tty {
+		return logs, nil
+	}
+	return demuxLogs(logs), nil
+}
+
+// demuxLogs merges a multiplexed stdout/stderr log stream into a plain stream
+func demuxLogs(logs io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, logs)
+		logs.Close()
+		pw.CloseWithError(err)
+	}()
+	return &demuxedLogs{PipeReader: pr, source: logs}
+}
+
+// demuxedLogs closes the underlying log stream together with the pipe
+type demuxedLogs struct {
+	*io.PipeReader
+	source io.Closer
+}
+
+func (l *demuxedLogs) Close() error {
+	l.PipeReader.Close()
+	return l.source.Close()
+}
+
+// Capabilities reports the features supported for Docker.
+// Labels can only be changed by recreating the container (see LabelRecreator).
+func (d *DockerRuntime) Capabilities() models.RuntimeCapabilities {
+	return models.RuntimeCapabilities{
+		Compose: true,
+	}
 }
 
 // GetRuntimeName returns "docker"
 func (d *DockerRuntime) GetRuntimeName() string {
 	return "docker"
 }
+
+// Close releases the connections of the Docker client
+func (d *DockerRuntime) Close() error {
+	return d.client.Close()
+}
+
+// Version returns the version of the Docker daemon
+func (d *DockerRuntime) Version(ctx context.Context) (string, error) {
+	version, err := d.client.ServerVersion(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Docker version: %w", err)
+	}
+	return version.Version, nil
+}
+
+// Ping checks that the Docker daemon is reachable
+func (d *DockerRuntime) Ping(ctx context.Context) error {
+	if _, err := d.client.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping Docker daemon: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDockerRuntime returns a Docker runtime or skips the test when no daemon is reachable
+func newTestDockerRuntime(t *testing.T) *DockerRuntime {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping Docker integration test in short mode")
+	}
+
+	d, err := NewDockerRuntime(DockerConnection{})
+	if err != nil {
+		t.Skipf("Docker not available: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := d.client.Ping(ctx); err != nil {
+		t.Skipf("Docker daemon not reachable: %v", err)
+	}
+	return d
+}
+
+func TestDockerBuildArgs(t *testing.T) {
+	assert.Nil(t, dockerBuildArgs(nil))
+
+	args := dockerBuildArgs(map[string]string{"VERSION": "1.0", "EMPTY": ""})
+	require.Len(t, args, 2)
+	assert.Equal(t, "1.0", *args["VERSION"])
+	assert.Equal(t, "", *args["EMPTY"])
+}
+
+func TestDockerRunConfig(t *testing.T) {
+	req := models.RunContainerRequest{Image: "busybox", Ports: []string{"8080:80"}, RestartPolicy: "always"}
+	config, hostConfig, err := dockerRunConfig(req, []string{"data:/data"})
+	require.NoError(t, err)
+	assert.Equal(t, "busybox", config.Image)
+	assert.Equal(t, []string{"data:/data"}, hostConfig.Binds)
+	assert.Equal(t, container.RestartPolicyAlways, hostConfig.RestartPolicy.Name)
+	assert.False(t, hostConfig.AutoRemove)
+
+	req = models.RunContainerRequest{Image: "busybox", AutoRemove: true, Privileged: true, SecurityOpt: []string{"apparmor=unconfined"}}
+	_, hostConfig, err = dockerRunConfig(req, nil)
+	require.NoError(t, err)
+	assert.True(t, hostConfig.AutoRemove)
+	assert.True(t, hostConfig.Privileged)
+	assert.Equal(t, []string{"apparmor=unconfined"}, hostConfig.SecurityOpt)
+
+	req = models.RunContainerRequest{
+		Image:      "busybox",
+		Tmpfs:      map[string]string{"/tmp": "size=64m"},
+		Devices:    []string{"/dev/snd"},
+		ExtraHosts: []string{"db=10.0.0.5"},
+	}
+	_, hostConfig, err = dockerRunConfig(req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/tmp": "size=64m"}, hostConfig.Tmpfs)
+	assert.Equal(t, []container.DeviceMapping{{PathOnHost: "/dev/snd", PathInContainer: "/dev/snd", CgroupPermissions: "rwm"}}, hostConfig.Devices)
+	assert.Equal(t, []string{"db:10.0.0.5"}, hostConfig.ExtraHosts)
+
+	req = models.RunContainerRequest{Image: "busybox", ReadOnly: true, WorkingDir: "/work", Entrypoint: []string{"/bin/sh", "-c"}, Command: []string{"echo hi"}}
+	config, hostConfig, err = dockerRunConfig(req, nil)
+	require.NoError(t, err)
+	assert.True(t, hostConfig.ReadonlyRootfs)
+	assert.Equal(t, "/work", config.WorkingDir)
+	assert.Equal(t, strslice.StrSlice{"/bin/sh", "-c"}, config.Entrypoint)
+	assert.Equal(t, strslice.StrSlice{"echo hi"}, config.Cmd)
+
+	config, _, err = dockerRunConfig(models.RunContainerRequest{Image: "busybox", User: "1000:1000", Hostname: "worker-1"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "1000:1000", config.User)
+	assert.Equal(t, "worker-1", config.Hostname)
+	_, _, err = dockerRunConfig(models.RunContainerRequest{Image: "busybox", User: ":"}, nil)
+	assert.ErrorIs(t, err, ErrInvalidUser)
+
+	// Without overrides the image defaults apply
+	config, hostConfig, err = dockerRunConfig(models.RunContainerRequest{Image: "busybox"}, nil)
+	require.NoError(t, err)
+	assert.False(t, hostConfig.ReadonlyRootfs)
+	assert.Nil(t, config.Entrypoint)
+	assert.Nil(t, config.Cmd)
+
+	_, _, err = dockerRunConfig(models.RunContainerRequest{Image: "busybox", Devices: []string{"snd"}}, nil)
+	assert.ErrorIs(t, err, ErrInvalidDevice)
+
+	_, _, err = dockerRunConfig(models.RunContainerRequest{Image: "busybox", Ports: []string{"http"}}, nil)
+	assert.ErrorIs(t, err, ErrInvalidPort)
+}
+
+func TestDockerBuildFromDockerfileTarget(t *testing.T) {
+	d := newTestDockerRuntime(t)
+	ctx := context.Background()
+
+	dockerfile := `FROM busybox AS first
+ARG GREETING
+LABEL stage=first greeting=$GREETING
+
+FROM busybox AS second
+LABEL stage=second
+`
+	imageName := "gintainer-test-build-target:latest"
+	err := d.BuildFromDockerfile(ctx, dockerfile, imageName, models.BuildOptions{
+		BuildArgs: map[string]string{"GREETING": "hello"},
+		Target:    "first",
+	})
+	require.NoError(t, err)
+	defer d.client.ImageRemove(ctx, imageName, image.RemoveOptions{Force: true})
+
+	inspect, err := d.client.ImageInspect(ctx, imageName)
+	require.NoError(t, err)
+	require.NotNil(t, inspect.Config)
+	assert.Equal(t, "first", inspect.Config.Labels["stage"])
+	assert.Equal(t, "hello", inspect.Config.Labels["greeting"])
+}
+
+func TestDockerReplacerStopTimeout(t *testing.T) {
+	timeout := 45
+	inspect := container.InspectResponse{Config: &container.Config{StopTimeout: &timeout}}
+	assert.Equal(t, 45, newDockerReplacer(nil, inspect).stopTimeout)
+
+	inspect.Config.StopTimeout = nil
+	assert.Equal(t, DefaultStopTimeout, newDockerReplacer(nil, inspect).stopTimeout)
+	assert.Equal(t, DefaultStopTimeout, newDockerReplacer(nil, container.InspectResponse{}).stopTimeout)
+}
+
+func TestDemuxLogs(t *testing.T) {
+	var buf bytes.Buffer
+	stdout := stdcopy.NewStdWriter(&buf, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(&buf, stdcopy.Stderr)
+	stdout.Write([]byte("line one\n"))
+	stderr.Write([]byte("oops\n"))
+	stdout.Write([]byte("line two\n"))
+
+	logs := demuxLogs(io.NopCloser(&buf))
+	defer logs.Close()
+
+	content, err := io.ReadAll(logs)
+	require.NoError(t, err)
+	assert.Equal(t, "line one\noops\nline two\n", string(content))
+}
+
+func TestDockerListContainersLabelFilter(t *testing.T) {
+	d := newTestDockerRuntime(t)
+	ctx := context.Background()
+
+	require.NoError(t, d.PullImage(ctx, "busybox:latest", nil))
+
+	for name, labels := range map[string]map[string]string{
+		"gintainer-test-label-prod": {"gintainer.test.env": "prod"},
+		"gintainer-test-label-dev":  {"gintainer.test.env": "dev"},
+	} {
+		_, err := d.client.ContainerCreate(ctx, &container.Config{Image: "busybox:latest", Labels: labels}, nil, nil, nil, name)
+		require.NoError(t, err)
+		defer d.client.ContainerRemove(ctx, name, container.RemoveOptions{Force: true})
+	}
+
+	names := func(label string) []string {
+		containers, err := d.ListContainers(ctx, models.FilterOptions{Label: label})
+		require.NoError(t, err)
+		var result []string
+		for _, c := range containers {
+			result = append(result, c.Name)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	assert.Equal(t, []string{"gintainer-test-label-dev", "gintainer-test-label-prod"}, names("gintainer.test.env"))
+	assert.Equal(t, []string{"gintainer-test-label-prod"}, names("gintainer.test.env=prod"))
+	assert.Empty(t, names("gintainer.test.env=staging"))
+}
+
+func TestDockerStartedAt(t *testing.T) {
+	assert.Equal(t, time.Date(2026, 5, 4, 3, 2, 1, 500, time.UTC), dockerStartedAt("2026-05-04T03:02:01.0000005Z"))
+	assert.True(t, dockerStartedAt("0001-01-01T00:00:00Z").IsZero(), "never started")
+	assert.True(t, dockerStartedAt("").IsZero())
+}
+
+func TestDockerListContainersStartedAt(t *testing.T) {
+	d := newTestDockerRuntime(t)
+	ctx := context.Background()
+
+	require.NoError(t, d.PullImage(ctx, "busybox:latest", nil))
+
+	const name = "gintainer-test-started-at"
+	_, err := d.client.ContainerCreate(ctx, &container.Config{Image: "busybox:latest", Cmd: []string{"sleep", "60"}}, nil, nil, nil, name)
+	require.NoError(t, err)
+	defer d.client.ContainerRemove(ctx, name, container.RemoveOptions{Force: true})
+
+	find := func(opts models.FilterOptions) models.ContainerInfo {
+		opts.Name = name
+		containers, err := d.ListContainers(ctx, opts)
+		require.NoError(t, err)
+		require.Len(t, containers, 1)
+		return containers[0]
+	}
+
+	// Not started yet
+	assert.True(t, find(models.FilterOptions{IncludeState: true}).StartedAt.IsZero())
+
+	before := time.Now().Add(-time.Second)
+	require.NoError(t, d.StartContainer(ctx, name))
+
+	started := find(models.FilterOptions{IncludeState: true})
+	assert.Equal(t, "running", started.State)
+	assert.True(t, started.StartedAt.After(before), "started at %s", started.StartedAt)
+	assert.Equal(t, started.StartedAt, find(models.FilterOptions{IncludeStats: true}).StartedAt)
+
+	// Without the options no inspect is made
+	assert.True(t, find(models.FilterOptions{}).StartedAt.IsZero())
+}
+
+func TestDockerImageHistory(t *testing.T) {
+	d := newTestDockerRuntime(t)
+	ctx := context.Background()
+
+	require.NoError(t, d.PullImage(ctx, "busybox:latest", nil))
+
+	layers, err := d.ImageHistory(ctx, "busybox:latest")
+	require.NoError(t, err)
+	require.NotEmpty(t, layers)
+	assert.NotEmpty(t, layers[0].CreatedBy)
+	assert.False(t, layers[0].CreatedAt.IsZero())
+
+	_, err = d.ImageHistory(ctx, "gintainer-test-missing:latest")
+	assert.ErrorIs(t, err, ErrImageNotFound)
+}
+
+func TestDockerRunContainerPullsMissingImage(t *testing.T) {
+	d := newTestDockerRuntime(t)
+	ctx := context.Background()
+
+	// A tag nothing else in the tests uses, removed first so it is guaranteed to be absent
+	const imageName = "busybox:1.36.1-musl"
+	_, _ = d.client.ImageRemove(ctx, imageName, image.RemoveOptions{Force: true})
+	defer d.client.ImageRemove(ctx, imageName, image.RemoveOptions{Force: true})
+
+	const name = "gintainer-test-auto-pull"
+	req := models.RunContainerRequest{Name: name, Image: imageName, NoPull: true}
+
+	// Without pulling, the missing image fails the create
+	_, err := d.RunContainer(ctx, req)
+	require.Error(t, err)
+
+	req.NoPull = false
+	id, err := d.RunContainer(ctx, req)
+	require.NoError(t, err)
+	defer d.client.ContainerRemove(ctx, id, container.RemoveOptions{Force: true})
+
+	_, err = d.client.ImageInspect(ctx, imageName)
+	assert.NoError(t, err)
+	inspect, err := d.client.ContainerInspect(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, imageName, inspect.Config.Image)
+	assert.False(t, dockerStartedAt(inspect.State.StartedAt).IsZero())
+}
+
+func TestDockerRunContainerUserAndHostname(t *testing.T) {
+	d := newTestDockerRuntime(t)
+	ctx := context.Background()
+
+	require.NoError(t, d.PullImage(ctx, "busybox:latest", nil))
+
+	const name = "gintainer-test-user"
+	id, err := d.RunContainer(ctx, models.RunContainerRequest{Name: name, Image: "busybox:latest", User: "1000:1000", Hostname: "worker-1", NoPull: true})
+	require.NoError(t, err)
+	defer d.client.ContainerRemove(ctx, id, container.RemoveOptions{Force: true})
+
+	inspect, err := d.client.ContainerInspect(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "1000:1000", inspect.Config.User)
+	assert.Equal(t, "worker-1", inspect.Config.Hostname)
+}
+
+func TestDockerListContainerPath(t *testing.T) {
+	d := newTestDockerRuntime(t)
+	ctx := context.Background()
+
+	require.NoError(t, d.PullImage(ctx, "busybox:latest", nil))
+
+	// Listing works without starting the container
+	const name = "gintainer-test-ls"
+	_, err := d.client.ContainerCreate(ctx, &container.Config{Image: "busybox:latest"}, nil, nil, nil, name)
+	require.NoError(t, err)
+	defer d.client.ContainerRemove(ctx, name, container.RemoveOptions{Force: true})
+
+	entries, err := d.ListContainerPath(ctx, name, "/etc")
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	assert.Contains(t, names, "passwd")
+
+	entries, err = d.ListContainerPath(ctx, name, "/etc/passwd")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "passwd", entries[0].Name)
+	assert.False(t, entries[0].IsDir)
+	assert.Greater(t, entries[0].Size, int64(0))
+
+	_, err = d.ListContainerPath(ctx, name, "/does/not/exist")
+	assert.ErrorIs(t, err, ErrPathNotFound)
+
+	// The archive API would export the whole filesystem
+	_, err = d.ListContainerPath(ctx, name, "/")
+	assert.ErrorIs(t, err, ErrDirectoryTooLarge)
+}
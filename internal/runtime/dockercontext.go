@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// dockerContextEndpoint is the daemon endpoint of a docker CLI context
+type dockerContextEndpoint struct {
+	Host          string
+	SkipTLSVerify bool
+	// TLS client files, empty if the context has no TLS material
+	CACert string
+	Cert   string
+	Key    string
+}
+
+// dockerContextMeta is the part of a context's meta.json that Gintainer needs
+type dockerContextMeta struct {
+	Name      string `json:"Name"`
+	Endpoints map[string]struct {
+		Host          string `json:"Host"`
+		SkipTLSVerify bool   `json:"SkipTLSVerify"`
+	} `json:"Endpoints"`
+}
+
+// dockerConfigDir returns the docker CLI config directory ($DOCKER_CONFIG or ~/.docker)
+func dockerConfigDir() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".docker"), nil
+}
+
+// resolveDockerContext reads the docker endpoint of a named context from the docker CLI
+// config in configDir. Contexts are stored under a directory named after the SHA-256 of their name.
+func resolveDockerContext(configDir, name string) (*dockerContextEndpoint, error) {
+	digest := sha256.Sum256([]byte(name))
+	id := hex.EncodeToString(digest[:])
+
+	data, err := os.ReadFile(filepath.Join(configDir, "contexts", "meta", id, "meta.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("docker context %q not found in %s", name, configDir)
+		}
+		return nil, fmt.Errorf("failed to read docker context %q: %w", name, err)
+	}
+
+	var meta dockerContextMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse docker context %q: %w", name, err)
+	}
+
+	docker, ok := meta.Endpoints["docker"]
+	if !ok || docker.Host == "" {
+		return nil, fmt.Errorf("docker context %q has no docker endpoint", name)
+	}
+
+	endpoint := &dockerContextEndpoint{Host: docker.Host, SkipTLSVerify: docker.SkipTLSVerify}
+
+	// TLS material is optional, but the CLI always stores all three files together
+	tlsDir := filepath.Join(configDir, "contexts", "tls", id, "docker")
+	if _, err := os.Stat(filepath.Join(tlsDir, "cert.pem")); err == nil {
+		endpoint.CACert = filepath.Join(tlsDir, "ca.pem")
+		endpoint.Cert = filepath.Join(tlsDir, "cert.pem")
+		endpoint.Key = filepath.Join(tlsDir, "key.pem")
+	}
+
+	return endpoint, nil
+}
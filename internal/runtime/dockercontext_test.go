@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDockerContext stores a context the way the docker CLI does
+func writeDockerContext(t *testing.T, configDir, name, meta string, withTLS bool) string {
+	t.Helper()
+	digest := sha256.Sum256([]byte(name))
+	id := hex.EncodeToString(digest[:])
+
+	metaDir := filepath.Join(configDir, "contexts", "meta", id)
+	require.NoError(t, os.MkdirAll(metaDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0o644))
+
+	tlsDir := filepath.Join(configDir, "contexts", "tls", id, "docker")
+	if withTLS {
+		require.NoError(t, os.MkdirAll(tlsDir, 0o755))
+		for _, file := range []string{"ca.pem", "cert.pem", "key.pem"} {
+			require.NoError(t, os.WriteFile(filepath.Join(tlsDir, file), []byte("pem"), 0o600))
+		}
+	}
+	return tlsDir
+}
+
+func TestResolveDockerContext(t *testing.T) {
+	configDir := t.TempDir()
+	writeDockerContext(t, configDir, "remote", `{"Name":"remote","Metadata":{},"Endpoints":{"docker":{"Host":"tcp://10.0.0.5:2375","SkipTLSVerify":false}}}`, false)
+
+	endpoint, err := resolveDockerContext(configDir, "remote")
+	require.NoError(t, err)
+	assert.Equal(t, "tcp://10.0.0.5:2375", endpoint.Host)
+	assert.Empty(t, endpoint.Cert)
+}
+
+func TestResolveDockerContextWithTLS(t *testing.T) {
+	configDir := t.TempDir()
+	tlsDir := writeDockerContext(t, configDir, "prod", `{"Name":"prod","Endpoints":{"docker":{"Host":"tcp://prod.example.com:2376"}}}`, true)
+
+	endpoint, err := resolveDockerContext(configDir, "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "tcp://prod.example.com:2376", endpoint.Host)
+	assert.Equal(t, filepath.Join(tlsDir, "ca.pem"), endpoint.CACert)
+	assert.Equal(t, filepath.Join(tlsDir, "cert.pem"), endpoint.Cert)
+	assert.Equal(t, filepath.Join(tlsDir, "key.pem"), endpoint.Key)
+}
+
+func TestResolveDockerContextErrors(t *testing.T) {
+	configDir := t.TempDir()
+	writeDockerContext(t, configDir, "empty", `{"Name":"empty","Endpoints":{}}`, false)
+	writeDockerContext(t, configDir, "broken", `{`, false)
+
+	_, err := resolveDockerContext(configDir, "missing")
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = resolveDockerContext(configDir, "empty")
+	assert.ErrorContains(t, err, "no docker endpoint")
+
+	_, err = resolveDockerContext(configDir, "broken")
+	assert.ErrorContains(t, err, "failed to parse")
+}
+
+func TestDockerConfigDir(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", "/etc/docker-cli")
+	dir, err := dockerConfigDir()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/docker-cli", dir)
+}
@@ -0,0 +1,77 @@
+package runtime
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/docker/docker/api/types/events"
+)
+
+// eventChannelBufferSize is the buffer size of the channels returned by StreamEvents
+const eventChannelBufferSize = 64
+
+// toRuntimeEvent converts a Docker-compatible event message into a RuntimeEvent.
+// Both Docker and Podman report events in this format; it returns false for events that are not tracked.
+func toRuntimeEvent(msg events.Message, runtimeName, healthStatus string) (models.RuntimeEvent, bool) {
+	if msg.Type != events.ContainerEventType {
+		return models.RuntimeEvent{}, false
+	}
+
+	action := string(msg.Action)
+	if action == "" {
+		action = msg.Status
+	}
+
+	event := models.RuntimeEvent{
+		ContainerID:   msg.Actor.ID,
+		ContainerName: msg.Actor.Attributes["name"],
+		Runtime:       runtimeName,
+		Attributes:    msg.Actor.Attributes,
+	}
+
+	switch {
+	case action == string(events.ActionStart):
+		event.Type = "start"
+	case action == string(events.ActionStop), action == string(events.ActionKill):
+		// Sent when a container is stopped or killed on request, around its die event
+		event.Type = action
+	case action == string(events.ActionDestroy) || action == "remove":
+		// Podman reports container removals as "remove"
+		event.Type = "destroy"
+	case action == string(events.ActionDie) || action == "died":
+		// Podman reports container exits as "died"
+		event.Type = "die"
+		event.ExitCode = exitCodeFromAttributes(msg.Actor.Attributes)
+	case strings.HasPrefix(action, string(events.ActionHealthStatus)):
+		// Docker reports e.g. "health_status: healthy", Podman sends the status separately
+		event.Type = "health_status"
+		event.HealthStatus = healthStatus
+		if event.HealthStatus == "" {
+			event.HealthStatus = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(action, string(events.ActionHealthStatus)), ":"))
+		}
+	default:
+		return models.RuntimeEvent{}, false
+	}
+
+	if msg.TimeNano != 0 {
+		event.Timestamp = time.Unix(0, msg.TimeNano)
+	} else {
+		event.Timestamp = time.Unix(msg.Time, 0)
+	}
+
+	return event, true
+}
+
+// exitCodeFromAttributes reads the exit code attribute set by Docker ("exitCode") or Podman ("containerExitCode")
+func exitCodeFromAttributes(attributes map[string]string) int {
+	for _, key := range []string{"exitCode", "containerExitCode"} {
+		if value, ok := attributes[key]; ok {
+			if code, err := strconv.Atoi(value); err == nil {
+				return code
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToRuntimeEvent(t *testing.T) {
+	actor := events.Actor{ID: "abc123", Attributes: map[string]string{"name": "web", "exitCode": "137"}}
+
+	event, ok := toRuntimeEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionDie, Actor: actor, Time: 1700000000}, "docker", "")
+	assert.True(t, ok)
+	assert.Equal(t, "die", event.Type)
+	assert.Equal(t, "abc123", event.ContainerID)
+	assert.Equal(t, "web", event.ContainerName)
+	assert.Equal(t, "docker", event.Runtime)
+	assert.Equal(t, 137, event.ExitCode)
+	assert.Equal(t, int64(1700000000), event.Timestamp.Unix())
+
+	event, ok = toRuntimeEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionHealthStatusUnhealthy, Actor: actor}, "docker", "")
+	assert.True(t, ok)
+	assert.Equal(t, "health_status", event.Type)
+	assert.Equal(t, "unhealthy", event.HealthStatus)
+
+	// Podman reports exits as "died" with a different exit code attribute
+	podmanActor := events.Actor{ID: "def456", Attributes: map[string]string{"name": "db", "containerExitCode": "1"}}
+	event, ok = toRuntimeEvent(events.Message{Type: events.ContainerEventType, Status: "died", Actor: podmanActor}, "podman", "")
+	assert.True(t, ok)
+	assert.Equal(t, "die", event.Type)
+	assert.Equal(t, 1, event.ExitCode)
+
+	event, ok = toRuntimeEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionHealthStatus, Actor: podmanActor}, "podman", "healthy")
+	assert.True(t, ok)
+	assert.Equal(t, "healthy", event.HealthStatus)
+
+	event, ok = toRuntimeEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionKill, Actor: actor}, "docker", "")
+	assert.True(t, ok)
+	assert.Equal(t, "kill", event.Type)
+
+	event, ok = toRuntimeEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionDestroy, Actor: actor}, "docker", "")
+	assert.True(t, ok)
+	assert.Equal(t, "destroy", event.Type)
+	event, ok = toRuntimeEvent(events.Message{Type: events.ContainerEventType, Status: "remove", Actor: podmanActor}, "podman", "")
+	assert.True(t, ok)
+	assert.Equal(t, "destroy", event.Type)
+
+	// Untracked actions and non-container events are dropped
+	_, ok = toRuntimeEvent(events.Message{Type: events.ContainerEventType, Action: events.ActionCreate, Actor: actor}, "docker", "")
+	assert.False(t, ok)
+	_, ok = toRuntimeEvent(events.Message{Type: events.ImageEventType, Action: events.ActionStart}, "docker", "")
+	assert.False(t, ok)
+}
@@ -0,0 +1,238 @@
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/containers/podman/v5/pkg/api/handlers"
+	"github.com/containers/podman/v5/pkg/bindings/containers"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// ExecSession is an interactive exec session with a TTY.
+// Read returns the terminal output (stdout and stderr are merged by the TTY) and
+// Write sends input to the command. Close detaches and releases the session.
+type ExecSession interface {
+	io.ReadWriteCloser
+
+	// Resize changes the terminal size
+	Resize(ctx context.Context, rows, cols uint) error
+
+	// ExitCode returns the exit code of the command once it has ended
+	ExitCode(ctx context.Context) (int, error)
+}
+
+// ExecInteractive starts a command with a TTY in a Docker container and attaches to it
+func (d *DockerRuntime) ExecInteractive(ctx context.Context, containerID string, opts models.ExecOptions) (ExecSession, error) {
+	inspect, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Docker container %s: %w", containerID, err)
+	}
+	if inspect.State == nil || !inspect.State.Running {
+		return nil, ErrContainerNotRunning
+	}
+
+	var consoleSize *[2]uint
+	if opts.Rows > 0 && opts.Cols > 0 {
+		consoleSize = &[2]uint{opts.Rows, opts.Cols}
+	}
+
+	created, err := d.client.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		User:         opts.User,
+		WorkingDir:   opts.WorkingDir,
+		Cmd:          opts.Cmd,
+		Tty:          true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		ConsoleSize:  consoleSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec session in Docker container %s: %w", containerID, err)
+	}
+
+	hijacked, err := d.client.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{Tty: true, ConsoleSize: consoleSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to exec session in Docker container %s: %w", containerID, err)
+	}
+
+	return &dockerExecSession{client: d.client, id: created.ID, conn: hijacked}, nil
+}
+
+// dockerExecSession is an exec session on the hijacked Docker API connection
+type dockerExecSession struct {
+	client *client.Client
+	id     string
+	conn   types.HijackedResponse
+}
+
+func (s *dockerExecSession) Read(p []byte) (int, error) {
+	return s.conn.Reader.Read(p)
+}
+
+func (s *dockerExecSession) Write(p []byte) (int, error) {
+	return s.conn.Conn.Write(p)
+}
+
+func (s *dockerExecSession) Resize(ctx context.Context, rows, cols uint) error {
+	return s.client.ContainerExecResize(ctx, s.id, container.ResizeOptions{Height: rows, Width: cols})
+}
+
+func (s *dockerExecSession) ExitCode(ctx context.Context) (int, error) {
+	inspect, err := s.client.ContainerExecInspect(ctx, s.id)
+	if err != nil {
+		return 0, err
+	}
+	return inspect.ExitCode, nil
+}
+
+// Close closes the command's input, so shells exit, and the connection
+func (s *dockerExecSession) Close() error {
+	_ = s.conn.CloseWrite()
+	s.conn.Close()
+	return nil
+}
+
+// ExecInteractive starts a command with a TTY in a Podman container and attaches to it
+func (p *PodmanRuntime) ExecInteractive(ctx context.Context, containerID string, opts models.ExecOptions) (ExecSession, error) {
+	inspectData, err := containers.Inspect(p.connCtx, containerID, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Podman container %s: %w", containerID, err)
+	}
+	if inspectData.State == nil || !inspectData.State.Running {
+		return nil, ErrContainerNotRunning
+	}
+
+	sessionID, err := containers.ExecCreate(p.connCtx, containerID, &handlers.ExecCreateConfig{ExecOptions: container.ExecOptions{
+		User:         opts.User,
+		WorkingDir:   opts.WorkingDir,
+		Cmd:          opts.Cmd,
+		Tty:          true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec session in Podman container %s: %w", containerID, err)
+	}
+
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	session := &podmanExecSession{connCtx: p.connCtx, id: sessionID, stdin: stdinWriter, stdout: stdoutReader}
+
+	// ExecStartAndAttach blocks until the command ends; its output is passed on through the pipe
+	options := new(containers.ExecStartAndAttachOptions).
+		WithInputStream(*bufio.NewReader(stdinReader)).
+		WithOutputStream(io.Writer(stdoutWriter)).
+		WithErrorStream(io.Writer(stdoutWriter)).
+		WithAttachInput(true).
+		WithAttachOutput(true).
+		WithAttachError(true)
+	go func() {
+		err := containers.ExecStartAndAttach(p.connCtx, sessionID, options)
+		if err == nil {
+			err = io.EOF
+		}
+		stdoutWriter.CloseWithError(err)
+		// Unblocks the input goroutine of the bindings
+		stdinReader.Close()
+	}()
+
+	if opts.Rows > 0 && opts.Cols > 0 {
+		// The session may not have started yet, in which case the client's first resize applies
+		_ = session.Resize(ctx, opts.Rows, opts.Cols)
+	}
+	return session, nil
+}
+
+// podmanExecSession is an exec session attached through the Podman bindings
+type podmanExecSession struct {
+	connCtx context.Context
+	id      string
+	stdin   *io.PipeWriter
+	stdout  *io.PipeReader
+}
+
+func (s *podmanExecSession) Read(p []byte) (int, error) {
+	return s.stdout.Read(p)
+}
+
+func (s *podmanExecSession) Write(p []byte) (int, error) {
+	return s.stdin.Write(p)
+}
+
+func (s *podmanExecSession) Resize(ctx context.Context, rows, cols uint) error {
+	height, width := int(rows), int(cols)
+	return containers.ResizeExecTTY(s.connCtx, s.id, new(containers.ResizeExecTTYOptions).WithHeight(height).WithWidth(width))
+}
+
+func (s *podmanExecSession) ExitCode(ctx context.Context) (int, error) {
+	inspect, err := containers.ExecInspect(s.connCtx, s.id, nil)
+	if err != nil {
+		return 0, err
+	}
+	return inspect.ExitCode, nil
+}
+
+// Close closes the command's input, so shells exit, and stops reading its output
+func (s *podmanExecSession) Close() error {
+	s.stdin.Close()
+	s.stdout.Close()
+	return nil
+}
+
+// execOutput runs a command without a TTY in a running Docker container and returns its stdout.
+// The exit code is not checked; callers validate the output instead.
+func (d *DockerRuntime) execOutput(ctx context.Context, containerID string, cmd []string) ([]byte, error) {
+	created, err := d.client.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec session in Docker container %s: %w", containerID, err)
+	}
+
+	hijacked, err := d.client.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to exec session in Docker container %s: %w", containerID, err)
+	}
+	defer hijacked.Close()
+
+	var stdout bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, io.Discard, hijacked.Reader); err != nil {
+		return nil, fmt.Errorf("failed to read exec output in Docker container %s: %w", containerID, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// execOutput runs a command without a TTY in a running Podman container and returns its stdout.
+// The exit code is not checked; callers validate the output instead.
+func (p *PodmanRuntime) execOutput(ctx context.Context, containerID string, cmd []string) ([]byte, error) {
+	sessionID, err := containers.ExecCreate(p.connCtx, containerID, &handlers.ExecCreateConfig{ExecOptions: container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec session in Podman container %s: %w", containerID, err)
+	}
+
+	var stdout bytes.Buffer
+	options := new(containers.ExecStartAndAttachOptions).
+		WithOutputStream(io.Writer(&stdout)).
+		WithErrorStream(io.Discard).
+		WithAttachOutput(true).
+		WithAttachError(true)
+	if err := containers.ExecStartAndAttach(p.connCtx, sessionID, options); err != nil {
+		return nil, fmt.Errorf("failed to run exec session in Podman container %s: %w", containerID, err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,188 @@
+package runtime
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+var (
+	// ErrPathNotFound is returned when a path does not exist in a container
+	ErrPathNotFound = errors.New("path not found")
+
+	// ErrDirectoryTooLarge is returned when a directory holds more than maxListEntries files, including subdirectories
+	ErrDirectoryTooLarge = errors.New("directory is too large to list")
+)
+
+// maxListEntries limits the archive entries read to list a directory.
+// The runtimes only export directories recursively, so their subdirectories count as well.
+const maxListEntries = 10000
+
+// maxLinkHops limits the symbolic links followed to find the directory to export
+const maxLinkHops = 16
+
+// findPrintfFields is the number of fields listDirCommand prints per entry
+const findPrintfFields = 7
+
+// listDirCommand lists a path and, if it is a directory, its direct children.
+// -H follows the path itself if it is a symbolic link, like /bin on merged-/usr systems.
+// Fields are NUL-separated, as names and link targets may contain any other byte.
+func listDirCommand(dir string) []string {
+	return []string{"find", "-H", dir, "-maxdepth", "1", "-printf", `%d\0%y\0%m\0%s\0%T@\0%l\0%f\0`}
+}
+
+// parseListDirOutput parses the output of listDirCommand like ListContainerPath returns it:
+// the children of a directory, or the path itself if it is no directory.
+// It returns false if the output does not start with the path, e.g. because find is missing
+// or does not support -printf (busybox).
+func parseListDirOutput(output []byte) ([]models.FileEntry, bool) {
+	fields := bytes.Split(output, []byte{0})
+	if len(fields) < findPrintfFields || string(fields[0]) != "0" {
+		return nil, false
+	}
+
+	entries := []models.FileEntry{}
+	var self models.FileEntry
+	for i := 0; i+findPrintfFields <= len(fields); i += findPrintfFields {
+		record := fields[i : i+findPrintfFields]
+		perm, err := strconv.ParseUint(string(record[2]), 8, 32)
+		if err != nil {
+			return nil, false
+		}
+		size, err := strconv.ParseInt(string(record[3]), 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		entry := fileEntry(string(record[6]), size, findFileMode(string(record[1]), perm), findModTime(string(record[4])), string(record[5]))
+		if string(record[0]) == "0" {
+			self = entry
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if !self.IsDir {
+		return []models.FileEntry{self}, true
+	}
+	return entries, true
+}
+
+// findFileMode converts find's %y file type and %m octal permissions to a FileMode
+func findFileMode(fileType string, perm uint64) os.FileMode {
+	mode := os.FileMode(perm & 0777)
+	if perm&04000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if perm&02000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if perm&01000 != 0 {
+		mode |= os.ModeSticky
+	}
+	switch fileType {
+	case "d":
+		mode |= os.ModeDir
+	case "l":
+		mode |= os.ModeSymlink
+	case "p":
+		mode |= os.ModeNamedPipe
+	case "s":
+		mode |= os.ModeSocket
+	case "c":
+		mode |= os.ModeDevice | os.ModeCharDevice
+	case "b":
+		mode |= os.ModeDevice
+	}
+	return mode
+}
+
+// findModTime parses find's %T@ modification time, seconds since the epoch with a fraction
+func findModTime(value string) time.Time {
+	secPart, fracPart, _ := strings.Cut(value, ".")
+	sec, err := strconv.ParseInt(secPart, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	if len(fracPart) > 9 {
+		fracPart = fracPart[:9]
+	}
+	nsec, _ := strconv.ParseInt(fracPart+strings.Repeat("0", 9-len(fracPart)), 10, 64)
+	return time.Unix(sec, nsec).UTC()
+}
+
+// linkTargetPath returns the path a symbolic link at linkPath points to.
+// Relative targets are relative to the link's directory.
+func linkTargetPath(linkPath, target string) string {
+	if path.IsAbs(target) {
+		return path.Clean(target)
+	}
+	return path.Join(path.Dir(linkPath), target)
+}
+
+// errArchiveRoot is returned instead of exporting the whole filesystem of a container through the archive API
+var errArchiveRoot = fmt.Errorf("%w: / can only be listed in running containers with GNU find", ErrDirectoryTooLarge)
+
+// fileEntry builds a FileEntry from stat results
+func fileEntry(name string, size int64, mode os.FileMode, modTime time.Time, linkTarget string) models.FileEntry {
+	entry := models.FileEntry{
+		Name:       name,
+		Size:       size,
+		Mode:       mode.String(),
+		IsDir:      mode.IsDir(),
+		ModTime:    modTime,
+		LinkTarget: linkTarget,
+	}
+	if entry.IsDir {
+		entry.Size = 0
+	}
+	return entry
+}
+
+// listArchiveDir lists the direct children of the directory exported as a tar archive by
+// the runtimes' copy APIs. The first entry is the directory itself; deeper entries are skipped.
+func listArchiveDir(r io.Reader) ([]models.FileEntry, error) {
+	tr := tar.NewReader(r)
+	entries := []models.FileEntry{}
+	root := ""
+	for i := 0; ; i++ {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if i > maxListEntries {
+			return nil, fmt.Errorf("%w (more than %d entries)", ErrDirectoryTooLarge, maxListEntries)
+		}
+
+		name := path.Clean(header.Name)
+		if i == 0 {
+			root = name
+			continue
+		}
+
+		rel := name
+		if root != "." && root != "/" {
+			var ok bool
+			if rel, ok = strings.CutPrefix(name, root+"/"); !ok {
+				continue
+			}
+		}
+		if rel == "" || strings.Contains(rel, "/") {
+			continue
+		}
+
+		info := header.FileInfo()
+		entries = append(entries, fileEntry(rel, header.Size, info.Mode(), header.ModTime, header.Linkname))
+	}
+}
@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"archive/tar"
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTar writes an archive with the given headers, filling regular files with Size zero bytes
+func writeTar(t *testing.T, headers ...*tar.Header) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, h := range headers {
+		require.NoError(t, tw.WriteHeader(h))
+		if h.Typeflag == tar.TypeReg {
+			_, err := tw.Write(make([]byte, h.Size))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	return &buf
+}
+
+func TestListArchiveDir(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	archive := writeTar(t,
+		&tar.Header{Name: "log/", Typeflag: tar.TypeDir, Mode: 0755, ModTime: modTime},
+		&tar.Header{Name: "log/apt/", Typeflag: tar.TypeDir, Mode: 0755, ModTime: modTime},
+		&tar.Header{Name: "log/apt/history.log", Typeflag: tar.TypeReg, Mode: 0644, Size: 10, ModTime: modTime},
+		&tar.Header{Name: "log/dpkg.log", Typeflag: tar.TypeReg, Mode: 0640, Size: 42, ModTime: modTime},
+		&tar.Header{Name: "log/current", Typeflag: tar.TypeSymlink, Linkname: "dpkg.log", Mode: 0777, ModTime: modTime},
+	)
+
+	entries, err := listArchiveDir(archive)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "apt", entries[0].Name)
+	assert.True(t, entries[0].IsDir)
+	assert.Equal(t, "drwxr-xr-x", entries[0].Mode)
+	assert.Zero(t, entries[0].Size)
+
+	assert.Equal(t, "dpkg.log", entries[1].Name)
+	assert.False(t, entries[1].IsDir)
+	assert.Equal(t, int64(42), entries[1].Size)
+	assert.Equal(t, "-rw-r-----", entries[1].Mode)
+	assert.True(t, modTime.Equal(entries[1].ModTime))
+
+	assert.Equal(t, "current", entries[2].Name)
+	assert.Equal(t, "dpkg.log", entries[2].LinkTarget)
+	assert.Equal(t, "Lrwxrwxrwx", entries[2].Mode)
+}
+
+func TestListArchiveDirRoot(t *testing.T) {
+	archive := writeTar(t,
+		&tar.Header{Name: "./", Typeflag: tar.TypeDir, Mode: 0755},
+		&tar.Header{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0755},
+		&tar.Header{Name: "etc/hosts", Typeflag: tar.TypeReg, Mode: 0644, Size: 1},
+		&tar.Header{Name: ".dockerenv", Typeflag: tar.TypeReg, Mode: 0755},
+	)
+
+	entries, err := listArchiveDir(archive)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "etc", entries[0].Name)
+	assert.Equal(t, ".dockerenv", entries[1].Name)
+}
+
+func TestListArchiveDirTooLarge(t *testing.T) {
+	headers := []*tar.Header{{Name: "big/", Typeflag: tar.TypeDir, Mode: 0755}}
+	for i := 0; i <= maxListEntries; i++ {
+		headers = append(headers, &tar.Header{Name: "big/sub/" + strconv.Itoa(i), Typeflag: tar.TypeReg, Mode: 0644})
+	}
+
+	_, err := listArchiveDir(writeTar(t, headers...))
+	assert.ErrorIs(t, err, ErrDirectoryTooLarge)
+}
+
+func TestParseListDirOutput(t *testing.T) {
+	record := func(fields ...string) string {
+		return strings.Join(fields, "\x00") + "\x00"
+	}
+
+	output := record("0", "d", "755", "4096", "1714564800.5000000000", "", "bin") +
+		record("1", "f", "4755", "1024", "1714564800.0000000000", "", "su") +
+		record("1", "l", "777", "7", "1714564800.0000000000", "busybox", "sh") +
+		record("1", "d", "1777", "60", "1714564800.0000000000", "", "tmp dir")
+
+	entries, ok := parseListDirOutput([]byte(output))
+	require.True(t, ok)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "su", entries[0].Name)
+	assert.Equal(t, "urwxr-xr-x", entries[0].Mode)
+	assert.Equal(t, int64(1024), entries[0].Size)
+	assert.True(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Equal(entries[0].ModTime))
+
+	assert.Equal(t, "sh", entries[1].Name)
+	assert.Equal(t, "Lrwxrwxrwx", entries[1].Mode)
+	assert.Equal(t, "busybox", entries[1].LinkTarget)
+
+	assert.Equal(t, "tmp dir", entries[2].Name)
+	assert.True(t, entries[2].IsDir)
+	assert.Zero(t, entries[2].Size)
+
+	// A path that is no directory returns just that entry
+	entries, ok = parseListDirOutput([]byte(record("0", "f", "644", "42", "1714564800.0000000000", "", "passwd")))
+	require.True(t, ok)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "passwd", entries[0].Name)
+	assert.Equal(t, "-rw-r--r--", entries[0].Mode)
+
+	// busybox find does not support -printf
+	_, ok = parseListDirOutput([]byte("find: unrecognized: -printf\n"))
+	assert.False(t, ok)
+	_, ok = parseListDirOutput(nil)
+	assert.False(t, ok)
+}
+
+func TestLinkTargetPath(t *testing.T) {
+	assert.Equal(t, "/usr/bin", linkTargetPath("/bin", "usr/bin"))
+	assert.Equal(t, "/usr/lib", linkTargetPath("/usr/lib64", "../usr/lib"))
+	assert.Equal(t, "/opt/app", linkTargetPath("/srv/app", "/opt/app/"))
+}
@@ -2,12 +2,59 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"io"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/ThraaxSession/gintainer/internal/logger"
 	"github.com/ThraaxSession/gintainer/internal/models"
 )
 
+// ErrContainerNotRunning is returned by operations that require a running container
+var ErrContainerNotRunning = errors.New("container is not running")
+
+// ErrImageNotFound is returned by image operations when the image does not exist
+var ErrImageNotFound = errors.New("image not found")
+
+// ErrPodNotFound is returned by pod operations when the pod does not exist
+var ErrPodNotFound = errors.New("pod not found")
+
+// socketURI turns a plain socket path into a unix:// URI and leaves URIs untouched
+func socketURI(socket string) string {
+	if strings.Contains(socket, "://") {
+		return socket
+	}
+	return "unix://" + socket
+}
+
+// firstIPAddress returns the IP address of the alphabetically first network that has one
+func firstIPAddress(ips map[string]string) string {
+	names := make([]string, 0, len(ips))
+	for name, ip := range ips {
+		if ip != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return ips[names[0]]
+}
+
+// RegistryAuthLookup returns the credentials for the registry of an image, or nil to pull anonymously
+type RegistryAuthLookup func(imageName string) *models.RegistryAuth
+
+// forImage resolves the credentials for an image, a nil lookup pulls anonymously
+func (lookup RegistryAuthLookup) forImage(imageName string) *models.RegistryAuth {
+	if lookup == nil {
+		return nil
+	}
+	return lookup(imageName)
+}
+
 // ContainerRuntime defines the interface for container runtime operations
 type ContainerRuntime interface {
 	// ListContainers lists all containers with optional filtering
@@ -22,11 +69,19 @@ type ContainerRuntime interface {
 	// StartContainer starts a container by ID
 	StartContainer(ctx context.Context, containerID string) error
 
-	// StopContainer stops a container by ID
-	StopContainer(ctx context.Context, containerID string) error
+	// StopContainer stops a container by ID, waiting timeout seconds (nil for the default) before killing it
+	StopContainer(ctx context.Context, containerID string, timeout *int) error
 
-	// RestartContainer restarts a container by ID
-	RestartContainer(ctx context.Context, containerID string) error
+	// RestartContainer restarts a container by ID, waiting timeout seconds (nil for the default) for it to stop
+	RestartContainer(ctx context.Context, containerID string, timeout *int) error
+
+	// UpdateContainerResources changes the limits and restart policy of a container in place, without recreating it.
+	// It returns ErrInvalidResourceUpdate if the update is invalid or changes nothing.
+	UpdateContainerResources(ctx context.Context, containerID string, update models.ResourceUpdate) error
+
+	// KillContainer sends a signal (DefaultKillSignal if empty) to a container by ID.
+	// It returns ErrInvalidSignal for unknown signals and ErrContainerNotRunning if the container is not running.
+	KillContainer(ctx context.Context, containerID string, signal string) error
 
 	// DeletePod deletes a pod by ID (Podman only)
 	DeletePod(ctx context.Context, podID string, force bool) error
@@ -40,30 +95,119 @@ type ContainerRuntime interface {
 	// RestartPod restarts a pod by ID (Podman only)
 	RestartPod(ctx context.Context, podID string) error
 
+	// PodStats returns the resource usage of a pod and each of its containers (Podman only).
+	// It returns ErrPodNotFound if the pod does not exist.
+	PodStats(ctx context.Context, podID string) (models.PodStats, error)
+
 	// BuildFromDockerfile builds an image from a Dockerfile
-	BuildFromDockerfile(ctx context.Context, dockerfile, imageName string) error
+	BuildFromDockerfile(ctx context.Context, dockerfile, imageName string, opts models.BuildOptions) error
+
+	// BuildFromContext builds an image from a tar archive of a build context.
+	// dockerfile is the path of the Dockerfile inside the context (defaults to "Dockerfile").
+	BuildFromContext(ctx context.Context, contextTar io.Reader, dockerfile, imageName string, opts models.BuildOptions) error
 
 	// RunContainer creates and runs a container from an image with configuration
 	RunContainer(ctx context.Context, req models.RunContainerRequest) (string, error)
 
-	// DeployFromCompose deploys containers from a compose file
-	DeployFromCompose(ctx context.Context, composeContent, projectName, deploymentPath string) error
+	// ContainerPorts returns the host ports a container is published on, as assigned by the runtime
+	ContainerPorts(ctx context.Context, containerID string) ([]models.PortMapping, error)
+
+	// DeployFromCompose deploys containers from a compose file.
+	// A non-empty env is written to a .env file next to the compose file and used for interpolation.
+	DeployFromCompose(ctx context.Context, composeContent, projectName, deploymentPath string, env map[string]string) error
+
+	// RemoveComposeDeployment stops and removes the containers and networks of a compose project,
+	// and its volumes when removeVolumes is set (like "compose down -v")
+	RemoveComposeDeployment(ctx context.Context, projectName, deploymentPath string, removeVolumes bool) error
+
+	// ComposeScale changes the number of containers of a service of the compose deployment in deploymentPath
+	// (like "compose up -d --scale service=replicas"); invalid replicas or services return ErrInvalidCompose
+	ComposeScale(ctx context.Context, projectName, deploymentPath, service string, replicas int) error
+
+	// PullImage pulls the latest version of an image, authenticating with auth when it is non-nil
+	PullImage(ctx context.Context, imageName string, auth *models.RegistryAuth) error
+
+	// PullImageStream pulls an image like PullImage and reports its progress on the returned channel.
+	// The channel is closed when the pull ends or ctx is cancelled; a failure is reported as a last update with Error set.
+	PullImageStream(ctx context.Context, imageName string, auth *models.RegistryAuth) (<-chan models.PullProgress, error)
+
+	// ImageHistory returns the layers of an image, newest first.
+	// It returns ErrImageNotFound if the image does not exist.
+	ImageHistory(ctx context.Context, imageName string) ([]models.ImageLayer, error)
+
+	// TagImage adds the target reference to an existing local image
+	TagImage(ctx context.Context, source, target string) error
+
+	// PushImage pushes an image to its registry and returns the pushed manifest digest
+	PushImage(ctx context.Context, imageName string, auth *models.RegistryAuth) (string, error)
+
+	// CheckImageUpdate pulls the image of a container and reports whether it differs
+	// from the image the container is running, without touching the container.
+	// auth returns the credentials for the image's registry and may be nil.
+	CheckImageUpdate(ctx context.Context, containerID string, auth RegistryAuthLookup) (bool, error)
 
-	// PullImage pulls the latest version of an image
-	PullImage(ctx context.Context, imageName string) error
+	// UpdateContainer updates a container by pulling the latest image and recreating it.
+	// auth returns the credentials for the image's registry and may be nil.
+	UpdateContainer(ctx context.Context, containerID string, auth RegistryAuthLookup) error
 
-	// UpdateContainer updates a container by pulling the latest image and recreating it
-	UpdateContainer(ctx context.Context, containerID string) error
+	// PruneContainers removes all stopped containers and returns their IDs and the disk space reclaimed
+	PruneContainers(ctx context.Context) (removed []string, reclaimed uint64, err error)
+
+	// DiskUsage reports the disk space used by images, containers, volumes and build cache
+	DiskUsage(ctx context.Context) (models.DiskUsage, error)
+
+	// SystemPrune removes unused containers, images, networks, volumes and build cache as selected by opts
+	SystemPrune(ctx context.Context, opts models.PruneOptions) (models.PruneReport, error)
+
+	// StreamEvents streams container start, die, stop, kill and health_status events until ctx is cancelled.
+	// The returned channel is closed when the stream ends.
+	StreamEvents(ctx context.Context) (<-chan models.RuntimeEvent, error)
+
+	// ContainerTop lists the processes running inside a container.
+	// It returns ErrContainerNotRunning if the container is not running.
+	ContainerTop(ctx context.Context, containerID string) ([]models.ProcessInfo, error)
+
+	// ListContainerPath lists the entries of a directory in a container, or returns a single entry if the path is no directory.
+	// It returns ErrPathNotFound if the path does not exist and ErrDirectoryTooLarge for very large directory trees.
+	ListContainerPath(ctx context.Context, containerID, path string) ([]models.FileEntry, error)
+
+	// CheckpointContainer checkpoints a running container with CRIU, stopping it, and exports the checkpoint
+	// to an archive at exportPath (Podman only, see Capabilities)
+	CheckpointContainer(ctx context.Context, containerID, exportPath string) error
+
+	// RestoreContainer creates and starts a container from a checkpoint archive and returns its ID (Podman only)
+	RestoreContainer(ctx context.Context, importPath string) (string, error)
+
+	// GenerateSystemd generates systemd unit files for a container, keyed by unit file name (Podman only)
+	GenerateSystemd(ctx context.Context, containerID string, opts models.SystemdOptions) (map[string]string, error)
+
+	// GenerateKube generates a Kubernetes manifest from containers and pods (Podman only)
+	GenerateKube(ctx context.Context, ids []string) (string, error)
+
+	// ExecInteractive starts a command with a TTY in a running container and attaches to it.
+	// It returns ErrContainerNotRunning if the container is not running.
+	ExecInteractive(ctx context.Context, containerID string, opts models.ExecOptions) (ExecSession, error)
 
 	// StreamLogs streams logs from a container
-	StreamLogs(ctx context.Context, containerID string, follow bool, tail string) (io.ReadCloser, error)
+	StreamLogs(ctx context.Context, containerID string, opts models.LogOptions) (io.ReadCloser, error)
+
+	// Capabilities reports which optional features are supported for the runtime
+	Capabilities() models.RuntimeCapabilities
 
 	// GetRuntimeName returns the name of the runtime ("docker" or "podman")
 	GetRuntimeName() string
+
+	// Version returns the version reported by the runtime's daemon or service
+	Version(ctx context.Context) (string, error)
+
+	// Ping checks that the runtime's daemon or service is reachable
+	Ping(ctx context.Context) error
 }
 
-// Manager manages multiple container runtimes
+// Manager manages multiple container runtimes.
+// It is safe for concurrent use.
 type Manager struct {
+	mu       sync.RWMutex
 	runtimes map[string]ContainerRuntime
 }
 
@@ -77,14 +221,33 @@ func NewManager() *Manager {
 // RegisterRuntime registers a container runtime
 func (m *Manager) RegisterRuntime(name string, runtime ContainerRuntime) {
 	logger.Debug("RuntimeManager: Registering runtime", "name", name)
+	m.mu.Lock()
 	m.runtimes[name] = runtime
-	logger.Info("RuntimeManager: Runtime registered successfully", "name", name, "total_runtimes", len(m.runtimes))
+	total := len(m.runtimes)
+	m.mu.Unlock()
+	logger.Info("RuntimeManager: Runtime registered successfully", "name", name, "total_runtimes", total)
+}
+
+// UnregisterRuntime removes a runtime and returns it, or false if it was not registered
+func (m *Manager) UnregisterRuntime(name string) (ContainerRuntime, bool) {
+	m.mu.Lock()
+	runtime, ok := m.runtimes[name]
+	delete(m.runtimes, name)
+	total := len(m.runtimes)
+	m.mu.Unlock()
+
+	if ok {
+		logger.Info("RuntimeManager: Runtime unregistered", "name", name, "total_runtimes", total)
+	}
+	return runtime, ok
 }
 
 // GetRuntime returns a runtime by name
 func (m *Manager) GetRuntime(name string) (ContainerRuntime, bool) {
 	logger.Debug("RuntimeManager: Looking up runtime", "name", name)
+	m.mu.RLock()
 	runtime, ok := m.runtimes[name]
+	m.mu.RUnlock()
 	if !ok {
 		logger.Warn("RuntimeManager: Runtime not found", "name", name, "available_runtimes", m.getRuntimeNames())
 	} else {
@@ -93,17 +256,38 @@ func (m *Manager) GetRuntime(name string) (ContainerRuntime, bool) {
 	return runtime, ok
 }
 
-// GetAllRuntimes returns all registered runtimes
+// GetAllRuntimes returns a copy of all registered runtimes
 func (m *Manager) GetAllRuntimes() map[string]ContainerRuntime {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	logger.Debug("RuntimeManager: Getting all runtimes", "count", len(m.runtimes))
-	return m.runtimes
+	runtimes := make(map[string]ContainerRuntime, len(m.runtimes))
+	for name, runtime := range m.runtimes {
+		runtimes[name] = runtime
+	}
+	return runtimes
 }
 
 // getRuntimeNames returns a list of registered runtime names for logging
 func (m *Manager) getRuntimeNames() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	names := make([]string, 0, len(m.runtimes))
 	for name := range m.runtimes {
 		names = append(names, name)
 	}
 	return names
 }
+
+// DefaultStopTimeout is the number of seconds a container is given to stop before it is killed
+const DefaultStopTimeout = 10
+
+// stopTimeout returns the requested stop timeout or DefaultStopTimeout if none was given
+func stopTimeout(timeout *int) int {
+	if timeout == nil {
+		return DefaultStopTimeout
+	}
+	return *timeout
+}
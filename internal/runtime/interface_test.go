@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// namedRuntime is a ContainerRuntime that only knows its name
+type namedRuntime struct {
+	ContainerRuntime
+	name string
+}
+
+func (n *namedRuntime) GetRuntimeName() string {
+	return n.name
+}
+
+func TestManagerGetAllRuntimesReturnsCopy(t *testing.T) {
+	manager := NewManager()
+	manager.RegisterRuntime("docker", &namedRuntime{name: "docker"})
+
+	runtimes := manager.GetAllRuntimes()
+	delete(runtimes, "docker")
+	runtimes["podman"] = &namedRuntime{name: "podman"}
+
+	_, ok := manager.GetRuntime("docker")
+	assert.True(t, ok)
+	_, ok = manager.GetRuntime("podman")
+	assert.False(t, ok)
+	assert.Len(t, manager.GetAllRuntimes(), 1)
+}
+
+// TestManagerConcurrentAccess is meant to be run with -race
+func TestManagerConcurrentAccess(t *testing.T) {
+	manager := NewManager()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("runtime-%d", i)
+			manager.RegisterRuntime(name, &namedRuntime{name: name})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for name, rt := range manager.GetAllRuntimes() {
+				assert.Equal(t, name, rt.GetRuntimeName())
+			}
+			manager.GetRuntime(fmt.Sprintf("runtime-%d", i))
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			manager.UnregisterRuntime(fmt.Sprintf("other-%d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, manager.GetAllRuntimes(), 10)
+}
+
+func TestManagerUnregisterRuntime(t *testing.T) {
+	manager := NewManager()
+	docker := &namedRuntime{name: "docker"}
+	manager.RegisterRuntime("docker", docker)
+
+	rt, ok := manager.UnregisterRuntime("docker")
+	assert.True(t, ok)
+	assert.Same(t, docker, rt)
+	assert.Empty(t, manager.GetAllRuntimes())
+
+	_, ok = manager.UnregisterRuntime("docker")
+	assert.False(t, ok)
+}
+
+func TestCapabilities(t *testing.T) {
+	docker := (&DockerRuntime{}).Capabilities()
+	assert.False(t, docker.Pods)
+	assert.False(t, docker.LiveLabels)
+	assert.True(t, docker.Compose)
+	assert.False(t, docker.Checkpoint)
+	assert.False(t, docker.Systemd)
+	assert.False(t, docker.KubeExport)
+
+	podman := (&PodmanRuntime{}).Capabilities()
+	assert.True(t, podman.Pods)
+	assert.True(t, podman.Compose)
+	assert.True(t, podman.Checkpoint)
+	assert.True(t, podman.Systemd)
+	assert.True(t, podman.KubeExport)
+
+	// Both runtimes recreate containers to change labels
+	assert.False(t, podman.LiveLabels)
+}
@@ -0,0 +1,34 @@
+package runtime
+
+import "context"
+
+// LabelRecreator is implemented by runtimes that can only change the labels of a container by
+// recreating it. The new container keeps the name and configuration of the original.
+// Neither the Docker nor the Podman API can change labels in place.
+type LabelRecreator interface {
+	// RecreateContainerWithLabels recreates the container with the labels in set added or
+	// replaced and the labels in remove deleted, and returns the ID of the new container
+	RecreateContainerWithLabels(ctx context.Context, containerID string, set map[string]string, remove []string) (string, error)
+}
+
+// Runtimes that have to recreate containers for label changes
+var (
+	_ LabelRecreator = (*DockerRuntime)(nil)
+	_ LabelRecreator = (*PodmanRuntime)(nil)
+)
+
+// applyLabelChanges returns a copy of labels with the labels in set added or replaced
+// and the labels in remove deleted
+func applyLabelChanges(labels map[string]string, set map[string]string, remove []string) map[string]string {
+	result := make(map[string]string, len(labels)+len(set))
+	for key, value := range labels {
+		result[key] = value
+	}
+	for _, key := range remove {
+		delete(result, key)
+	}
+	for key, value := range set {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyLabelChanges(t *testing.T) {
+	labels := map[string]string{"keep": "1", "caddy.domain": "old.example.com", "drop": "x"}
+
+	result := applyLabelChanges(labels, map[string]string{"caddy.domain": "example.com", "caddy.port": "8080"}, []string{"drop", "missing"})
+	assert.Equal(t, map[string]string{"keep": "1", "caddy.domain": "example.com", "caddy.port": "8080"}, result)
+	// The original labels are left untouched
+	assert.Equal(t, "old.example.com", labels["caddy.domain"])
+	assert.Contains(t, labels, "drop")
+
+	assert.Empty(t, applyLabelChanges(nil, nil, []string{"drop"}))
+}
+
+func TestContainerEndpoints(t *testing.T) {
+	inspect := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:         "0123456789abcdef0123",
+			HostConfig: &container.HostConfig{NetworkMode: "app"},
+		},
+		NetworkSettings: &container.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+			"app":     {Aliases: []string{"web", "0123456789ab"}, IPAddress: "172.18.0.5", EndpointID: "ep1"},
+			"backend": {IPAMConfig: &network.EndpointIPAMConfig{IPv4Address: "10.0.0.5"}},
+		}},
+	}
+
+	assert.Equal(t, "app", primaryNetwork(inspect.HostConfig))
+	endpoints := containerEndpoints(inspect)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, &network.EndpointSettings{Aliases: []string{"web"}}, endpoints["app"])
+	assert.Equal(t, "10.0.0.5", endpoints["backend"].IPAMConfig.IPv4Address)
+
+	assert.Equal(t, network.NetworkBridge, primaryNetwork(&container.HostConfig{NetworkMode: "default"}))
+	inspect.HostConfig.NetworkMode = "host"
+	assert.Empty(t, primaryNetwork(inspect.HostConfig))
+	assert.Nil(t, containerEndpoints(inspect))
+}
+
+func TestReplaceStoppedContainer(t *testing.T) {
+	f := newFakeReplacer()
+	f.running["old"] = false
+
+	require.NoError(t, replaceStoppedContainer(context.Background(), f, "old", "web"))
+	assert.Equal(t, []string{"old"}, f.removed)
+	assert.Equal(t, "web", f.names["new"])
+	assert.False(t, f.running["new"])
+
+	// A failed create restores the original name
+	f = newFakeReplacer()
+	f.createErr = assert.AnError
+	err := replaceStoppedContainer(context.Background(), f, "old", "web")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, "web", f.names["old"])
+	assert.Empty(t, f.removed)
+}
+
+func TestDockerRecreateContainerWithLabels(t *testing.T) {
+	d := newTestDockerRuntime(t)
+	d.updateGracePeriod = 500 * time.Millisecond
+	ctx := context.Background()
+
+	require.NoError(t, d.PullImage(ctx, "busybox:latest", nil))
+
+	const networkName = "gintainer-test-labels"
+	_, err := d.client.NetworkCreate(ctx, networkName, network.CreateOptions{})
+	require.NoError(t, err)
+	defer d.client.NetworkRemove(ctx, networkName)
+
+	const name = "gintainer-test-labels"
+	resp, err := d.client.ContainerCreate(ctx, &container.Config{
+		Image:  "busybox:latest",
+		Cmd:    []string{"sleep", "300"},
+		Env:    []string{"FOO=bar"},
+		Labels: map[string]string{"keep": "1", "drop": "x"},
+	}, &container.HostConfig{NetworkMode: networkName}, nil, nil, name)
+	require.NoError(t, err)
+	defer d.client.ContainerRemove(ctx, name, container.RemoveOptions{Force: true})
+	require.NoError(t, d.client.ContainerStart(ctx, resp.ID, container.StartOptions{}))
+
+	newID, err := d.RecreateContainerWithLabels(ctx, resp.ID, map[string]string{"caddy.domain": "example.com"}, []string{"drop"})
+	require.NoError(t, err)
+	assert.NotEqual(t, resp.ID, newID)
+
+	containers, err := d.ListContainers(ctx, models.FilterOptions{Name: name})
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	assert.Equal(t, newID, containers[0].ID)
+	assert.Equal(t, "example.com", containers[0].Labels["caddy.domain"])
+	assert.Equal(t, "1", containers[0].Labels["keep"])
+	assert.NotContains(t, containers[0].Labels, "drop")
+
+	inspect, err := d.client.ContainerInspect(ctx, newID)
+	require.NoError(t, err)
+	assert.True(t, inspect.State.Running)
+	assert.Contains(t, inspect.Config.Env, "FOO=bar")
+	assert.Contains(t, inspect.NetworkSettings.Networks, networkName)
+}
@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultListWorkers is how many containers have their stats or inspect data fetched at once while listing
+	DefaultListWorkers = 8
+
+	// listCallTimeout bounds a single stats or inspect call while listing, so one hung container
+	// cannot hold up the whole list
+	listCallTimeout = 5 * time.Second
+)
+
+// listWorkers returns the configured number of list workers, or DefaultListWorkers if unset
+func listWorkers(n int) int {
+	if n <= 0 {
+		return DefaultListWorkers
+	}
+	return n
+}
+
+// forEachParallel calls fn for every index below n, running at most workers calls at once.
+// Each call gets a context that ends after timeout. It returns once all calls are done.
+// Callers write results by index, which keeps the output order.
+func forEachParallel(ctx context.Context, n, workers int, timeout time.Duration, fn func(ctx context.Context, i int)) {
+	if n == 0 {
+		return
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				callCtx, cancel := context.WithTimeout(ctx, timeout)
+				fn(callCtx, i)
+				cancel()
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,71 @@
+package runtime
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachParallel(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	result := make([]int, 50)
+
+	forEachParallel(context.Background(), len(result), 4, time.Second, func(ctx context.Context, i int) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		result[i] = i * i
+	})
+
+	for i, v := range result {
+		assert.Equal(t, i*i, v)
+	}
+	assert.LessOrEqual(t, maxRunning.Load(), int32(4))
+}
+
+func TestForEachParallelTimeout(t *testing.T) {
+	done := make([]bool, 3)
+	start := time.Now()
+
+	// A hung call ends with its own timeout and does not block the others
+	forEachParallel(context.Background(), len(done), 2, 50*time.Millisecond, func(ctx context.Context, i int) {
+		if i == 0 {
+			<-ctx.Done()
+			return
+		}
+		done[i] = true
+	})
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, []bool{false, true, true}, done)
+}
+
+func TestListWorkers(t *testing.T) {
+	assert.Equal(t, DefaultListWorkers, listWorkers(0))
+	assert.Equal(t, 3, listWorkers(3))
+}
+
+// BenchmarkForEachParallel lists 32 containers whose stats call takes a millisecond
+func BenchmarkForEachParallel(b *testing.B) {
+	for _, bm := range []struct {
+		name    string
+		workers int
+	}{{"serial", 1}, {"default", DefaultListWorkers}} {
+		b.Run(bm.name, func(b *testing.B) {
+			for b.Loop() {
+				forEachParallel(context.Background(), 32, bm.workers, listCallTimeout, func(ctx context.Context, i int) {
+					time.Sleep(time.Millisecond)
+				})
+			}
+		})
+	}
+}
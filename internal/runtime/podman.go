@@ -1,13 +1,17 @@
 package runtime
 
 import (
+	"bufio"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,12 +19,21 @@ import (
 
 	"github.com/ThraaxSession/gintainer/internal/logger"
 	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/containers/podman/v5/libpod/define"
 	"github.com/containers/podman/v5/pkg/bindings"
 	"github.com/containers/podman/v5/pkg/bindings/containers"
+	"github.com/containers/podman/v5/pkg/bindings/generate"
 	"github.com/containers/podman/v5/pkg/bindings/images"
+	"github.com/containers/podman/v5/pkg/bindings/network"
 	"github.com/containers/podman/v5/pkg/bindings/pods"
+	"github.com/containers/podman/v5/pkg/bindings/system"
+	"github.com/containers/podman/v5/pkg/bindings/volumes"
+	podmancopy "github.com/containers/podman/v5/pkg/copy"
+	"github.com/containers/podman/v5/pkg/domain/entities/reports"
 	"github.com/containers/podman/v5/pkg/domain/entities/types"
+	"github.com/containers/podman/v5/pkg/errorhandling"
 	"github.com/containers/podman/v5/pkg/specgen"
+	"github.com/docker/docker/pkg/archive"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
 	nettypes "go.podman.io/common/libnetwork/types"
 	"gopkg.in/yaml.v3"
@@ -28,13 +41,30 @@ import (
 
 // PodmanRuntime implements ContainerRuntime for Podman using Golang Bindings
 type PodmanRuntime struct {
-	connCtx context.Context
+	connCtx           context.Context
+	updateGracePeriod time.Duration // How long an updated container must stay up before the old one is removed
+	listWorkers       int           // Containers inspected at once while listing
 }
 
-// NewPodmanRuntime creates a new Podman runtime using the Golang Bindings
-func NewPodmanRuntime() (*PodmanRuntime, error) {
+// PodmanConnection describes how to reach the Podman service
+type PodmanConnection struct {
+	Socket      string // Socket path or connection URI (unix://, ssh:// or tcp://)
+	Identity    string // SSH private key for ssh:// URIs
+	Passphrase  string // Passphrase of the SSH private key
+	ListWorkers int    // Parallel inspect calls while listing containers (0 for DefaultListWorkers)
+}
+
+// NewPodmanRuntime creates a new Podman runtime using the Golang Bindings.
+// A remote ssh:// or tcp:// URI is used exclusively; any other non-empty socket
+// is tried before the local default socket locations.
+func NewPodmanRuntime(conn PodmanConnection) (*PodmanRuntime, error) {
 	logger.Debug("NewPodmanRuntime: Starting Podman runtime initialization")
 
+	if isRemotePodmanURI(conn.Socket) {
+		return newRemotePodmanRuntime(conn)
+	}
+	socket := conn.Socket
+
 	// Connect to Podman socket (unix socket by default)
 	// Try different socket locations
 	socketPaths := []string{
@@ -48,10 +78,13 @@ func NewPodmanRuntime() (*PodmanRuntime, error) {
 	if customSocket := os.Getenv("PODMAN_SOCKET"); customSocket != "" {
 		logger.Debug("NewPodmanRuntime: Custom socket path specified via PODMAN_SOCKET", "socket", customSocket)
 		// Prepend custom socket to try it first
-		if !strings.HasPrefix(customSocket, "unix://") {
-			customSocket = "unix://" + customSocket
-		}
-		socketPaths = append([]string{customSocket}, socketPaths...)
+		socketPaths = append([]string{socketURI(customSocket)}, socketPaths...)
+	}
+
+	// A socket from the config takes precedence over everything else
+	if socket != "" {
+		logger.Debug("NewPodmanRuntime: Using socket from config", "socket", socket)
+		socketPaths = append([]string{socketURI(socket)}, socketPaths...)
 	}
 
 	logger.Debug("NewPodmanRuntime: Will attempt to connect to sockets", "paths", socketPaths)
@@ -111,14 +144,70 @@ func NewPodmanRuntime() (*PodmanRuntime, error) {
 	}
 
 	logger.Info("NewPodmanRuntime: Podman runtime initialized successfully")
-	return &PodmanRuntime{connCtx: connCtx}, nil
+	return &PodmanRuntime{connCtx: connCtx, updateGracePeriod: defaultUpdateGracePeriod, listWorkers: listWorkers(conn.ListWorkers)}, nil
+}
+
+// isRemotePodmanURI reports whether socket points at a remote Podman service
+func isRemotePodmanURI(socket string) bool {
+	return strings.HasPrefix(socket, "ssh://") || strings.HasPrefix(socket, "tcp://")
+}
+
+// newRemotePodmanRuntime connects to a Podman service over SSH or TCP.
+// Local sockets are not tried, so a misconfigured remote never silently falls back to the local host.
+func newRemotePodmanRuntime(conn PodmanConnection) (*PodmanRuntime, error) {
+	uri, err := podmanConnectionURI(conn)
+	if err != nil {
+		return nil, err
+	}
+	redacted := redactedURI(uri)
+
+	logger.Debug("NewPodmanRuntime: Connecting to remote Podman service", "uri", redacted, "identity", conn.Identity)
+	connCtx, err := bindings.NewConnectionWithIdentity(context.Background(), uri, conn.Identity, false)
+	if err != nil {
+		logger.Error("NewPodmanRuntime: Remote connection failed", "uri", redacted, "error", err)
+		return nil, fmt.Errorf("failed to connect to remote Podman service at %s: %w", redacted, err)
+	}
+
+	logger.Info("NewPodmanRuntime: Connected to remote Podman service", "uri", redacted)
+	return &PodmanRuntime{connCtx: connCtx, updateGracePeriod: defaultUpdateGracePeriod, listWorkers: listWorkers(conn.ListWorkers)}, nil
+}
+
+// podmanConnectionURI returns the connection URI, carrying the SSH key passphrase
+// as the URI password, which is where the bindings read it from
+func podmanConnectionURI(conn PodmanConnection) (string, error) {
+	if conn.Passphrase == "" || !strings.HasPrefix(conn.Socket, "ssh://") {
+		return conn.Socket, nil
+	}
+
+	u, err := url.Parse(conn.Socket)
+	if err != nil {
+		return "", fmt.Errorf("invalid Podman connection URI: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", fmt.Errorf("the ssh:// URI must include a user when a passphrase is set")
+	}
+	u.User = url.UserPassword(u.User.Username(), conn.Passphrase)
+	return u.String(), nil
+}
+
+// redactedURI hides the password of a URI for logs and errors
+func redactedURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	return u.Redacted()
 }
 
 // ListContainers lists all Podman containers
 func (p *PodmanRuntime) ListContainers(ctx context.Context, filterOpts models.FilterOptions) ([]models.ContainerInfo, error) {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
 	logger.Debug("PodmanRuntime.ListContainers: Starting container list",
 		"name_filter", filterOpts.Name,
 		"status_filter", filterOpts.Status,
+		"label_filter", filterOpts.Label,
 		"include_stats", filterOpts.IncludeStats,
 		"include_privileged", filterOpts.IncludePrivileged)
 
@@ -133,6 +222,10 @@ func (p *PodmanRuntime) ListContainers(ctx context.Context, filterOpts models.Fi
 	if filterOpts.Status != "" {
 		filters["status"] = []string{filterOpts.Status}
 	}
+	if filterOpts.Label != "" {
+		// Podman matches both "key" and "key=value"
+		filters["label"] = []string{filterOpts.Label}
+	}
 	if len(filters) > 0 {
 		logger.Debug("PodmanRuntime.ListContainers: Applying filters", "filters", filters)
 		listOpts.WithFilters(filters)
@@ -141,7 +234,7 @@ func (p *PodmanRuntime) ListContainers(ctx context.Context, filterOpts models.Fi
 	}
 
 	// List containers using bindings
-	podmanContainers, err := containers.List(p.connCtx, listOpts)
+	podmanContainers, err := containers.List(callCtx, listOpts)
 	if err != nil {
 		logger.Error("PodmanRuntime.ListContainers: Failed to list containers", "error", err)
 		return nil, fmt.Errorf("failed to list Podman containers: %w", err)
@@ -178,81 +271,50 @@ func (p *PodmanRuntime) ListContainers(ctx context.Context, filterOpts models.Fi
 			Labels:  pc.Labels,
 			Ports:   ports,
 		}
+		// The list already reports the start time, no inspect is needed
+		if (filterOpts.IncludeState || filterOpts.IncludeStats) && pc.StartedAt > 0 {
+			containerInfo.StartedAt = time.Unix(pc.StartedAt, 0)
+		}
 
 		containerInfos = append(containerInfos, containerInfo)
 	}
 
-	// Add privileged and stats support if requested
-	for i := range containerInfos {
-		if filterOpts.IncludePrivileged {
-			// Inspect container to check if it's privileged
-			inspectData, err := containers.Inspect(p.connCtx, containerInfos[i].ID, new(containers.InspectOptions).WithSize(false))
-			if err == nil && inspectData.HostConfig != nil {
-				containerInfos[i].Privileged = inspectData.HostConfig.Privileged
-			}
+	// Add privileged and network support if requested, inspecting containers in parallel
+	forEachParallel(ctx, len(containerInfos), listWorkers(p.listWorkers), listCallTimeout, func(ctx context.Context, i int) {
+		if !filterOpts.IncludePrivileged && (!filterOpts.IncludeNetwork || containerInfos[i].State != "running") {
+			return
 		}
 
-		if filterOpts.IncludeStats && containerInfos[i].State == "running" {
-			// Get stats for running containers using the stats command (bindings don't provide direct stats API in a simple way)
-			// We'll use the CLI approach for stats as the bindings Stats API is streaming-based
-			logger.Debug("PodmanRuntime.ListContainers: Getting stats for container", "id", containerInfos[i].ID, "name", containerInfos[i].Name)
-			statsCmd := exec.CommandContext(ctx, "podman", "stats", "--no-stream", "--format", "json", containerInfos[i].ID)
-			statsOut, err := statsCmd.Output()
-			if err != nil {
-				logger.Debug("PodmanRuntime.ListContainers: Failed to get stats via CLI", "id", containerInfos[i].ID, "error", err)
-				// Try to get stats using the bindings API as fallback
-				// Note: This requires the statsReport to be available but may work in some environments
-				continue
-			}
-
-			if len(statsOut) == 0 {
-				logger.Debug("PodmanRuntime.ListContainers: Empty stats output", "id", containerInfos[i].ID)
-				continue
-			}
-
-			var podmanStats []struct {
-				ID            string `json:"id"`
-				Name          string `json:"name"`
-				CPUPercentage string `json:"cpu_percent"`
-				MemUsage      string `json:"mem_usage"`
-				MemPercentage string `json:"mem_percent"`
-				NetIO         string `json:"net_io"`
-				BlockIO       string `json:"block_io"`
-				PIDs          string `json:"pids"`
-			}
-			if err := json.Unmarshal(statsOut, &podmanStats); err != nil {
-				logger.Debug("PodmanRuntime.ListContainers: Failed to unmarshal stats", "id", containerInfos[i].ID, "error", err)
-				continue
-			}
-
-			if len(podmanStats) == 0 {
-				logger.Debug("PodmanRuntime.ListContainers: No stats in response", "id", containerInfos[i].ID)
-				continue
-			}
-
-			// Parse CPU percentage (format: "0.50%")
-			cpuStr := strings.TrimSuffix(podmanStats[0].CPUPercentage, "%")
-			cpuPerc, _ := strconv.ParseFloat(cpuStr, 64)
+		// The bindings need the connection context; cancel it together with ctx
+		inspectCtx, cancel := context.WithCancel(p.connCtx)
+		defer cancel()
+		stop := context.AfterFunc(ctx, cancel)
+		defer stop()
 
-			// Parse memory usage (format: "100MB / 8GB")
-			memParts := strings.Split(podmanStats[0].MemUsage, " / ")
-			var memUsage, memLimit uint64
-			if len(memParts) == 2 {
-				memUsage = parseSize(strings.TrimSpace(memParts[0]))
-				memLimit = parseSize(strings.TrimSpace(memParts[1]))
+		// Inspect container to check if it's privileged and to resolve its IP address
+		inspectData, err := containers.Inspect(inspectCtx, containerInfos[i].ID, new(containers.InspectOptions).WithSize(false))
+		if err == nil && inspectData.HostConfig != nil && filterOpts.IncludePrivileged {
+			containerInfos[i].Privileged = inspectData.HostConfig.Privileged
+		}
+		if err == nil && inspectData.NetworkSettings != nil {
+			containerInfos[i].IPAddress = podmanIPAddress(inspectData.NetworkSettings)
+		}
+	})
+
+	// Get stats for running containers in a single one-shot request
+	if filterOpts.IncludeStats {
+		var running []string
+		for _, info := range containerInfos {
+			if info.State == "running" {
+				running = append(running, info.ID)
 			}
+		}
 
-			// Parse memory percentage (format: "1.25%")
-			memPercStr := strings.TrimSuffix(podmanStats[0].MemPercentage, "%")
-			memPerc, _ := strconv.ParseFloat(memPercStr, 64)
-
-			containerInfos[i].Stats = &models.ContainerStats{
-				CPUPercent:    cpuPerc,
-				MemoryUsage:   memUsage,
-				MemoryLimit:   memLimit,
-				MemoryPercent: memPerc,
+		stats := p.containerStats(running)
+		for i := range containerInfos {
+			if s, ok := stats[containerInfos[i].ID]; ok {
+				containerInfos[i].Stats = s
 			}
-			logger.Debug("PodmanRuntime.ListContainers: Stats retrieved", "id", containerInfos[i].ID, "cpu", cpuPerc, "mem_percent", memPerc)
 		}
 	}
 
@@ -262,6 +324,9 @@ func (p *PodmanRuntime) ListContainers(ctx context.Context, filterOpts models.Fi
 
 // ListPods lists all Podman pods
 func (p *PodmanRuntime) ListPods(ctx context.Context, filterOpts models.FilterOptions) ([]models.PodInfo, error) {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
 	// Prepare list options
 	listOpts := new(pods.ListOptions)
 
@@ -278,7 +343,7 @@ func (p *PodmanRuntime) ListPods(ctx context.Context, filterOpts models.FilterOp
 	}
 
 	// List pods using bindings
-	podmanPods, err := pods.List(p.connCtx, listOpts)
+	podmanPods, err := pods.List(callCtx, listOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list Podman pods: %w", err)
 	}
@@ -313,8 +378,11 @@ func (p *PodmanRuntime) ListPods(ctx context.Context, filterOpts models.FilterOp
 
 // DeleteContainer deletes a Podman container
 func (p *PodmanRuntime) DeleteContainer(ctx context.Context, containerID string, force bool) error {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
 	removeOpts := new(containers.RemoveOptions).WithForce(force)
-	_, err := containers.Remove(p.connCtx, containerID, removeOpts)
+	_, err := containers.Remove(callCtx, containerID, removeOpts)
 	if err != nil {
 		return fmt.Errorf("failed to delete Podman container %s: %w", containerID, err)
 	}
@@ -323,7 +391,10 @@ func (p *PodmanRuntime) DeleteContainer(ctx context.Context, containerID string,
 
 // StartContainer starts a Podman container
 func (p *PodmanRuntime) StartContainer(ctx context.Context, containerID string) error {
-	err := containers.Start(p.connCtx, containerID, nil)
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	err := containers.Start(callCtx, containerID, nil)
 	if err != nil {
 		return fmt.Errorf("failed to start Podman container %s: %w", containerID, err)
 	}
@@ -331,8 +402,12 @@ func (p *PodmanRuntime) StartContainer(ctx context.Context, containerID string)
 }
 
 // StopContainer stops a Podman container
-func (p *PodmanRuntime) StopContainer(ctx context.Context, containerID string) error {
-	err := containers.Stop(p.connCtx, containerID, nil)
+func (p *PodmanRuntime) StopContainer(ctx context.Context, containerID string, timeout *int) error {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	stopOpts := new(containers.StopOptions).WithTimeout(uint(stopTimeout(timeout)))
+	err := containers.Stop(callCtx, containerID, stopOpts)
 	if err != nil {
 		return fmt.Errorf("failed to stop Podman container %s: %w", containerID, err)
 	}
@@ -340,18 +415,87 @@ func (p *PodmanRuntime) StopContainer(ctx context.Context, containerID string) e
 }
 
 // RestartContainer restarts a Podman container
-func (p *PodmanRuntime) RestartContainer(ctx context.Context, containerID string) error {
-	err := containers.Restart(p.connCtx, containerID, nil)
+func (p *PodmanRuntime) RestartContainer(ctx context.Context, containerID string, timeout *int) error {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	restartOpts := new(containers.RestartOptions).WithTimeout(stopTimeout(timeout))
+	err := containers.Restart(callCtx, containerID, restartOpts)
 	if err != nil {
 		return fmt.Errorf("failed to restart Podman container %s: %w", containerID, err)
 	}
 	return nil
 }
 
+// UpdateContainerResources changes the cgroup limits and restart policy of a Podman container in place
+func (p *PodmanRuntime) UpdateContainerResources(ctx context.Context, containerID string, update models.ResourceUpdate) error {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	if err := ValidateResourceUpdate(update); err != nil {
+		return err
+	}
+
+	updateOpts := &types.ContainerUpdateOptions{NameOrID: containerID}
+	if update.Memory > 0 || update.CPUs > 0 {
+		resources := &spec.LinuxResources{}
+		if update.Memory > 0 {
+			limit := update.Memory
+			resources.Memory = &spec.LinuxMemory{Limit: &limit}
+		}
+		if update.CPUs > 0 {
+			quota := cpuQuota(update.CPUs)
+			period := uint64(cpuPeriod)
+			resources.CPU = &spec.LinuxCPU{Quota: &quota, Period: &period}
+		}
+		updateOpts.Resources = resources
+	}
+	if update.RestartPolicy != "" {
+		name, retries, _ := parseRestartPolicy(update.RestartPolicy)
+		updateOpts.RestartPolicy = &name
+		if retries > 0 {
+			count := uint(retries)
+			updateOpts.RestartRetries = &count
+		}
+	}
+
+	if _, err := containers.Update(callCtx, updateOpts); err != nil {
+		return fmt.Errorf("failed to update Podman container %s: %w", containerID, err)
+	}
+	return nil
+}
+
+// KillContainer sends a signal to a Podman container
+func (p *PodmanRuntime) KillContainer(ctx context.Context, containerID string, signal string) error {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	signal, err := NormalizeSignal(signal)
+	if err != nil {
+		return err
+	}
+
+	inspectData, err := containers.Inspect(callCtx, containerID, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return fmt.Errorf("failed to inspect Podman container %s: %w", containerID, err)
+	}
+	if inspectData.State == nil || !inspectData.State.Running {
+		return ErrContainerNotRunning
+	}
+
+	if err := containers.Kill(callCtx, containerID, new(containers.KillOptions).WithSignal(signal)); err != nil {
+		return fmt.Errorf("failed to kill Podman container %s: %w", containerID, err)
+	}
+	return nil
+}
+
 // DeletePod deletes a Podman pod
 func (p *PodmanRuntime) DeletePod(ctx context.Context, podID string, force bool) error {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
 	removeOpts := new(pods.RemoveOptions).WithForce(force)
-	_, err := pods.Remove(p.connCtx, podID, removeOpts)
+	_, err := pods.Remove(callCtx, podID, removeOpts)
 	if err != nil {
 		return fmt.Errorf("failed to delete Podman pod %s: %w", podID, err)
 	}
@@ -360,16 +504,74 @@ func (p *PodmanRuntime) DeletePod(ctx context.Context, podID string, force bool)
 
 // StartPod starts a Podman pod
 func (p *PodmanRuntime) StartPod(ctx context.Context, podID string) error {
-	_, err := pods.Start(p.connCtx, podID, nil)
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	_, err := pods.Start(callCtx, podID, nil)
 	if err != nil {
 		return fmt.Errorf("failed to start Podman pod %s: %w", podID, err)
 	}
 	return nil
 }
 
+// PodStats samples the stats of all containers of a Podman pod and sums them up.
+// The pod stats API only reports preformatted strings, so the containers are sampled like in ListContainers.
+func (p *PodmanRuntime) PodStats(ctx context.Context, podID string) (models.PodStats, error) {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+	report, err := pods.Inspect(callCtx, podID, nil)
+	if err != nil {
+		if podmanNotFound(err) {
+			return models.PodStats{}, fmt.Errorf("%w: %s", ErrPodNotFound, podID)
+		}
+		return models.PodStats{}, fmt.Errorf("failed to inspect Podman pod %s: %w", podID, err)
+	}
+
+	containerIDs := make([]string, 0, len(report.Containers))
+	for _, c := range report.Containers {
+		if c.State == "running" {
+			containerIDs = append(containerIDs, c.ID)
+		}
+	}
+	stats := p.containerStats(containerIDs)
+
+	result := models.PodStats{PodID: report.ID, PodName: report.Name, Containers: make([]models.PodContainerStats, 0, len(report.Containers))}
+	for _, c := range report.Containers {
+		result.Containers = append(result.Containers, models.PodContainerStats{ID: c.ID, Name: c.Name, State: c.State, Stats: stats[c.ID]})
+	}
+	result.Total = sumPodStats(result.Containers)
+	return result, nil
+}
+
+// sumPodStats adds up the stats of the containers of a pod. The containers share the memory of the
+// host unless limited, so the largest container limit is used as the pod limit instead of their sum.
+func sumPodStats(containers []models.PodContainerStats) models.ContainerStats {
+	var total models.ContainerStats
+	for _, c := range containers {
+		if c.Stats == nil {
+			continue
+		}
+		total.CPUPercent += c.Stats.CPUPercent
+		total.MemoryUsage += c.Stats.MemoryUsage
+		total.MemoryLimit = max(total.MemoryLimit, c.Stats.MemoryLimit)
+		total.NetworkRx += c.Stats.NetworkRx
+		total.NetworkTx += c.Stats.NetworkTx
+		total.BlockRead += c.Stats.BlockRead
+		total.BlockWrite += c.Stats.BlockWrite
+		total.PIDs += c.Stats.PIDs
+	}
+	if total.MemoryLimit > 0 {
+		total.MemoryPercent = float64(total.MemoryUsage) / float64(total.MemoryLimit) * 100
+	}
+	return total
+}
+
 // StopPod stops a Podman pod
 func (p *PodmanRuntime) StopPod(ctx context.Context, podID string) error {
-	_, err := pods.Stop(p.connCtx, podID, nil)
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	_, err := pods.Stop(callCtx, podID, nil)
 	if err != nil {
 		return fmt.Errorf("failed to stop Podman pod %s: %w", podID, err)
 	}
@@ -378,7 +580,10 @@ func (p *PodmanRuntime) StopPod(ctx context.Context, podID string) error {
 
 // RestartPod restarts a Podman pod
 func (p *PodmanRuntime) RestartPod(ctx context.Context, podID string) error {
-	_, err := pods.Restart(p.connCtx, podID, nil)
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	_, err := pods.Restart(callCtx, podID, nil)
 	if err != nil {
 		return fmt.Errorf("failed to restart Podman pod %s: %w", podID, err)
 	}
@@ -386,7 +591,7 @@ func (p *PodmanRuntime) RestartPod(ctx context.Context, podID string) error {
 }
 
 // BuildFromDockerfile builds a Podman image from a Dockerfile
-func (p *PodmanRuntime) BuildFromDockerfile(ctx context.Context, dockerfile, imageName string) error {
+func (p *PodmanRuntime) BuildFromDockerfile(ctx context.Context, dockerfile, imageName string, opts models.BuildOptions) error {
 	// Create a temporary directory for the build context
 	tempDir, err := os.MkdirTemp("", "podman-build-*")
 	if err != nil {
@@ -400,15 +605,44 @@ func (p *PodmanRuntime) BuildFromDockerfile(ctx context.Context, dockerfile, ima
 		return fmt.Errorf("failed to write Dockerfile: %w", err)
 	}
 
+	return p.buildFromDirectory(tempDir, dockerfilePath, imageName, opts)
+}
+
+// BuildFromContext builds a Podman image from a tar archive of a build context
+func (p *PodmanRuntime) BuildFromContext(ctx context.Context, contextTar io.Reader, dockerfile, imageName string, opts models.BuildOptions) error {
+	// Extract the build context into a temporary directory
+	tempDir, err := os.MkdirTemp("", "podman-build-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := archive.Untar(contextTar, tempDir, &archive.TarOptions{NoLchown: true}); err != nil {
+		return fmt.Errorf("failed to extract build context: %w", err)
+	}
+
+	// The Dockerfile is read on this host, it must not escape the build context
+	dockerfilePath, err := resolveContextDockerfile(tempDir, dockerfile)
+	if err != nil {
+		return err
+	}
+
+	return p.buildFromDirectory(tempDir, dockerfilePath, imageName, opts)
+}
+
+// buildFromDirectory builds an image using contextDir as the build context
+func (p *PodmanRuntime) buildFromDirectory(contextDir, dockerfilePath, imageName string, opts models.BuildOptions) error {
 	// Build the image using bindings
 	buildOptions := types.BuildOptions{
 		ContainerFiles: []string{dockerfilePath},
 	}
 	// Set the context directory and output tag using the embedded buildahDefine.BuildOptions
-	buildOptions.ContextDirectory = tempDir
+	buildOptions.ContextDirectory = contextDir
 	buildOptions.Output = imageName
+	buildOptions.Args = opts.BuildArgs
+	buildOptions.Target = opts.Target
 
-	_, err = images.Build(p.connCtx, []string{dockerfilePath}, buildOptions)
+	_, err := images.Build(p.connCtx, []string{dockerfilePath}, buildOptions)
 	if err != nil {
 		return fmt.Errorf("failed to build Podman image: %w", err)
 	}
@@ -416,12 +650,117 @@ func (p *PodmanRuntime) BuildFromDockerfile(ctx context.Context, dockerfile, ima
 	return nil
 }
 
+// podmanPortMappings converts "[host:]container[/protocol]" port mappings to Podman port mappings.
+// A host port of 0 lets Podman pick a random one. Podman cannot pick a host port
+// from a range, so a host range needs a container range of the same length.
+func podmanPortMappings(specs []string) ([]nettypes.PortMapping, error) {
+	portMappings := make([]nettypes.PortMapping, 0, len(specs))
+	for _, spec := range specs {
+		ps, err := parsePortSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		if ps.hostPortCount() > 1 && ps.containerPortCount() == 1 {
+			return nil, fmt.Errorf("%w %q: Podman does not support host port ranges for a single container port", ErrInvalidPort, spec)
+		}
+		portMappings = append(portMappings, nettypes.PortMapping{
+			HostPort:      ps.HostPort,
+			ContainerPort: ps.ContainerPort,
+			Range:         uint16(ps.containerPortCount()),
+			Protocol:      ps.Protocol,
+		})
+	}
+	return portMappings, nil
+}
+
+// ContainerPorts returns the host ports a Podman container is published on
+func (p *PodmanRuntime) ContainerPorts(ctx context.Context, containerID string) ([]models.PortMapping, error) {
+	inspectData, err := containers.Inspect(p.connCtx, containerID, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Podman container %s: %w", containerID, err)
+	}
+
+	bindings := make(map[string][]string)
+	if inspectData.NetworkSettings != nil {
+		for port, hostPorts := range inspectData.NetworkSettings.Ports {
+			for _, hostPort := range hostPorts {
+				bindings[port] = append(bindings[port], hostPort.HostPort)
+			}
+		}
+	}
+	return portMappingsFromBindings(bindings), nil
+}
+
 // RunContainer creates and runs a container from an image with configuration
 func (p *PodmanRuntime) RunContainer(ctx context.Context, req models.RunContainerRequest) (string, error) {
+	s, err := podmanRunSpec(req)
+	if err != nil {
+		return "", err
+	}
+
+	if !req.NoPull {
+		if err := p.ensureImage(ctx, req.Image, req.Auth); err != nil {
+			return "", err
+		}
+	}
+
+	// Create the container
+	createResp, err := containers.CreateWithSpec(p.connCtx, s, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create container: %w", err)
+	}
+
+	// Start the container
+	if err := containers.Start(p.connCtx, createResp.ID, nil); err != nil {
+		// Try to remove the container if start fails
+		if _, removeErr := containers.Remove(p.connCtx, createResp.ID, new(containers.RemoveOptions).WithForce(true)); removeErr != nil {
+			logger.Warn("RunContainer: Failed to cleanup container after start failure", "containerID", createResp.ID, "error", removeErr)
+		}
+		return "", fmt.Errorf("failed to start container: %w", err)
+	}
+
+	return createResp.ID, nil
+}
+
+// podmanRunSpec returns the spec generator for a run request
+func podmanRunSpec(req models.RunContainerRequest) (*specgen.SpecGenerator, error) {
 	// Create a spec generator for the container
 	s := specgen.NewSpecGenerator(req.Image, false)
 	s.Name = req.Name
 
+	if req.AutoRemove {
+		remove := true
+		s.Remove = &remove
+	}
+	if req.Privileged {
+		privileged := true
+		s.Privileged = &privileged
+	}
+	if req.ReadOnly {
+		readOnly := true
+		s.ReadOnlyFilesystem = &readOnly
+	}
+	s.WorkDir = req.WorkingDir
+	s.Entrypoint = req.Entrypoint
+	s.Command = req.Command
+	if err := validateUser(req.User); err != nil {
+		return nil, err
+	}
+	s.User = req.User
+	s.Hostname = req.Hostname
+	if err := applyPodmanSecurityOpts(s, req.SecurityOpt); err != nil {
+		return nil, err
+	}
+
+	devices, err := podmanDevices(req.Devices)
+	if err != nil {
+		return nil, err
+	}
+	s.Devices = devices
+	if s.HostAdd, err = extraHosts(req.ExtraHosts); err != nil {
+		return nil, err
+	}
+
 	// Add restart policy
 	if req.RestartPolicy != "" {
 		s.RestartPolicy = req.RestartPolicy
@@ -429,35 +768,9 @@ func (p *PodmanRuntime) RunContainer(ctx context.Context, req models.RunContaine
 
 	// Add port mappings
 	if len(req.Ports) > 0 {
-		portMappings := make([]nettypes.PortMapping, 0, len(req.Ports))
-		for _, portMap := range req.Ports {
-			// Parse port mapping (format: "hostPort:containerPort" or "hostPort:containerPort/protocol")
-			parts := strings.Split(portMap, ":")
-			if len(parts) >= 2 {
-				hostPortStr := parts[0]
-				containerPortProto := parts[1]
-
-				// Parse container port and protocol
-				protocol := "tcp"
-				containerPortStr := containerPortProto
-				if strings.Contains(containerPortProto, "/") {
-					cpParts := strings.Split(containerPortProto, "/")
-					containerPortStr = cpParts[0]
-					if len(cpParts) > 1 {
-						protocol = cpParts[1]
-					}
-				}
-
-				// Convert to uint16
-				hostPort, _ := strconv.ParseUint(hostPortStr, 10, 16)
-				containerPort, _ := strconv.ParseUint(containerPortStr, 10, 16)
-
-				portMappings = append(portMappings, nettypes.PortMapping{
-					HostPort:      uint16(hostPort),
-					ContainerPort: uint16(containerPort),
-					Protocol:      protocol,
-				})
-			}
+		portMappings, err := podmanPortMappings(req.Ports)
+		if err != nil {
+			return nil, err
 		}
 		s.PortMappings = portMappings
 	}
@@ -493,6 +806,11 @@ func (p *PodmanRuntime) RunContainer(ctx context.Context, req models.RunContaine
 	if len(volumes) > 0 {
 		s.Volumes = volumes
 	}
+	tmpfsMounts, err := podmanTmpfsMounts(req.Tmpfs)
+	if err != nil {
+		return nil, err
+	}
+	mounts = append(mounts, tmpfsMounts...)
 	if len(mounts) > 0 {
 		s.Mounts = mounts
 	}
@@ -509,26 +827,11 @@ func (p *PodmanRuntime) RunContainer(ctx context.Context, req models.RunContaine
 		s.Env = envVars
 	}
 
-	// Create the container
-	createResp, err := containers.CreateWithSpec(p.connCtx, s, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to create container: %w", err)
-	}
-
-	// Start the container
-	if err := containers.Start(p.connCtx, createResp.ID, nil); err != nil {
-		// Try to remove the container if start fails
-		if _, removeErr := containers.Remove(p.connCtx, createResp.ID, new(containers.RemoveOptions).WithForce(true)); removeErr != nil {
-			logger.Warn("RunContainer: Failed to cleanup container after start failure", "containerID", createResp.ID, "error", removeErr)
-		}
-		return "", fmt.Errorf("failed to start container: %w", err)
-	}
-
-	return createResp.ID, nil
+	return s, nil
 }
 
 // DeployFromCompose deploys containers from a Podman Compose file
-func (p *PodmanRuntime) DeployFromCompose(ctx context.Context, composeContent, projectName, deploymentPath string) error {
+func (p *PodmanRuntime) DeployFromCompose(ctx context.Context, composeContent, projectName, deploymentPath string, env map[string]string) error {
 	// Use deployment path if provided, otherwise use temp directory
 	var composePath string
 	var cleanupFunc func()
@@ -578,6 +881,15 @@ func (p *PodmanRuntime) DeployFromCompose(ctx context.Context, composeContent, p
 	if err := os.WriteFile(composePath, []byte(composeContent), 0644); err != nil {
 		return fmt.Errorf("failed to write compose file: %w", err)
 	}
+	if err := writeComposeDotEnv(filepath.Dir(composePath), env); err != nil {
+		return err
+	}
+
+	// Validate the file before handing it to podman-compose, which reports errors less clearly
+	project, err := loadComposeProject(ctx, composePath, projectName, env)
+	if err != nil {
+		return err
+	}
 
 	// Use podman-compose if available
 	if _, err := exec.LookPath("podman-compose"); err == nil {
@@ -589,94 +901,804 @@ func (p *PodmanRuntime) DeployFromCompose(ctx context.Context, composeContent, p
 		args = append(args, "up", "-d")
 
 		cmd := exec.CommandContext(ctx, "podman-compose", args...)
+		cmd.Env = composeCommandEnv(env)
 		if output, err := cmd.CombinedOutput(); err != nil {
 			return fmt.Errorf("failed to deploy with podman-compose: %w, output: %s", err, string(output))
 		}
+		if deploymentPath != "" {
+			return writeDeploymentManifest(deploymentPath, p.GetRuntimeName(), project.Name, project.ServiceNames())
+		}
 		return nil
 	}
 
 	return fmt.Errorf("podman-compose not found in PATH")
 }
 
-// PullImage pulls the latest version of a Podman image
-func (p *PodmanRuntime) PullImage(ctx context.Context, imageName string) error {
-	pullOpts := new(images.PullOptions)
-	_, err := images.Pull(p.connCtx, imageName, pullOpts)
-	if err != nil {
-		return fmt.Errorf("failed to pull Podman image %s: %w", imageName, err)
+// RemoveComposeDeployment runs podman-compose down for the compose file in the deployment directory
+func (p *PodmanRuntime) RemoveComposeDeployment(ctx context.Context, projectName, deploymentPath string, removeVolumes bool) error {
+	if _, err := exec.LookPath("podman-compose"); err != nil {
+		return fmt.Errorf("podman-compose not found in PATH")
 	}
-	return nil
-}
 
-// UpdateContainer updates a Podman container by pulling the latest image and recreating it
-func (p *PodmanRuntime) UpdateContainer(ctx context.Context, containerID string) error {
-	// Inspect the container to get its configuration
-	inspectData, err := containers.Inspect(p.connCtx, containerID, new(containers.InspectOptions).WithSize(false))
-	if err != nil {
-		return fmt.Errorf("failed to inspect container: %w", err)
+	args := []string{"-f", filepath.Join(deploymentPath, "docker-compose.yml")}
+	if projectName != "" {
+		args = append(args, "-p", projectName)
 	}
-
-	imageName := inspectData.ImageName
-	containerName := inspectData.Name
-
-	// Pull the latest image
-	if err := p.PullImage(ctx, imageName); err != nil {
-		return err
+	args = append(args, "down")
+	if removeVolumes {
+		args = append(args, "-v")
 	}
 
-	// Stop the container
-	if err := containers.Stop(p.connCtx, containerID, nil); err != nil {
-		return fmt.Errorf("failed to stop container: %w", err)
+	cmd := exec.CommandContext(ctx, "podman-compose", args...)
+	cmd.Dir = deploymentPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove deployment with podman-compose: %w, output: %s", err, string(output))
 	}
+	return nil
+}
 
-	// Remove the old container
-	if err := p.DeleteContainer(ctx, containerID, true); err != nil {
+// ComposeScale scales a service of a Podman compose deployment with podman-compose
+func (p *PodmanRuntime) ComposeScale(ctx context.Context, projectName, deploymentPath, service string, replicas int) error {
+	args, err := composeScaleArgs(ctx, projectName, deploymentPath, service, replicas)
+	if err != nil {
 		return err
 	}
+	if _, err := exec.LookPath("podman-compose"); err != nil {
+		return fmt.Errorf("podman-compose not found in PATH")
+	}
 
-	// Create and start a new container with the same configuration
-	// Note: This is simplified - ideally we'd preserve all original settings
-	s := specgen.NewSpecGenerator(imageName, false)
-	s.Name = containerName
+	cmd := exec.CommandContext(ctx, "podman-compose", args...)
+	cmd.Dir = deploymentPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to scale deployment with podman-compose: %w, output: %s", err, string(output))
+	}
+	return nil
+}
 
-	createResp, err := containers.CreateWithSpec(p.connCtx, s, nil)
+// ensureImage pulls an image that is not present locally
+func (p *PodmanRuntime) ensureImage(ctx context.Context, imageName string, auth *models.RegistryAuth) error {
+	exists, err := images.Exists(p.connCtx, imageName, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create new container: %w", err)
+		return fmt.Errorf("failed to check Podman image %s: %w", imageName, err)
 	}
-
-	if err := containers.Start(p.connCtx, createResp.ID, nil); err != nil {
-		return fmt.Errorf("failed to start new container: %w", err)
+	if exists {
+		return nil
 	}
 
-	return nil
+	logger.Info("PodmanRuntime: Pulling missing image", "image", imageName, "authenticated", auth != nil)
+	return p.PullImage(ctx, imageName, auth)
 }
 
-// StreamLogs streams logs from a Podman container
-func (p *PodmanRuntime) StreamLogs(ctx context.Context, containerID string, follow bool, tail string) (io.ReadCloser, error) {
-	// Buffer size for log channels
-	const logChannelBufferSize = 100
+// podmanPullOptions returns the pull options authenticating with auth when it is non-nil
+func podmanPullOptions(auth *models.RegistryAuth) *images.PullOptions {
+	pullOpts := new(images.PullOptions)
+	if auth != nil {
+		username, password := podmanCredentials(auth)
+		if username != "" {
+			pullOpts.WithUsername(username)
+		}
+		if password != "" {
+			pullOpts.WithPassword(password)
+		}
+	}
+	return pullOpts
+}
 
-	// Create a pipe for the logs
-	pr, pw := io.Pipe()
+// PullImage pulls the latest version of a Podman image
+func (p *PodmanRuntime) PullImage(ctx context.Context, imageName string, auth *models.RegistryAuth) error {
+	_, err := images.Pull(p.connCtx, imageName, podmanPullOptions(auth))
+	if err != nil {
+		return fmt.Errorf("failed to pull Podman image %s: %w", imageName, err)
+	}
+	return nil
+}
 
-	// Create channels for stdout and stderr
-	stdoutChan := make(chan string, logChannelBufferSize)
-	stderrChan := make(chan string, logChannelBufferSize)
+// PullImageStream pulls the latest version of a Podman image and reports its progress.
+// Podman only reports status lines like "Copying blob ...", so the updates carry no layer ID or byte counts.
+func (p *PodmanRuntime) PullImageStream(ctx context.Context, imageName string, auth *models.RegistryAuth) (<-chan models.PullProgress, error) {
+	// The bindings take the connection context, cancel the pull together with ctx
+	pullCtx, cancel := context.WithCancel(p.connCtx)
+	stop := context.AfterFunc(ctx, cancel)
 
-	// Prepare log options
-	logOpts := new(containers.LogOptions).WithFollow(follow).WithTimestamps(true)
-	if tail != "" && tail != "all" {
-		logOpts.WithTail(tail)
-	}
+	reader, writer := io.Pipe()
+	var progressWriter io.Writer = writer
+	pullOpts := podmanPullOptions(auth).WithProgressWriter(progressWriter)
 
-	// Start goroutine to receive logs and write to pipe
 	go func() {
-		defer pw.Close()
-		defer close(stdoutChan)
-		defer close(stderrChan)
+		_, err := images.Pull(pullCtx, imageName, pullOpts)
+		if err != nil {
+			err = fmt.Errorf("failed to pull Podman image %s: %w", imageName, err)
+		}
+		writer.CloseWithError(err)
+	}()
 
-		// Start logging in a goroutine
-		errChan := make(chan error, 1)
+	result := make(chan models.PullProgress, pullProgressBufferSize)
+	go func() {
+		defer close(result)
+		defer stop()
+		defer cancel()
+		defer reader.Close()
+
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			if !sendPullProgress(ctx, result, models.PullProgress{Status: line}) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			sendPullProgress(ctx, result, models.PullProgress{Error: err.Error()})
+		}
+	}()
+
+	return result, nil
+}
+
+// ImageHistory returns the layers of a Podman image, newest first
+func (p *PodmanRuntime) ImageHistory(ctx context.Context, imageName string) ([]models.ImageLayer, error) {
+	history, err := images.History(p.connCtx, imageName, nil)
+	if err != nil {
+		if podmanNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrImageNotFound, imageName)
+		}
+		return nil, fmt.Errorf("failed to get history of Podman image %s: %w", imageName, err)
+	}
+
+	layers := make([]models.ImageLayer, 0, len(history))
+	for _, item := range history {
+		layers = append(layers, models.ImageLayer{
+			ID:        item.ID,
+			CreatedBy: item.CreatedBy,
+			Size:      item.Size,
+			CreatedAt: time.Unix(item.Created, 0),
+			Tags:      item.Tags,
+			Comment:   item.Comment,
+		})
+	}
+	return layers, nil
+}
+
+// podmanNotFound reports whether err is a 404 response of the Podman API
+func podmanNotFound(err error) bool {
+	var model *errorhandling.ErrorModel
+	return errors.As(err, &model) && model.ResponseCode == http.StatusNotFound
+}
+
+// TagImage tags a Podman image with a new reference
+func (p *PodmanRuntime) TagImage(ctx context.Context, source, target string) error {
+	repo, tag := splitImageReference(target)
+	if err := images.Tag(p.connCtx, source, tag, repo, nil); err != nil {
+		return fmt.Errorf("failed to tag Podman image %s as %s: %w", source, target, err)
+	}
+	return nil
+}
+
+// PushImage pushes a Podman image to its registry and returns the manifest digest
+func (p *PodmanRuntime) PushImage(ctx context.Context, imageName string, auth *models.RegistryAuth) (string, error) {
+	pushOpts := new(images.PushOptions).WithQuiet(true)
+	if auth != nil {
+		username, password := podmanCredentials(auth)
+		if username != "" {
+			pushOpts.WithUsername(username)
+		}
+		if password != "" {
+			pushOpts.WithPassword(password)
+		}
+	}
+
+	if err := images.Push(p.connCtx, imageName, imageName, pushOpts); err != nil {
+		return "", fmt.Errorf("failed to push Podman image %s: %w", imageName, err)
+	}
+
+	return pushOpts.GetManifestDigest(), nil
+}
+
+// splitImageReference splits an image reference into repository and tag, defaulting the tag to "latest"
+func splitImageReference(ref string) (string, string) {
+	// Only a colon after the last slash separates the tag (a colon before it belongs to a registry port)
+	lastSlash := strings.LastIndex(ref, "/")
+	if idx := strings.LastIndex(ref, ":"); idx > lastSlash {
+		return ref[:idx], ref[idx+1:]
+	}
+	return ref, "latest"
+}
+
+// podmanCredentials returns the username and password to send to Podman.
+// Podman only accepts username/password, so a token is sent as the password.
+func podmanCredentials(auth *models.RegistryAuth) (string, string) {
+	password := auth.Password
+	if password == "" {
+		password = auth.Token
+	}
+	return auth.Username, password
+}
+
+// CheckImageUpdate pulls the image of a Podman container and compares it with the running image
+func (p *PodmanRuntime) CheckImageUpdate(ctx context.Context, containerID string, auth RegistryAuthLookup) (bool, error) {
+	inspectData, err := containers.Inspect(p.connCtx, containerID, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	imageName := inspectData.ImageName
+	if err := p.PullImage(ctx, imageName, auth.forImage(imageName)); err != nil {
+		return false, err
+	}
+
+	latest, err := images.GetImage(p.connCtx, imageName, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect Podman image %s: %w", imageName, err)
+	}
+
+	return latest.ID != inspectData.Image, nil
+}
+
+// UpdateContainer updates a Podman container by pulling the latest image and recreating it.
+// The old container is kept until the new one has been running for the grace period,
+// and restored if the new container fails.
+func (p *PodmanRuntime) UpdateContainer(ctx context.Context, containerID string, auth RegistryAuthLookup) error {
+	// Inspect the container to get its configuration
+	inspectData, err := containers.Inspect(p.connCtx, containerID, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	imageName := inspectData.ImageName
+
+	// Pull the latest image
+	if err := p.PullImage(ctx, imageName, auth.forImage(imageName)); err != nil {
+		return err
+	}
+
+	replacer := newPodmanReplacer(p, inspectData)
+	return replaceContainer(ctx, replacer, containerID, inspectData.Name, p.updateGracePeriod)
+}
+
+// RecreateContainerWithLabels recreates a Podman container with changed labels, as the Podman API
+// cannot update them in place. A running container is replaced like on updates and restarted,
+// a stopped one is replaced without starting it. It returns the ID of the new container.
+func (p *PodmanRuntime) RecreateContainerWithLabels(ctx context.Context, containerID string, set map[string]string, remove []string) (string, error) {
+	inspectData, err := containers.Inspect(p.connCtx, containerID, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect Podman container %s: %w", containerID, err)
+	}
+
+	if inspectData.Config != nil {
+		config := *inspectData.Config
+		config.Labels = applyLabelChanges(inspectData.Config.Labels, set, remove)
+		inspectData.Config = &config
+	}
+
+	name := inspectData.Name
+	replacer := newPodmanReplacer(p, inspectData)
+	if inspectData.State != nil && inspectData.State.Running {
+		err = replaceContainer(ctx, replacer, inspectData.ID, name, p.updateGracePeriod)
+	} else {
+		err = replaceStoppedContainer(ctx, replacer, inspectData.ID, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to recreate Podman container %s: %w", name, err)
+	}
+
+	recreated, err := containers.Inspect(p.connCtx, name, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect recreated Podman container %s: %w", name, err)
+	}
+	logger.Info("PodmanRuntime.RecreateContainerWithLabels: Recreated container", "name", name, "old_id", inspectData.ID, "new_id", recreated.ID)
+	return recreated.ID, nil
+}
+
+// podmanReplacer implements containerReplacer for Podman
+type podmanReplacer struct {
+	p           *PodmanRuntime
+	inspect     *define.InspectContainerData // The original container, inspected before it was renamed
+	stopTimeout int                          // Seconds the old container gets to stop before it is killed
+}
+
+// newPodmanReplacer returns a replacer for the inspected container, which is stopped
+// with the container's own stop timeout or DefaultStopTimeout
+func newPodmanReplacer(p *PodmanRuntime, data *define.InspectContainerData) *podmanReplacer {
+	var timeout *int
+	if data.Config != nil && data.Config.StopTimeout > 0 {
+		seconds := int(data.Config.StopTimeout)
+		timeout = &seconds
+	}
+	return &podmanReplacer{p: p, inspect: data, stopTimeout: stopTimeout(timeout)}
+}
+
+func (r *podmanReplacer) stop(ctx context.Context, id string) error {
+	return containers.Stop(r.p.connCtx, id, new(containers.StopOptions).WithTimeout(uint(r.stopTimeout)))
+}
+
+func (r *podmanReplacer) rename(ctx context.Context, id, name string) error {
+	return containers.Rename(r.p.connCtx, id, new(containers.RenameOptions).WithName(name))
+}
+
+func (r *podmanReplacer) create(ctx context.Context, name string) (string, error) {
+	s, err := podmanCloneSpec(r.inspect, name)
+	if err != nil {
+		return "", err
+	}
+
+	createResp, err := containers.CreateWithSpec(r.p.connCtx, s, nil)
+	if err != nil {
+		return "", err
+	}
+	return createResp.ID, nil
+}
+
+// podmanCloneSpec returns the spec of a container with the settings of the inspected one, created
+// from the same image reference so that a freshly pulled image is used
+func podmanCloneSpec(data *define.InspectContainerData, name string) (*specgen.SpecGenerator, error) {
+	s := specgen.NewSpecGenerator(data.ImageName, false)
+	s.Name = name
+
+	if config := data.Config; config != nil {
+		s.Labels = config.Labels
+		s.Annotations = config.Annotations
+		s.Command = config.Cmd
+		s.Entrypoint = config.Entrypoint
+		s.WorkDir = config.WorkingDir
+		s.User = config.User
+		s.HealthConfig = config.Healthcheck
+		if len(config.Env) > 0 {
+			s.Env = make(map[string]string, len(config.Env))
+			for _, env := range config.Env {
+				key, value, _ := strings.Cut(env, "=")
+				s.Env[key] = value
+			}
+		}
+		if config.StopTimeout > 0 {
+			stopTimeout := config.StopTimeout
+			s.StopTimeout = &stopTimeout
+		}
+		// Podman defaults the hostname to the short container ID, which the new container must not inherit
+		if config.Hostname != "" && !strings.HasPrefix(data.ID, config.Hostname) {
+			s.Hostname = config.Hostname
+		}
+	}
+
+	for _, m := range data.Mounts {
+		options := slices.Clone(m.Options)
+		if !m.RW && !slices.Contains(options, "ro") {
+			options = append(options, "ro")
+		}
+		if m.Type == "volume" {
+			s.Volumes = append(s.Volumes, &specgen.NamedVolume{Name: m.Name, Dest: m.Destination, Options: options})
+			continue
+		}
+		s.Mounts = append(s.Mounts, spec.Mount{Type: m.Type, Source: m.Source, Destination: m.Destination, Options: options})
+	}
+
+	if data.Pod != "" {
+		// Networking and published ports belong to the pod
+		s.Pod = data.Pod
+	} else if err := applyPodmanCloneNetwork(s, data); err != nil {
+		return nil, err
+	}
+
+	host := data.HostConfig
+	if host == nil {
+		return s, nil
+	}
+	if host.RestartPolicy != nil && host.RestartPolicy.Name != "" && host.RestartPolicy.Name != "no" {
+		s.RestartPolicy = host.RestartPolicy.Name
+		if host.RestartPolicy.MaximumRetryCount > 0 {
+			retries := host.RestartPolicy.MaximumRetryCount
+			s.RestartRetries = &retries
+		}
+	}
+	if host.Privileged {
+		s.Privileged = &host.Privileged
+	}
+	if host.ReadonlyRootfs {
+		s.ReadOnlyFilesystem = &host.ReadonlyRootfs
+	}
+	s.CapAdd = host.CapAdd
+	s.CapDrop = host.CapDrop
+	s.HostAdd = host.ExtraHosts
+	for _, device := range host.Devices {
+		s.Devices = append(s.Devices, spec.LinuxDevice{Path: device.PathOnHost + ":" + device.PathInContainer})
+	}
+	if host.Memory > 0 || host.NanoCpus > 0 {
+		s.ResourceLimits = &spec.LinuxResources{}
+		if host.Memory > 0 {
+			s.ResourceLimits.Memory = &spec.LinuxMemory{Limit: &host.Memory}
+		}
+		if host.NanoCpus > 0 {
+			period := uint64(100000)
+			quota := host.NanoCpus * int64(period) / 1e9
+			s.ResourceLimits.CPU = &spec.LinuxCPU{Period: &period, Quota: &quota}
+		}
+	}
+	return s, nil
+}
+
+// applyPodmanCloneNetwork sets the network mode, networks and published ports of the inspected container on s
+func applyPodmanCloneNetwork(s *specgen.SpecGenerator, data *define.InspectContainerData) error {
+	if host := data.HostConfig; host != nil {
+		for port, bindings := range host.PortBindings {
+			containerPort, protocol, _ := strings.Cut(port, "/")
+			cport, err := strconv.ParseUint(containerPort, 10, 16)
+			if err != nil {
+				return fmt.Errorf("invalid port %q: %w", port, err)
+			}
+			for _, binding := range bindings {
+				hport, err := strconv.ParseUint(binding.HostPort, 10, 16)
+				if err != nil {
+					return fmt.Errorf("invalid host port %q: %w", binding.HostPort, err)
+				}
+				s.PortMappings = append(s.PortMappings, nettypes.PortMapping{
+					HostIP:        binding.HostIP,
+					HostPort:      uint16(hport),
+					ContainerPort: uint16(cport),
+					Protocol:      protocol,
+				})
+			}
+		}
+		sort.Slice(s.PortMappings, func(i, j int) bool { return s.PortMappings[i].ContainerPort < s.PortMappings[j].ContainerPort })
+
+		mode, value, _ := strings.Cut(host.NetworkMode, ":")
+		switch mode {
+		case "host":
+			s.NetNS = specgen.Namespace{NSMode: specgen.Host}
+			return nil
+		case "none":
+			s.NetNS = specgen.Namespace{NSMode: specgen.NoNetwork}
+			return nil
+		case "container":
+			s.NetNS = specgen.Namespace{NSMode: specgen.FromContainer, Value: value}
+			return nil
+		case "slirp4netns":
+			s.NetNS = specgen.Namespace{NSMode: specgen.Slirp}
+			return nil
+		case "pasta":
+			s.NetNS = specgen.Namespace{NSMode: specgen.Pasta}
+			return nil
+		}
+	}
+
+	if data.NetworkSettings == nil || len(data.NetworkSettings.Networks) == 0 {
+		return nil
+	}
+	s.NetNS = specgen.Namespace{NSMode: specgen.Bridge}
+	s.Networks = make(map[string]nettypes.PerNetworkOptions, len(data.NetworkSettings.Networks))
+	for networkName, settings := range data.NetworkSettings.Networks {
+		var options nettypes.PerNetworkOptions
+		if settings != nil {
+			// Podman adds the short container ID as an alias itself
+			for _, alias := range settings.Aliases {
+				if !strings.HasPrefix(data.ID, alias) {
+					options.Aliases = append(options.Aliases, alias)
+				}
+			}
+		}
+		s.Networks[networkName] = options
+	}
+	return nil
+}
+
+func (r *podmanReplacer) start(ctx context.Context, id string) error {
+	return containers.Start(r.p.connCtx, id, nil)
+}
+
+func (r *podmanReplacer) healthy(ctx context.Context, id string) (bool, error) {
+	inspectData, err := containers.Inspect(r.p.connCtx, id, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return false, err
+	}
+	return inspectData.State != nil && inspectData.State.Running && !inspectData.State.Restarting && inspectData.RestartCount == 0, nil
+}
+
+func (r *podmanReplacer) remove(ctx context.Context, id string) error {
+	return r.p.DeleteContainer(ctx, id, true)
+}
+
+// PruneContainers removes all stopped Podman containers
+func (p *PodmanRuntime) PruneContainers(ctx context.Context) ([]string, uint64, error) {
+	pruneReports, err := containers.Prune(p.connCtx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to prune Podman containers: %w", err)
+	}
+
+	var removed []string
+	var reclaimed uint64
+	for _, r := range pruneReports {
+		if r.Err != nil {
+			logger.Warn("PodmanRuntime.PruneContainers: Failed to remove container", "id", r.Id, "error", r.Err)
+			continue
+		}
+		removed = append(removed, r.Id)
+		reclaimed += r.Size
+	}
+	return removed, reclaimed, nil
+}
+
+// SystemPrune prunes unused Podman resources.
+// The per-category prune bindings are used so the selection in opts is honored;
+// Podman keeps its build cache as intermediate images, so it is pruned through the images binding.
+func (p *PodmanRuntime) SystemPrune(ctx context.Context, opts models.PruneOptions) (models.PruneReport, error) {
+	report := models.PruneReport{Runtime: "podman"}
+
+	if opts.Containers {
+		removed, reclaimed, err := p.PruneContainers(ctx)
+		if err != nil {
+			return report, err
+		}
+		report.Containers = len(removed)
+		report.ReclaimedBytes += reclaimed
+	}
+
+	if opts.Images || opts.AllImages || opts.BuildCache {
+		imagePruneOpts := new(images.PruneOptions).WithAll(opts.AllImages).WithBuildCache(opts.BuildCache)
+		reports, err := images.Prune(p.connCtx, imagePruneOpts)
+		if err != nil {
+			return report, fmt.Errorf("failed to prune Podman images: %w", err)
+		}
+		report.Images, report.ReclaimedBytes = countPruned(reports, report.ReclaimedBytes)
+	}
+
+	if opts.Networks {
+		reports, err := network.Prune(p.connCtx, nil)
+		if err != nil {
+			return report, fmt.Errorf("failed to prune Podman networks: %w", err)
+		}
+		for _, r := range reports {
+			if r.Error == nil {
+				report.Networks++
+			}
+		}
+	}
+
+	if opts.Volumes {
+		reports, err := volumes.Prune(p.connCtx, nil)
+		if err != nil {
+			return report, fmt.Errorf("failed to prune Podman volumes: %w", err)
+		}
+		report.Volumes, report.ReclaimedBytes = countPruned(reports, report.ReclaimedBytes)
+	}
+
+	return report, nil
+}
+
+// countPruned counts successfully pruned entries and adds their size to reclaimed
+func countPruned(pruneReports []*reports.PruneReport, reclaimed uint64) (int, uint64) {
+	count := 0
+	for _, r := range pruneReports {
+		if r.Err == nil {
+			count++
+			reclaimed += r.Size
+		}
+	}
+	return count, reclaimed
+}
+
+// StreamEvents streams Podman container lifecycle events
+func (p *PodmanRuntime) StreamEvents(ctx context.Context) (<-chan models.RuntimeEvent, error) {
+	eventChan := make(chan types.Event, eventChannelBufferSize)
+	cancelChan := make(chan bool, 1)
+
+	eventOpts := new(system.EventsOptions).
+		WithStream(true).
+		WithFilters(map[string][]string{"type": {"container"}})
+
+	if err := system.Events(p.connCtx, eventChan, cancelChan, eventOpts); err != nil {
+		return nil, fmt.Errorf("failed to stream Podman events: %w", err)
+	}
+
+	result := make(chan models.RuntimeEvent, eventChannelBufferSize)
+	go func() {
+		defer close(result)
+		// Closing the response body ends the stream; drain eventChan so the decoder can exit
+		defer func() {
+			cancelChan <- true
+			go func() {
+				for range eventChan {
+				}
+			}()
+		}()
+		for {
+			select {
+			case e, ok := <-eventChan:
+				if !ok {
+					return
+				}
+				event, ok := toRuntimeEvent(e.Message, "podman", e.HealthStatus)
+				if !ok {
+					continue
+				}
+				select {
+				case result <- event:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return result, nil
+}
+
+// ListContainerPath lists a directory in a Podman container. Running containers list it with find,
+// one level only; stopped containers and images without find fall back to the archive API.
+func (p *PodmanRuntime) ListContainerPath(ctx context.Context, containerID, path string) ([]models.FileEntry, error) {
+	inspectData, err := containers.Inspect(p.connCtx, containerID, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Podman container %s: %w", containerID, err)
+	}
+	if inspectData.State != nil && inspectData.State.Running {
+		if output, err := p.execOutput(ctx, containerID, listDirCommand(path)); err == nil {
+			if entries, ok := parseListDirOutput(output); ok {
+				return entries, nil
+			}
+		}
+	}
+
+	stat, err := containers.Stat(p.connCtx, containerID, path)
+	for hops := 0; err == nil && stat.Mode&os.ModeSymlink != 0 && stat.LinkTarget != "" && hops < maxLinkHops; hops++ {
+		// The archive API exports links themselves, so directory links are resolved first
+		path = linkTargetPath(path, stat.LinkTarget)
+		stat, err = containers.Stat(p.connCtx, containerID, path)
+	}
+	if err != nil {
+		if errors.Is(err, podmancopy.ErrENOENT) {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to stat %s in Podman container %s: %w", path, containerID, err)
+	}
+	if !stat.IsDir {
+		return []models.FileEntry{fileEntry(stat.Name, stat.Size, stat.Mode, stat.ModTime, stat.LinkTarget)}, nil
+	}
+	if path == "/" {
+		return nil, errArchiveRoot
+	}
+
+	reader, writer := io.Pipe()
+	defer reader.Close()
+
+	copyFunc, err := containers.CopyToArchive(p.connCtx, containerID, path, writer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from Podman container %s: %w", path, containerID, err)
+	}
+	go func() {
+		writer.CloseWithError(copyFunc())
+	}()
+
+	return listArchiveDir(reader)
+}
+
+// CheckpointContainer checkpoints a running Podman container and exports the checkpoint to exportPath
+func (p *PodmanRuntime) CheckpointContainer(ctx context.Context, containerID, exportPath string) error {
+	inspectData, err := containers.Inspect(p.connCtx, containerID, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return fmt.Errorf("failed to inspect Podman container %s: %w", containerID, err)
+	}
+	if inspectData.State == nil || !inspectData.State.Running {
+		return ErrContainerNotRunning
+	}
+
+	if _, err := containers.Checkpoint(p.connCtx, containerID, new(containers.CheckpointOptions).WithExport(exportPath)); err != nil {
+		return fmt.Errorf("failed to checkpoint Podman container %s: %w", containerID, err)
+	}
+	return nil
+}
+
+// RestoreContainer restores a Podman container from the checkpoint archive at importPath
+func (p *PodmanRuntime) RestoreContainer(ctx context.Context, importPath string) (string, error) {
+	report, err := containers.Restore(p.connCtx, "", new(containers.RestoreOptions).WithImportArchive(importPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to restore Podman container from %s: %w", importPath, err)
+	}
+	return report.Id, nil
+}
+
+// GenerateSystemd generates systemd unit files for a Podman container. Units are named after the container
+// rather than its ID; with opts.New the unit creates a fresh container on every start.
+func (p *PodmanRuntime) GenerateSystemd(ctx context.Context, containerID string, opts models.SystemdOptions) (map[string]string, error) {
+	if err := ValidateSystemdOptions(opts); err != nil {
+		return nil, err
+	}
+
+	systemdOpts := new(generate.SystemdOptions).WithUseName(true).WithNew(opts.New)
+	if opts.NamePrefix != "" {
+		systemdOpts.WithContainerPrefix(opts.NamePrefix)
+	}
+	if opts.RestartPolicy != "" {
+		systemdOpts.WithRestartPolicy(opts.RestartPolicy)
+	}
+
+	report, err := generate.Systemd(p.connCtx, containerID, systemdOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate systemd units for Podman container %s: %w", containerID, err)
+	}
+	return report.Units, nil
+}
+
+// GenerateKube generates a Kubernetes manifest from Podman containers and pods (like podman generate kube)
+func (p *PodmanRuntime) GenerateKube(ctx context.Context, ids []string) (string, error) {
+	report, err := generate.Kube(p.connCtx, ids, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate Kubernetes YAML for %s: %w", strings.Join(ids, ", "), err)
+	}
+	if closer, ok := report.Reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	data, err := io.ReadAll(report.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Kubernetes YAML for %s: %w", strings.Join(ids, ", "), err)
+	}
+	return string(data), nil
+}
+
+// ContainerTop lists the processes running inside a Podman container
+func (p *PodmanRuntime) ContainerTop(ctx context.Context, containerID string) ([]models.ProcessInfo, error) {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	inspectData, err := containers.Inspect(callCtx, containerID, new(containers.InspectOptions).WithSize(false))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Podman container %s: %w", containerID, err)
+	}
+	if inspectData.State == nil || !inspectData.State.Running {
+		return nil, ErrContainerNotRunning
+	}
+
+	lines, err := containers.Top(callCtx, containerID, new(containers.TopOptions).WithDescriptors(topDescriptors))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes of Podman container %s: %w", containerID, err)
+	}
+	if len(lines) == 0 {
+		return []models.ProcessInfo{}, nil
+	}
+
+	// The bindings return tab-separated rows with the column titles first
+	titles := strings.Split(lines[0], "\t")
+	rows := make([][]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		rows = append(rows, strings.Split(line, "\t"))
+	}
+
+	return parseTopOutput(titles, rows), nil
+}
+
+// StreamLogs streams logs from a Podman container
+func (p *PodmanRuntime) StreamLogs(ctx context.Context, containerID string, opts models.LogOptions) (io.ReadCloser, error) {
+	// Buffer size for log channels
+	const logChannelBufferSize = 100
+
+	// Create a pipe for the logs
+	pr, pw := io.Pipe()
+
+	// Create channels for stdout and stderr
+	stdoutChan := make(chan string, logChannelBufferSize)
+	stderrChan := make(chan string, logChannelBufferSize)
+
+	// Prepare log options
+	logOpts := new(containers.LogOptions).WithFollow(opts.Follow).WithTimestamps(true)
+	if opts.Tail != "" && opts.Tail != "all" {
+		logOpts.WithTail(opts.Tail)
+	}
+	if !opts.Since.IsZero() {
+		logOpts.WithSince(opts.Since.Format(time.RFC3339Nano))
+	}
+	if !opts.Until.IsZero() {
+		logOpts.WithUntil(opts.Until.Format(time.RFC3339Nano))
+	}
+
+	// Start goroutine to receive logs and write to pipe
+	go func() {
+		defer pw.Close()
+		defer close(stdoutChan)
+		defer close(stderrChan)
+
+		// Start logging in a goroutine
+		errChan := make(chan error, 1)
 		go func() {
 			errChan <- containers.Logs(p.connCtx, containerID, logOpts, stdoutChan, stderrChan)
 		}()
@@ -709,36 +1731,132 @@ func (p *PodmanRuntime) StreamLogs(ctx context.Context, containerID string, foll
 	return pr, nil
 }
 
+// Capabilities reports the features supported for Podman.
+// The v5 bindings cannot change labels, so live label updates are not available yet.
+func (p *PodmanRuntime) Capabilities() models.RuntimeCapabilities {
+	return models.RuntimeCapabilities{
+		Pods:       true,
+		Checkpoint: true,
+		Compose:    true,
+		Systemd:    true,
+		KubeExport: true,
+	}
+}
+
 // GetRuntimeName returns "podman"
 func (p *PodmanRuntime) GetRuntimeName() string {
 	return "podman"
 }
 
-// parseSize parses a size string like "100MB" or "8GB" to bytes
-func parseSize(sizeStr string) uint64 {
-	sizeStr = strings.TrimSpace(strings.ToUpper(sizeStr))
-	var multiplier uint64 = 1
+// callCtx returns the connection context the bindings need, cancelled together with ctx,
+// so calls end with the request or its runtime timeout
+func (p *PodmanRuntime) callCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	callCtx, cancel := context.WithCancel(p.connCtx)
+	stop := context.AfterFunc(ctx, cancel)
+	return callCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// Version returns the version of the Podman service
+func (p *PodmanRuntime) Version(ctx context.Context) (string, error) {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	report, err := system.Version(callCtx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Podman version: %w", err)
+	}
+	if report.Server != nil {
+		return report.Server.Version, nil
+	}
+	return report.Client.Version, nil
+}
+
+// Ping checks that the Podman service is reachable by requesting its version
+func (p *PodmanRuntime) Ping(ctx context.Context) error {
+	callCtx, cancel := p.callCtx(ctx)
+	defer cancel()
+
+	if _, err := system.Version(callCtx, nil); err != nil {
+		return fmt.Errorf("failed to ping Podman service: %w", err)
+	}
+	return nil
+}
+
+// containerStats reads one stats sample per container through the bindings Stats API.
+// If the batch request fails (e.g. a container stopped in the meantime), each container is queried on its own.
+func (p *PodmanRuntime) containerStats(containerIDs []string) map[string]*models.ContainerStats {
+	result := make(map[string]*models.ContainerStats, len(containerIDs))
+	if len(containerIDs) == 0 {
+		return result
+	}
 
-	if strings.HasSuffix(sizeStr, "KB") {
-		multiplier = 1024
-		sizeStr = strings.TrimSuffix(sizeStr, "KB")
-	} else if strings.HasSuffix(sizeStr, "MB") {
-		multiplier = 1024 * 1024
-		sizeStr = strings.TrimSuffix(sizeStr, "MB")
-	} else if strings.HasSuffix(sizeStr, "GB") {
-		multiplier = 1024 * 1024 * 1024
-		sizeStr = strings.TrimSuffix(sizeStr, "GB")
-	} else if strings.HasSuffix(sizeStr, "TB") {
-		multiplier = 1024 * 1024 * 1024 * 1024
-		sizeStr = strings.TrimSuffix(sizeStr, "TB")
-	} else if strings.HasSuffix(sizeStr, "B") {
-		sizeStr = strings.TrimSuffix(sizeStr, "B")
+	if err := p.readStats(containerIDs, result); err != nil {
+		logger.Debug("PodmanRuntime.containerStats: Batch stats request failed, retrying per container", "error", err)
+		for _, id := range containerIDs {
+			if err := p.readStats([]string{id}, result); err != nil {
+				logger.Debug("PodmanRuntime.containerStats: Failed to get stats", "id", id, "error", err)
+			}
+		}
 	}
 
-	val, err := strconv.ParseFloat(sizeStr, 64)
+	return result
+}
+
+// readStats requests a single, non-streaming stats report for the containers and stores it in result
+func (p *PodmanRuntime) readStats(containerIDs []string, result map[string]*models.ContainerStats) error {
+	reports, err := containers.Stats(p.connCtx, containerIDs, new(containers.StatsOptions).WithStream(false))
 	if err != nil {
-		return 0
+		return err
+	}
+
+	var reportErr error
+	for report := range reports {
+		if report.Error != nil {
+			reportErr = report.Error
+			continue
+		}
+		for _, stats := range report.Stats {
+			result[stats.ContainerID] = statsFromPodman(stats)
+		}
+	}
+	return reportErr
+}
+
+// statsFromPodman converts a Podman stats sample to ContainerStats, summing all network interfaces
+func statsFromPodman(stats define.ContainerStats) *models.ContainerStats {
+	var rx, tx uint64
+	for _, network := range stats.Network {
+		rx += network.RxBytes
+		tx += network.TxBytes
+	}
+
+	return &models.ContainerStats{
+		CPUPercent:    stats.CPU,
+		MemoryUsage:   stats.MemUsage,
+		MemoryLimit:   stats.MemLimit,
+		MemoryPercent: stats.MemPerc,
+		NetworkRx:     rx,
+		NetworkTx:     tx,
+		BlockRead:     stats.BlockInput,
+		BlockWrite:    stats.BlockOutput,
+		PIDs:          stats.PIDs,
 	}
+}
 
-	return uint64(val * float64(multiplier))
+// podmanIPAddress returns the container IP from Podman network settings,
+// preferring the default network and falling back to the first joined network
+func podmanIPAddress(settings *define.InspectNetworkSettings) string {
+	if settings.IPAddress != "" {
+		return settings.IPAddress
+	}
+	ips := make(map[string]string, len(settings.Networks))
+	for name, endpoint := range settings.Networks {
+		if endpoint != nil {
+			ips[name] = endpoint.IPAddress
+		}
+	}
+	return firstIPAddress(ips)
 }
@@ -0,0 +1,170 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// ErrInvalidPort is returned for port mappings that cannot be parsed
+var ErrInvalidPort = errors.New("invalid port mapping")
+
+// portSpec is a parsed "[host:]container[/protocol]" port mapping.
+// Both sides may be ranges ("8000-8005"); a single port has End equal to its start.
+type portSpec struct {
+	HostPort         uint16 // 0 publishes on a random host port
+	HostPortEnd      uint16
+	ContainerPort    uint16
+	ContainerPortEnd uint16
+	Protocol         string // "tcp", "udp" or "sctp"
+}
+
+// hostPortCount returns the number of host ports, 0 for a random one
+func (ps portSpec) hostPortCount() int {
+	if ps.HostPort == 0 {
+		return 0
+	}
+	return int(ps.HostPortEnd-ps.HostPort) + 1
+}
+
+// containerPortCount returns the number of container ports
+func (ps portSpec) containerPortCount() int {
+	return int(ps.ContainerPortEnd-ps.ContainerPort) + 1
+}
+
+// parsePortSpec parses a port mapping of the form "[host:]container[/protocol]".
+// Without a host port the container port is published on a random host port.
+// Either side may be a range like "8000-8005"; a host range with a single container port
+// lets the runtime pick a free port from the range. The protocol defaults to tcp.
+func parsePortSpec(spec string) (portSpec, error) {
+	ports, protocol, hasProtocol := strings.Cut(spec, "/")
+	if !hasProtocol {
+		protocol = "tcp"
+	}
+	protocol = strings.ToLower(protocol)
+	switch protocol {
+	case "tcp", "udp", "sctp":
+	default:
+		return portSpec{}, fmt.Errorf("%w %q: protocol must be tcp, udp or sctp", ErrInvalidPort, spec)
+	}
+
+	ps := portSpec{Protocol: protocol}
+	hostPorts, containerPorts, hasHost := strings.Cut(ports, ":")
+	if !hasHost {
+		containerPorts = hostPorts
+	} else {
+		start, end, err := parsePortRange(hostPorts)
+		if err != nil {
+			return portSpec{}, fmt.Errorf("%w %q: host port %v", ErrInvalidPort, spec, err)
+		}
+		ps.HostPort, ps.HostPortEnd = start, end
+	}
+
+	start, end, err := parsePortRange(containerPorts)
+	if err != nil {
+		return portSpec{}, fmt.Errorf("%w %q: container port %v", ErrInvalidPort, spec, err)
+	}
+	ps.ContainerPort, ps.ContainerPortEnd = start, end
+
+	if ps.containerPortCount() > 1 && ps.HostPort != 0 && ps.hostPortCount() != ps.containerPortCount() {
+		return portSpec{}, fmt.Errorf("%w %q: host and container port ranges must have the same length", ErrInvalidPort, spec)
+	}
+	return ps, nil
+}
+
+// parsePortRange parses a port number or a range like "8000-8005"
+func parsePortRange(value string) (uint16, uint16, error) {
+	first, last, isRange := strings.Cut(value, "-")
+	start, err := parsePortNumber(first)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return start, start, nil
+	}
+	end, err := parsePortNumber(last)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("range %q must not end before it starts", value)
+	}
+	return start, end, nil
+}
+
+// parsePortNumber parses a port number between 1 and 65535
+func parsePortNumber(value string) (uint16, error) {
+	port, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("%q must be a number between 1 and 65535", value)
+	}
+	return uint16(port), nil
+}
+
+// RequestedHostPorts returns the fixed host ports requested by "[host:]container[/protocol]" port mappings.
+// Random host ports and host ranges the runtime picks a free port from are left out.
+func RequestedHostPorts(specs []string) ([]models.PortMapping, error) {
+	var ports []models.PortMapping
+	for _, spec := range specs {
+		ps, err := parsePortSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		if ps.HostPort == 0 || (ps.containerPortCount() == 1 && ps.hostPortCount() > 1) {
+			continue
+		}
+		for i := 0; i < ps.containerPortCount(); i++ {
+			ports = append(ports, models.PortMapping{
+				ContainerPort: int(ps.ContainerPort) + i,
+				HostPort:      int(ps.HostPort) + i,
+				Protocol:      ps.Protocol,
+			})
+		}
+	}
+	return ports, nil
+}
+
+// portMappingsFromBindings converts published ports as reported by an inspect
+// ("80/tcp" -> host ports) to port mappings, sorted by container port, protocol and host port.
+// A port bound on several host IPs (e.g. IPv4 and IPv6) is only listed once.
+func portMappingsFromBindings(bindings map[string][]string) []models.PortMapping {
+	seen := make(map[models.PortMapping]bool)
+	mappings := make([]models.PortMapping, 0, len(bindings))
+	for key, hostPorts := range bindings {
+		port, protocol, _ := strings.Cut(key, "/")
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		containerPort, err := strconv.Atoi(port)
+		if err != nil {
+			continue
+		}
+		for _, hostPort := range hostPorts {
+			host, err := strconv.Atoi(hostPort)
+			if err != nil {
+				continue
+			}
+			mapping := models.PortMapping{ContainerPort: containerPort, HostPort: host, Protocol: protocol}
+			if !seen[mapping] {
+				seen[mapping] = true
+				mappings = append(mappings, mapping)
+			}
+		}
+	}
+
+	sort.Slice(mappings, func(i, j int) bool {
+		a, b := mappings[i], mappings[j]
+		if a.ContainerPort != b.ContainerPort {
+			return a.ContainerPort < b.ContainerPort
+		}
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		return a.HostPort < b.HostPort
+	})
+	return mappings
+}
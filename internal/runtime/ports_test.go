@@ -0,0 +1,103 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	nettypes "go.podman.io/common/libnetwork/types"
+)
+
+func TestParsePortSpec(t *testing.T) {
+	tests := map[string]portSpec{
+		"8080:80":             {HostPort: 8080, HostPortEnd: 8080, ContainerPort: 80, ContainerPortEnd: 80, Protocol: "tcp"},
+		"53:53/udp":           {HostPort: 53, HostPortEnd: 53, ContainerPort: 53, ContainerPortEnd: 53, Protocol: "udp"},
+		"443:443/TCP":         {HostPort: 443, HostPortEnd: 443, ContainerPort: 443, ContainerPortEnd: 443, Protocol: "tcp"},
+		"9000:90/sctp":        {HostPort: 9000, HostPortEnd: 9000, ContainerPort: 90, ContainerPortEnd: 90, Protocol: "sctp"},
+		"80":                  {ContainerPort: 80, ContainerPortEnd: 80, Protocol: "tcp"},
+		"53/udp":              {ContainerPort: 53, ContainerPortEnd: 53, Protocol: "udp"},
+		"8000-8005:80":        {HostPort: 8000, HostPortEnd: 8005, ContainerPort: 80, ContainerPortEnd: 80, Protocol: "tcp"},
+		"9000-9002:7000-7002": {HostPort: 9000, HostPortEnd: 9002, ContainerPort: 7000, ContainerPortEnd: 7002, Protocol: "tcp"},
+		"7000-7002":           {ContainerPort: 7000, ContainerPortEnd: 7002, Protocol: "tcp"},
+	}
+	for spec, expected := range tests {
+		ps, err := parsePortSpec(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, expected, ps, spec)
+	}
+
+	for _, spec := range []string{"", "80:", ":80", "http:80", "8080:80/icmp", "70000:80", "0:80", "8080:80:90", "8005-8000:80", "8000-8002:80-81", "8000:80-82", "80-"} {
+		_, err := parsePortSpec(spec)
+		assert.ErrorIs(t, err, ErrInvalidPort, spec)
+	}
+}
+
+func TestDockerPortBindingsUDP(t *testing.T) {
+	exposed, bindings, err := dockerPortBindings([]string{"53:53/udp", "53:53", "8080:80"})
+	require.NoError(t, err)
+
+	assert.Equal(t, nat.PortSet{"53/udp": {}, "53/tcp": {}, "80/tcp": {}}, exposed)
+	assert.Equal(t, []nat.PortBinding{{HostPort: "53"}}, bindings["53/udp"])
+	assert.Equal(t, []nat.PortBinding{{HostPort: "53"}}, bindings["53/tcp"])
+	assert.Equal(t, []nat.PortBinding{{HostPort: "8080"}}, bindings["80/tcp"])
+
+	_, _, err = dockerPortBindings([]string{"53:53/icmp"})
+	assert.ErrorIs(t, err, ErrInvalidPort)
+}
+
+func TestDockerPortBindingsRandomAndRanges(t *testing.T) {
+	exposed, bindings, err := dockerPortBindings([]string{"80", "8000-8005:443", "9000-9001:7000-7001/udp"})
+	require.NoError(t, err)
+
+	assert.Equal(t, nat.PortSet{"80/tcp": {}, "443/tcp": {}, "7000/udp": {}, "7001/udp": {}}, exposed)
+	// An empty host port is assigned by Docker, a host range for one port picks a free port from it
+	assert.Equal(t, []nat.PortBinding{{HostPort: ""}}, bindings["80/tcp"])
+	assert.Equal(t, []nat.PortBinding{{HostPort: "8000-8005"}}, bindings["443/tcp"])
+	// Ranges of the same length map one to one
+	assert.Equal(t, []nat.PortBinding{{HostPort: "9000"}}, bindings["7000/udp"])
+	assert.Equal(t, []nat.PortBinding{{HostPort: "9001"}}, bindings["7001/udp"])
+}
+
+func TestPodmanPortMappings(t *testing.T) {
+	mappings, err := podmanPortMappings([]string{"53:53/udp", "8080:80", "80", "9000-9002:7000-7002"})
+	require.NoError(t, err)
+	assert.Equal(t, []nettypes.PortMapping{
+		{HostPort: 53, ContainerPort: 53, Range: 1, Protocol: "udp"},
+		{HostPort: 8080, ContainerPort: 80, Range: 1, Protocol: "tcp"},
+		{HostPort: 0, ContainerPort: 80, Range: 1, Protocol: "tcp"},
+		{HostPort: 9000, ContainerPort: 7000, Range: 3, Protocol: "tcp"},
+	}, mappings)
+
+	_, err = podmanPortMappings([]string{"8000-8005:80"})
+	assert.ErrorIs(t, err, ErrInvalidPort)
+}
+
+func TestRequestedHostPorts(t *testing.T) {
+	ports, err := RequestedHostPorts([]string{"8080:80", "53:53/udp", "80", "8000-8005:443", "9000-9001:7000-7001"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.PortMapping{
+		{ContainerPort: 80, HostPort: 8080, Protocol: "tcp"},
+		{ContainerPort: 53, HostPort: 53, Protocol: "udp"},
+		{ContainerPort: 7000, HostPort: 9000, Protocol: "tcp"},
+		{ContainerPort: 7001, HostPort: 9001, Protocol: "tcp"},
+	}, ports)
+
+	_, err = RequestedHostPorts([]string{"8080:http"})
+	assert.ErrorIs(t, err, ErrInvalidPort)
+}
+
+func TestPortMappingsFromBindings(t *testing.T) {
+	mappings := portMappingsFromBindings(map[string][]string{
+		"443/tcp": {"8003"},
+		"80/tcp":  {"32768", "32768"}, // IPv4 and IPv6
+		"53/udp":  {"5353"},
+		"9000":    {"invalid"},
+	})
+	assert.Equal(t, []models.PortMapping{
+		{ContainerPort: 53, HostPort: 5353, Protocol: "udp"},
+		{ContainerPort: 80, HostPort: 32768, Protocol: "tcp"},
+		{ContainerPort: 443, HostPort: 8003, Protocol: "tcp"},
+	}, mappings)
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// pullProgressBufferSize is the buffer size of the channels returned by PullImageStream
+const pullProgressBufferSize = 64
+
+// decodePullProgress reads a Docker JSON pull stream and passes each update to send.
+// It stops after the first error in the stream or when send returns false.
+func decodePullProgress(r io.Reader, send func(models.PullProgress) bool) {
+	decoder := json.NewDecoder(r)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err != io.EOF {
+				send(models.PullProgress{Error: fmt.Sprintf("failed to read pull output: %v", err)})
+			}
+			return
+		}
+
+		progress := models.PullProgress{ID: msg.ID, Status: msg.Status}
+		if msg.Progress != nil {
+			progress.Current = msg.Progress.Current
+			progress.Total = msg.Progress.Total
+		}
+		if msg.Error != nil {
+			progress.Error = msg.Error.Message
+		}
+		if !send(progress) || msg.Error != nil {
+			return
+		}
+	}
+}
+
+// sendPullProgress sends an update on the channel and reports false if ctx ended first
+func sendPullProgress(ctx context.Context, ch chan<- models.PullProgress, progress models.PullProgress) bool {
+	select {
+	case ch <- progress:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodePullProgress(t *testing.T) {
+	stream := `{"status":"Pulling from library/nginx","id":"latest"}
+{"status":"Pulling fs layer","progressDetail":{},"id":"a2abf6c4d29d"}
+{"status":"Downloading","progressDetail":{"current":1024,"total":31357311},"progress":"[>   ]","id":"a2abf6c4d29d"}
+{"status":"Pull complete","progressDetail":{},"id":"a2abf6c4d29d"}
+{"status":"Status: Downloaded newer image for nginx:latest"}
+`
+
+	var updates []models.PullProgress
+	decodePullProgress(strings.NewReader(stream), func(progress models.PullProgress) bool {
+		updates = append(updates, progress)
+		return true
+	})
+
+	assert.Equal(t, []models.PullProgress{
+		{ID: "latest", Status: "Pulling from library/nginx"},
+		{ID: "a2abf6c4d29d", Status: "Pulling fs layer"},
+		{ID: "a2abf6c4d29d", Status: "Downloading", Current: 1024, Total: 31357311},
+		{ID: "a2abf6c4d29d", Status: "Pull complete"},
+		{Status: "Status: Downloaded newer image for nginx:latest"},
+	}, updates)
+}
+
+func TestDecodePullProgressErrors(t *testing.T) {
+	collect := func(stream string) []models.PullProgress {
+		var updates []models.PullProgress
+		decodePullProgress(strings.NewReader(stream), func(progress models.PullProgress) bool {
+			updates = append(updates, progress)
+			return true
+		})
+		return updates
+	}
+
+	// The stream ends with the first reported error
+	updates := collect(`{"status":"Pulling from library/nginx","id":"latest"}
+{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}
+{"status":"unexpected"}
+`)
+	assert.Len(t, updates, 2)
+	assert.Equal(t, "manifest unknown", updates[1].Error)
+
+	updates = collect(`{"status":"Pulling`)
+	assert.Len(t, updates, 1)
+	assert.Contains(t, updates[0].Error, "failed to read pull output")
+
+	// Decoding stops when the receiver is gone
+	calls := 0
+	decodePullProgress(strings.NewReader(`{"status":"a"}{"status":"b"}`), func(models.PullProgress) bool {
+		calls++
+		return false
+	})
+	assert.Equal(t, 1, calls)
+}
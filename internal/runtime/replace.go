@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/logger"
+)
+
+// defaultUpdateGracePeriod is how long a recreated container must stay up before
+// the original container is removed
+const defaultUpdateGracePeriod = 10 * time.Second
+
+// backupNameSuffix is appended to the original container's name while its replacement is verified
+const backupNameSuffix = "-gintainer-backup"
+
+// containerReplacer provides the runtime operations used to swap a container for a new one
+type containerReplacer interface {
+	stop(ctx context.Context, id string) error
+	rename(ctx context.Context, id, name string) error
+	// create creates (but does not start) the replacement container under the given name
+	create(ctx context.Context, name string) (string, error)
+	start(ctx context.Context, id string) error
+	// healthy reports whether the container is running and has not restarted
+	healthy(ctx context.Context, id string) (bool, error)
+	remove(ctx context.Context, id string) error
+}
+
+// replaceContainer replaces the container oldID with a new one of the same name.
+// The original container is stopped and renamed, but only removed once the new
+// container has been started and is still running after the grace period. On any
+// failure the new container is removed and the original is restored and restarted.
+func replaceContainer(ctx context.Context, r containerReplacer, oldID, name string, grace time.Duration) error {
+	if err := r.stop(ctx, oldID); err != nil {
+		return fmt.Errorf("failed to stop container: %w", err)
+	}
+
+	backupName := name + backupNameSuffix
+	if err := r.rename(ctx, oldID, backupName); err != nil {
+		if startErr := r.start(ctx, oldID); startErr != nil {
+			logger.Error("replaceContainer: Failed to restart original container", "id", oldID, "error", startErr)
+		}
+		return fmt.Errorf("failed to rename container: %w", err)
+	}
+
+	// rollback removes the replacement (if any) and brings the original container back
+	rollback := func(newID string, cause error) error {
+		logger.Warn("replaceContainer: Rolling back to original container", "name", name, "cause", cause)
+		// Restore the original even if the update was canceled
+		ctx := context.WithoutCancel(ctx)
+		if newID != "" {
+			if err := r.remove(ctx, newID); err != nil {
+				logger.Error("replaceContainer: Failed to remove new container", "id", newID, "error", err)
+			}
+		}
+		if err := r.rename(ctx, oldID, name); err != nil {
+			return fmt.Errorf("%w (rollback failed to rename original container: %v)", cause, err)
+		}
+		if err := r.start(ctx, oldID); err != nil {
+			return fmt.Errorf("%w (rollback failed to start original container: %v)", cause, err)
+		}
+		return fmt.Errorf("%w (rolled back to the original container)", cause)
+	}
+
+	newID, err := r.create(ctx, name)
+	if err != nil {
+		return rollback("", fmt.Errorf("failed to create new container: %w", err))
+	}
+
+	if err := r.start(ctx, newID); err != nil {
+		return rollback(newID, fmt.Errorf("failed to start new container: %w", err))
+	}
+
+	select {
+	case <-time.After(grace):
+	case <-ctx.Done():
+		return rollback(newID, ctx.Err())
+	}
+
+	healthy, err := r.healthy(ctx, newID)
+	if err != nil {
+		return rollback(newID, fmt.Errorf("failed to inspect new container: %w", err))
+	}
+	if !healthy {
+		return rollback(newID, fmt.Errorf("new container did not stay up for %s", grace))
+	}
+
+	if err := r.remove(ctx, oldID); err != nil {
+		// The update itself succeeded, only the backup is left behind
+		logger.Warn("replaceContainer: Failed to remove original container", "id", oldID, "name", backupName, "error", err)
+	}
+
+	return nil
+}
+
+// replaceStoppedContainer replaces the stopped container oldID with a new one of the same name
+// without starting it. The original is only removed once the new container has been created.
+func replaceStoppedContainer(ctx context.Context, r containerReplacer, oldID, name string) error {
+	backupName := name + backupNameSuffix
+	if err := r.rename(ctx, oldID, backupName); err != nil {
+		return fmt.Errorf("failed to rename container: %w", err)
+	}
+
+	if _, err := r.create(ctx, name); err != nil {
+		if renameErr := r.rename(context.WithoutCancel(ctx), oldID, name); renameErr != nil {
+			return fmt.Errorf("failed to create new container: %w (rollback failed to rename original container: %v)", err, renameErr)
+		}
+		return fmt.Errorf("failed to create new container: %w (rolled back to the original container)", err)
+	}
+
+	if err := r.remove(ctx, oldID); err != nil {
+		logger.Warn("replaceStoppedContainer: Failed to remove original container", "id", oldID, "name", backupName, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeReplacer simulates a runtime with a single container named "web"
+type fakeReplacer struct {
+	names      map[string]string // Container ID -> name
+	running    map[string]bool
+	removed    []string
+	crashes    bool // The new container exits right after start
+	createErr  error
+	createdIDs []string
+}
+
+func newFakeReplacer() *fakeReplacer {
+	return &fakeReplacer{
+		names:   map[string]string{"old": "web"},
+		running: map[string]bool{"old": true},
+	}
+}
+
+func (f *fakeReplacer) stop(ctx context.Context, id string) error {
+	f.running[id] = false
+	return nil
+}
+
+func (f *fakeReplacer) rename(ctx context.Context, id, name string) error {
+	for otherID, otherName := range f.names {
+		if otherName == name && otherID != id {
+			return errors.New("name already in use")
+		}
+	}
+	f.names[id] = name
+	return nil
+}
+
+func (f *fakeReplacer) create(ctx context.Context, name string) (string, error) {
+	if f.createErr != nil {
+		return "", f.createErr
+	}
+	if err := f.rename(ctx, "new", name); err != nil {
+		return "", err
+	}
+	f.createdIDs = append(f.createdIDs, "new")
+	return "new", nil
+}
+
+func (f *fakeReplacer) start(ctx context.Context, id string) error {
+	f.running[id] = !(id == "new" && f.crashes)
+	return nil
+}
+
+func (f *fakeReplacer) healthy(ctx context.Context, id string) (bool, error) {
+	return f.running[id], nil
+}
+
+func (f *fakeReplacer) remove(ctx context.Context, id string) error {
+	delete(f.names, id)
+	delete(f.running, id)
+	f.removed = append(f.removed, id)
+	return nil
+}
+
+func TestReplaceContainerSuccess(t *testing.T) {
+	f := newFakeReplacer()
+
+	err := replaceContainer(context.Background(), f, "old", "web", time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"old"}, f.removed)
+	assert.Equal(t, "web", f.names["new"])
+	assert.True(t, f.running["new"])
+}
+
+func TestReplaceContainerCrashLoopRollsBack(t *testing.T) {
+	f := newFakeReplacer()
+	f.crashes = true
+
+	err := replaceContainer(context.Background(), f, "old", "web", time.Millisecond)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rolled back")
+
+	// The new container is gone and the original is back under its name
+	assert.Equal(t, []string{"new"}, f.removed)
+	assert.Equal(t, map[string]string{"old": "web"}, f.names)
+	assert.True(t, f.running["old"])
+}
+
+func TestReplaceContainerCreateFailureRollsBack(t *testing.T) {
+	f := newFakeReplacer()
+	f.createErr = errors.New("no such image")
+
+	err := replaceContainer(context.Background(), f, "old", "web", time.Millisecond)
+	assert.ErrorContains(t, err, "no such image")
+	assert.Empty(t, f.removed)
+	assert.Equal(t, "web", f.names["old"])
+	assert.True(t, f.running["old"])
+}
+
+func TestReplaceContainerCanceledRollsBack(t *testing.T) {
+	f := newFakeReplacer()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := replaceContainer(ctx, f, "old", "web", time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "web", f.names["old"])
+	assert.True(t, f.running["old"])
+}
@@ -0,0 +1,60 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// ErrInvalidResourceUpdate is returned for resource updates with invalid values or nothing to change
+var ErrInvalidResourceUpdate = errors.New("invalid resource update")
+
+// cpuPeriod is the CFS period in microseconds that CPU limits are converted to, as with "docker run --cpus"
+const cpuPeriod = 100000
+
+// restartPolicies are the restart policy names both runtimes accept
+var restartPolicies = map[string]bool{"no": true, "always": true, "unless-stopped": true, "on-failure": true}
+
+// ValidateResourceUpdate checks that the update changes at least one setting and all its values are valid
+func ValidateResourceUpdate(update models.ResourceUpdate) error {
+	if update.Memory == 0 && update.CPUs == 0 && update.RestartPolicy == "" {
+		return fmt.Errorf("%w: nothing to update", ErrInvalidResourceUpdate)
+	}
+	if update.Memory < 0 {
+		return fmt.Errorf("%w: memory must not be negative", ErrInvalidResourceUpdate)
+	}
+	if update.CPUs < 0 {
+		return fmt.Errorf("%w: cpus must not be negative", ErrInvalidResourceUpdate)
+	}
+	if update.RestartPolicy != "" {
+		if _, _, err := parseRestartPolicy(update.RestartPolicy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRestartPolicy splits a "<name>[:<max-retries>]" restart policy; retries are only allowed for "on-failure"
+func parseRestartPolicy(policy string) (string, int, error) {
+	name, retries, hasRetries := strings.Cut(policy, ":")
+	if !restartPolicies[name] {
+		return "", 0, fmt.Errorf("%w: unknown restart policy %q", ErrInvalidResourceUpdate, policy)
+	}
+	if !hasRetries {
+		return name, 0, nil
+	}
+
+	count, err := strconv.Atoi(retries)
+	if name != "on-failure" || err != nil || count < 0 {
+		return "", 0, fmt.Errorf("%w: invalid restart policy %q", ErrInvalidResourceUpdate, policy)
+	}
+	return name, count, nil
+}
+
+// cpuQuota converts a number of CPUs into a CFS quota for cpuPeriod
+func cpuQuota(cpus float64) int64 {
+	return int64(cpus * cpuPeriod)
+}
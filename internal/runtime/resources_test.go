@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateResourceUpdate(t *testing.T) {
+	for _, update := range []models.ResourceUpdate{
+		{Memory: 512 * 1024 * 1024},
+		{CPUs: 1.5},
+		{RestartPolicy: "unless-stopped"},
+		{RestartPolicy: "on-failure:3", Memory: 1 << 30},
+	} {
+		assert.NoError(t, ValidateResourceUpdate(update), "%+v", update)
+	}
+
+	for _, update := range []models.ResourceUpdate{
+		{},
+		{Memory: -1},
+		{CPUs: -0.5},
+		{RestartPolicy: "sometimes"},
+		{RestartPolicy: "always:3"},
+		{RestartPolicy: "on-failure:many"},
+		{RestartPolicy: "on-failure:-1"},
+	} {
+		assert.ErrorIs(t, ValidateResourceUpdate(update), ErrInvalidResourceUpdate, "%+v", update)
+	}
+}
+
+func TestParseRestartPolicy(t *testing.T) {
+	name, retries, err := parseRestartPolicy("on-failure:5")
+	assert.NoError(t, err)
+	assert.Equal(t, "on-failure", name)
+	assert.Equal(t, 5, retries)
+
+	name, retries, err = parseRestartPolicy("always")
+	assert.NoError(t, err)
+	assert.Equal(t, "always", name)
+	assert.Zero(t, retries)
+
+	assert.Equal(t, int64(150000), cpuQuota(1.5))
+}
@@ -0,0 +1,227 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/docker/docker/api/types/container"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var (
+	// ErrInvalidDevice is returned for device mappings not in "host[:container][:permissions]" format
+	ErrInvalidDevice = errors.New("invalid device")
+	// ErrInvalidExtraHost is returned for extra hosts not in "hostname:ip" format
+	ErrInvalidExtraHost = errors.New("invalid extra host")
+	// ErrInvalidTmpfs is returned for tmpfs mounts on a relative path
+	ErrInvalidTmpfs = errors.New("invalid tmpfs mount")
+	// ErrInvalidUser is returned for users not in "user[:group]" format
+	ErrInvalidUser = errors.New("invalid user")
+)
+
+// defaultDevicePermissions are the cgroup permissions of devices mapped without any
+const defaultDevicePermissions = "rwm"
+
+// deviceMapping is a host device made available in a container
+type deviceMapping struct {
+	HostPath      string
+	ContainerPath string
+	Permissions   string // Any of "r", "w" and "m"
+}
+
+// String returns the mapping in "host:container:permissions" format
+func (d deviceMapping) String() string {
+	return d.HostPath + ":" + d.ContainerPath + ":" + d.Permissions
+}
+
+// validDevicePermissions reports whether perms is a combination of "r", "w" and "m"
+func validDevicePermissions(perms string) bool {
+	if perms == "" || len(perms) > 3 {
+		return false
+	}
+	for _, c := range perms {
+		if !strings.ContainsRune(defaultDevicePermissions, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseDevice parses a device in "host[:container][:permissions]" format like the --device flag.
+// The container path defaults to the host path and the permissions to "rwm".
+func parseDevice(device string) (deviceMapping, error) {
+	parts := strings.Split(device, ":")
+	mapping := deviceMapping{HostPath: parts[0], Permissions: defaultDevicePermissions}
+
+	switch len(parts) {
+	case 1:
+	case 2:
+		// The second part is either the container path or the permissions
+		if validDevicePermissions(parts[1]) {
+			mapping.Permissions = parts[1]
+		} else {
+			mapping.ContainerPath = parts[1]
+		}
+	case 3:
+		if !validDevicePermissions(parts[2]) {
+			return deviceMapping{}, fmt.Errorf("%w %q: permissions must be a combination of r, w and m", ErrInvalidDevice, device)
+		}
+		mapping.ContainerPath, mapping.Permissions = parts[1], parts[2]
+	default:
+		return deviceMapping{}, fmt.Errorf("%w %q: expected host[:container][:permissions]", ErrInvalidDevice, device)
+	}
+	if mapping.ContainerPath == "" {
+		mapping.ContainerPath = mapping.HostPath
+	}
+
+	if !path.IsAbs(mapping.HostPath) || !path.IsAbs(mapping.ContainerPath) {
+		return deviceMapping{}, fmt.Errorf("%w %q: device paths must be absolute", ErrInvalidDevice, device)
+	}
+	return mapping, nil
+}
+
+// parseExtraHost parses an extra /etc/hosts entry in "hostname:ip" (or "hostname=ip") format and returns it as "hostname:ip".
+// The ip may be "host-gateway", which the runtimes resolve to the host.
+func parseExtraHost(host string) (string, error) {
+	name, ip, ok := strings.Cut(host, "=")
+	if !ok {
+		name, ip, ok = strings.Cut(host, ":")
+	}
+	if !ok || name == "" || (ip != "host-gateway" && net.ParseIP(ip) == nil) {
+		return "", fmt.Errorf("%w %q: expected hostname:ip", ErrInvalidExtraHost, host)
+	}
+	return name + ":" + ip, nil
+}
+
+// ValidateRunOptions checks the security options, devices, extra hosts, user and tmpfs mounts of a run request
+func ValidateRunOptions(req models.RunContainerRequest) error {
+	if err := ValidateSecurityOpts(req.SecurityOpt); err != nil {
+		return err
+	}
+	for _, device := range req.Devices {
+		if _, err := parseDevice(device); err != nil {
+			return err
+		}
+	}
+	for _, host := range req.ExtraHosts {
+		if _, err := parseExtraHost(host); err != nil {
+			return err
+		}
+	}
+	if err := validateUser(req.User); err != nil {
+		return err
+	}
+	return validateTmpfs(req.Tmpfs)
+}
+
+// validateUser checks that a user is empty or in "user[:group]" format, where both may be names or numeric IDs
+func validateUser(user string) error {
+	if user == "" {
+		return nil
+	}
+	name, group, hasGroup := strings.Cut(user, ":")
+	if name == "" || (hasGroup && (group == "" || strings.Contains(group, ":"))) || strings.ContainsFunc(user, unicode.IsSpace) {
+		return fmt.Errorf("%w %q: expected user[:group]", ErrInvalidUser, user)
+	}
+	return nil
+}
+
+// validateTmpfs checks that tmpfs mounts are on absolute container paths
+func validateTmpfs(tmpfs map[string]string) error {
+	for target := range tmpfs {
+		if !path.IsAbs(target) {
+			return fmt.Errorf("%w %q: the container path must be absolute", ErrInvalidTmpfs, target)
+		}
+	}
+	return nil
+}
+
+// IsInvalidRunOption reports whether err was caused by an invalid option of a run request
+func IsInvalidRunOption(err error) bool {
+	for _, target := range []error{ErrInvalidPort, ErrInvalidSecurityOpt, ErrInvalidDevice, ErrInvalidExtraHost, ErrInvalidTmpfs, ErrInvalidUser} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// dockerDevices converts device strings to Docker device mappings
+func dockerDevices(devices []string) ([]container.DeviceMapping, error) {
+	if len(devices) == 0 {
+		return nil, nil
+	}
+	mappings := make([]container.DeviceMapping, 0, len(devices))
+	for _, device := range devices {
+		mapping, err := parseDevice(device)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, container.DeviceMapping{
+			PathOnHost:        mapping.HostPath,
+			PathInContainer:   mapping.ContainerPath,
+			CgroupPermissions: mapping.Permissions,
+		})
+	}
+	return mappings, nil
+}
+
+// podmanDevices converts device strings to Podman devices, which carry the whole mapping in the path
+func podmanDevices(devices []string) ([]spec.LinuxDevice, error) {
+	if len(devices) == 0 {
+		return nil, nil
+	}
+	linuxDevices := make([]spec.LinuxDevice, 0, len(devices))
+	for _, device := range devices {
+		mapping, err := parseDevice(device)
+		if err != nil {
+			return nil, err
+		}
+		linuxDevices = append(linuxDevices, spec.LinuxDevice{Path: mapping.String()})
+	}
+	return linuxDevices, nil
+}
+
+// extraHosts normalizes extra hosts to the "hostname:ip" format both runtimes expect
+func extraHosts(hosts []string) ([]string, error) {
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		entry, err := parseExtraHost(host)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, entry)
+	}
+	return normalized, nil
+}
+
+// podmanTmpfsMounts converts tmpfs mounts (container path -> comma-separated options) to Podman mounts, sorted by path
+func podmanTmpfsMounts(tmpfs map[string]string) ([]spec.Mount, error) {
+	if err := validateTmpfs(tmpfs); err != nil {
+		return nil, err
+	}
+	targets := make([]string, 0, len(tmpfs))
+	for target := range tmpfs {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	mounts := make([]spec.Mount, 0, len(targets))
+	for _, target := range targets {
+		mount := spec.Mount{Type: "tmpfs", Source: "tmpfs", Destination: target}
+		if options := tmpfs[target]; options != "" {
+			mount.Options = strings.Split(options, ",")
+		}
+		mounts = append(mounts, mount)
+	}
+	return mounts, nil
+}
@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/docker/docker/api/types/container"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDevice(t *testing.T) {
+	tests := []struct {
+		device string
+		want   deviceMapping
+	}{
+		{"/dev/snd", deviceMapping{HostPath: "/dev/snd", ContainerPath: "/dev/snd", Permissions: "rwm"}},
+		{"/dev/snd:r", deviceMapping{HostPath: "/dev/snd", ContainerPath: "/dev/snd", Permissions: "r"}},
+		{"/dev/ttyUSB0:/dev/modem", deviceMapping{HostPath: "/dev/ttyUSB0", ContainerPath: "/dev/modem", Permissions: "rwm"}},
+		{"/dev/ttyUSB0:/dev/modem:rw", deviceMapping{HostPath: "/dev/ttyUSB0", ContainerPath: "/dev/modem", Permissions: "rw"}},
+	}
+	for _, tt := range tests {
+		got, err := parseDevice(tt.device)
+		require.NoError(t, err, tt.device)
+		assert.Equal(t, tt.want, got, tt.device)
+	}
+
+	for _, device := range []string{"", "snd", "/dev/snd:dev/snd", "/dev/snd:/dev/snd:rx", "/dev/snd:/dev/snd:rw:m"} {
+		_, err := parseDevice(device)
+		assert.ErrorIs(t, err, ErrInvalidDevice, device)
+	}
+}
+
+func TestParseExtraHost(t *testing.T) {
+	for host, want := range map[string]string{
+		"db:10.0.0.5":           "db:10.0.0.5",
+		"db=10.0.0.5":           "db:10.0.0.5",
+		"v6:::1":                "v6:::1",
+		"api:host-gateway":      "api:host-gateway",
+		"registry.local:fe80::": "registry.local:fe80::",
+	} {
+		got, err := parseExtraHost(host)
+		require.NoError(t, err, host)
+		assert.Equal(t, want, got, host)
+	}
+
+	for _, host := range []string{"", "db", ":10.0.0.5", "db:localhost", "db:10.0.0"} {
+		_, err := parseExtraHost(host)
+		assert.ErrorIs(t, err, ErrInvalidExtraHost, host)
+	}
+}
+
+func TestValidateUser(t *testing.T) {
+	for _, user := range []string{"", "1000", "1000:1000", "app", "app:staff"} {
+		assert.NoError(t, validateUser(user), user)
+	}
+	for _, user := range []string{":", ":1000", "1000:", "a:b:c", "my user"} {
+		assert.ErrorIs(t, validateUser(user), ErrInvalidUser, user)
+	}
+}
+
+func TestValidateRunOptions(t *testing.T) {
+	assert.NoError(t, ValidateRunOptions(models.RunContainerRequest{
+		Tmpfs:      map[string]string{"/tmp": "rw,size=64m"},
+		Devices:    []string{"/dev/snd"},
+		ExtraHosts: []string{"db:10.0.0.5"},
+	}))
+
+	tests := []struct {
+		req  models.RunContainerRequest
+		want error
+	}{
+		{models.RunContainerRequest{Devices: []string{"snd"}}, ErrInvalidDevice},
+		{models.RunContainerRequest{ExtraHosts: []string{"db"}}, ErrInvalidExtraHost},
+		{models.RunContainerRequest{Tmpfs: map[string]string{"tmp": ""}}, ErrInvalidTmpfs},
+		{models.RunContainerRequest{SecurityOpt: []string{"bogus"}}, ErrInvalidSecurityOpt},
+		{models.RunContainerRequest{User: "1000:"}, ErrInvalidUser},
+	}
+	for _, tt := range tests {
+		err := ValidateRunOptions(tt.req)
+		assert.ErrorIs(t, err, tt.want)
+		assert.True(t, IsInvalidRunOption(err))
+	}
+	assert.False(t, IsInvalidRunOption(assert.AnError))
+}
+
+func TestDockerDevices(t *testing.T) {
+	devices, err := dockerDevices([]string{"/dev/snd", "/dev/ttyUSB0:/dev/modem:rw"})
+	require.NoError(t, err)
+	assert.Equal(t, []container.DeviceMapping{
+		{PathOnHost: "/dev/snd", PathInContainer: "/dev/snd", CgroupPermissions: "rwm"},
+		{PathOnHost: "/dev/ttyUSB0", PathInContainer: "/dev/modem", CgroupPermissions: "rw"},
+	}, devices)
+
+	_, err = dockerDevices([]string{"/dev/snd:/dev/snd:x"})
+	assert.ErrorIs(t, err, ErrInvalidDevice)
+}
+
+func TestPodmanDevices(t *testing.T) {
+	devices, err := podmanDevices([]string{"/dev/snd", "/dev/ttyUSB0:/dev/modem:r"})
+	require.NoError(t, err)
+	assert.Equal(t, []spec.LinuxDevice{{Path: "/dev/snd:/dev/snd:rwm"}, {Path: "/dev/ttyUSB0:/dev/modem:r"}}, devices)
+}
+
+func TestPodmanTmpfsMounts(t *testing.T) {
+	mounts, err := podmanTmpfsMounts(map[string]string{"/tmp": "rw,size=64m", "/run": ""})
+	require.NoError(t, err)
+	assert.Equal(t, []spec.Mount{
+		{Type: "tmpfs", Source: "tmpfs", Destination: "/run"},
+		{Type: "tmpfs", Source: "tmpfs", Destination: "/tmp", Options: []string{"rw", "size=64m"}},
+	}, mounts)
+
+	_, err = podmanTmpfsMounts(map[string]string{"tmp": ""})
+	assert.ErrorIs(t, err, ErrInvalidTmpfs)
+}
@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/containers/podman/v5/pkg/specgen"
+)
+
+// ErrInvalidSecurityOpt is returned for security options the runtimes do not understand
+var ErrInvalidSecurityOpt = errors.New("invalid security option")
+
+// securityOptKeys are the security options accepted on run, in "key=value" format
+var securityOptKeys = map[string]bool{
+	"label": true, "apparmor": true, "seccomp": true, "no-new-privileges": true,
+	"mask": true, "unmask": true,
+}
+
+// parseSecurityOpt splits a security option into key and value. "no-new-privileges" may be given without a value.
+func parseSecurityOpt(opt string) (string, string, error) {
+	key, value, hasValue := strings.Cut(opt, "=")
+	if key == "no-new-privileges" && !hasValue {
+		return key, "true", nil
+	}
+	if !securityOptKeys[key] || value == "" {
+		return "", "", fmt.Errorf("%w %q", ErrInvalidSecurityOpt, opt)
+	}
+	if key == "no-new-privileges" && value != "true" && value != "false" {
+		return "", "", fmt.Errorf("%w %q: no-new-privileges must be true or false", ErrInvalidSecurityOpt, opt)
+	}
+	// Profile paths would be read on the host, only accept profiles that come with the request
+	if key == "seccomp" && value != "unconfined" && !isInlineSeccompProfile(value) {
+		return "", "", fmt.Errorf("%w %q: seccomp must be unconfined or an inline JSON profile", ErrInvalidSecurityOpt, opt)
+	}
+	return key, value, nil
+}
+
+// isInlineSeccompProfile reports whether a seccomp value is a JSON profile rather than a file path
+func isInlineSeccompProfile(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), "{") && json.Valid([]byte(value))
+}
+
+// ValidateSecurityOpts checks that all security options are well-formed
+func ValidateSecurityOpts(opts []string) error {
+	for _, opt := range opts {
+		if _, _, err := parseSecurityOpt(opt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dockerSecurityOpts returns the security options for a Docker host config
+func dockerSecurityOpts(opts []string) ([]string, error) {
+	securityOpts := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		key, value, err := parseSecurityOpt(opt)
+		if err != nil {
+			return nil, err
+		}
+		securityOpts = append(securityOpts, key+"="+value)
+	}
+	if len(securityOpts) == 0 {
+		return nil, nil
+	}
+	return securityOpts, nil
+}
+
+// applyPodmanSecurityOpts sets the security options on a Podman spec generator.
+// The Podman API only takes seccomp profile paths, so inline profiles are rejected.
+func applyPodmanSecurityOpts(s *specgen.SpecGenerator, opts []string) error {
+	for _, opt := range opts {
+		key, value, err := parseSecurityOpt(opt)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "label":
+			s.SelinuxOpts = append(s.SelinuxOpts, value)
+		case "apparmor":
+			s.ApparmorProfile = value
+		case "seccomp":
+			if value != "unconfined" {
+				return fmt.Errorf("%w %q: Podman only supports seccomp=unconfined", ErrInvalidSecurityOpt, opt)
+			}
+			s.SeccompProfilePath = value
+		case "no-new-privileges":
+			noNewPrivileges := value == "true"
+			s.NoNewPrivileges = &noNewPrivileges
+		case "mask":
+			s.Mask = append(s.Mask, strings.Split(value, ":")...)
+		case "unmask":
+			s.Unmask = append(s.Unmask, strings.Split(value, ":")...)
+		}
+	}
+	return nil
+}
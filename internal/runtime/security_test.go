@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/containers/podman/v5/pkg/specgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSecurityOpts(t *testing.T) {
+	assert.NoError(t, ValidateSecurityOpts(nil))
+	assert.NoError(t, ValidateSecurityOpts([]string{"seccomp=unconfined", "no-new-privileges", "no-new-privileges=false", "label=type:svirt_apache_t"}))
+
+	assert.NoError(t, ValidateSecurityOpts([]string{`seccomp={"defaultAction": "SCMP_ACT_ALLOW"}`}))
+
+	// Profile paths are never read on the host
+	for _, opt := range []string{"", "seccomp", "seccomp=", "seccomp=/etc/passwd", "seccomp={not json", "selinux=disable", "no-new-privileges=yes"} {
+		assert.ErrorIs(t, ValidateSecurityOpts([]string{opt}), ErrInvalidSecurityOpt, opt)
+	}
+}
+
+func TestDockerSecurityOpts(t *testing.T) {
+	opts, err := dockerSecurityOpts([]string{`seccomp={"defaultAction": "SCMP_ACT_ALLOW"}`, "seccomp=unconfined", "no-new-privileges"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`seccomp={"defaultAction": "SCMP_ACT_ALLOW"}`, "seccomp=unconfined", "no-new-privileges=true"}, opts)
+
+	_, err = dockerSecurityOpts([]string{"seccomp=/etc/seccomp.json"})
+	assert.ErrorIs(t, err, ErrInvalidSecurityOpt)
+
+	opts, err = dockerSecurityOpts(nil)
+	require.NoError(t, err)
+	assert.Nil(t, opts)
+}
+
+func TestApplyPodmanSecurityOpts(t *testing.T) {
+	s := specgen.NewSpecGenerator("busybox", false)
+	require.NoError(t, applyPodmanSecurityOpts(s, []string{
+		"label=disable", "apparmor=unconfined", "seccomp=unconfined",
+		"no-new-privileges", "mask=/proc/a:/proc/b", "unmask=ALL",
+	}))
+
+	assert.Equal(t, []string{"disable"}, s.SelinuxOpts)
+	assert.Equal(t, "unconfined", s.ApparmorProfile)
+	assert.Equal(t, "unconfined", s.SeccompProfilePath)
+	require.NotNil(t, s.NoNewPrivileges)
+	assert.True(t, *s.NoNewPrivileges)
+	assert.Equal(t, []string{"/proc/a", "/proc/b"}, s.Mask)
+	assert.Equal(t, []string{"ALL"}, s.Unmask)
+
+	assert.ErrorIs(t, applyPodmanSecurityOpts(s, []string{"bogus=1"}), ErrInvalidSecurityOpt)
+	assert.ErrorIs(t, applyPodmanSecurityOpts(s, []string{`seccomp={"defaultAction": "SCMP_ACT_ALLOW"}`}), ErrInvalidSecurityOpt)
+}
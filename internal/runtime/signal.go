@@ -0,0 +1,39 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultKillSignal is sent by KillContainer when no signal is given
+const DefaultKillSignal = "SIGKILL"
+
+// ErrInvalidSignal is returned for signal names that are not in knownSignals
+var ErrInvalidSignal = errors.New("invalid signal")
+
+// knownSignals are the signals that can be sent to a container, by name
+var knownSignals = map[string]bool{
+	"SIGABRT": true, "SIGALRM": true, "SIGCONT": true, "SIGHUP": true,
+	"SIGINT": true, "SIGKILL": true, "SIGPIPE": true, "SIGQUIT": true,
+	"SIGSTOP": true, "SIGTERM": true, "SIGTRAP": true, "SIGTSTP": true,
+	"SIGTTIN": true, "SIGTTOU": true, "SIGUSR1": true, "SIGUSR2": true,
+	"SIGWINCH": true,
+}
+
+// NormalizeSignal returns the canonical name of a signal ("hup", "HUP" and "SIGHUP" all become "SIGHUP").
+// An empty signal becomes DefaultKillSignal; unknown names return ErrInvalidSignal.
+func NormalizeSignal(signal string) (string, error) {
+	if signal == "" {
+		return DefaultKillSignal, nil
+	}
+
+	name := strings.ToUpper(strings.TrimSpace(signal))
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	if !knownSignals[name] {
+		return "", fmt.Errorf("%w %q", ErrInvalidSignal, signal)
+	}
+	return name, nil
+}
@@ -0,0 +1,27 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSignal(t *testing.T) {
+	for input, expected := range map[string]string{
+		"":         DefaultKillSignal,
+		"SIGHUP":   "SIGHUP",
+		"hup":      "SIGHUP",
+		" sigterm": "SIGTERM",
+		"WINCH":    "SIGWINCH",
+	} {
+		signal, err := NormalizeSignal(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, signal, input)
+	}
+
+	for _, input := range []string{"SIGBOGUS", "9", "SIG", "kill -9"} {
+		_, err := NormalizeSignal(input)
+		assert.ErrorIs(t, err, ErrInvalidSignal, input)
+	}
+}
@@ -0,0 +1,32 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// ErrInvalidSystemdOptions is returned for systemd options with invalid values
+var ErrInvalidSystemdOptions = errors.New("invalid systemd options")
+
+// systemdRestartPolicies are the values systemd accepts for Restart=
+var systemdRestartPolicies = map[string]bool{
+	"no": true, "on-success": true, "on-failure": true, "on-abnormal": true,
+	"on-watchdog": true, "on-abort": true, "always": true,
+}
+
+// systemdUnitPrefix matches the characters allowed in a systemd unit name prefix
+var systemdUnitPrefix = regexp.MustCompile(`^[a-zA-Z0-9:_.-]*$`)
+
+// ValidateSystemdOptions checks that the name prefix and restart policy can be used in a unit file
+func ValidateSystemdOptions(opts models.SystemdOptions) error {
+	if !systemdUnitPrefix.MatchString(opts.NamePrefix) {
+		return fmt.Errorf("%w: prefix %q contains characters not allowed in unit names", ErrInvalidSystemdOptions, opts.NamePrefix)
+	}
+	if opts.RestartPolicy != "" && !systemdRestartPolicies[opts.RestartPolicy] {
+		return fmt.Errorf("%w: unknown restart policy %q", ErrInvalidSystemdOptions, opts.RestartPolicy)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSystemdOptions(t *testing.T) {
+	valid := []models.SystemdOptions{
+		{},
+		{NamePrefix: "app", RestartPolicy: "always", New: true},
+		{NamePrefix: "my-app_1.service:x", RestartPolicy: "on-abnormal"},
+	}
+	for _, opts := range valid {
+		assert.NoError(t, ValidateSystemdOptions(opts), opts)
+	}
+
+	invalid := []models.SystemdOptions{
+		{NamePrefix: "../app"},
+		{NamePrefix: "with space"},
+		{RestartPolicy: "unless-stopped"},
+		{RestartPolicy: "on-failure:3"},
+	}
+	for _, opts := range invalid {
+		assert.ErrorIs(t, ValidateSystemdOptions(opts), ErrInvalidSystemdOptions, opts)
+	}
+}
@@ -0,0 +1,39 @@
+package runtime
+
+import (
+	"strings"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// topDescriptors are the ps columns requested from both runtimes
+var topDescriptors = []string{"pid", "user", "pcpu", "rss", "args"}
+
+// parseTopOutput normalizes a top table (column titles and rows) into ProcessInfo entries
+func parseTopOutput(titles []string, rows [][]string) []models.ProcessInfo {
+	columns := make(map[string]int, len(titles))
+	for i, title := range titles {
+		columns[strings.ToUpper(strings.TrimSpace(title))] = i
+	}
+
+	cell := func(row []string, names ...string) string {
+		for _, name := range names {
+			if idx, ok := columns[name]; ok && idx < len(row) {
+				return strings.TrimSpace(row[idx])
+			}
+		}
+		return ""
+	}
+
+	processes := make([]models.ProcessInfo, 0, len(rows))
+	for _, row := range rows {
+		processes = append(processes, models.ProcessInfo{
+			PID:     cell(row, "PID"),
+			User:    cell(row, "USER", "UID"),
+			CPU:     cell(row, "%CPU", "C"),
+			Memory:  cell(row, "RSS", "%MEM"),
+			Command: cell(row, "COMMAND", "CMD"),
+		})
+	}
+	return processes
+}
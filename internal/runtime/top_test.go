@@ -0,0 +1,38 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTopOutput(t *testing.T) {
+	titles := []string{"PID", "USER", "%CPU", "RSS", "COMMAND"}
+	rows := [][]string{
+		{"1", "root", "0.5", "2048", "nginx: master process nginx -g daemon off;"},
+		{"29", "nginx", "0.0", "1024", "nginx: worker process"},
+	}
+
+	processes := parseTopOutput(titles, rows)
+	assert.Len(t, processes, 2)
+	assert.Equal(t, "1", processes[0].PID)
+	assert.Equal(t, "root", processes[0].User)
+	assert.Equal(t, "0.5", processes[0].CPU)
+	assert.Equal(t, "2048", processes[0].Memory)
+	assert.Equal(t, "nginx: master process nginx -g daemon off;", processes[0].Command)
+	assert.Equal(t, "nginx", processes[1].User)
+}
+
+func TestParseTopOutputDefaultColumns(t *testing.T) {
+	// Default `ps -ef` columns as returned when custom descriptors are ignored
+	titles := []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"}
+	rows := [][]string{{"root", "1", "0", "0", "10:00", "?", "00:00:00", "sleep infinity"}}
+
+	processes := parseTopOutput(titles, rows)
+	assert.Len(t, processes, 1)
+	assert.Equal(t, "1", processes[0].PID)
+	assert.Equal(t, "root", processes[0].User)
+	assert.Equal(t, "0", processes[0].CPU)
+	assert.Equal(t, "", processes[0].Memory)
+	assert.Equal(t, "sleep infinity", processes[0].Command)
+}
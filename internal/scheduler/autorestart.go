@@ -0,0 +1,255 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+)
+
+const (
+	// maxRestartBackoff caps the delay between restarts of a crash-looping container
+	maxRestartBackoff = 5 * time.Minute
+
+	// eventReconnectDelay is the wait before an ended event stream is opened again
+	eventReconnectDelay = 5 * time.Second
+)
+
+// AutoRestarter starts containers again that exit with a non-zero code. It follows the
+// event streams of all runtimes; containers stopped or killed on request are left alone.
+// Restarts are delayed with an exponential backoff and capped per container within a window.
+type AutoRestarter struct {
+	runtimeManager *runtime.Manager
+
+	mu       sync.Mutex
+	config   config.AutoRestartConfig
+	cancel   context.CancelFunc     // Stops the event watchers, nil while disabled
+	restarts map[string][]time.Time // Runtime/container ID -> restarts within the window
+	stopping map[string]bool        // Runtime/container ID -> stop or kill seen since the last start
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) bool // Waits for d, false if ctx ended first
+}
+
+// NewAutoRestarter creates a disabled auto-restarter for the runtimes of the manager
+func NewAutoRestarter(runtimeManager *runtime.Manager) *AutoRestarter {
+	return &AutoRestarter{
+		runtimeManager: runtimeManager,
+		restarts:       make(map[string][]time.Time),
+		stopping:       make(map[string]bool),
+		now:            time.Now,
+		sleep:          sleepContext,
+	}
+}
+
+// UpdateConfig applies the config and (re)starts watching the registered runtimes when enabled.
+// It is meant to be called again after the runtimes changed.
+func (a *AutoRestarter) UpdateConfig(cfg config.AutoRestartConfig) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cancel != nil {
+		a.cancel()
+		a.cancel = nil
+	}
+	a.config = cfg
+	if !cfg.Enabled {
+		logger.Debug("AutoRestarter.UpdateConfig: Auto-restart disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	for name, rt := range a.runtimeManager.GetAllRuntimes() {
+		go a.watch(ctx, name, rt)
+	}
+	logger.Info("AutoRestarter.UpdateConfig: Auto-restart enabled", "filters", cfg.Filters, "exclude", cfg.Exclude, "max_restarts", cfg.MaxRestarts, "window_minutes", cfg.WindowMinutes)
+}
+
+// Stop stops watching the runtimes
+func (a *AutoRestarter) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil {
+		a.cancel()
+		a.cancel = nil
+	}
+}
+
+// watch handles the events of one runtime until ctx is cancelled, reopening the stream when it ends
+func (a *AutoRestarter) watch(ctx context.Context, runtimeName string, rt runtime.ContainerRuntime) {
+	for ctx.Err() == nil {
+		events, err := rt.StreamEvents(ctx)
+		if err != nil {
+			logger.Warn("AutoRestarter.watch: Failed to stream events", "runtime", runtimeName, "error", err)
+		} else {
+			for event := range events {
+				a.handleEvent(ctx, runtimeName, rt, event)
+			}
+		}
+
+		if !a.sleep(ctx, eventReconnectDelay) {
+			return
+		}
+		logger.Debug("AutoRestarter.watch: Reconnecting to event stream", "runtime", runtimeName)
+	}
+}
+
+// handleEvent tracks containers being stopped on request and schedules a restart for unexpected exits
+func (a *AutoRestarter) handleEvent(ctx context.Context, runtimeName string, rt runtime.ContainerRuntime, event models.RuntimeEvent) {
+	key := runtimeName + "/" + event.ContainerID
+
+	switch event.Type {
+	case "stop", "kill":
+		// Sent for stop/kill requests (Docker before the exit, Podman possibly after it).
+		// Kills with signals like SIGHUP, which reload rather than stop many services, do not count.
+		if event.Type == "kill" && !isTerminatingSignal(event.Attributes["signal"]) {
+			return
+		}
+		a.mu.Lock()
+		a.stopping[key] = true
+		a.mu.Unlock()
+		return
+	case "start":
+		a.mu.Lock()
+		delete(a.stopping, key)
+		a.mu.Unlock()
+		return
+	case "destroy":
+		// Container IDs are not reused, so nothing is kept for removed containers
+		a.mu.Lock()
+		delete(a.stopping, key)
+		delete(a.restarts, key)
+		a.mu.Unlock()
+		return
+	case "die":
+	default:
+		return
+	}
+
+	fields := []interface{}{"runtime", runtimeName, "container", event.ContainerName, "id", event.ContainerID, "exit_code", event.ExitCode}
+	if event.ExitCode == 0 {
+		logger.Info("AutoRestarter: Container exited cleanly, not restarting", fields...)
+		return
+	}
+
+	a.mu.Lock()
+	cfg := a.config
+	a.mu.Unlock()
+
+	container := models.ContainerInfo{ID: event.ContainerID, Name: event.ContainerName, Labels: event.Attributes}
+	if !matchesAutoRestart(container, cfg) {
+		logger.Debug("AutoRestarter: Container not selected for auto-restart", fields...)
+		return
+	}
+	if a.isStopping(key) {
+		logger.Info("AutoRestarter: Container was stopped on request, not restarting", fields...)
+		return
+	}
+
+	delay, ok := a.reserveRestart(key, cfg)
+	if !ok {
+		logger.Warn("AutoRestarter: Restart limit reached, not restarting", append(fields, "max_restarts", cfg.MaxRestarts, "window_minutes", cfg.WindowMinutes)...)
+		return
+	}
+
+	logger.Info("AutoRestarter: Container exited unexpectedly, restarting", append(fields, "delay", delay)...)
+	go func() {
+		if !a.sleep(ctx, delay) {
+			return
+		}
+		// Podman may report the stop request only after the exit
+		if a.isStopping(key) {
+			logger.Info("AutoRestarter: Container was stopped on request, not restarting", fields...)
+			return
+		}
+
+		if err := rt.StartContainer(ctx, event.ContainerID); err != nil {
+			logger.Error("AutoRestarter: Failed to restart container", append(fields, "error", err)...)
+			return
+		}
+		logger.Info("AutoRestarter: Restarted container", fields...)
+	}()
+}
+
+// terminatingSignals are the kill signals sent to stop a container, by number and name
+var terminatingSignals = map[string]bool{
+	"2": true, "3": true, "9": true, "15": true,
+	"SIGINT": true, "SIGQUIT": true, "SIGKILL": true, "SIGTERM": true,
+	"INT": true, "QUIT": true, "KILL": true, "TERM": true,
+}
+
+// isTerminatingSignal reports whether the signal attribute of a kill event stops the container.
+// Runtimes that do not report the signal are assumed to kill the container.
+func isTerminatingSignal(signal string) bool {
+	return signal == "" || terminatingSignals[strings.ToUpper(signal)]
+}
+
+// isStopping reports whether a stop or kill was requested since the container last started
+func (a *AutoRestarter) isStopping(key string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stopping[key]
+}
+
+// reserveRestart counts a restart of the container within the window and returns the
+// backoff to wait before it, or false when the container was restarted too often
+func (a *AutoRestarter) reserveRestart(key string, cfg config.AutoRestartConfig) (time.Duration, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	window := time.Duration(cfg.WindowMinutes) * time.Minute
+	recent := a.restarts[key][:0]
+	for _, t := range a.restarts[key] {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= cfg.MaxRestarts {
+		a.restarts[key] = recent
+		return 0, false
+	}
+
+	a.restarts[key] = append(recent, now)
+	return restartBackoff(time.Duration(cfg.BackoffSeconds)*time.Second, len(recent)), true
+}
+
+// restartBackoff doubles the base delay for each earlier restart, up to maxRestartBackoff
+func restartBackoff(base time.Duration, previous int) time.Duration {
+	delay := base
+	for i := 0; i < previous && delay < maxRestartBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRestartBackoff)
+}
+
+// matchesAutoRestart reports whether a container is selected by the filters and not excluded
+func matchesAutoRestart(container models.ContainerInfo, cfg config.AutoRestartConfig) bool {
+	if !matchesIncludes(container, cfg.Filters) {
+		return false
+	}
+	for _, exclude := range cfg.Exclude {
+		if matchesContainer(container, exclude) {
+			return false
+		}
+	}
+	return true
+}
+
+// sleepContext waits for d and reports false if ctx ends first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestAutoRestarter returns an enabled auto-restarter for the mock that does not wait between restarts
+func newTestAutoRestarter(t *testing.T, rt *mockRuntime, cfg config.AutoRestartConfig) *AutoRestarter {
+	t.Helper()
+	manager := runtime.NewManager()
+	manager.RegisterRuntime("mock", rt)
+
+	a := NewAutoRestarter(manager)
+	a.sleep = func(ctx context.Context, d time.Duration) bool { return ctx.Err() == nil }
+	cfg.Enabled = true
+	a.UpdateConfig(cfg)
+	t.Cleanup(a.Stop)
+	return a
+}
+
+func TestAutoRestarter(t *testing.T) {
+	rt := &mockRuntime{events: make(chan models.RuntimeEvent)}
+	newTestAutoRestarter(t, rt, config.AutoRestartConfig{
+		Filters:     []string{"web-*", "label:restart=yes"},
+		Exclude:     []string{"web-debug"},
+		MaxRestarts: 2, WindowMinutes: 10,
+	})
+
+	for _, event := range []models.RuntimeEvent{
+		{Type: "die", ContainerID: "crashed", ContainerName: "web-1", ExitCode: 1},
+		{Type: "die", ContainerID: "clean", ContainerName: "web-2", ExitCode: 0},
+		{Type: "die", ContainerID: "other", ContainerName: "db", ExitCode: 1},
+		{Type: "die", ContainerID: "labeled", ContainerName: "worker", ExitCode: 2, Attributes: map[string]string{"restart": "yes"}},
+		{Type: "die", ContainerID: "excluded", ContainerName: "web-debug", ExitCode: 1},
+		// Stopped on request: kill before the exit
+		{Type: "kill", ContainerID: "stopped", ContainerName: "web-3"},
+		{Type: "die", ContainerID: "stopped", ContainerName: "web-3", ExitCode: 137},
+		// A SIGHUP is no stop request; the exit after it is a crash
+		{Type: "kill", ContainerID: "reloaded", ContainerName: "web-4", Attributes: map[string]string{"signal": "1"}},
+		{Type: "die", ContainerID: "reloaded", ContainerName: "web-4", ExitCode: 1},
+		{Type: "kill", ContainerID: "terminated", ContainerName: "web-5", Attributes: map[string]string{"signal": "15"}},
+		{Type: "die", ContainerID: "terminated", ContainerName: "web-5", ExitCode: 143},
+	} {
+		rt.events <- event
+	}
+
+	assert.Eventually(t, func() bool { return len(rt.startedIDs()) == 3 }, time.Second, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.ElementsMatch(t, []string{"crashed", "labeled", "reloaded"}, rt.startedIDs())
+}
+
+func TestAutoRestarterStartAndDestroyClearState(t *testing.T) {
+	rt := &mockRuntime{events: make(chan models.RuntimeEvent)}
+	a := newTestAutoRestarter(t, rt, config.AutoRestartConfig{MaxRestarts: 1, WindowMinutes: 10})
+
+	// A start after a stop request makes the next crash restart the container again
+	rt.events <- models.RuntimeEvent{Type: "stop", ContainerID: "app", ContainerName: "app"}
+	rt.events <- models.RuntimeEvent{Type: "start", ContainerID: "app", ContainerName: "app"}
+	rt.events <- models.RuntimeEvent{Type: "die", ContainerID: "app", ContainerName: "app", ExitCode: 1}
+	assert.Eventually(t, func() bool { return len(rt.startedIDs()) == 1 }, time.Second, 10*time.Millisecond)
+
+	rt.events <- models.RuntimeEvent{Type: "stop", ContainerID: "app", ContainerName: "app"}
+	rt.events <- models.RuntimeEvent{Type: "destroy", ContainerID: "app", ContainerName: "app"}
+	// Sent after destroy was handled, as the channel is unbuffered
+	rt.events <- models.RuntimeEvent{Type: "start", ContainerID: "other", ContainerName: "other"}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	assert.Empty(t, a.stopping)
+	assert.NotContains(t, a.restarts, "mock/app")
+}
+
+func TestAutoRestarterLimit(t *testing.T) {
+	rt := &mockRuntime{events: make(chan models.RuntimeEvent)}
+	a := newTestAutoRestarter(t, rt, config.AutoRestartConfig{MaxRestarts: 2, WindowMinutes: 10})
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	a.mu.Lock()
+	a.now = func() time.Time { return now }
+	a.mu.Unlock()
+
+	crash := models.RuntimeEvent{Type: "die", ContainerID: "loop", ContainerName: "app", ExitCode: 1}
+	for i := 0; i < 3; i++ {
+		rt.events <- crash
+		rt.events <- models.RuntimeEvent{Type: "start", ContainerID: "loop"}
+	}
+	assert.Eventually(t, func() bool { return len(rt.startedIDs()) == 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, rt.startedIDs(), 2)
+
+	// Restarts outside the window no longer count
+	a.mu.Lock()
+	now = now.Add(11 * time.Minute)
+	a.mu.Unlock()
+	rt.events <- crash
+	assert.Eventually(t, func() bool { return len(rt.startedIDs()) == 3 }, time.Second, 10*time.Millisecond)
+}
+
+func TestRestartBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, restartBackoff(time.Second, 0))
+	assert.Equal(t, 4*time.Second, restartBackoff(time.Second, 2))
+	assert.Equal(t, maxRestartBackoff, restartBackoff(time.Second, 20))
+	assert.Equal(t, time.Duration(0), restartBackoff(0, 3))
+}
+
+func TestAutoRestarterDisabled(t *testing.T) {
+	rt := &mockRuntime{events: make(chan models.RuntimeEvent, 1)}
+	manager := runtime.NewManager()
+	manager.RegisterRuntime("mock", rt)
+
+	a := NewAutoRestarter(manager)
+	a.UpdateConfig(config.AutoRestartConfig{Enabled: false})
+	defer a.Stop()
+
+	rt.events <- models.RuntimeEvent{Type: "die", ContainerID: "crashed", ContainerName: "web", ExitCode: 1}
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, rt.startedIDs())
+}
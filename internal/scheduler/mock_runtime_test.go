@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+)
+
+// mockRuntime is a ContainerRuntime that serves a fixed container list and records updates.
+// Methods the scheduler does not use are left to the embedded nil interface.
+type mockRuntime struct {
+	runtime.ContainerRuntime
+
+	containers []models.ContainerInfo
+	updateErrs map[string]error // Container ID -> error returned by UpdateContainer
+	outdated   map[string]bool  // Container ID -> newer image available
+
+	events chan models.RuntimeEvent // Returned by StreamEvents
+
+	updateDelay time.Duration // Time each UpdateContainer call takes
+
+	mu        sync.Mutex
+	updated   []string
+	started   []string
+	updating  int                             // UpdateContainer calls in progress
+	maxActive int                             // Most UpdateContainer calls seen in progress at once
+	auths     map[string]*models.RegistryAuth // Container ID -> credentials passed for its image
+}
+
+func (m *mockRuntime) ListContainers(ctx context.Context, filters models.FilterOptions) ([]models.ContainerInfo, error) {
+	return m.containers, nil
+}
+
+func (m *mockRuntime) UpdateContainer(ctx context.Context, containerID string, auth runtime.RegistryAuthLookup) error {
+	m.mu.Lock()
+	m.recordAuth(containerID, auth)
+	m.updating++
+	m.maxActive = max(m.maxActive, m.updating)
+	m.mu.Unlock()
+
+	time.Sleep(m.updateDelay)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updating--
+	m.updated = append(m.updated, containerID)
+	return m.updateErrs[containerID]
+}
+
+func (m *mockRuntime) CheckImageUpdate(ctx context.Context, containerID string, auth runtime.RegistryAuthLookup) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recordAuth(containerID, auth)
+	return m.outdated[containerID], nil
+}
+
+// recordAuth keeps the credentials the lookup returns for the container's image, m.mu must be held
+func (m *mockRuntime) recordAuth(containerID string, auth runtime.RegistryAuthLookup) {
+	if auth == nil {
+		return
+	}
+	for _, container := range m.containers {
+		if container.ID == containerID {
+			if m.auths == nil {
+				m.auths = map[string]*models.RegistryAuth{}
+			}
+			m.auths[containerID] = auth(container.Image)
+		}
+	}
+}
+
+func (m *mockRuntime) StartContainer(ctx context.Context, containerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started = append(m.started, containerID)
+	return nil
+}
+
+func (m *mockRuntime) StreamEvents(ctx context.Context) (<-chan models.RuntimeEvent, error) {
+	return m.events, nil
+}
+
+func (m *mockRuntime) GetRuntimeName() string {
+	return "mock"
+}
+
+// updatedIDs returns the IDs of all containers UpdateContainer was called for
+func (m *mockRuntime) updatedIDs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.updated...)
+}
+
+// startedIDs returns the IDs of all containers StartContainer was called for
+func (m *mockRuntime) startedIDs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.started...)
+}
+
+// newMockScheduler returns a scheduler whose only runtime is the given mock
+func newMockScheduler(rt *mockRuntime) *Scheduler {
+	manager := runtime.NewManager()
+	manager.RegisterRuntime("mock", rt)
+	return NewScheduler(manager)
+}
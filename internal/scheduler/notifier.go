@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/logger"
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+const (
+	webhookTimeout    = 5 * time.Second
+	webhookAttempts   = 3
+	webhookRetryDelay = 2 * time.Second
+)
+
+// webhookNotifier posts a summary of scheduler runs to a webhook
+type webhookNotifier struct {
+	url        string
+	on         string
+	client     *http.Client
+	retryDelay time.Duration
+}
+
+// webhookContainer is a container entry in the webhook payload
+type webhookContainer struct {
+	Name    string `json:"name"`
+	ID      string `json:"id"`
+	Runtime string `json:"runtime"`
+	Error   string `json:"error,omitempty"`
+}
+
+// webhookPayload is the JSON body sent to the webhook.
+// Text and Content carry the same summary for Slack and Discord respectively.
+type webhookPayload struct {
+	Text          string             `json:"text"`
+	Content       string             `json:"content"`
+	Job           string             `json:"job"`
+	StartedAt     time.Time          `json:"started_at"`
+	FinishedAt    time.Time          `json:"finished_at"`
+	DryRun        bool               `json:"dry_run"`
+	Updated       []webhookContainer `json:"updated"`
+	Failed        []webhookContainer `json:"failed"`
+	WouldUpdate   []webhookContainer `json:"would_update,omitempty"`   // Dry run: containers with a newer image
+	SkippedReason string             `json:"skipped_reason,omitempty"` // Set when the run did not start
+}
+
+// newWebhookNotifier returns a notifier for the config, or nil if notifications are disabled
+func newWebhookNotifier(cfg config.NotificationsConfig) *webhookNotifier {
+	if cfg.WebhookURL == "" {
+		return nil
+	}
+
+	on := cfg.On
+	if on == "" {
+		on = config.NotifyAlways
+	}
+
+	return &webhookNotifier{
+		url:        cfg.WebhookURL,
+		on:         on,
+		client:     &http.Client{Timeout: webhookTimeout},
+		retryDelay: webhookRetryDelay,
+	}
+}
+
+// shouldNotify reports whether a run matches the configured event filter
+func (n *webhookNotifier) shouldNotify(report models.UpdateReport) bool {
+	switch n.on {
+	case config.NotifyOnSuccess:
+		return report.Updated > 0 && report.Failed == 0
+	case config.NotifyOnFailure:
+		return report.Failed > 0 || report.SkippedReason != ""
+	default:
+		return true
+	}
+}
+
+// buildPayload summarizes the updated and failed containers of a run
+func buildPayload(report models.UpdateReport) webhookPayload {
+	payload := webhookPayload{
+		Job:           report.Job,
+		StartedAt:     report.StartedAt,
+		FinishedAt:    report.FinishedAt,
+		DryRun:        report.DryRun,
+		Updated:       []webhookContainer{},
+		Failed:        []webhookContainer{},
+		SkippedReason: report.SkippedReason,
+	}
+
+	if report.SkippedReason != "" {
+		payload.Text = fmt.Sprintf("Gintainer update run %q skipped: %s", report.Job, report.SkippedReason)
+		payload.Content = payload.Text
+		return payload
+	}
+
+	for _, result := range report.Results {
+		entry := webhookContainer{
+			Name:    result.ContainerName,
+			ID:      result.ContainerID,
+			Runtime: result.Runtime,
+			Error:   result.Error,
+		}
+		switch result.Status {
+		case models.UpdateStatusUpdated:
+			payload.Updated = append(payload.Updated, entry)
+		case models.UpdateStatusFailed:
+			payload.Failed = append(payload.Failed, entry)
+		case models.UpdateStatusWouldUpdate:
+			payload.WouldUpdate = append(payload.WouldUpdate, entry)
+		}
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Gintainer update run %q: %d updated, %d failed", report.Job, len(payload.Updated), len(payload.Failed))
+	if len(payload.WouldUpdate) > 0 {
+		fmt.Fprintf(&summary, ", %d would update (dry run)", len(payload.WouldUpdate))
+	}
+	for _, c := range payload.Failed {
+		fmt.Fprintf(&summary, "\n- %s (%s): %s", c.Name, c.Runtime, c.Error)
+	}
+	payload.Text = summary.String()
+	payload.Content = payload.Text
+
+	return payload
+}
+
+// notify sends the run summary if it matches the event filter.
+// Failures are logged and never returned to the caller.
+func (n *webhookNotifier) notify(report models.UpdateReport) {
+	if !n.shouldNotify(report) {
+		return
+	}
+
+	if err := n.send(context.Background(), buildPayload(report)); err != nil {
+		logger.Error("Notifier: Failed to send webhook notification", "job", report.Job, "error", err)
+	}
+}
+
+// send posts the payload, retrying on network errors and non-2xx responses
+func (n *webhookNotifier) send(ctx context.Context, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(n.retryDelay)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := n.client.Do(req)
+		if err != nil {
+			lastErr = err
+			logger.Warn("Notifier: Webhook request failed", "attempt", attempt, "error", err)
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		logger.Warn("Notifier: Webhook returned unexpected status", "attempt", attempt, "status", resp.StatusCode)
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", webhookAttempts, lastErr)
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func testReport() models.UpdateReport {
+	return models.UpdateReport{
+		Job:       "nightly",
+		StartedAt: time.Now(),
+		Updated:   1,
+		Failed:    1,
+		Skipped:   1,
+		Results: []models.ContainerUpdateResult{
+			{ContainerID: "1", ContainerName: "web", Runtime: "docker", Status: models.UpdateStatusUpdated},
+			{ContainerID: "2", ContainerName: "api", Runtime: "podman", Status: models.UpdateStatusFailed, Error: "pull failed"},
+			{ContainerID: "3", ContainerName: "db", Runtime: "docker", Status: models.UpdateStatusSkipped},
+		},
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	received := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received <- payload
+	}))
+	defer server.Close()
+
+	n := newWebhookNotifier(config.NotificationsConfig{WebhookURL: server.URL})
+	n.notify(testReport())
+
+	payload := <-received
+	assert.Equal(t, "nightly", payload["job"])
+	assert.Contains(t, payload["text"], "1 updated, 1 failed")
+	assert.Equal(t, payload["text"], payload["content"])
+
+	updated := payload["updated"].([]any)
+	assert.Len(t, updated, 1)
+	assert.Equal(t, "web", updated[0].(map[string]any)["name"])
+
+	failed := payload["failed"].([]any)
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "api", failed[0].(map[string]any)["name"])
+	assert.Equal(t, "pull failed", failed[0].(map[string]any)["error"])
+}
+
+func TestWebhookPayloadSkippedRun(t *testing.T) {
+	payload := buildPayload(models.UpdateReport{Job: "nightly", SkippedReason: ErrRunInProgress.Error()})
+	assert.Equal(t, ErrRunInProgress.Error(), payload.SkippedReason)
+	assert.Contains(t, payload.Text, `"nightly" skipped: `+ErrRunInProgress.Error())
+	assert.Equal(t, payload.Text, payload.Content)
+	assert.Empty(t, payload.Updated)
+	assert.Empty(t, payload.Failed)
+}
+
+func TestWebhookRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < webhookAttempts {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := newWebhookNotifier(config.NotificationsConfig{WebhookURL: server.URL})
+	n.retryDelay = time.Millisecond
+
+	assert.NoError(t, n.send(t.Context(), buildPayload(testReport())))
+	assert.Equal(t, int32(webhookAttempts), calls.Load())
+}
+
+func TestWebhookGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := newWebhookNotifier(config.NotificationsConfig{WebhookURL: server.URL})
+	n.retryDelay = time.Millisecond
+
+	assert.Error(t, n.send(t.Context(), buildPayload(testReport())))
+}
+
+func TestWebhookEventFilter(t *testing.T) {
+	success := models.UpdateReport{Updated: 2}
+	failure := models.UpdateReport{Updated: 1, Failed: 1}
+	idle := models.UpdateReport{Skipped: 3}
+	skipped := models.UpdateReport{SkippedReason: ErrRunInProgress.Error()}
+
+	always := newWebhookNotifier(config.NotificationsConfig{WebhookURL: "http://example.invalid"})
+	assert.True(t, always.shouldNotify(success))
+	assert.True(t, always.shouldNotify(failure))
+	assert.True(t, always.shouldNotify(idle))
+
+	onSuccess := newWebhookNotifier(config.NotificationsConfig{WebhookURL: "http://example.invalid", On: config.NotifyOnSuccess})
+	assert.True(t, onSuccess.shouldNotify(success))
+	assert.False(t, onSuccess.shouldNotify(failure))
+	assert.False(t, onSuccess.shouldNotify(idle))
+
+	onFailure := newWebhookNotifier(config.NotificationsConfig{WebhookURL: "http://example.invalid", On: config.NotifyOnFailure})
+	assert.False(t, onFailure.shouldNotify(success))
+	assert.True(t, onFailure.shouldNotify(failure))
+	assert.True(t, onFailure.shouldNotify(skipped))
+	assert.False(t, onSuccess.shouldNotify(skipped))
+
+	assert.Nil(t, newWebhookNotifier(config.NotificationsConfig{}))
+}
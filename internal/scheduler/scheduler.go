@@ -2,28 +2,86 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/ThraaxSession/gintainer/internal/config"
 	"github.com/ThraaxSession/gintainer/internal/logger"
 	"github.com/ThraaxSession/gintainer/internal/models"
 	"github.com/ThraaxSession/gintainer/internal/runtime"
 	"github.com/robfig/cron/v3"
 )
 
+const (
+	// DefaultJobName is the name of the job built from the legacy single-job config
+	DefaultJobName = "default"
+
+	// ManualRunName is the job name reported for runs triggered through RunNow
+	ManualRunName = "manual"
+
+	// historySize is the number of past runs kept in memory
+	historySize = 20
+
+	// AutoUpdateLabel opts a container out of scheduled updates when set to "false"
+	AutoUpdateLabel = "gintainer.auto-update"
+
+	// labelFilterPrefix marks a filter that matches container labels instead of names
+	labelFilterPrefix = "label:"
+
+	// PreviewRuns is the number of upcoming run times returned by Preview
+	PreviewRuns = 5
+
+	// DefaultUpdateConcurrency is the number of containers updated at once when not configured
+	DefaultUpdateConcurrency = 3
+)
+
+// scheduleParser parses the standard five-field cron expressions and descriptors like @daily.
+// It is used for the cron entries and for previews, so both accept the same schedules.
+var scheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+var (
+	// ErrRunInProgress is returned when an update run is requested while another one is still running
+	ErrRunInProgress = errors.New("an update run is already in progress")
+
+	// ErrInvalidTimezone is returned for scheduler time zones that are not known IANA names
+	ErrInvalidTimezone = errors.New("unknown time zone")
+
+	// ErrInvalidSchedule is returned for schedules that are not valid cron expressions
+	ErrInvalidSchedule = errors.New("invalid cron expression")
+)
+
 // Scheduler manages cron jobs for automatic container updates
 type Scheduler struct {
 	cron           *cron.Cron
+	location       *time.Location // Time zone of the cron schedules
+	started        bool           // Set between Start and Stop, so a recreated cron is started too
 	runtimeManager *runtime.Manager
 	config         *models.CronJobConfig
 	mu             sync.RWMutex
-	jobID          cron.EntryID
+	jobs           []scheduledJob
+	running        atomic.Bool           // Set while an update run is in progress
+	history        []models.UpdateReport // Most recent run first
+	historyMu      sync.RWMutex
+	notifier       *webhookNotifier                      // nil when notifications are disabled
+	registries     map[string]config.RegistryCredentials // Credentials for pulling updated images
+}
+
+// scheduledJob is a job together with its cron entry
+type scheduledJob struct {
+	job     models.CronJob
+	entryID cron.EntryID
 }
 
 // NewScheduler creates a new scheduler
 func NewScheduler(runtimeManager *runtime.Manager) *Scheduler {
 	return &Scheduler{
-		cron:           cron.New(),
+		cron:           newCron(time.Local),
+		location:       time.Local,
 		runtimeManager: runtimeManager,
 		config: &models.CronJobConfig{
 			Schedule: "0 2 * * *", // Default: 2 AM daily
@@ -34,40 +92,184 @@ func NewScheduler(runtimeManager *runtime.Manager) *Scheduler {
 
 // Start starts the scheduler
 func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = true
 	s.cron.Start()
 }
 
 // Stop stops the scheduler
 func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = false
 	s.cron.Stop()
 }
 
-// UpdateConfig updates the scheduler configuration
+// newCron creates a cron evaluating its schedules in loc
+func newCron(loc *time.Location) *cron.Cron {
+	return cron.New(cron.WithLocation(loc), cron.WithParser(scheduleParser))
+}
+
+// LoadTimezone resolves an IANA time zone name; an empty name is the server's local time
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrInvalidTimezone, name)
+	}
+	return loc, nil
+}
+
+// Preview returns the next n run times after from of a schedule in the given time zone,
+// without scheduling anything. Schedules that never fire again yield fewer times.
+func Preview(schedule, timezone string, from time.Time, n int) ([]time.Time, error) {
+	loc, err := LoadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := scheduleParser.Parse(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidSchedule, schedule, err)
+	}
+
+	runs := make([]time.Time, 0, n)
+	next := from.In(loc)
+	for len(runs) < n {
+		if next = parsed.Next(next); next.IsZero() {
+			break
+		}
+		runs = append(runs, next)
+	}
+	return runs, nil
+}
+
+// Location returns the time zone the schedules are evaluated in
+func (s *Scheduler) Location() *time.Location {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.location
+}
+
+// jobsFromConfig returns the legacy single job as "default" followed by the named jobs
+func jobsFromConfig(config models.CronJobConfig) ([]models.CronJob, error) {
+	var jobs []models.CronJob
+	names := map[string]bool{}
+
+	if config.Schedule != "" {
+		jobs = append(jobs, models.CronJob{
+			Name:     DefaultJobName,
+			Schedule: config.Schedule,
+			Enabled:  config.Enabled,
+			Filters:  config.Filters,
+			Exclude:  config.Exclude,
+			DryRun:   config.DryRun,
+		})
+		names[DefaultJobName] = true
+	}
+
+	for _, job := range config.Jobs {
+		if job.Name == "" {
+			return nil, fmt.Errorf("scheduler job name must not be empty")
+		}
+		if names[job.Name] {
+			return nil, fmt.Errorf("duplicate scheduler job name %q", job.Name)
+		}
+		names[job.Name] = true
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// UpdateConfig updates the scheduler configuration, replacing all cron entries.
+// A changed time zone replaces the cron itself, as its location is fixed on creation.
 func (s *Scheduler) UpdateConfig(config models.CronJobConfig) error {
+	jobs, err := jobsFromConfig(config)
+	if err != nil {
+		return err
+	}
+	loc, err := LoadTimezone(config.Timezone)
+	if err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Remove existing job if any
-	if s.jobID != 0 {
-		s.cron.Remove(s.jobID)
-		s.jobID = 0
+	target := s.cron
+	if loc.String() != s.location.String() {
+		target = newCron(loc)
 	}
 
-	// Update config
-	s.config = &config
+	// Register the new entries first so a bad schedule leaves the current jobs untouched
+	scheduled := make([]scheduledJob, 0, len(jobs))
+	for _, job := range jobs {
+		sj := scheduledJob{job: job}
+		if job.Enabled {
+			job := job
+			entryID, err := target.AddFunc(job.Schedule, func() { s.runUpdate(job) })
+			if err != nil {
+				for _, added := range scheduled {
+					if added.entryID != 0 {
+						target.Remove(added.entryID)
+					}
+				}
+				return fmt.Errorf("failed to add cron job %q: %w", job.Name, err)
+			}
+			sj.entryID = entryID
+		}
+		scheduled = append(scheduled, sj)
+	}
 
-	// Add new job if enabled
-	if config.Enabled {
-		jobID, err := s.cron.AddFunc(config.Schedule, s.runUpdate)
-		if err != nil {
-			return fmt.Errorf("failed to add cron job: %w", err)
+	if target != s.cron {
+		// Running jobs of the old cron finish on their own
+		s.cron.Stop()
+		if s.started {
+			target.Start()
+		}
+		s.cron = target
+		s.location = loc
+		logger.Printf("Scheduler time zone set to %s", loc)
+	} else {
+		// Remove existing jobs
+		for _, sj := range s.jobs {
+			if sj.entryID != 0 {
+				s.cron.Remove(sj.entryID)
+			}
 		}
-		s.jobID = jobID
 	}
 
+	// Update config
+	s.config = &config
+	s.jobs = scheduled
+
 	return nil
 }
 
+// SetNotifications configures the webhook notified after each run
+func (s *Scheduler) SetNotifications(cfg config.NotificationsConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = newWebhookNotifier(cfg)
+}
+
+// SetRegistries sets the registry credentials used to pull the images of updated containers
+func (s *Scheduler) SetRegistries(registries map[string]config.RegistryCredentials) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.registries = registries
+}
+
+// registryAuth returns the configured credentials for the registry of an image
+func (s *Scheduler) registryAuth(imageName string) *models.RegistryAuth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return config.RegistryAuth(s.registries, imageName)
+}
+
 // GetConfig returns the current configuration
 func (s *Scheduler) GetConfig() models.CronJobConfig {
 	s.mu.RLock()
@@ -75,17 +277,86 @@ func (s *Scheduler) GetConfig() models.CronJobConfig {
 	return *s.config
 }
 
-// runUpdate executes the update job
-func (s *Scheduler) runUpdate() {
+// GetJobs returns all configured jobs, including the legacy "default" job
+func (s *Scheduler) GetJobs() []models.CronJob {
 	s.mu.RLock()
-	config := *s.config
-	s.mu.RUnlock()
+	defer s.mu.RUnlock()
+
+	jobs := make([]models.CronJob, 0, len(s.jobs))
+	for _, sj := range s.jobs {
+		jobs = append(jobs, sj.job)
+	}
+	return jobs
+}
 
-	logger.Println("Starting scheduled container update")
+// RunNow runs all enabled jobs immediately and returns a per-container report.
+// A container selected by several jobs is updated once. If dryRun is set, every job
+// runs in dry-run mode regardless of its own setting. It returns ErrRunInProgress
+// if another run, cron-triggered or manual, has not finished yet.
+func (s *Scheduler) RunNow(ctx context.Context, dryRun bool) (models.UpdateReport, error) {
+	var jobs []models.CronJob
+	for _, job := range s.GetJobs() {
+		if job.Enabled {
+			if dryRun {
+				job.DryRun = true
+			}
+			jobs = append(jobs, job)
+		}
+	}
+
+	return s.run(ctx, ManualRunName, jobs)
+}
+
+// runUpdate executes the update job
+func (s *Scheduler) runUpdate(job models.CronJob) {
+	if _, err := s.run(context.Background(), job.Name, []models.CronJob{job}); err != nil {
+		logger.Printf("Skipping scheduled container update (job: %s): %v", job.Name, err)
+	}
+}
+
+// run updates every container selected by at least one of the jobs.
+// A run requested while another one is in progress is recorded and notified as skipped.
+func (s *Scheduler) run(ctx context.Context, name string, jobs []models.CronJob) (models.UpdateReport, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		now := time.Now()
+		report := models.UpdateReport{
+			Job:           name,
+			StartedAt:     now,
+			FinishedAt:    now,
+			Results:       []models.ContainerUpdateResult{},
+			SkippedReason: ErrRunInProgress.Error(),
+		}
+		s.recordRun(report)
+		s.notify(report)
+		return report, ErrRunInProgress
+	}
+	defer s.running.Store(false)
+
+	logger.Printf("Starting scheduled container update (job: %s)", name)
+
+	report := models.UpdateReport{
+		Job:       name,
+		DryRun:    len(jobs) > 0,
+		StartedAt: time.Now(),
+		Results:   []models.ContainerUpdateResult{},
+	}
+	for _, job := range jobs {
+		report.DryRun = report.DryRun && job.DryRun
+	}
 
-	ctx := context.Background()
+	concurrency := s.GetConfig().UpdateConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultUpdateConcurrency
+	}
 
-	// Update containers across all runtimes
+	// Collect the containers across all runtimes; each result keeps its place in the report
+	type pendingUpdate struct {
+		index     int
+		rt        runtime.ContainerRuntime
+		container models.ContainerInfo
+		dryRun    bool
+	}
+	var pending []pendingUpdate
 	for runtimeName, rt := range s.runtimeManager.GetAllRuntimes() {
 		logger.Printf("Updating containers in %s runtime", runtimeName)
 
@@ -96,50 +367,218 @@ func (s *Scheduler) runUpdate() {
 			continue
 		}
 
-		// Update each container
 		for _, container := range containers {
-			// Apply filters if specified
-			if len(config.Filters) > 0 {
-				shouldUpdate := false
-				for _, filter := range config.Filters {
-					if matchesFilter(container.Name, filter) {
-						shouldUpdate = true
-						break
-					}
-				}
-				if !shouldUpdate {
-					continue
-				}
+			result := models.ContainerUpdateResult{
+				ContainerID:   container.ID,
+				ContainerName: container.Name,
+				Runtime:       runtimeName,
 			}
 
-			logger.Printf("Updating container: %s (%s)", container.Name, container.ID)
-			if err := rt.UpdateContainer(ctx, container.ID); err != nil {
-				logger.Printf("Failed to update container %s: %v", container.ID, err)
+			selected, dryRun := selectContainer(container, jobs)
+			if !selected {
+				result.Status = models.UpdateStatusSkipped
 			} else {
-				logger.Printf("Successfully updated container: %s", container.Name)
+				pending = append(pending, pendingUpdate{index: len(report.Results), rt: rt, container: container, dryRun: dryRun})
 			}
+			report.Results = append(report.Results, result)
+		}
+	}
+
+	// Update up to concurrency containers at once, a failed update does not stop the others
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for _, p := range pending {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			updateContainer(ctx, p.rt, p.container, p.dryRun, s.registryAuth, &report.Results[p.index])
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Results {
+		switch result.Status {
+		case models.UpdateStatusUpdated:
+			report.Updated++
+		case models.UpdateStatusFailed:
+			report.Failed++
+		case models.UpdateStatusSkipped:
+			report.Skipped++
+		case models.UpdateStatusWouldUpdate:
+			report.WouldUpdate++
+		case models.UpdateStatusUpToDate:
+			report.UpToDate++
 		}
 	}
 
-	logger.Println("Scheduled container update completed")
+	report.FinishedAt = time.Now()
+	report.DurationMs = report.FinishedAt.Sub(report.StartedAt).Milliseconds()
+	s.recordRun(report)
+	logger.Printf("Scheduled container update completed (job: %s)", name)
+
+	s.notify(report)
+
+	return report, nil
 }
 
-// matchesFilter checks if a container name matches a filter pattern
-func matchesFilter(name, pattern string) bool {
-	// Simple substring matching for now
-	// In production, you might want to use glob patterns or regex
-	return len(pattern) == 0 || name == pattern || contains(name, pattern)
+// updateContainer updates one selected container and sets the status of its result.
+// In dry-run mode the image is only pulled and compared, the container is never stopped or recreated.
+func updateContainer(ctx context.Context, rt runtime.ContainerRuntime, container models.ContainerInfo, dryRun bool, auth runtime.RegistryAuthLookup, result *models.ContainerUpdateResult) {
+	if dryRun {
+		outdated, err := rt.CheckImageUpdate(ctx, container.ID, auth)
+		switch {
+		case err != nil:
+			logger.Printf("Failed to check container %s for updates: %v", container.ID, err)
+			result.Status = models.UpdateStatusFailed
+			result.Error = err.Error()
+		case outdated:
+			logger.Printf("Dry run: container %s would be updated", container.Name)
+			result.Status = models.UpdateStatusWouldUpdate
+		default:
+			result.Status = models.UpdateStatusUpToDate
+		}
+		return
+	}
+
+	logger.Printf("Updating container: %s (%s)", container.Name, container.ID)
+	if err := rt.UpdateContainer(ctx, container.ID, auth); err != nil {
+		logger.Printf("Failed to update container %s: %v", container.ID, err)
+		result.Status = models.UpdateStatusFailed
+		result.Error = err.Error()
+		return
+	}
+	logger.Printf("Successfully updated container: %s", container.Name)
+	result.Status = models.UpdateStatusUpdated
+}
+
+// recordRun adds a report to the run history, dropping the oldest entries beyond historySize
+func (s *Scheduler) recordRun(report models.UpdateReport) {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	s.history = append([]models.UpdateReport{report}, s.history...)
+	if len(s.history) > historySize {
+		s.history = s.history[:historySize]
+	}
 }
 
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || containsMiddle(s, substr)))
+// notify sends the report to the configured notifier in the background
+func (s *Scheduler) notify(report models.UpdateReport) {
+	s.mu.RLock()
+	notifier := s.notifier
+	s.mu.RUnlock()
+	if notifier != nil {
+		go notifier.notify(report)
+	}
 }
 
-func containsMiddle(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
+// GetHistory returns the reports of the most recent runs, newest first
+func (s *Scheduler) GetHistory() []models.UpdateReport {
+	s.historyMu.RLock()
+	defer s.historyMu.RUnlock()
+	return append([]models.UpdateReport{}, s.history...)
+}
+
+// NextRuns returns the next scheduled run time of every enabled job, keyed by job name.
+// Times are only known once the scheduler has been started.
+func (s *Scheduler) NextRuns() map[string]time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	next := make(map[string]time.Time)
+	for _, sj := range s.jobs {
+		if sj.entryID == 0 {
+			continue
+		}
+		if t := s.cron.Entry(sj.entryID).Next; !t.IsZero() {
+			next[sj.job.Name] = t
+		}
+	}
+	return next
+}
+
+// selectContainer reports whether at least one job selects the container, and whether
+// it must only be checked because every selecting job is a dry run
+func selectContainer(container models.ContainerInfo, jobs []models.CronJob) (selected, dryRun bool) {
+	dryRun = true
+	for _, job := range jobs {
+		if shouldUpdate(container, job) {
+			selected = true
+			dryRun = dryRun && job.DryRun
+		}
+	}
+	return selected, selected && dryRun
+}
+
+// shouldUpdate reports whether a container is selected by a job.
+// Name and label filters are combined with OR semantics; without filters every
+// container is selected. Exclude patterns are evaluated afterwards and always win,
+// and containers labeled gintainer.auto-update=false are always skipped.
+func shouldUpdate(container models.ContainerInfo, job models.CronJob) bool {
+	if value, ok := container.Labels[AutoUpdateLabel]; ok && strings.EqualFold(value, "false") {
+		return false
+	}
+
+	if !matchesIncludes(container, job.Filters) {
+		return false
+	}
+
+	for _, exclude := range job.Exclude {
+		if matchesContainer(container, exclude) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesIncludes reports whether any include filter matches; an empty list matches everything
+func matchesIncludes(container models.ContainerInfo, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	for _, filter := range filters {
+		if matchesContainer(container, filter) {
 			return true
 		}
 	}
 	return false
 }
+
+// matchesContainer checks a single filter against a container.
+// Filters of the form "label:key=value" match a label value, "label:key" matches
+// the presence of a label, and anything else is a name pattern.
+func matchesContainer(container models.ContainerInfo, filter string) bool {
+	if selector, ok := strings.CutPrefix(filter, labelFilterPrefix); ok {
+		key, value, hasValue := strings.Cut(selector, "=")
+		labelValue, exists := container.Labels[key]
+		if !exists {
+			return false
+		}
+		return !hasValue || labelValue == value
+	}
+
+	return matchesFilter(container.Name, filter)
+}
+
+// matchesFilter checks if a container name matches a filter pattern.
+// Patterns containing glob metacharacters (*, ?, [) use path.Match semantics,
+// e.g. "web-*", "*-prod" or "db?". Other patterns match exact names or substrings.
+func matchesFilter(name, pattern string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	if strings.ContainsAny(pattern, "*?[") {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			logger.Warn("matchesFilter: Invalid glob pattern", "pattern", pattern, "error", err)
+			return false
+		}
+		return matched
+	}
+
+	return strings.Contains(name, pattern)
+}
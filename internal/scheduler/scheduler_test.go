@@ -0,0 +1,482 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+	"github.com/ThraaxSession/gintainer/internal/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobsFromConfigLegacy(t *testing.T) {
+	jobs, err := jobsFromConfig(models.CronJobConfig{
+		Schedule: "0 2 * * *",
+		Enabled:  true,
+		Filters:  []string{"web"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CronJob{
+		{Name: DefaultJobName, Schedule: "0 2 * * *", Enabled: true, Filters: []string{"web"}},
+	}, jobs)
+}
+
+func TestJobsFromConfigDuplicateNames(t *testing.T) {
+	_, err := jobsFromConfig(models.CronJobConfig{
+		Jobs: []models.CronJob{
+			{Name: "db", Schedule: "0 3 * * 0"},
+			{Name: "db", Schedule: "0 4 * * 0"},
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = jobsFromConfig(models.CronJobConfig{
+		Schedule: "0 2 * * *",
+		Jobs:     []models.CronJob{{Name: DefaultJobName, Schedule: "0 3 * * 0"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestUpdateConfigMultipleJobs(t *testing.T) {
+	s := NewScheduler(runtime.NewManager())
+
+	err := s.UpdateConfig(models.CronJobConfig{
+		Schedule: "0 2 * * *",
+		Enabled:  true,
+		Jobs: []models.CronJob{
+			{Name: "databases", Schedule: "0 3 * * 0", Enabled: true, Filters: []string{"db-*"}},
+			{Name: "paused", Schedule: "0 4 * * *", Enabled: false},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, s.GetJobs(), 3)
+	// Only enabled jobs get a cron entry
+	assert.Len(t, s.cron.Entries(), 2)
+
+	// Replacing the config removes the old entries
+	err = s.UpdateConfig(models.CronJobConfig{Schedule: "0 2 * * *", Enabled: true})
+	assert.NoError(t, err)
+	assert.Len(t, s.GetJobs(), 1)
+	assert.Len(t, s.cron.Entries(), 1)
+}
+
+func TestUpdateConfigInvalidScheduleKeepsJobs(t *testing.T) {
+	s := NewScheduler(runtime.NewManager())
+
+	assert.NoError(t, s.UpdateConfig(models.CronJobConfig{Schedule: "0 2 * * *", Enabled: true}))
+
+	err := s.UpdateConfig(models.CronJobConfig{
+		Schedule: "0 2 * * *",
+		Enabled:  true,
+		Jobs:     []models.CronJob{{Name: "broken", Schedule: "not a schedule", Enabled: true}},
+	})
+	assert.Error(t, err)
+	assert.Len(t, s.GetJobs(), 1)
+	assert.Len(t, s.cron.Entries(), 1)
+}
+
+func TestMatchesFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    bool
+	}{
+		{"web-frontend", "web-*", true},
+		{"api-web-frontend", "web-*", false},
+		{"shop-prod", "*-prod", true},
+		{"shop-prod-old", "*-prod", false},
+		{"db1", "db?", true},
+		{"db12", "db?", false},
+		{"app1", "app[0-9]", true},
+		{"appx", "app[0-9]", false},
+		{"nginx", "nginx", true},
+		{"my-nginx-proxy", "nginx", true},
+		{"redis", "nginx", false},
+		{"anything", "", true},
+		{"web", "[", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchesFilter(tt.name, tt.pattern))
+		})
+	}
+}
+
+func TestMatchesContainerLabelFilter(t *testing.T) {
+	container := models.ContainerInfo{
+		Name:   "web",
+		Labels: map[string]string{"gintainer.auto-update": "true", "tier": "frontend"},
+	}
+
+	assert.True(t, matchesContainer(container, "label:gintainer.auto-update=true"))
+	assert.True(t, matchesContainer(container, "label:tier=frontend"))
+	assert.True(t, matchesContainer(container, "label:tier"))
+	assert.False(t, matchesContainer(container, "label:tier=backend"))
+	assert.False(t, matchesContainer(container, "label:missing"))
+	assert.True(t, matchesContainer(container, "web"))
+}
+
+func TestRunUpdateLabelAndNameFilters(t *testing.T) {
+	rt := &mockRuntime{
+		containers: []models.ContainerInfo{
+			{ID: "1", Name: "web-frontend"},
+			{ID: "2", Name: "db", Labels: map[string]string{"gintainer.auto-update": "true"}},
+			{ID: "3", Name: "cache"},
+			{ID: "4", Name: "web-admin", Labels: map[string]string{"gintainer.auto-update": "false"}},
+		},
+	}
+	s := newMockScheduler(rt)
+
+	s.runUpdate(models.CronJob{
+		Name:    "test",
+		Filters: []string{"web-*", "label:gintainer.auto-update=true"},
+	})
+
+	// web-admin matches the name pattern but opted out via label
+	assert.ElementsMatch(t, []string{"1", "2"}, rt.updatedIDs())
+}
+
+func TestRunUpdateOptOutWithoutFilters(t *testing.T) {
+	rt := &mockRuntime{
+		containers: []models.ContainerInfo{
+			{ID: "1", Name: "web"},
+			{ID: "2", Name: "db", Labels: map[string]string{"gintainer.auto-update": "false"}},
+		},
+	}
+	s := newMockScheduler(rt)
+
+	s.runUpdate(models.CronJob{Name: "test"})
+
+	assert.Equal(t, []string{"1"}, rt.updatedIDs())
+}
+
+func TestRunUpdateExcludeWinsOverInclude(t *testing.T) {
+	rt := &mockRuntime{
+		containers: []models.ContainerInfo{
+			{ID: "1", Name: "web-frontend"},
+			{ID: "2", Name: "web-db"},
+			{ID: "3", Name: "web-cache", Labels: map[string]string{"stateful": "true"}},
+			{ID: "4", Name: "worker"},
+		},
+	}
+	s := newMockScheduler(rt)
+
+	s.runUpdate(models.CronJob{
+		Name:    "test",
+		Filters: []string{"web-*"},
+		Exclude: []string{"*-db", "label:stateful=true"},
+	})
+
+	assert.Equal(t, []string{"1"}, rt.updatedIDs())
+}
+
+func TestRunUpdateExcludeWithoutIncludes(t *testing.T) {
+	rt := &mockRuntime{
+		containers: []models.ContainerInfo{
+			{ID: "1", Name: "web"},
+			{ID: "2", Name: "postgres"},
+		},
+	}
+	s := newMockScheduler(rt)
+
+	s.runUpdate(models.CronJob{Name: "test", Exclude: []string{"postgres"}})
+
+	assert.Equal(t, []string{"1"}, rt.updatedIDs())
+}
+
+func TestRunNowReport(t *testing.T) {
+	rt := &mockRuntime{
+		containers: []models.ContainerInfo{
+			{ID: "1", Name: "web-frontend"},
+			{ID: "2", Name: "web-api"},
+			{ID: "3", Name: "db"},
+		},
+		updateErrs: map[string]error{"2": errors.New("pull failed")},
+	}
+	s := newMockScheduler(rt)
+	assert.NoError(t, s.UpdateConfig(models.CronJobConfig{
+		Schedule: "0 2 * * *",
+		Enabled:  true,
+		Filters:  []string{"web-*"},
+		Jobs: []models.CronJob{
+			// Overlaps with the default job, the container must only be updated once
+			{Name: "frontend", Schedule: "0 3 * * *", Enabled: true, Filters: []string{"web-frontend"}},
+			{Name: "paused", Schedule: "0 4 * * *", Enabled: false, Filters: []string{"db"}},
+		},
+	}))
+
+	report, err := s.RunNow(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, ManualRunName, report.Job)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Skipped)
+	assert.ElementsMatch(t, []string{"1", "2"}, rt.updatedIDs())
+
+	statuses := map[string]string{}
+	for _, result := range report.Results {
+		statuses[result.ContainerName] = result.Status
+	}
+	assert.Equal(t, map[string]string{
+		"web-frontend": models.UpdateStatusUpdated,
+		"web-api":      models.UpdateStatusFailed,
+		"db":           models.UpdateStatusSkipped,
+	}, statuses)
+}
+
+func TestRunNowRejectsConcurrentRun(t *testing.T) {
+	s := newMockScheduler(&mockRuntime{})
+	s.running.Store(true)
+
+	_, err := s.RunNow(context.Background(), false)
+	assert.ErrorIs(t, err, ErrRunInProgress)
+
+	// The skipped run is kept in the history with the reason
+	history := s.GetHistory()
+	assert.Len(t, history, 1)
+	assert.Equal(t, ManualRunName, history[0].Job)
+	assert.Equal(t, ErrRunInProgress.Error(), history[0].SkippedReason)
+	assert.Empty(t, history[0].Results)
+}
+
+func TestRunHistory(t *testing.T) {
+	rt := &mockRuntime{containers: []models.ContainerInfo{{ID: "1", Name: "web"}}}
+	s := newMockScheduler(rt)
+
+	s.runUpdate(models.CronJob{Name: "nightly"})
+	_, err := s.RunNow(context.Background(), false)
+	assert.NoError(t, err)
+
+	history := s.GetHistory()
+	assert.Len(t, history, 2)
+	assert.Equal(t, ManualRunName, history[0].Job)
+	assert.Equal(t, "nightly", history[1].Job)
+	assert.Equal(t, 1, history[1].Updated)
+	assert.False(t, history[1].StartedAt.IsZero())
+
+	for i := 0; i < historySize+5; i++ {
+		s.runUpdate(models.CronJob{Name: "nightly"})
+	}
+	assert.Len(t, s.GetHistory(), historySize)
+}
+
+func TestNextRuns(t *testing.T) {
+	s := NewScheduler(runtime.NewManager())
+	assert.NoError(t, s.UpdateConfig(models.CronJobConfig{
+		Schedule: "0 2 * * *",
+		Enabled:  true,
+		Jobs:     []models.CronJob{{Name: "paused", Schedule: "0 3 * * *"}},
+	}))
+
+	s.Start()
+	defer s.Stop()
+
+	next := s.NextRuns()
+	assert.Len(t, next, 1)
+	assert.Equal(t, 2, next[DefaultJobName].Hour())
+	assert.True(t, next[DefaultJobName].After(time.Now()))
+}
+
+func TestUpdateConfigTimezone(t *testing.T) {
+	s := NewScheduler(runtime.NewManager())
+	assert.Equal(t, time.Local, s.Location())
+
+	s.Start()
+	defer s.Stop()
+
+	require.NoError(t, s.UpdateConfig(models.CronJobConfig{Schedule: "0 2 * * *", Enabled: true, Timezone: "Asia/Tokyo"}))
+	assert.Equal(t, "Asia/Tokyo", s.Location().String())
+
+	// The schedule is evaluated in the configured zone, and the recreated cron is running
+	next := s.NextRuns()[DefaultJobName]
+	require.False(t, next.IsZero())
+	assert.Equal(t, "Asia/Tokyo", next.Location().String())
+	assert.Equal(t, 2, next.Hour())
+
+	// An unknown zone leaves the current schedules untouched
+	err := s.UpdateConfig(models.CronJobConfig{Schedule: "0 4 * * *", Enabled: true, Timezone: "Mars/Olympus_Mons"})
+	assert.ErrorIs(t, err, ErrInvalidTimezone)
+	assert.Equal(t, "Asia/Tokyo", s.Location().String())
+	assert.Equal(t, "0 2 * * *", s.GetConfig().Schedule)
+
+	// Without a zone the server's local time is used again
+	require.NoError(t, s.UpdateConfig(models.CronJobConfig{Schedule: "0 2 * * *", Enabled: true}))
+	assert.Equal(t, time.Local, s.Location())
+}
+
+func TestRunNowDryRun(t *testing.T) {
+	rt := &mockRuntime{
+		containers: []models.ContainerInfo{
+			{ID: "1", Name: "web"},
+			{ID: "2", Name: "api"},
+		},
+		outdated: map[string]bool{"1": true},
+	}
+	s := newMockScheduler(rt)
+	assert.NoError(t, s.UpdateConfig(models.CronJobConfig{Schedule: "0 2 * * *", Enabled: true}))
+
+	report, err := s.RunNow(context.Background(), true)
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.WouldUpdate)
+	assert.Equal(t, 1, report.UpToDate)
+	assert.Equal(t, 0, report.Updated)
+	// Nothing must be recreated in dry-run mode
+	assert.Empty(t, rt.updatedIDs())
+
+	statuses := map[string]string{}
+	for _, result := range report.Results {
+		statuses[result.ContainerName] = result.Status
+	}
+	assert.Equal(t, models.UpdateStatusWouldUpdate, statuses["web"])
+	assert.Equal(t, models.UpdateStatusUpToDate, statuses["api"])
+}
+
+func TestRunUsesRegistryCredentials(t *testing.T) {
+	rt := &mockRuntime{
+		containers: []models.ContainerInfo{
+			{ID: "1", Name: "app", Image: "ghcr.io/org/app:latest"},
+			{ID: "2", Name: "web", Image: "nginx:latest"},
+		},
+	}
+	s := newMockScheduler(rt)
+	s.SetRegistries(map[string]config.RegistryCredentials{
+		"ghcr.io": {Username: "bot", Password: "secret"},
+	})
+	assert.NoError(t, s.UpdateConfig(models.CronJobConfig{Schedule: "0 2 * * *", Enabled: true}))
+
+	_, err := s.RunNow(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.RegistryAuth{Username: "bot", Password: "secret"}, rt.auths["1"])
+	assert.Nil(t, rt.auths["2"])
+
+	// Dry runs pull with the same credentials
+	rt.auths = nil
+	_, err = s.RunNow(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.RegistryAuth{Username: "bot", Password: "secret"}, rt.auths["1"])
+}
+
+func TestDefaultJobDryRun(t *testing.T) {
+	rt := &mockRuntime{
+		containers: []models.ContainerInfo{{ID: "1", Name: "web"}},
+		outdated:   map[string]bool{"1": true},
+	}
+	s := newMockScheduler(rt)
+	assert.NoError(t, s.UpdateConfig(models.CronJobConfig{Schedule: "0 2 * * *", Enabled: true, DryRun: true}))
+
+	jobs := s.GetJobs()
+	require.Len(t, jobs, 1)
+	assert.True(t, jobs[0].DryRun)
+
+	report, err := s.RunNow(context.Background(), false)
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.WouldUpdate)
+	assert.Empty(t, rt.updatedIDs())
+
+	s.runUpdate(jobs[0])
+	assert.Empty(t, rt.updatedIDs())
+}
+
+func TestRunDryRunJobOnlyAppliesToItsContainers(t *testing.T) {
+	rt := &mockRuntime{
+		containers: []models.ContainerInfo{
+			{ID: "1", Name: "web"},
+			{ID: "2", Name: "db"},
+		},
+		outdated: map[string]bool{"2": true},
+	}
+	s := newMockScheduler(rt)
+	assert.NoError(t, s.UpdateConfig(models.CronJobConfig{
+		Schedule: "0 2 * * *",
+		Enabled:  true,
+		Filters:  []string{"web"},
+		Jobs:     []models.CronJob{{Name: "db", Schedule: "0 3 * * *", Enabled: true, DryRun: true, Filters: []string{"db"}}},
+	}))
+
+	report, err := s.RunNow(context.Background(), false)
+	assert.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, []string{"1"}, rt.updatedIDs())
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.WouldUpdate)
+}
+
+func TestPreview(t *testing.T) {
+	from := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	runs, err := Preview("0 2 * * *", "Europe/Berlin", from, PreviewRuns)
+	require.NoError(t, err)
+	require.Len(t, runs, PreviewRuns)
+	for i, run := range runs {
+		assert.Equal(t, "Europe/Berlin", run.Location().String())
+		assert.Equal(t, 2, run.Hour())
+		assert.Equal(t, 2+i, run.Day())
+	}
+
+	runs, err = Preview("@hourly", "UTC", from, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{from.Add(time.Hour), from.Add(2 * time.Hour)}, runs)
+
+	// February 30th never comes
+	runs, err = Preview("0 0 30 2 *", "UTC", from, PreviewRuns)
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+
+	_, err = Preview("every day", "UTC", from, PreviewRuns)
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
+	_, err = Preview("0 2 * * *", "Nowhere/Special", from, PreviewRuns)
+	assert.ErrorIs(t, err, ErrInvalidTimezone)
+}
+
+func TestRunUpdateConcurrency(t *testing.T) {
+	var containers []models.ContainerInfo
+	updateErrs := map[string]error{}
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("%d", i)
+		containers = append(containers, models.ContainerInfo{ID: id, Name: "app-" + id})
+		if i%3 == 0 {
+			updateErrs[id] = errors.New("pull failed")
+		}
+	}
+
+	for _, tt := range []struct {
+		name        string
+		concurrency int
+		want        int
+	}{
+		{name: "default", concurrency: 0, want: DefaultUpdateConcurrency},
+		{name: "configured", concurrency: 5, want: 5},
+		{name: "serial", concurrency: 1, want: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &mockRuntime{containers: containers, updateErrs: updateErrs, updateDelay: 20 * time.Millisecond}
+			s := newMockScheduler(rt)
+			require.NoError(t, s.UpdateConfig(models.CronJobConfig{Schedule: "0 2 * * *", Enabled: true, UpdateConcurrency: tt.concurrency}))
+
+			report, err := s.RunNow(context.Background(), false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rt.maxActive)
+
+			// Failures do not stop the other updates, and every result keeps its place
+			assert.Equal(t, 6, report.Updated)
+			assert.Equal(t, 4, report.Failed)
+			require.Len(t, report.Results, len(containers))
+			for i, result := range report.Results {
+				assert.Equal(t, containers[i].ID, result.ContainerID)
+				if updateErrs[result.ContainerID] != nil {
+					assert.Equal(t, models.UpdateStatusFailed, result.Status)
+				} else {
+					assert.Equal(t, models.UpdateStatusUpdated, result.Status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"github.com/ThraaxSession/gintainer/internal/config"
+	"github.com/ThraaxSession/gintainer/internal/models"
+)
+
+// FromConfig converts the scheduler section of the config file into a scheduler configuration
+func FromConfig(cfg config.SchedulerConfig) models.CronJobConfig {
+	jobConfig := models.CronJobConfig{
+		Schedule:          cfg.Schedule,
+		Enabled:           cfg.Enabled,
+		Filters:           cfg.Filters,
+		Exclude:           cfg.Exclude,
+		DryRun:            cfg.DryRun,
+		Timezone:          cfg.Timezone,
+		UpdateConcurrency: cfg.UpdateConcurrency,
+	}
+	for _, job := range cfg.Jobs {
+		jobConfig.Jobs = append(jobConfig.Jobs, models.CronJob{
+			Name:     job.Name,
+			Schedule: job.Schedule,
+			Enabled:  job.Enabled,
+			Filters:  job.Filters,
+			Exclude:  job.Exclude,
+			DryRun:   job.DryRun,
+		})
+	}
+	return jobConfig
+}
+
+// ToConfig converts a scheduler configuration into the scheduler section of the config file
+func ToConfig(jobConfig models.CronJobConfig) config.SchedulerConfig {
+	cfg := config.SchedulerConfig{
+		Enabled:           jobConfig.Enabled,
+		Schedule:          jobConfig.Schedule,
+		Filters:           jobConfig.Filters,
+		Exclude:           jobConfig.Exclude,
+		DryRun:            jobConfig.DryRun,
+		Timezone:          jobConfig.Timezone,
+		UpdateConcurrency: jobConfig.UpdateConcurrency,
+	}
+	for _, job := range jobConfig.Jobs {
+		cfg.Jobs = append(cfg.Jobs, config.SchedulerJob{
+			Name:     job.Name,
+			Enabled:  job.Enabled,
+			Schedule: job.Schedule,
+			Filters:  job.Filters,
+			Exclude:  job.Exclude,
+			DryRun:   job.DryRun,
+		})
+	}
+	return cfg
+}
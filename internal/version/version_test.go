@@ -0,0 +1,22 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	oldVersion, oldCommit, oldBuildDate := Version, Commit, BuildDate
+	defer func() { Version, Commit, BuildDate = oldVersion, oldCommit, oldBuildDate }()
+
+	Version, Commit, BuildDate = "v1.2.0", "abc1234", "2026-01-01T00:00:00Z"
+	assert.Equal(t, Info{Version: "v1.2.0", Commit: "abc1234", BuildDate: "2026-01-01T00:00:00Z", GoVersion: runtime.Version()}, Get())
+
+	// Test binaries carry no VCS information
+	Commit, BuildDate = "", ""
+	info := Get()
+	assert.Equal(t, "unknown", info.Commit)
+	assert.Equal(t, "unknown", info.BuildDate)
+}